@@ -17,7 +17,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
+	"github.com/autobrr/dashbrr/internal/api/handlers"
 	"github.com/autobrr/dashbrr/internal/api/middleware"
 	"github.com/autobrr/dashbrr/internal/api/routes"
 	"github.com/autobrr/dashbrr/internal/buildinfo"
@@ -26,6 +29,10 @@ import (
 	"github.com/autobrr/dashbrr/internal/database"
 	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/services"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/core"
+	"github.com/autobrr/dashbrr/internal/services/overseerr"
+	"github.com/autobrr/dashbrr/internal/services/tailscale"
 	"github.com/autobrr/dashbrr/web"
 )
 
@@ -120,12 +127,54 @@ func startServer() {
 		}
 	}
 
-	db, err := database.InitDB(cfg.Database.Path)
+	dbConfig := database.NewConfig()
+	if dbConfig.Driver == "sqlite" {
+		dbConfig.Path = cfg.Database.Path
+	}
+	// secret.key of PostgreSQL setups goes to the configured data directory
+	dbConfig.DataDir = cfg.DataDirectory()
+
+	db, err := database.InitDBWithConfig(dbConfig)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize database")
 	}
 	defer db.Close()
 
+	// Always sync, so services removed from the config file become editable again
+	configServices, err := cfg.ServiceConfigurations()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid services in config file")
+	}
+	if err := db.SyncConfigServices(context.Background(), configServices); err != nil {
+		log.Fatal().Err(err).Msg("Failed to sync services from config file")
+	}
+	if len(configServices) > 0 {
+		log.Info().Int("services", len(configServices)).Msg("Synced services from config file")
+	}
+
+	core.SetSlowResponseThreshold(time.Duration(cfg.Health.SlowResponseThreshold) * time.Millisecond)
+	core.SetPoolSettings(core.PoolSettings{
+		MaxIdleConns:        cfg.HTTP.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.HTTP.MaxIdleConnsPerHost,
+		IdleConnTimeout:     time.Duration(cfg.HTTP.IdleConnTimeout) * time.Second,
+	})
+	logger.SetChangeSampleRate(cfg.Log.ChangeSampleRate)
+	handlers.SetPlexTranscodeLimit(cfg.Health.PlexTranscodeLimit)
+	handlers.SetInitialCheckConcurrency(cfg.Health.InitialConcurrency)
+	handlers.SetHealthPollWait(cfg.Health.PollWait)
+	handlers.SetBroadcastTimeout(cfg.Health.BroadcastTimeout)
+	cache.SetDataDir(cfg.DataDirectory())
+	handlers.SetProwlarrFailingIndexerThreshold(cfg.Health.FailingIndexerAlert)
+	overseerr.SetTitleLookupConcurrency(cfg.Overseerr.TitleLookupConcurrency)
+	tailscale.SetKeyExpiryWarningDays(cfg.Health.TailscaleKeyExpiryDays)
+	for _, rule := range cfg.Queue.AutoRemove {
+		if err := handlers.SetQueueCleanupRule(rule.InstanceID, rule.Pattern, time.Duration(rule.After)*time.Minute); err != nil {
+			log.Error().Err(err).Msg("Ignoring queue auto remove rule")
+			continue
+		}
+		log.Info().Str("instanceId", rule.InstanceID).Int("after", rule.After).Msg("Stalled queue items will be removed automatically")
+	}
+
 	healthService := services.NewHealthService()
 
 	if os.Getenv("GIN_MODE") == "debug" {
@@ -141,7 +190,7 @@ func startServer() {
 	if gin.Mode() == gin.DebugMode {
 		err = r.SetTrustedProxies(nil)
 	} else {
-		err = r.SetTrustedProxies([]string{"127.0.0.1", "::1"})
+		err = r.SetTrustedProxies(trustedProxies(cfg.Server.TrustedProxies))
 	}
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to set trusted proxies")
@@ -149,26 +198,31 @@ func startServer() {
 
 	r.Use(middleware.SetupCORS())
 
-	cacheStore := routes.SetupRoutes(r, db, healthService)
+	cacheStore, eventsHandler := routes.SetupRoutes(r, cfg, db, healthService)
 	defer func() {
 		if err := cacheStore.Close(); err != nil {
-			cacheType := strings.ToLower(os.Getenv("CACHE_TYPE"))
-			if cacheType == "redis" {
+			switch strings.ToLower(os.Getenv("CACHE_TYPE")) {
+			case "redis":
 				log.Error().Err(err).Msg("Failed to close Redis cache connection")
-			} else {
+			case "bolt":
+				log.Error().Err(err).Msg("Failed to close bolt cache database")
+			default:
 				log.Debug().Err(err).Msg("Cache cleanup completed")
 			}
 		}
 	}()
 
-	web.ServeStatic(r)
+	web.ServeStatic(r, cfg.Server.BasePath)
 
+	// h2c serves HTTP/2 over cleartext to clients that ask for it, e.g. a reverse proxy
+	// using prior knowledge, and falls back to HTTP/1.1 for everyone else
 	srv := &http.Server{
-		Addr:         cfg.Server.ListenAddr,
-		Handler:      r,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:              cfg.Server.ListenAddr,
+		Handler:           h2c.NewHandler(r, &http2.Server{}),
+		ReadTimeout:       config.Timeout(cfg.Server.ReadTimeout, config.DefaultReadTimeout),
+		ReadHeaderTimeout: config.Timeout(cfg.Server.ReadHeaderTimeout, config.DefaultReadHeaderTimeout),
+		WriteTimeout:      config.Timeout(cfg.Server.WriteTimeout, config.DefaultWriteTimeout),
+		IdleTimeout:       config.Timeout(cfg.Server.IdleTimeout, config.DefaultIdleTimeout),
 	}
 
 	go func() {
@@ -187,6 +241,9 @@ func startServer() {
 	<-quit
 	log.Info().Msg("Shutting down server...")
 
+	// Open SSE streams would keep the server from shutting down until the timeout
+	eventsHandler.Shutdown()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -196,3 +253,23 @@ func startServer() {
 
 	log.Info().Msg("Server exiting")
 }
+
+// trustedProxies parses the configured proxies into CIDR ranges for gin, logging and
+// skipping invalid entries. Loopback is trusted when none are configured.
+func trustedProxies(configured []string) []string {
+	if len(configured) == 0 {
+		configured = config.DefaultTrustedProxies
+	}
+
+	proxies, invalid := config.ParseTrustedProxies(configured)
+	for _, entry := range invalid {
+		log.Error().Str("proxy", entry).Msg("Ignoring invalid trusted proxy, expected an IP address or CIDR range")
+	}
+
+	ranges := make([]string, 0, len(proxies))
+	for _, proxy := range proxies {
+		ranges = append(ranges, proxy.String())
+	}
+	log.Debug().Strs("proxies", ranges).Msg("Trusted proxies")
+	return ranges
+}
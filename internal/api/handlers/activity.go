@@ -0,0 +1,278 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/arr"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/core"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+
+	arrHistoryPrefix        = "activity:history:"
+	arrHistoryCacheDuration = time.Minute
+)
+
+// overseerrRequestStatus names the request status codes of Overseerr
+var overseerrRequestStatus = map[int]string{
+	1: "pending",
+	2: "approved",
+	3: "declined",
+}
+
+type ActivityHandler struct {
+	db        *database.DB
+	cache     cache.Store
+	autobrr   *AutobrrHandler
+	overseerr *OverseerrHandler
+}
+
+func NewActivityHandler(db *database.DB, cache cache.Store) *ActivityHandler {
+	return &ActivityHandler{
+		db:        db,
+		cache:     cache,
+		autobrr:   NewAutobrrHandler(db, cache),
+		overseerr: NewOverseerrHandler(db, cache),
+	}
+}
+
+// GetActivity merges the recent autobrr releases, Overseerr requests and Sonarr and Radarr
+// grabs into a single feed, newest first. Cached data is used where the service handlers
+// have already fetched it.
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil || limit > maxActivityLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxActivityLimit)})
+		return
+	}
+	if limit == 0 {
+		limit = defaultActivityLimit
+	}
+
+	ctx := c.Request.Context()
+
+	services, err := h.db.GetAllServices(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	feed := types.ActivityFeed{Items: []types.ActivityItem{}}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, service := range services {
+		wg.Add(1)
+		go func(service models.ServiceConfiguration) {
+			defer wg.Done()
+
+			items, err := h.serviceActivity(ctx, service, limit)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Debug().Err(err).Str("instanceId", service.InstanceID).Msg("Failed to fetch activity")
+				if feed.Errors == nil {
+					feed.Errors = make(map[string]string)
+				}
+				feed.Errors[service.InstanceID] = err.Error()
+				return
+			}
+			feed.Items = append(feed.Items, items...)
+		}(service)
+	}
+	wg.Wait()
+
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return feed.Items[i].Timestamp.After(feed.Items[j].Timestamp)
+	})
+	if len(feed.Items) > limit {
+		feed.Items = feed.Items[:limit]
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// serviceActivity returns the recent activity of a service, nil for types without any
+func (h *ActivityHandler) serviceActivity(ctx context.Context, service models.ServiceConfiguration, limit int) ([]types.ActivityItem, error) {
+	serviceType, _, _ := strings.Cut(service.InstanceID, "-")
+
+	switch serviceType {
+	case "autobrr":
+		return h.releaseActivity(ctx, service.InstanceID, limit)
+	case "overseerr":
+		return h.requestActivity(ctx, service.InstanceID, limit)
+	case "sonarr", "radarr":
+		return h.grabActivity(ctx, serviceType, service, limit)
+	}
+	return nil, nil
+}
+
+// ignoreNotConfigured drops the error of a service without URL, it simply has no activity
+func ignoreNotConfigured(err error) error {
+	if err.Error() == "service not configured" {
+		return nil
+	}
+	return err
+}
+
+func (h *ActivityHandler) releaseActivity(ctx context.Context, instanceID string, limit int) ([]types.ActivityItem, error) {
+	cacheKey := releasesPrefix + instanceID
+
+	var releases types.ReleasesResponse
+	if err := h.cache.Get(ctx, cacheKey, &releases); err != nil {
+		// Detached from the request, the fetch below is shared with concurrent requests
+		result, err, _ := h.autobrr.sf.Do("releases:"+instanceID, func() (interface{}, error) {
+			return h.autobrr.fetchAndCacheReleases(context.Background(), instanceID, cacheKey)
+		})
+		if err != nil {
+			return nil, ignoreNotConfigured(err)
+		}
+		releases = result.(types.ReleasesResponse)
+	}
+
+	items := make([]types.ActivityItem, 0, min(len(releases.Data), limit))
+	for _, release := range releases.Data {
+		if len(items) == limit {
+			break
+		}
+
+		title := release.Name
+		if title == "" {
+			title = release.Title
+		}
+
+		detail := release.FilterStatus
+		if release.Indexer.Name != "" {
+			detail = fmt.Sprintf("%s from %s", detail, release.Indexer.Name)
+		}
+		if release.Filter != "" {
+			detail = fmt.Sprintf("%s (filter %s)", detail, release.Filter)
+		}
+
+		items = append(items, types.ActivityItem{
+			Timestamp: release.Timestamp,
+			Service:   instanceID,
+			Type:      types.ActivityRelease,
+			Title:     title,
+			Detail:    detail,
+		})
+	}
+
+	return items, nil
+}
+
+func (h *ActivityHandler) requestActivity(ctx context.Context, instanceID string, limit int) ([]types.ActivityItem, error) {
+	cacheKey := overseerrCachePrefix + instanceID
+
+	var stats *types.RequestsStats
+	if err := h.cache.Get(ctx, cacheKey, &stats); err != nil || stats == nil {
+		// Detached from the request, the fetch below is shared with concurrent requests
+		result, err, _ := h.overseerr.sf.Do("requests:"+instanceID, func() (interface{}, error) {
+			return h.overseerr.fetchAndCacheRequests(context.Background(), instanceID, cacheKey)
+		})
+		if err != nil {
+			return nil, ignoreNotConfigured(err)
+		}
+		stats, _ = result.(*types.RequestsStats)
+	}
+	if stats == nil {
+		return nil, nil
+	}
+
+	items := make([]types.ActivityItem, 0, min(len(stats.Requests), limit))
+	for _, request := range stats.Requests {
+		if len(items) == limit {
+			break
+		}
+
+		title := request.Media.Title
+		if title == "" {
+			title = fmt.Sprintf("%s %d", request.Media.MediaType, request.Media.TmdbID)
+		}
+
+		detail := "requested"
+		if request.RequestedBy.Username != "" {
+			detail = "requested by " + request.RequestedBy.Username
+		}
+		if status, ok := overseerrRequestStatus[request.Status]; ok {
+			detail = fmt.Sprintf("%s, %s", detail, status)
+		}
+
+		items = append(items, types.ActivityItem{
+			Timestamp: request.CreatedAt,
+			Service:   instanceID,
+			Type:      types.ActivityRequest,
+			Title:     title,
+			Detail:    detail,
+		})
+	}
+
+	return items, nil
+}
+
+func (h *ActivityHandler) grabActivity(ctx context.Context, serviceType string, service models.ServiceConfiguration, limit int) ([]types.ActivityItem, error) {
+	cacheKey := arrHistoryPrefix + service.InstanceID
+
+	// The maximum is always fetched, so the cached history serves any limit
+	var records []arr.HistoryRecord
+	if err := h.cache.Get(ctx, cacheKey, &records); err != nil {
+		client := &core.ServiceCore{}
+		service.Configure(client)
+		records, err = arr.GetGrabHistory(ctx, serviceType, service.URL, service.APIKey, service.APIVersion, client, maxActivityLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.cache.Set(ctx, cacheKey, records, arrHistoryCacheDuration); err != nil {
+			log.Warn().Err(err).Str("instanceId", service.InstanceID).Msg("Failed to cache history")
+		}
+	}
+
+	items := make([]types.ActivityItem, 0, min(len(records), limit))
+	for _, record := range records {
+		if len(items) == limit {
+			break
+		}
+
+		var detail string
+		if indexer := record.Data["indexer"]; indexer != "" {
+			detail = "grabbed from " + indexer
+		}
+		if client := record.Data["downloadClient"]; client != "" {
+			if detail == "" {
+				detail = "grabbed"
+			}
+			detail = fmt.Sprintf("%s to %s", detail, client)
+		}
+
+		items = append(items, types.ActivityItem{
+			Timestamp: record.Date,
+			Service:   service.InstanceID,
+			Type:      types.ActivityGrab,
+			Title:     record.SourceTitle,
+			Detail:    detail,
+		})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestActivityHandler_GetActivity(t *testing.T) {
+	settings, db := setupSettingsHandler(t)
+	store := settings.cache
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	radarr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/history" || r.URL.Query().Get("eventType") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"records": []map[string]interface{}{
+				{"id": 1, "date": now.Add(-time.Minute), "eventType": "grabbed", "sourceTitle": "Movie.2024.1080p", "data": map[string]string{"indexer": "Tracker"}},
+			},
+		})
+	}))
+	defer radarr.Close()
+
+	for _, svc := range []models.ServiceConfiguration{
+		{InstanceID: "autobrr-1", DisplayName: "autobrr", URL: "http://autobrr:7474"},
+		{InstanceID: "overseerr-1", DisplayName: "Overseerr", URL: "http://overseerr:5055"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: radarr.URL},
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://127.0.0.1:1"},
+	} {
+		svc := svc
+		if err := db.CreateService(ctx, &svc); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	// autobrr and Overseerr are served from the cache their handlers fill
+	releases := types.ReleasesResponse{Data: []types.Release{
+		{Name: "Show.S01E01.1080p", FilterStatus: "PUSH_APPROVED", Timestamp: now},
+		{Name: "Old.Release", FilterStatus: "FILTER_REJECTED", Timestamp: now.Add(-time.Hour)},
+	}}
+	if err := store.Set(ctx, releasesPrefix+"autobrr-1", releases, time.Minute); err != nil {
+		t.Fatalf("Failed to seed releases: %v", err)
+	}
+	request := types.MediaRequest{Status: 1, CreatedAt: now.Add(-2 * time.Minute)}
+	request.Media.Title = "Requested Movie"
+	if err := store.Set(ctx, overseerrCachePrefix+"overseerr-1", &types.RequestsStats{Requests: []types.MediaRequest{request}}, time.Minute); err != nil {
+		t.Fatalf("Failed to seed requests: %v", err)
+	}
+
+	c, w := newTestContext(http.MethodGet, "/api/activity?limit=3", nil)
+
+	NewActivityHandler(db, store).GetActivity(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var feed types.ActivityFeed
+	if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("Failed to decode feed: %v", err)
+	}
+
+	want := []struct{ service, typ, title string }{
+		{"autobrr-1", types.ActivityRelease, "Show.S01E01.1080p"},
+		{"radarr-1", types.ActivityGrab, "Movie.2024.1080p"},
+		{"overseerr-1", types.ActivityRequest, "Requested Movie"},
+	}
+	if len(feed.Items) != len(want) {
+		t.Fatalf("Expected %d items, got %+v", len(want), feed.Items)
+	}
+	for i, item := range feed.Items {
+		if item.Service != want[i].service || item.Type != want[i].typ || item.Title != want[i].title {
+			t.Errorf("Item %d = %+v, want %+v", i, item, want[i])
+		}
+	}
+
+	// The unreachable Sonarr is reported without failing the feed
+	if _, ok := feed.Errors["sonarr-1"]; !ok {
+		t.Errorf("Expected an error for sonarr-1, got %v", feed.Errors)
+	}
+
+	c, w = newTestContext(http.MethodGet, "/api/activity?limit=1000", nil)
+	NewActivityHandler(db, store).GetActivity(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a limit above the maximum, got %d", w.Code)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+// sessionPrefixes lists the cache key prefixes of built-in and OIDC sessions
+var sessionPrefixes = []string{cache.PrefixSession, "oidc:session:"}
+
+// dbMaintenanceStatus describes the current or most recent database maintenance run
+type dbMaintenanceStatus struct {
+	Driver     string     `json:"driver"`
+	Running    bool       `json:"running"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Duration   string     `json:"duration,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type AdminHandler struct {
+	db    *database.DB
+	cache cache.Store
+
+	mu          sync.Mutex
+	maintenance dbMaintenanceStatus
+}
+
+func NewAdminHandler(db *database.DB, cache cache.Store) *AdminHandler {
+	return &AdminHandler{
+		db:    db,
+		cache: cache,
+		maintenance: dbMaintenanceStatus{
+			Driver: db.Driver(),
+		},
+	}
+}
+
+// GetDBStats reports the database connection pool statistics
+func (h *AdminHandler) GetDBStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.db.PoolStats())
+}
+
+// RunDBMaintenance starts a database checkpoint and vacuum in the background.
+// Progress can be followed with GetDBMaintenance.
+func (h *AdminHandler) RunDBMaintenance(c *gin.Context) {
+	h.mu.Lock()
+	if h.maintenance.Running || h.db.MaintenanceRunning() {
+		status := h.maintenance
+		h.mu.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "Database maintenance already running", "status": status})
+		return
+	}
+
+	startedAt := time.Now()
+	h.maintenance = dbMaintenanceStatus{
+		Driver:    h.db.Driver(),
+		Running:   true,
+		StartedAt: &startedAt,
+	}
+	status := h.maintenance
+	h.mu.Unlock()
+
+	go h.runMaintenance(startedAt)
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// GetDBMaintenance reports the status of the current or most recent maintenance run
+func (h *AdminHandler) GetDBMaintenance(c *gin.Context) {
+	h.mu.Lock()
+	status := h.maintenance
+	h.mu.Unlock()
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetLogs returns the most recent log entries of this instance, oldest first.
+// The level query parameter sets the minimum level, limit keeps only the newest entries.
+func (h *AdminHandler) GetLogs(c *gin.Context) {
+	minLevel := zerolog.TraceLevel
+	if value := c.Query("level"); value != "" {
+		level, err := zerolog.ParseLevel(strings.ToLower(value))
+		if err != nil || level == zerolog.NoLevel {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid log level"})
+			return
+		}
+		minLevel = level
+	}
+
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative number"})
+		return
+	}
+
+	entries := logger.Recent().Entries(minLevel)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// RotateAuth invalidates every built-in and OIDC session, including the caller's, forcing
+// everyone to log in again. Sessions are random tokens looked up in the cache, so there is
+// no signing key to regenerate, dropping them is enough.
+func (h *AdminHandler) RotateAuth(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	revoked := 0
+	for _, prefix := range sessionPrefixes {
+		keys, err := h.cache.Keys(ctx, prefix)
+		if err != nil {
+			log.Error().Err(err).Str("prefix", prefix).Msg("Failed to list sessions")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions"})
+			return
+		}
+
+		for _, key := range keys {
+			if err := h.cache.Delete(ctx, key); err != nil {
+				log.Error().Err(err).Str("prefix", prefix).Msg("Failed to revoke session")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions", "revoked": revoked})
+				return
+			}
+			revoked++
+		}
+	}
+
+	event := log.Warn().Int("revoked", revoked).Str("clientIP", c.ClientIP())
+	if session, ok := c.Get("session"); ok {
+		if sessionData, ok := session.(types.SessionData); ok {
+			event = event.Str("authType", sessionData.AuthType).Int64("userId", sessionData.UserID).Str("provider", sessionData.Provider)
+		}
+	}
+	event.Msg("All sessions revoked, everyone has to log in again")
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+func (h *AdminHandler) runMaintenance(startedAt time.Time) {
+	// Not tied to the request, the run outlives it
+	err := h.db.Maintenance(context.Background())
+
+	finishedAt := time.Now()
+	duration := finishedAt.Sub(startedAt)
+
+	h.mu.Lock()
+	h.maintenance.Running = false
+	h.maintenance.FinishedAt = &finishedAt
+	h.maintenance.Duration = duration.Round(time.Millisecond).String()
+	if err != nil {
+		h.maintenance.Error = err.Error()
+	}
+	h.mu.Unlock()
+
+	if err != nil {
+		if errors.Is(err, database.ErrMaintenanceRunning) {
+			log.Warn().Msg("Database maintenance already running")
+			return
+		}
+		log.Error().Err(err).Msg("Database maintenance failed")
+		return
+	}
+
+	log.Info().
+		Str("driver", h.db.Driver()).
+		Dur("duration", duration).
+		Msg("Database maintenance completed")
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestAdminHandler_RotateAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	store := setupTestCache(t)
+
+	session := types.SessionData{AuthType: "builtin", UserID: 1}
+	for _, key := range []string{"session:a", "session:b", "oidc:session:c"} {
+		if err := store.Set(ctx, key, session, time.Hour); err != nil {
+			t.Fatalf("Failed to store session: %v", err)
+		}
+	}
+	if err := store.Set(ctx, "health:sonarr-1", "online", time.Hour); err != nil {
+		t.Fatalf("Failed to store health: %v", err)
+	}
+
+	handler := &AdminHandler{cache: store}
+
+	c, w := newTestContext(http.MethodPost, "/api/admin/auth/rotate", nil)
+	c.Set("session", session)
+
+	handler.RotateAuth(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Revoked int `json:"revoked"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if resp.Revoked != 3 {
+		t.Errorf("Expected 3 revoked sessions, got %d", resp.Revoked)
+	}
+
+	for _, prefix := range sessionPrefixes {
+		keys, _ := store.Keys(ctx, prefix)
+		if len(keys) != 0 {
+			t.Errorf("Expected no sessions under %q, got %v", prefix, keys)
+		}
+	}
+
+	var health string
+	if err := store.Get(ctx, "health:sonarr-1", &health); err != nil {
+		t.Errorf("Expected other cache keys to survive, got %v", err)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,18 +23,77 @@ import (
 	"github.com/autobrr/dashbrr/internal/types"
 )
 
+// defaultProviderName is used when the primary OIDC provider has no explicit name
+const defaultProviderName = "default"
+
+const (
+	// oidcSessionTTL is how long an OIDC session with a refresh token lasts after its last
+	// refresh. Access tokens are short lived and refreshed server-side within it.
+	oidcSessionTTL = 7 * 24 * time.Hour
+
+	// accessTokenRefreshLeeway is how long before it expires an access token is refreshed
+	accessTokenRefreshLeeway = time.Minute
+)
+
+// basePath is the sub-path the app is served under, used for redirects that don't know the frontend URL
+var basePath string
+
+// ConfigureBasePath sets the base path used in redirects to the frontend
+func ConfigureBasePath(path string) {
+	basePath = path
+}
+
 type AuthHandler struct {
 	config       *types.AuthConfig
 	cache        cache.Store
 	oauth2Config *oauth2.Config
+	providers    map[string]*oidcProvider
 	httpClient   *http.Client
 }
 
-func NewAuthHandler(config *types.AuthConfig, store cache.Store) *AuthHandler {
+// oidcProvider pairs a provider's configuration with its oauth2 client config
+type oidcProvider struct {
+	config       *types.AuthConfig
+	oauth2Config *oauth2.Config
+}
+
+// NewAuthHandler creates an OIDC auth handler. The first config is the default provider,
+// any additional configs are registered as named providers selectable at login.
+func NewAuthHandler(config *types.AuthConfig, store cache.Store, additional ...*types.AuthConfig) *AuthHandler {
+	if config.Name == "" {
+		config.Name = defaultProviderName
+	}
+
+	h := &AuthHandler{
+		config:       config,
+		cache:        store,
+		oauth2Config: newOAuth2Config(config),
+		providers:    make(map[string]*oidcProvider),
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+	h.providers[config.Name] = &oidcProvider{config: config, oauth2Config: h.oauth2Config}
+
+	for _, cfg := range additional {
+		if cfg.Name == "" {
+			log.Warn().Str("issuer", cfg.Issuer).Msg("Skipping OIDC provider without a name")
+			continue
+		}
+		if _, exists := h.providers[cfg.Name]; exists {
+			log.Warn().Str("provider", cfg.Name).Msg("Skipping duplicate OIDC provider")
+			continue
+		}
+		h.providers[cfg.Name] = &oidcProvider{config: cfg, oauth2Config: newOAuth2Config(cfg)}
+	}
+
+	return h
+}
+
+// newOAuth2Config builds the oauth2 client configuration for a provider
+func newOAuth2Config(config *types.AuthConfig) *oauth2.Config {
 	// Ensure issuer URL doesn't have trailing slash
 	issuer := strings.TrimRight(config.Issuer, "/")
 
-	oauth2Config := &oauth2.Config{
+	return &oauth2.Config{
 		ClientID:     config.ClientID,
 		ClientSecret: config.ClientSecret,
 		RedirectURL:  config.RedirectURL,
@@ -42,13 +103,37 @@ func NewAuthHandler(config *types.AuthConfig, store cache.Store) *AuthHandler {
 		},
 		Scopes: []string{"openid", "profile", "email"},
 	}
+}
 
-	return &AuthHandler{
-		config:       config,
-		cache:        store,
-		oauth2Config: oauth2Config,
-		httpClient:   &http.Client{Timeout: 10 * time.Second},
+// provider returns the named provider, falling back to the default provider when name is empty
+func (h *AuthHandler) provider(name string) (*oidcProvider, bool) {
+	if name == "" {
+		if h.config == nil {
+			return nil, false
+		}
+		if p, ok := h.providers[h.config.Name]; ok {
+			return p, true
+		}
+		return &oidcProvider{config: h.config, oauth2Config: h.oauth2Config}, true
 	}
+	p, ok := h.providers[name]
+	return p, ok
+}
+
+// ProviderNames returns the names of all configured providers, default provider first
+func (h *AuthHandler) ProviderNames() []string {
+	names := make([]string, 0, len(h.providers))
+	if h.config != nil {
+		names = append(names, h.config.Name)
+	}
+	var rest []string
+	for name := range h.providers {
+		if h.config == nil || name != h.config.Name {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
 }
 
 // generateSecureRandomString generates a cryptographically secure random string
@@ -60,6 +145,75 @@ func generateSecureRandomString(length int) (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes)[:length], nil
 }
 
+// sessionIDFromRequest returns the session id from the session cookie or a Bearer Authorization header
+func sessionIDFromRequest(c *gin.Context) (string, bool) {
+	if sessionID, err := c.Cookie(sessionCookieName); err == nil && sessionID != "" {
+		return sessionID, true
+	}
+
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" && parts[1] != "" {
+		return parts[1], true
+	}
+
+	return "", false
+}
+
+// errUnknownProvider is returned when a session references a provider that's no longer configured
+var errUnknownProvider = errors.New("unknown OIDC provider")
+
+// oidcSessionLifetime returns how long a session for token lasts. With a refresh token the
+// session outlives the access token, which is refreshed server-side, otherwise it ends with it.
+func oidcSessionLifetime(token *oauth2.Token) time.Duration {
+	if token.RefreshToken != "" || token.Expiry.IsZero() {
+		return oidcSessionTTL
+	}
+	return time.Until(token.Expiry)
+}
+
+// refreshSession exchanges the session's refresh token for a new access token and stores
+// the updated session, extending it by the session lifetime
+func (h *AuthHandler) refreshSession(ctx context.Context, sessionKey string, sessionData *types.SessionData) (*oauth2.Token, error) {
+	provider, ok := h.provider(sessionData.Provider)
+	if !ok {
+		return nil, errUnknownProvider
+	}
+
+	// Without the access token the token source always asks the provider for a new one
+	token := &oauth2.Token{
+		RefreshToken: sessionData.RefreshToken,
+		Expiry:       sessionData.ExpiresAt,
+	}
+	newToken, err := provider.oauth2Config.TokenSource(ctx, token).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionData.AccessToken = newToken.AccessToken
+	sessionData.TokenType = newToken.TokenType
+	sessionData.RefreshToken = newToken.RefreshToken
+	sessionData.ExpiresAt = newToken.Expiry
+	if rawIDToken, ok := newToken.Extra("id_token").(string); ok {
+		sessionData.IDToken = rawIDToken
+	}
+
+	if err := h.cache.Set(ctx, sessionKey, *sessionData, oidcSessionLifetime(newToken)); err != nil {
+		return nil, fmt.Errorf("failed to update session in cache: %w", err)
+	}
+	return newToken, nil
+}
+
+// ensureFreshSession refreshes the session's access token server-side once it's about to
+// expire, so the session stays usable for as long as the refresh token is
+func (h *AuthHandler) ensureFreshSession(ctx context.Context, sessionKey string, sessionData *types.SessionData) error {
+	if sessionData.RefreshToken == "" || sessionData.ExpiresAt.IsZero() ||
+		time.Until(sessionData.ExpiresAt) > accessTokenRefreshLeeway {
+		return nil
+	}
+	_, err := h.refreshSession(ctx, sessionKey, sessionData)
+	return err
+}
+
 // Login initiates the OIDC authentication flow
 func (h *AuthHandler) Login(c *gin.Context) {
 	// Create context with timeout for login flow
@@ -75,6 +229,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	providerName := c.Query("provider")
+	provider, ok := h.provider(providerName)
+	if !ok {
+		log.Error().Str("provider", providerName).Msg("unknown OIDC provider")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown authentication provider"})
+		return
+	}
+
 	state, err := generateSecureRandomString(32)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to generate state")
@@ -95,6 +257,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	stateData := map[string]interface{}{
 		"timestamp":   time.Now().Unix(),
 		"frontendUrl": frontendUrl,
+		"provider":    provider.config.Name,
 	}
 
 	if err := h.cache.Set(ctx, stateKey, stateData, 5*time.Minute); err != nil {
@@ -121,7 +284,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	authURL := h.oauth2Config.AuthCodeURL(
+	authURL := provider.oauth2Config.AuthCodeURL(
 		state,
 		oauth2.SetAuthURLParam("nonce", nonce),
 		oauth2.SetAuthURLParam("response_type", "code"),
@@ -141,7 +304,7 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 
 	if code == "" {
 		log.Error().Msg("no code in callback")
-		c.Redirect(http.StatusTemporaryRedirect, "/login?error=no_code")
+		c.Redirect(http.StatusTemporaryRedirect, basePath+"/login?error=no_code")
 		return
 	}
 
@@ -150,7 +313,7 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	if err := h.cache.Get(ctx, stateKey, &stateData); err != nil {
 		if ctx.Err() != nil {
 			log.Error().Err(ctx.Err()).Msg("Context canceled while retrieving state")
-			c.Redirect(http.StatusTemporaryRedirect, "/login?error=timeout")
+			c.Redirect(http.StatusTemporaryRedirect, basePath+"/login?error=timeout")
 			return
 		}
 		if err == cache.ErrKeyNotFound {
@@ -158,14 +321,14 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 		} else {
 			log.Error().Err(err).Msg("failed to get state from cache")
 		}
-		c.Redirect(http.StatusTemporaryRedirect, "/login?error=invalid_state")
+		c.Redirect(http.StatusTemporaryRedirect, basePath+"/login?error=invalid_state")
 		return
 	}
 
 	frontendUrl, ok := stateData["frontendUrl"].(string)
 	if !ok {
 		log.Error().Msg("no frontend URL in state data")
-		c.Redirect(http.StatusTemporaryRedirect, "/login?error=invalid_state")
+		c.Redirect(http.StatusTemporaryRedirect, basePath+"/login?error=invalid_state")
 		return
 	}
 
@@ -173,8 +336,17 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 		log.Error().Err(err).Msg("failed to delete state from cache")
 	}
 
+	// The provider is recorded in the state so the code is exchanged with the same token endpoint
+	providerName, _ := stateData["provider"].(string)
+	provider, ok := h.provider(providerName)
+	if !ok {
+		log.Error().Str("provider", providerName).Msg("unknown OIDC provider in state data")
+		c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%s/login?error=invalid_state", frontendUrl))
+		return
+	}
+
 	// Exchange code for token using context
-	token, err := h.oauth2Config.Exchange(ctx, code)
+	token, err := provider.oauth2Config.Exchange(ctx, code)
 	if err != nil {
 		if ctx.Err() != nil {
 			log.Error().Err(ctx.Err()).Msg("Context canceled during token exchange")
@@ -200,10 +372,21 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 		IDToken:      rawIDToken,
 		ExpiresAt:    token.Expiry,
 		AuthType:     "oidc",
+		Provider:     provider.config.Name,
 	}
 
-	sessionKey := fmt.Sprintf("oidc:session:%s", token.AccessToken)
-	if err := h.cache.Set(ctx, sessionKey, sessionData, time.Until(token.Expiry)); err != nil {
+	// The cookie carries a random session id rather than the access token, so token
+	// refreshes and restarts don't invalidate the browser session
+	sessionID, err := generateSecureRandomString(32)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate session id")
+		c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%s/login?error=session_failed", frontendUrl))
+		return
+	}
+
+	lifetime := oidcSessionLifetime(token)
+	sessionKey := fmt.Sprintf("oidc:session:%s", sessionID)
+	if err := h.cache.Set(ctx, sessionKey, sessionData, lifetime); err != nil {
 		if ctx.Err() != nil {
 			log.Error().Err(ctx.Err()).Msg("Context canceled while storing session")
 			c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%s/login?error=timeout", frontendUrl))
@@ -214,21 +397,11 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 		return
 	}
 
-	var isSecure = c.GetHeader("X-Forwarded-Proto") == "https"
-
-	c.SetCookie(
-		"session",
-		token.AccessToken,
-		int(time.Until(token.Expiry).Seconds()),
-		"/",
-		"",
-		isSecure,
-		true,
-	)
+	setSessionCookie(c, sessionID, int(lifetime.Seconds()))
 
 	c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%s?access_token=%s&id_token=%s",
 		frontendUrl,
-		token.AccessToken,
+		sessionID,
 		rawIDToken,
 	))
 }
@@ -246,14 +419,23 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	sessionID, err := c.Cookie("session")
-	if err != nil {
-		log.Error().Err(err).Msg("no session cookie found")
+	sessionID, ok := sessionIDFromRequest(c)
+	if !ok {
+		log.Error().Msg("no session cookie found")
 		c.JSON(http.StatusOK, gin.H{"message": "Already logged out"})
 		return
 	}
 
 	sessionKey := fmt.Sprintf("oidc:session:%s", sessionID)
+
+	// Look up which provider issued the session so we log out of the right one
+	var sessionData types.SessionData
+	_ = h.cache.Get(ctx, sessionKey, &sessionData)
+	provider, ok := h.provider(sessionData.Provider)
+	if !ok {
+		provider, _ = h.provider("")
+	}
+
 	if err := h.cache.Delete(ctx, sessionKey); err != nil {
 		if ctx.Err() != nil {
 			log.Error().Err(ctx.Err()).Msg("Context canceled while deleting session")
@@ -265,21 +447,11 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		}
 	}
 
-	var isSecure = c.GetHeader("X-Forwarded-Proto") == "https"
-
-	c.SetCookie(
-		"session",
-		"",
-		-1,
-		"/",
-		"",
-		isSecure,
-		true,
-	)
+	setSessionCookie(c, "", -1)
 
 	logoutURL := fmt.Sprintf("%s/v2/logout?client_id=%s&returnTo=%s",
-		strings.TrimRight(h.config.Issuer, "/"),
-		h.config.ClientID,
+		strings.TrimRight(provider.config.Issuer, "/"),
+		provider.config.ClientID,
 		frontendUrl,
 	)
 	c.Redirect(http.StatusTemporaryRedirect, logoutURL)
@@ -291,8 +463,8 @@ func (h *AuthHandler) VerifyToken(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	sessionID, err := c.Cookie("session")
-	if err != nil {
+	sessionID, ok := sessionIDFromRequest(c)
+	if !ok {
 		log.Trace().Msg("no session cookie found")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "No session found"})
 		return
@@ -315,6 +487,17 @@ func (h *AuthHandler) VerifyToken(c *gin.Context) {
 		return
 	}
 
+	if err := h.ensureFreshSession(ctx, sessionKey, &sessionData); err != nil {
+		if ctx.Err() != nil {
+			log.Error().Err(ctx.Err()).Msg("Context canceled during token refresh")
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Operation timed out"})
+			return
+		}
+		log.Error().Err(err).Msg("token refresh failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Token is valid",
 	})
@@ -326,9 +509,9 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	sessionID, err := c.Cookie("session")
-	if err != nil {
-		log.Error().Err(err).Msg("no session cookie found")
+	sessionID, ok := sessionIDFromRequest(c)
+	if !ok {
+		log.Error().Msg("no session cookie found")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "No session found"})
 		return
 	}
@@ -350,61 +533,27 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	token := &oauth2.Token{
-		RefreshToken: sessionData.RefreshToken,
-		Expiry:       sessionData.ExpiresAt,
-	}
-
-	// Create token source with context
-	tokenSource := h.oauth2Config.TokenSource(ctx, token)
-
-	// Refresh the token
-	newToken, err := tokenSource.Token()
+	newToken, err := h.refreshSession(ctx, sessionKey, &sessionData)
 	if err != nil {
 		if ctx.Err() != nil {
 			log.Error().Err(ctx.Err()).Msg("Context canceled during token refresh")
 			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Operation timed out"})
 			return
 		}
-		log.Error().Err(err).Msg("token refresh failed")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
-		return
-	}
-
-	// Update session with new token data
-	sessionData.AccessToken = newToken.AccessToken
-	sessionData.RefreshToken = newToken.RefreshToken
-	sessionData.ExpiresAt = newToken.Expiry
-	if rawIDToken, ok := newToken.Extra("id_token").(string); ok {
-		sessionData.IDToken = rawIDToken
-	}
-
-	// Store updated session
-	if err := h.cache.Set(ctx, sessionKey, sessionData, time.Until(newToken.Expiry)); err != nil {
-		if ctx.Err() != nil {
-			log.Error().Err(ctx.Err()).Msg("Context canceled while updating session")
-			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Operation timed out"})
+		if err == errUnknownProvider {
+			log.Error().Str("provider", sessionData.Provider).Msg("session references unknown OIDC provider")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
 			return
 		}
-		log.Error().Err(err).Msg("failed to update session in cache")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
+		log.Error().Err(err).Msg("token refresh failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
 		return
 	}
 
-	var isSecure = c.GetHeader("X-Forwarded-Proto") == "https"
-
-	c.SetCookie(
-		"session",
-		newToken.AccessToken,
-		int(time.Until(newToken.Expiry).Seconds()),
-		"/",
-		"",
-		isSecure,
-		true,
-	)
+	setSessionCookie(c, sessionID, int(oidcSessionLifetime(newToken).Seconds()))
 
 	c.JSON(http.StatusOK, gin.H{
-		"access_token":  newToken.AccessToken,
+		"access_token":  sessionID,
 		"token_type":    newToken.TokenType,
 		"expires_in":    int(time.Until(newToken.Expiry).Seconds()),
 		"refresh_token": newToken.RefreshToken,
@@ -417,9 +566,9 @@ func (h *AuthHandler) UserInfo(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	sessionID, err := c.Cookie("session")
-	if err != nil {
-		log.Error().Err(err).Msg("no session cookie found")
+	sessionID, ok := sessionIDFromRequest(c)
+	if !ok {
+		log.Error().Msg("no session cookie found")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "No session found"})
 		return
 	}
@@ -441,7 +590,25 @@ func (h *AuthHandler) UserInfo(c *gin.Context) {
 		return
 	}
 
-	userinfoURL := fmt.Sprintf("%s/userinfo", strings.TrimRight(h.config.Issuer, "/"))
+	provider, ok := h.provider(sessionData.Provider)
+	if !ok {
+		log.Error().Str("provider", sessionData.Provider).Msg("session references unknown OIDC provider")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if err := h.ensureFreshSession(ctx, sessionKey, &sessionData); err != nil {
+		if ctx.Err() != nil {
+			log.Error().Err(ctx.Err()).Msg("Context canceled during token refresh")
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Operation timed out"})
+			return
+		}
+		log.Error().Err(err).Msg("token refresh failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired"})
+		return
+	}
+
+	userinfoURL := fmt.Sprintf("%s/userinfo", strings.TrimRight(provider.config.Issuer, "/"))
 	req, err := http.NewRequestWithContext(ctx, "GET", userinfoURL, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create userinfo request")
@@ -485,3 +652,11 @@ func (h *AuthHandler) UserInfo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, userInfo)
 }
+
+// Providers lists the OIDC providers that can be selected at login
+func (h *AuthHandler) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"providers": h.ProviderNames(),
+		"default":   h.config.Name,
+	})
+}
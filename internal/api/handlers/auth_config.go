@@ -5,16 +5,38 @@ package handlers
 
 import (
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
+// AuthConfigHandler reports the authentication methods available to the frontend
+type AuthConfigHandler struct {
+	disabled      bool
+	oidcProviders []string
+}
+
+func NewAuthConfigHandler(disabled bool, oidcProviders []string) *AuthConfigHandler {
+	return &AuthConfigHandler{
+		disabled:      disabled,
+		oidcProviders: oidcProviders,
+	}
+}
+
 // GetAuthConfig returns the available authentication methods
-func GetAuthConfig(c *gin.Context) {
-	hasOIDC := os.Getenv("OIDC_ISSUER") != "" &&
-		os.Getenv("OIDC_CLIENT_ID") != "" &&
-		os.Getenv("OIDC_CLIENT_SECRET") != ""
+func (h *AuthConfigHandler) GetAuthConfig(c *gin.Context) {
+	if h.disabled {
+		c.JSON(http.StatusOK, gin.H{
+			"methods": map[string]bool{
+				"builtin": false,
+				"oidc":    false,
+			},
+			"default":      "none",
+			"authDisabled": true,
+		})
+		return
+	}
+
+	hasOIDC := len(h.oidcProviders) > 0
 
 	defaultMethod := "builtin"
 	if hasOIDC {
@@ -26,6 +48,15 @@ func GetAuthConfig(c *gin.Context) {
 			"builtin": !hasOIDC, // Built-in auth is only available when OIDC is not configured
 			"oidc":    hasOIDC,
 		},
-		"default": defaultMethod,
+		"default":   defaultMethod,
+		"providers": h.oidcProviders,
+	})
+}
+
+// AuthDisabled answers auth endpoints when authentication is turned off (auth.mode = "none")
+func AuthDisabled(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Authentication is disabled",
+		"authDisabled": true,
 	})
 }
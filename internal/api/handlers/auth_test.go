@@ -5,16 +5,21 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/oauth2"
 
+	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/types"
 )
 
@@ -114,6 +119,23 @@ func (m *MockStore) Expire(ctx context.Context, key string, expiration time.Dura
 	return errors.New("unknown error")
 }
 
+func (m *MockStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	args := m.safeArgs(m.Called(ctx, prefix))
+	var keys []string
+	if args.Get(0) != nil {
+		if k, ok := args.Get(0).([]string); ok {
+			keys = k
+		}
+	}
+	var err error
+	if len(args) > 1 && args.Get(1) != nil {
+		if e, ok := args.Get(1).(error); ok {
+			err = e
+		}
+	}
+	return keys, err
+}
+
 func (m *MockStore) Close() error {
 	args := m.safeArgs(m.Called())
 	if args.Get(0) == nil {
@@ -216,3 +238,441 @@ func TestLogout_NoFrontendURL(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	mockStore.AssertExpectations(t)
 }
+
+// stubIssuer is an OIDC issuer answering token requests with tokens named after it
+type stubIssuer struct {
+	*httptest.Server
+	name string
+
+	mu     sync.Mutex
+	grants []string
+}
+
+func newStubIssuer(t *testing.T, name string) *stubIssuer {
+	t.Helper()
+
+	issuer := &stubIssuer{name: name}
+	issuer.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		issuer.mu.Lock()
+		issuer.grants = append(issuer.grants, r.PostForm.Get("grant_type"))
+		issuer.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  name + "-access",
+			"token_type":    "Bearer",
+			"refresh_token": name + "-refresh",
+			"expires_in":    300,
+			"id_token":      name + "-id",
+		})
+	}))
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// tokenRequests returns the grant types of the token requests the issuer received
+func (s *stubIssuer) tokenRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.grants...)
+}
+
+// setupAuthHandler returns a handler with an unnamed default provider and a provider named work
+func setupAuthHandler(t *testing.T) (*AuthHandler, cache.Store, *stubIssuer, *stubIssuer) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	defaultIssuer := newStubIssuer(t, "default")
+	workIssuer := newStubIssuer(t, "work")
+	store := setupTestCache(t)
+
+	handler := NewAuthHandler(&types.AuthConfig{
+		Issuer:       defaultIssuer.URL,
+		ClientID:     "default-client",
+		ClientSecret: "default-secret",
+		RedirectURL:  "http://localhost:3000/callback",
+	}, store, &types.AuthConfig{
+		Name:         "work",
+		Issuer:       workIssuer.URL + "/",
+		ClientID:     "work-client",
+		ClientSecret: "work-secret",
+		RedirectURL:  "http://localhost:3000/callback",
+	})
+
+	return handler, store, defaultIssuer, workIssuer
+}
+
+func TestNewAuthHandler_Providers(t *testing.T) {
+	handler, _, defaultIssuer, workIssuer := setupAuthHandler(t)
+
+	assert.Equal(t, []string{defaultProviderName, "work"}, handler.ProviderNames())
+
+	provider, ok := handler.provider("")
+	assert.True(t, ok)
+	assert.Equal(t, defaultProviderName, provider.config.Name)
+	assert.Equal(t, defaultIssuer.URL+"/oauth/token", provider.oauth2Config.Endpoint.TokenURL)
+
+	provider, ok = handler.provider("work")
+	assert.True(t, ok)
+	assert.Equal(t, "work-client", provider.oauth2Config.ClientID)
+	assert.Equal(t, workIssuer.URL+"/oauth/token", provider.oauth2Config.Endpoint.TokenURL)
+
+	_, ok = handler.provider("unknown")
+	assert.False(t, ok)
+}
+
+func TestLogin_Provider(t *testing.T) {
+	handler, store, defaultIssuer, workIssuer := setupAuthHandler(t)
+
+	tests := []struct {
+		name             string
+		provider         string
+		expectedCode     int
+		expectedIssuer   string
+		expectedClient   string
+		expectedProvider string
+	}{
+		{"Default provider", "", http.StatusTemporaryRedirect, defaultIssuer.URL, "default-client", defaultProviderName},
+		{"Named provider", "work", http.StatusTemporaryRedirect, workIssuer.URL, "work-client", "work"},
+		{"Unknown provider", "unknown", http.StatusBadRequest, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/login?frontendUrl=" + url.QueryEscape("http://localhost:3000")
+			if tt.provider != "" {
+				target += "&provider=" + tt.provider
+			}
+			c, w := newTestContext(http.MethodGet, target, nil)
+
+			handler.Login(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusTemporaryRedirect {
+				return
+			}
+
+			location, err := url.Parse(w.Header().Get("Location"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIssuer+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+			assert.Equal(t, tt.expectedClient, location.Query().Get("client_id"))
+
+			// The callback exchanges the code with the provider recorded in the state
+			var stateData map[string]interface{}
+			err = store.Get(context.Background(), "oidc:state:"+location.Query().Get("state"), &stateData)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedProvider, stateData["provider"])
+		})
+	}
+}
+
+func TestCallback_Provider(t *testing.T) {
+	handler, store, defaultIssuer, workIssuer := setupAuthHandler(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		provider         interface{}
+		expectedIssuer   *stubIssuer
+		expectedProvider string
+	}{
+		{"Provider in state", "work", workIssuer, "work"},
+		{"State without provider", nil, defaultIssuer, defaultProviderName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateData := map[string]interface{}{"frontendUrl": "http://localhost:3000"}
+			if tt.provider != nil {
+				stateData["provider"] = tt.provider
+			}
+			assert.NoError(t, store.Set(ctx, "oidc:state:test-state", stateData, time.Minute))
+
+			c, w := newTestContext(http.MethodGet, "/callback?code=test-code&state=test-state", nil)
+			handler.Callback(c)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			location, err := url.Parse(w.Header().Get("Location"))
+			assert.NoError(t, err)
+			sessionID := location.Query().Get("access_token")
+			assert.NotEmpty(t, sessionID)
+
+			var session types.SessionData
+			assert.NoError(t, store.Get(ctx, "oidc:session:"+sessionID, &session))
+			assert.Equal(t, tt.expectedProvider, session.Provider)
+			assert.Equal(t, tt.expectedIssuer.name+"-access", session.AccessToken)
+		})
+	}
+
+	// A state naming a provider that's no longer configured is refused
+	assert.NoError(t, store.Set(ctx, "oidc:state:test-state", map[string]interface{}{
+		"frontendUrl": "http://localhost:3000",
+		"provider":    "unknown",
+	}, time.Minute))
+	c, w := newTestContext(http.MethodGet, "/callback?code=test-code&state=test-state", nil)
+	handler.Callback(c)
+
+	assert.Equal(t, "http://localhost:3000/login?error=invalid_state", w.Header().Get("Location"))
+	assert.Len(t, defaultIssuer.tokenRequests(), 1)
+	assert.Len(t, workIssuer.tokenRequests(), 1)
+}
+
+func TestLogout_Provider(t *testing.T) {
+	handler, store, defaultIssuer, workIssuer := setupAuthHandler(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		provider       string
+		expectedIssuer string
+		expectedClient string
+	}{
+		{"Named provider", "work", workIssuer.URL, "work-client"},
+		{"Session without provider", "", defaultIssuer.URL, "default-client"},
+		{"Unknown provider", "unknown", defaultIssuer.URL, "default-client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, store.Set(ctx, "oidc:session:test-session", types.SessionData{
+				AuthType: "oidc",
+				Provider: tt.provider,
+			}, time.Minute))
+
+			c, w := newTestContext(http.MethodGet, "/logout?frontendUrl=http://localhost:3000", nil)
+			c.Request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "test-session"})
+			handler.Logout(c)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			location, err := url.Parse(w.Header().Get("Location"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIssuer+"/v2/logout", location.Scheme+"://"+location.Host+location.Path)
+			assert.Equal(t, tt.expectedClient, location.Query().Get("client_id"))
+
+			var session types.SessionData
+			assert.ErrorIs(t, store.Get(ctx, "oidc:session:test-session", &session), cache.ErrKeyNotFound)
+		})
+	}
+}
+
+func TestRefreshToken_Provider(t *testing.T) {
+	handler, store, defaultIssuer, workIssuer := setupAuthHandler(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		provider       string
+		expectedCode   int
+		expectedIssuer *stubIssuer
+	}{
+		{"Named provider", "work", http.StatusOK, workIssuer},
+		{"Session without provider", "", http.StatusOK, defaultIssuer},
+		{"Unknown provider", "unknown", http.StatusUnauthorized, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, store.Set(ctx, "oidc:session:test-session", types.SessionData{
+				AccessToken:  "expired-access",
+				RefreshToken: "old-refresh",
+				ExpiresAt:    time.Now().Add(-time.Minute),
+				AuthType:     "oidc",
+				Provider:     tt.provider,
+			}, time.Minute))
+
+			c, w := newTestContext(http.MethodPost, "/refresh", nil)
+			c.Request.Header.Set("Authorization", "Bearer test-session")
+			handler.RefreshToken(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedIssuer == nil {
+				return
+			}
+
+			var session types.SessionData
+			assert.NoError(t, store.Get(ctx, "oidc:session:test-session", &session))
+			assert.Equal(t, tt.expectedIssuer.name+"-access", session.AccessToken)
+			assert.Equal(t, tt.expectedIssuer.name+"-refresh", session.RefreshToken)
+		})
+	}
+
+	assert.Equal(t, []string{"refresh_token"}, workIssuer.tokenRequests())
+	assert.Equal(t, []string{"refresh_token"}, defaultIssuer.tokenRequests())
+}
+
+// sessionCookie returns the session cookie set on the response
+func sessionCookie(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			return cookie
+		}
+	}
+	t.Fatal("Expected a session cookie")
+	return nil
+}
+
+func TestCallback_SessionID(t *testing.T) {
+	handler, store, defaultIssuer, _ := setupAuthHandler(t)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Set(ctx, "oidc:state:test-state", map[string]interface{}{
+		"frontendUrl": "http://localhost:3000",
+	}, time.Minute))
+
+	c, w := newTestContext(http.MethodGet, "/callback?code=test-code&state=test-state", nil)
+	handler.Callback(c)
+
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, []string{"authorization_code"}, defaultIssuer.tokenRequests())
+
+	// The cookie carries the session id rather than the access token
+	cookie := sessionCookie(t, w)
+	assert.NotEqual(t, "default-access", cookie.Value)
+
+	// With a refresh token the session outlives the 5 minute access token
+	assert.Equal(t, int(oidcSessionTTL.Seconds()), cookie.MaxAge)
+
+	var session types.SessionData
+	assert.NoError(t, store.Get(ctx, "oidc:session:"+cookie.Value, &session))
+	assert.Equal(t, "default-access", session.AccessToken)
+	assert.Equal(t, "default-refresh", session.RefreshToken)
+	assert.Equal(t, "default-id", session.IDToken)
+
+	var state map[string]interface{}
+	assert.ErrorIs(t, store.Get(ctx, "oidc:state:test-state", &state), cache.ErrKeyNotFound)
+}
+
+func TestOIDCSessionLifetime(t *testing.T) {
+	expiry := time.Now().Add(5 * time.Minute)
+
+	assert.Equal(t, oidcSessionTTL, oidcSessionLifetime(&oauth2.Token{RefreshToken: "refresh", Expiry: expiry}))
+	assert.Equal(t, oidcSessionTTL, oidcSessionLifetime(&oauth2.Token{}))
+	assert.InDelta(t, (5 * time.Minute).Seconds(), oidcSessionLifetime(&oauth2.Token{Expiry: expiry}).Seconds(), 1)
+}
+
+func TestVerifyToken_SessionID(t *testing.T) {
+	tests := []struct {
+		name           string
+		sessionID      string
+		session        *types.SessionData
+		expectedCode   int
+		expectedGrants []string
+		expectedAccess string
+	}{
+		{
+			name:         "Unknown session",
+			sessionID:    "other-session",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:      "Valid access token",
+			sessionID: "test-session",
+			session: &types.SessionData{
+				AccessToken:  "current-access",
+				RefreshToken: "current-refresh",
+				ExpiresAt:    time.Now().Add(time.Hour),
+			},
+			expectedCode:   http.StatusOK,
+			expectedAccess: "current-access",
+		},
+		{
+			name:      "Expired access token is refreshed",
+			sessionID: "test-session",
+			session: &types.SessionData{
+				AccessToken:  "expired-access",
+				RefreshToken: "current-refresh",
+				ExpiresAt:    time.Now().Add(-time.Minute),
+			},
+			expectedCode:   http.StatusOK,
+			expectedGrants: []string{"refresh_token"},
+			expectedAccess: "default-access",
+		},
+		{
+			name:      "Session of an unknown provider",
+			sessionID: "test-session",
+			session: &types.SessionData{
+				AccessToken:  "expired-access",
+				RefreshToken: "current-refresh",
+				ExpiresAt:    time.Now().Add(-time.Minute),
+				Provider:     "unknown",
+			},
+			expectedCode:   http.StatusUnauthorized,
+			expectedAccess: "expired-access",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, store, defaultIssuer, _ := setupAuthHandler(t)
+			ctx := context.Background()
+
+			if tt.session != nil {
+				tt.session.AuthType = "oidc"
+				assert.NoError(t, store.Set(ctx, "oidc:session:test-session", *tt.session, time.Hour))
+			}
+
+			c, w := newTestContext(http.MethodGet, "/verify", nil)
+			c.Request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: tt.sessionID})
+			handler.VerifyToken(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedGrants, defaultIssuer.tokenRequests())
+
+			if tt.session != nil {
+				var session types.SessionData
+				assert.NoError(t, store.Get(ctx, "oidc:session:test-session", &session))
+				assert.Equal(t, tt.expectedAccess, session.AccessToken)
+			}
+		})
+	}
+}
+
+func TestRefreshToken_SessionID(t *testing.T) {
+	handler, store, defaultIssuer, _ := setupAuthHandler(t)
+	ctx := context.Background()
+
+	c, w := newTestContext(http.MethodPost, "/refresh", nil)
+	c.Request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "test-session"})
+	handler.RefreshToken(c)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	assert.NoError(t, store.Set(ctx, "oidc:session:test-session", types.SessionData{
+		AccessToken:  "current-access",
+		RefreshToken: "current-refresh",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		AuthType:     "oidc",
+	}, time.Hour))
+
+	c, w = newTestContext(http.MethodPost, "/refresh", nil)
+	c.Request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "test-session"})
+	handler.RefreshToken(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"refresh_token"}, defaultIssuer.tokenRequests())
+
+	// The session id stays the same across refreshes, only the tokens behind it change
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "test-session", response["access_token"])
+
+	cookie := sessionCookie(t, w)
+	assert.Equal(t, "test-session", cookie.Value)
+	assert.Equal(t, int(oidcSessionTTL.Seconds()), cookie.MaxAge)
+
+	var session types.SessionData
+	assert.NoError(t, store.Get(ctx, "oidc:session:test-session", &session))
+	assert.Equal(t, "default-access", session.AccessToken)
+	assert.Equal(t, "default-refresh", session.RefreshToken)
+	assert.True(t, session.ExpiresAt.After(time.Now()))
+}
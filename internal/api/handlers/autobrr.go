@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,6 +29,7 @@ const (
 	autobrrReleasesCacheDuration = 30 * time.Second
 	statsPrefix                  = "autobrr:stats:"
 	ircPrefix                    = "autobrr:irc:"
+	ircDetailPrefix              = "autobrr:ircdetail:"
 	releasesPrefix               = "autobrr:releases:"
 )
 
@@ -38,10 +38,9 @@ type AutobrrHandler struct {
 	store cache.Store
 	sf    *singleflight.Group
 
-	lastReleasesHash  map[string]string
-	lastStatsHash     map[string]string
-	lastIRCStatusHash map[string]string
-	hashMu            sync.Mutex
+	lastReleasesHash  *hashTracker
+	lastStatsHash     *hashTracker
+	lastIRCStatusHash *hashTracker
 }
 
 func NewAutobrrHandler(db *database.DB, store cache.Store) *AutobrrHandler {
@@ -50,10 +49,10 @@ func NewAutobrrHandler(db *database.DB, store cache.Store) *AutobrrHandler {
 		store: store,
 		sf:    &singleflight.Group{},
 
-		// Initialize the new maps
-		lastReleasesHash:  make(map[string]string),
-		lastStatsHash:     make(map[string]string),
-		lastIRCStatusHash: make(map[string]string),
+		// Initialize the hash trackers
+		lastReleasesHash:  newHashTracker(db),
+		lastStatsHash:     newHashTracker(db),
+		lastIRCStatusHash: newHashTracker(db),
 	}
 }
 
@@ -117,9 +116,8 @@ func (h *AutobrrHandler) GetAutobrrReleases(c *gin.Context) {
 
 	releases = result.(types.ReleasesResponse)
 
-	h.hashMu.Lock()
 	currentHash := createAutobrrReleaseHash(releases)
-	lastHash := h.lastReleasesHash[instanceId]
+	lastHash := h.lastReleasesHash.Swap(instanceId, currentHash)
 
 	// Only log when there are releases and the hash has changed
 	if (lastHash == "" || currentHash != lastHash) && len(releases.Data) > 0 {
@@ -132,9 +130,7 @@ func (h *AutobrrHandler) GetAutobrrReleases(c *gin.Context) {
 		log.Debug().
 			Str("instanceId", instanceId).
 			Msg("Autobrr releases changed")
-		h.lastReleasesHash[instanceId] = currentHash
 	}
-	h.hashMu.Unlock()
 
 	// Broadcast releases update via SSE
 	h.broadcastReleases(instanceId, releases)
@@ -204,9 +200,8 @@ func (h *AutobrrHandler) GetAutobrrReleaseStats(c *gin.Context) {
 
 	stats = result.(types.AutobrrStats)
 
-	h.hashMu.Lock()
 	currentHash := createAutobrrStatsHash(stats)
-	lastHash := h.lastStatsHash[instanceId]
+	lastHash := h.lastStatsHash.Swap(instanceId, currentHash)
 
 	// Only log when there are stats and the hash has changed
 	if (lastHash == "" || currentHash != lastHash) && stats.TotalCount > 0 {
@@ -219,9 +214,7 @@ func (h *AutobrrHandler) GetAutobrrReleaseStats(c *gin.Context) {
 		log.Debug().
 			Str("instanceId", instanceId).
 			Msg("[Autobrr] Stats updated")
-		h.lastStatsHash[instanceId] = currentHash
 	}
-	h.hashMu.Unlock()
 
 	// Broadcast stats update via SSE
 	h.broadcastStats(instanceId, stats)
@@ -288,9 +281,8 @@ func (h *AutobrrHandler) GetAutobrrIRCStatus(c *gin.Context) {
 	// Broadcast IRC status update via SSE
 	h.broadcastIRCStatus(instanceId, status)
 
-	h.hashMu.Lock()
 	currentHash := createIRCStatusHash(status)
-	lastHash := h.lastIRCStatusHash[instanceId]
+	lastHash := h.lastIRCStatusHash.Swap(instanceId, currentHash)
 
 	// Only log when there are status entries and the hash has changed
 	if (lastHash == "" || currentHash != lastHash) && len(status) > 0 {
@@ -303,18 +295,60 @@ func (h *AutobrrHandler) GetAutobrrIRCStatus(c *gin.Context) {
 		log.Debug().
 			Str("instanceId", instanceId).
 			Msg("Autobrr IRC status changed")
-		h.lastIRCStatusHash[instanceId] = currentHash
 	}
-	h.hashMu.Unlock()
 
 	c.JSON(http.StatusOK, status)
 }
 
+// GetAutobrrIRCDetail returns every IRC network of an instance with its health and enabled
+// state. GetAutobrrIRCStatus only reports the unhealthy networks the health check cares about.
+func (h *AutobrrHandler) GetAutobrrIRCDetail(c *gin.Context) {
+	instanceId := c.Query("instanceId")
+	if instanceId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Instance ID is required"})
+		return
+	}
+
+	if !isServiceType(instanceId, "autobrr") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Autobrr instance ID"})
+		return
+	}
+
+	cacheKey := ircDetailPrefix + instanceId
+
+	var detail types.IRCDetailResponse
+	if err := h.store.Get(c.Request.Context(), cacheKey, &detail); err == nil {
+		c.JSON(http.StatusOK, detail)
+		return
+	}
+
+	// Detached from the request, the fetch below is shared with concurrent requests
+	ctx := context.Background()
+	result, err, _ := h.sf.Do("irc_detail:"+instanceId, func() (interface{}, error) {
+		return h.fetchAndCacheIRCDetail(ctx, instanceId, cacheKey)
+	})
+	if err != nil {
+		if err.Error() == "service not configured" {
+			c.JSON(http.StatusOK, types.NewIRCDetailResponse([]types.IRCStatus{}))
+			return
+		}
+
+		log.Error().Err(err).Str("instanceId", instanceId).Msg("Failed to fetch Autobrr IRC networks")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail = result.(types.IRCDetailResponse)
+	h.broadcastIRCDetail(instanceId, detail)
+
+	c.JSON(http.StatusOK, detail)
+}
+
 // broadcastReleases broadcasts release updates to all connected SSE clients
 func (h *AutobrrHandler) broadcastReleases(instanceId string, releases types.ReleasesResponse) {
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "online",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
 		Message:     "autobrr_releases",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
@@ -326,8 +360,8 @@ func (h *AutobrrHandler) broadcastReleases(instanceId string, releases types.Rel
 // broadcastStats broadcasts stats updates to all connected SSE clients
 func (h *AutobrrHandler) broadcastStats(instanceId string, stats types.AutobrrStats) {
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "online",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
 		Message:     "autobrr_stats",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
@@ -339,19 +373,19 @@ func (h *AutobrrHandler) broadcastStats(instanceId string, stats types.AutobrrSt
 // broadcastIRCStatus broadcasts IRC status updates to all connected SSE clients
 func (h *AutobrrHandler) broadcastIRCStatus(instanceId string, status []types.IRCStatus) {
 	// Check for unhealthy IRC connections
-	serviceStatus := "online"
+	serviceStatus := models.StatusOnline
 	message := "autobrr_irc_status"
 
 	for _, s := range status {
 		if !s.Healthy && s.Enabled {
-			serviceStatus = "warning"
+			serviceStatus = models.StatusWarning
 			message = fmt.Sprintf("IRC network %s is unhealthy", s.Name)
 			break
 		}
 	}
 
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
+		InstanceID:  instanceId,
 		Status:      serviceStatus,
 		Message:     message,
 		LastChecked: time.Now(),
@@ -363,6 +397,26 @@ func (h *AutobrrHandler) broadcastIRCStatus(instanceId string, status []types.IR
 	})
 }
 
+// broadcastIRCDetail broadcasts the full IRC network list to all connected SSE clients
+func (h *AutobrrHandler) broadcastIRCDetail(instanceId string, detail types.IRCDetailResponse) {
+	serviceStatus := models.StatusOnline
+	if detail.Healthy < detail.Enabled {
+		serviceStatus = models.StatusWarning
+	}
+
+	BroadcastHealth(models.ServiceHealth{
+		InstanceID:  instanceId,
+		Status:      serviceStatus,
+		Message:     "autobrr_irc_detail",
+		LastChecked: time.Now(),
+		Details: map[string]interface{}{
+			"autobrr": map[string]interface{}{
+				"ircDetail": detail,
+			},
+		},
+	})
+}
+
 func (h *AutobrrHandler) fetchAndCacheStats(ctx context.Context, instanceId, cacheKey string) (types.AutobrrStats, error) {
 	autobrrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
@@ -453,6 +507,36 @@ func (h *AutobrrHandler) fetchAndCacheIRC(ctx context.Context, instanceId, cache
 	return status, nil
 }
 
+func (h *AutobrrHandler) fetchAndCacheIRCDetail(ctx context.Context, instanceId, cacheKey string) (types.IRCDetailResponse, error) {
+	autobrrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
+	if err != nil {
+		return types.IRCDetailResponse{}, err
+	}
+
+	if autobrrConfig == nil || autobrrConfig.URL == "" {
+		return types.IRCDetailResponse{}, fmt.Errorf("service not configured")
+	}
+
+	service := &autobrr.AutobrrService{
+		ServiceCore: core.ServiceCore{},
+	}
+
+	networks, err := service.GetIRCNetworks(ctx, autobrrConfig.URL, autobrrConfig.APIKey)
+	if err != nil {
+		return types.IRCDetailResponse{}, err
+	}
+
+	detail := types.NewIRCDetailResponse(networks)
+	if err := h.store.Set(ctx, cacheKey, detail, autobrrIRCCacheDuration); err != nil {
+		log.Warn().
+			Err(err).
+			Str("instanceId", instanceId).
+			Msg("[Autobrr] Failed to cache IRC networks")
+	}
+
+	return detail, nil
+}
+
 func (h *AutobrrHandler) refreshStatsCache(instanceId, cacheKey string) {
 	sfKey := fmt.Sprintf("stats_refresh:%s", instanceId)
 	result, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
@@ -471,9 +555,8 @@ func (h *AutobrrHandler) refreshStatsCache(instanceId, cacheKey string) {
 	if err == nil {
 		stats := result.(types.AutobrrStats)
 
-		h.hashMu.Lock()
 		currentHash := createAutobrrStatsHash(stats)
-		lastHash := h.lastStatsHash[instanceId]
+		lastHash := h.lastStatsHash.Swap(instanceId, currentHash)
 
 		// Only log when there are stats and the hash has changed
 		if (lastHash == "" || currentHash != lastHash) && stats.TotalCount > 0 {
@@ -486,9 +569,7 @@ func (h *AutobrrHandler) refreshStatsCache(instanceId, cacheKey string) {
 			log.Debug().
 				Str("instanceId", instanceId).
 				Msg("[Autobrr] Stats updated")
-			h.lastStatsHash[instanceId] = currentHash
 		}
-		h.hashMu.Unlock()
 
 		// Broadcast stats update via SSE
 		h.broadcastStats(instanceId, stats)
@@ -513,9 +594,8 @@ func (h *AutobrrHandler) refreshIRCCache(instanceId, cacheKey string) {
 	if err == nil {
 		status := result.([]types.IRCStatus)
 
-		h.hashMu.Lock()
 		currentHash := createIRCStatusHash(status)
-		lastHash := h.lastIRCStatusHash[instanceId]
+		lastHash := h.lastIRCStatusHash.Swap(instanceId, currentHash)
 
 		if (lastHash == "" || currentHash != lastHash) && len(status) > 0 {
 			log.Debug().
@@ -527,9 +607,7 @@ func (h *AutobrrHandler) refreshIRCCache(instanceId, cacheKey string) {
 			log.Debug().
 				Str("instanceId", instanceId).
 				Msg("Autobrr IRC status changed")
-			h.lastIRCStatusHash[instanceId] = currentHash
 		}
-		h.hashMu.Unlock()
 
 		// Broadcast IRC status update via SSE
 		h.broadcastIRCStatus(instanceId, status)
@@ -554,9 +632,8 @@ func (h *AutobrrHandler) refreshReleasesCache(instanceId, cacheKey string) {
 	if err == nil {
 		releases := result.(types.ReleasesResponse)
 
-		h.hashMu.Lock()
 		currentHash := createAutobrrReleaseHash(releases)
-		lastHash := h.lastReleasesHash[instanceId]
+		lastHash := h.lastReleasesHash.Swap(instanceId, currentHash)
 
 		if (lastHash == "" || currentHash != lastHash) && len(releases.Data) > 0 {
 			log.Debug().
@@ -568,9 +645,7 @@ func (h *AutobrrHandler) refreshReleasesCache(instanceId, cacheKey string) {
 			log.Debug().
 				Str("instanceId", instanceId).
 				Msg("Autobrr releases changed")
-			h.lastReleasesHash[instanceId] = currentHash
 		}
-		h.hashMu.Unlock()
 
 		// Broadcast releases update via SSE
 		h.broadcastReleases(instanceId, releases)
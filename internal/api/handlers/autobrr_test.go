@@ -0,0 +1,66 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestAutobrrHandler_GetAutobrrIRCDetail(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/irc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"name":"network-1","healthy":true,"enabled":true},
+			{"name":"network-2","healthy":true,"enabled":true},
+			{"name":"network-3","healthy":false,"enabled":true},
+			{"name":"network-4","healthy":false,"enabled":false}
+		]`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	_, db := setupSettingsHandler(t)
+	ctx := context.Background()
+	if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: "autobrr-1", URL: upstream.URL, APIKey: "key"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	store := setupTestCache(t)
+
+	handler := NewAutobrrHandler(db, store)
+
+	get := func(instanceID string) (int, types.IRCDetailResponse) {
+		c, w := newTestContext(http.MethodGet, "/api/autobrr/irc/detail?instanceId="+instanceID, nil)
+
+		handler.GetAutobrrIRCDetail(c)
+
+		var detail types.IRCDetailResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &detail)
+		return w.Code, detail
+	}
+
+	code, detail := get("autobrr-1")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+	}
+	if len(detail.Networks) != 4 || detail.Total != 4 {
+		t.Errorf("Expected all 4 networks, got %+v", detail)
+	}
+	if detail.Enabled != 3 || detail.Healthy != 2 {
+		t.Errorf("Expected 2 of 3 enabled networks to be healthy, got %d of %d", detail.Healthy, detail.Enabled)
+	}
+
+	if code, _ := get("sonarr-1"); code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a non-autobrr instance, got %d", http.StatusBadRequest, code)
+	}
+}
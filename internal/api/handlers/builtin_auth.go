@@ -4,7 +4,6 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -71,7 +70,7 @@ func (h *BuiltinAuthHandler) Register(c *gin.Context) {
 	}
 
 	// Check if username exists
-	existingUser, err := h.db.FindUser(context.Background(), types.FindUserParams{Username: req.Username})
+	existingUser, err := h.db.FindUser(c.Request.Context(), types.FindUserParams{Username: req.Username})
 	if err != nil {
 		log.Error().Err(err).Msg("failed to check username")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
@@ -83,7 +82,7 @@ func (h *BuiltinAuthHandler) Register(c *gin.Context) {
 	}
 
 	// Check if email exists
-	existingUser, err = h.db.FindUser(context.Background(), types.FindUserParams{Email: req.Email})
+	existingUser, err = h.db.FindUser(c.Request.Context(), types.FindUserParams{Email: req.Email})
 	if err != nil {
 		log.Error().Err(err).Msg("failed to check email")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
@@ -134,7 +133,7 @@ func (h *BuiltinAuthHandler) Login(c *gin.Context) {
 	}
 
 	// Get user by username
-	user, err := h.db.FindUser(context.Background(), types.FindUserParams{Username: req.Username})
+	user, err := h.db.FindUser(c.Request.Context(), types.FindUserParams{Username: req.Username})
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get user")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
@@ -177,18 +176,8 @@ func (h *BuiltinAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	var isSecure = c.GetHeader("X-Forwarded-Proto") == "https"
-
 	// Set session cookie
-	c.SetCookie(
-		"session",
-		sessionToken,
-		int(time.Until(expiresAt).Seconds()),
-		"/",
-		"",
-		isSecure, // Secure
-		true,     // HttpOnly
-	)
+	setSessionCookie(c, sessionToken, int(time.Until(expiresAt).Seconds()))
 
 	c.JSON(http.StatusOK, gin.H{
 		"access_token": sessionToken,
@@ -252,18 +241,8 @@ func (h *BuiltinAuthHandler) Logout(c *gin.Context) {
 		log.Error().Err(err).Msg("failed to delete session from cache")
 	}
 
-	var isSecure = c.GetHeader("X-Forwarded-Proto") == "https"
-
 	// Clear session cookie
-	c.SetCookie(
-		"session",
-		"",
-		-1,
-		"/",
-		"",
-		isSecure,
-		true,
-	)
+	setSessionCookie(c, "", -1)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
@@ -291,7 +270,7 @@ func (h *BuiltinAuthHandler) GetUserInfo(c *gin.Context) {
 	}
 
 	// Get user from database
-	user, err := h.db.FindUser(context.Background(), types.FindUserParams{ID: sessionData.UserID})
+	user, err := h.db.FindUser(c.Request.Context(), types.FindUserParams{ID: sessionData.UserID})
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get user")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
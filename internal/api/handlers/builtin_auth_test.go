@@ -0,0 +1,65 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/api/middleware"
+)
+
+func TestBuiltinAuthHandler_LoginRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, store := setupTestDB(t)
+
+	const limit = 3
+	window := 2 * time.Second
+
+	r := gin.New()
+	r.POST("/api/auth/login", middleware.NewRateLimiter(store, window, limit, "login:").RateLimit(), NewBuiltinAuthHandler(db, store).Login)
+
+	login := func(remoteAddr string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"username":"admin","password":"guess"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Failed logins in quick succession all count towards the limit
+	for i := 0; i < limit; i++ {
+		if w := login("192.0.2.1:40000"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status code %d for attempt %d, got %d", http.StatusUnauthorized, i+1, w.Code)
+		}
+	}
+
+	w := login("192.0.2.1:40000")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status code %d once the limit is reached, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retryAfter < 1 {
+		t.Errorf("Expected a Retry-After of at least a second, got %q", w.Header().Get("Retry-After"))
+	}
+
+	// Another client isn't blocked
+	if w := login("192.0.2.2:40000"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d for another client, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	// Nor is the same client once the window has passed
+	time.Sleep(window + 100*time.Millisecond)
+	if w := login("192.0.2.1:40000"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d in a later window, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
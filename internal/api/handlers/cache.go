@@ -0,0 +1,134 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+)
+
+// instanceCachePrefixes lists the cache key prefixes that are followed by an instance id.
+// Keys under these prefixes are considered orphaned once the instance is removed.
+var instanceCachePrefixes = []string{
+	statsPrefix,
+	ircPrefix,
+	ircDetailPrefix,
+	releasesPrefix,
+	omegabrrStatusPrefix,
+	plexCachePrefix,
+	overseerrCachePrefix,
+	devicesCachePrefix,
+	tailnetCachePrefix,
+	prowlarrStatsPrefix,
+	prowlarrIndexerPrefix,
+	prowlarrIndexerStatsPrefix,
+	prowlarrIndexerStatusPrefix,
+	cachePrefix,
+	radarrQueuePrefix,
+	sonarrQueuePrefix,
+	sonarrStatsPrefix,
+	queueStalledPrefix,
+	iconCachePrefix,
+	arrHistoryPrefix,
+}
+
+type CacheHandler struct {
+	db    *database.DB
+	cache cache.Store
+}
+
+func NewCacheHandler(db *database.DB, cache cache.Store) *CacheHandler {
+	return &CacheHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// ListKeys returns all cache keys, optionally filtered by the prefix query parameter
+func (h *CacheHandler) ListKeys(c *gin.Context) {
+	prefix := c.Query("prefix")
+
+	keys, err := h.cache.Keys(c.Request.Context(), prefix)
+	if err != nil {
+		log.Error().Err(err).Str("prefix", prefix).Msg("Failed to list cache keys")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list cache keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+// PruneKeys removes cache keys belonging to instances that no longer exist
+func (h *CacheHandler) PruneKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	services, err := h.db.GetAllServices(ctx, true)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch services for cache prune")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	instances := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		instances[service.InstanceID] = struct{}{}
+	}
+
+	var removed []string
+	for _, prefix := range instanceCachePrefixes {
+		keys, err := h.cache.Keys(ctx, prefix)
+		if err != nil {
+			log.Error().Err(err).Str("prefix", prefix).Msg("Failed to list cache keys")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list cache keys"})
+			return
+		}
+
+		for _, key := range keys {
+			instanceID := cacheKeyInstanceID(key, prefix)
+			if instanceID == "" {
+				continue
+			}
+			if _, ok := instances[instanceID]; ok {
+				continue
+			}
+
+			if err := h.cache.Delete(ctx, key); err != nil {
+				log.Warn().Err(err).Str("key", key).Msg("Failed to delete orphaned cache key")
+				continue
+			}
+			removed = append(removed, key)
+		}
+	}
+
+	log.Info().Int("count", len(removed)).Msg("Pruned orphaned cache keys")
+
+	c.JSON(http.StatusOK, gin.H{
+		"removed": removed,
+		"count":   len(removed),
+	})
+}
+
+// cacheKeyInstanceID extracts the instance id from a key such as "sonarr:queue:sonarr-1:stale".
+// Keys that don't reference an instance (e.g. "tailscale:devices:direct:...") return an empty string.
+func cacheKeyInstanceID(key, prefix string) string {
+	rest := strings.TrimPrefix(key, prefix)
+	if rest == key || rest == "" {
+		return ""
+	}
+
+	instanceID, _, _ := strings.Cut(rest, ":")
+	if instanceID == "direct" {
+		return ""
+	}
+	return instanceID
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+// cacheKeysResponse is the body of ListKeys and PruneKeys
+type cacheKeysResponse struct {
+	Keys    []string `json:"keys"`
+	Removed []string `json:"removed"`
+	Count   int      `json:"count"`
+}
+
+func TestCacheHandler_PruneKeys(t *testing.T) {
+	db, store := setupTestDB(t)
+	handler := NewCacheHandler(db, store)
+	ctx := context.Background()
+
+	for _, service := range []*models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "autobrr-1", DisplayName: "autobrr", URL: "http://autobrr:7474"},
+	} {
+		if err := db.CreateService(ctx, service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	// sonarr-2 and autobrr-2 have been deleted, their keys are left behind
+	live := []string{
+		sonarrQueuePrefix + "sonarr-1",
+		sonarrStatsPrefix + "sonarr-1",
+		queueStalledPrefix + "sonarr-1:42",
+		iconCachePrefix + "sonarr-1",
+		arrHistoryPrefix + "sonarr-1",
+		ircPrefix + "autobrr-1",
+		ircDetailPrefix + "autobrr-1",
+	}
+	orphaned := []string{
+		sonarrQueuePrefix + "sonarr-2",
+		sonarrStatsPrefix + "sonarr-2",
+		queueStalledPrefix + "sonarr-2:42",
+		iconCachePrefix + "sonarr-2",
+		arrHistoryPrefix + "sonarr-2",
+		ircPrefix + "autobrr-2",
+		ircDetailPrefix + "autobrr-2",
+	}
+	// Keys that don't belong to an instance are never pruned
+	unrelated := []string{
+		devicesCachePrefix + "direct:tskey-ab",
+		validationCachePrefix + "sonarr:v3:GET:http://sonarr:8989",
+		"oidc:session:test-session",
+	}
+	for _, key := range slices.Concat(live, orphaned, unrelated) {
+		if err := store.Set(ctx, key, "value", time.Hour); err != nil {
+			t.Fatalf("Failed to seed %s: %v", key, err)
+		}
+	}
+
+	listKeys := func(prefix string) []string {
+		c, w := newTestContext(http.MethodGet, "/api/cache/keys?prefix="+prefix, nil)
+		handler.ListKeys(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+
+		var resp cacheKeysResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Count != len(resp.Keys) {
+			t.Errorf("Expected count %d to match the keys, got %d", len(resp.Keys), resp.Count)
+		}
+		slices.Sort(resp.Keys)
+		return resp.Keys
+	}
+
+	if keys := listKeys(sonarrQueuePrefix); !slices.Equal(keys, []string{sonarrQueuePrefix + "sonarr-1", sonarrQueuePrefix + "sonarr-2"}) {
+		t.Errorf("Unexpected keys for prefix %s: %v", sonarrQueuePrefix, keys)
+	}
+
+	c, w := newTestContext(http.MethodPost, "/api/cache/prune", nil)
+	handler.PruneKeys(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp cacheKeysResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	slices.Sort(resp.Removed)
+	want := slices.Sorted(slices.Values(orphaned))
+	if !slices.Equal(resp.Removed, want) || resp.Count != len(want) {
+		t.Errorf("Expected the orphaned keys %v to be pruned, got %v (count %d)", want, resp.Removed, resp.Count)
+	}
+
+	remaining := listKeys("")
+	for _, key := range slices.Concat(live, unrelated) {
+		if !slices.Contains(remaining, key) {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+	for _, key := range orphaned {
+		if slices.Contains(remaining, key) {
+			t.Errorf("Expected %s to be pruned", key)
+		}
+	}
+}
+
+// sharedCachePrefixes are the cache key prefixes that aren't followed by an instance id
+var sharedCachePrefixes = []string{
+	changelogCachePrefix,
+	validationCachePrefix,
+}
+
+// TestInstanceCachePrefixes fails when a cache key prefix declared in this package is
+// missing from instanceCachePrefixes, which would leave its keys behind once an instance
+// is deleted. Prefixes of keys that don't belong to an instance go in sharedCachePrefixes.
+func TestInstanceCachePrefixes(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list source files: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	var declared int
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			decl, ok := node.(*ast.GenDecl)
+			if !ok || decl.Tok != token.CONST {
+				return true
+			}
+			for _, spec := range decl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, name := range valueSpec.Names {
+					if !strings.HasSuffix(name.Name, "Prefix") || i >= len(valueSpec.Values) {
+						continue
+					}
+					lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					prefix, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatalf("Failed to read %s: %v", name.Name, err)
+					}
+
+					declared++
+					if !slices.Contains(instanceCachePrefixes, prefix) && !slices.Contains(sharedCachePrefixes, prefix) {
+						t.Errorf("%s (%q in %s) is missing from instanceCachePrefixes, keys of deleted instances under it are never pruned",
+							name.Name, prefix, file)
+					}
+				}
+			}
+			return false
+		})
+	}
+
+	if declared < len(instanceCachePrefixes) {
+		t.Errorf("Expected at least %d cache key prefixes to be declared, found %d", len(instanceCachePrefixes), declared)
+	}
+}
+
+func TestCacheHandler_PruneKeysEveryPrefix(t *testing.T) {
+	db, store := setupTestDB(t)
+	handler := NewCacheHandler(db, store)
+	ctx := context.Background()
+
+	// No instance exists, so a key under every per-instance prefix is orphaned
+	for _, prefix := range instanceCachePrefixes {
+		if err := store.Set(ctx, prefix+"sonarr-2", "value", time.Hour); err != nil {
+			t.Fatalf("Failed to seed %s: %v", prefix, err)
+		}
+	}
+
+	c, w := newTestContext(http.MethodPost, "/api/cache/prune", nil)
+	handler.PruneKeys(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	for _, prefix := range instanceCachePrefixes {
+		var value string
+		if err := store.Get(ctx, prefix+"sonarr-2", &value); err == nil {
+			t.Errorf("Expected %s to be pruned", prefix+"sonarr-2")
+		}
+	}
+}
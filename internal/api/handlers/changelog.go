@@ -0,0 +1,158 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/changelog"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const (
+	changelogCachePrefix  = "changelog:"
+	changelogCacheTTL     = 6 * time.Hour
+	changelogFetchTimeout = 15 * time.Second
+
+	// changelogRateLimitKey is set while the GitHub rate limit is exhausted, so requests
+	// fail fast instead of calling GitHub again before the limit resets
+	changelogRateLimitKey = "changelog:ratelimit"
+)
+
+type ChangelogHandler struct {
+	db    *database.DB
+	cache cache.Store
+	sf    singleflight.Group
+}
+
+func NewChangelogHandler(db *database.DB, cache cache.Store) *ChangelogHandler {
+	return &ChangelogHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// GetChangelog returns the release notes between the installed and the latest version of
+// a service. The installed version is taken from the last health check.
+func (h *ChangelogHandler) GetChangelog(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	service, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Failed to fetch service configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service configuration"})
+		return
+	}
+	if service == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+
+	serviceType, _, _ := strings.Cut(instanceID, "-")
+	repo, err := changelog.Repo(serviceType)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No release notes are available for " + serviceType})
+		return
+	}
+
+	releases, err := h.releases(c.Request.Context(), repo)
+	if err != nil {
+		var rateLimited *changelog.ErrRateLimited
+		if errors.As(err, &rateLimited) {
+			if !rateLimited.Reset.IsZero() {
+				c.Header("Retry-After", strconv.Itoa(int(time.Until(rateLimited.Reset).Seconds())+1))
+			}
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": rateLimited.Error()})
+			return
+		}
+		log.Error().Err(err).Str("instance", instanceID).Str("repo", repo).Msg("Failed to fetch release notes")
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch release notes"})
+		return
+	}
+
+	response := types.ChangelogResponse{
+		InstanceID: instanceID,
+		Repo:       repo,
+		Installed:  h.installedVersion(c.Request.Context(), instanceID),
+		Releases:   []types.ReleaseNote{},
+	}
+	if len(releases) > 0 {
+		response.Latest = releases[0].Version
+
+		if response.Installed == "" {
+			response.Releases = releases[:1]
+		} else {
+			newer, found := changelog.Since(releases, response.Installed)
+			response.Releases = newer
+			response.Partial = !found
+			response.UpdateAvailable = len(newer) > 0
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// installedVersion returns the version reported by the last health check of the service
+func (h *ChangelogHandler) installedVersion(ctx context.Context, instanceID string) string {
+	var health models.ServiceHealth
+	if err := h.cache.Get(ctx, cache.PrefixHealth+instanceID, &health); err != nil {
+		return ""
+	}
+	return health.Version
+}
+
+// releases returns the cached releases of a repository, fetching them from GitHub when
+// the cache has expired
+func (h *ChangelogHandler) releases(ctx context.Context, repo string) ([]types.ReleaseNote, error) {
+	cacheKey := changelogCachePrefix + repo
+
+	var releases []types.ReleaseNote
+	if err := h.cache.Get(ctx, cacheKey, &releases); err == nil {
+		return releases, nil
+	}
+
+	var reset time.Time
+	if err := h.cache.Get(ctx, changelogRateLimitKey, &reset); err == nil {
+		return nil, &changelog.ErrRateLimited{Reset: reset}
+	}
+
+	result, err, _ := h.sf.Do(cacheKey, func() (interface{}, error) {
+		// Detached from the request, the fetch below is shared with concurrent requests
+		fetchCtx, cancel := context.WithTimeout(context.Background(), changelogFetchTimeout)
+		defer cancel()
+
+		releases, err := changelog.FetchReleases(fetchCtx, repo)
+		if err != nil {
+			var rateLimited *changelog.ErrRateLimited
+			if errors.As(err, &rateLimited) && time.Until(rateLimited.Reset) > 0 {
+				if err := h.cache.Set(fetchCtx, changelogRateLimitKey, rateLimited.Reset, time.Until(rateLimited.Reset)); err != nil {
+					log.Warn().Err(err).Msg("Failed to cache the GitHub rate limit")
+				}
+			}
+			return nil, err
+		}
+
+		if err := h.cache.Set(fetchCtx, cacheKey, releases, changelogCacheTTL); err != nil {
+			log.Warn().Err(err).Str("repo", repo).Msg("Failed to cache release notes")
+		}
+		return releases, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]types.ReleaseNote), nil
+}
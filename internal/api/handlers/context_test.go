@@ -0,0 +1,92 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHandlersUseRequestContext makes sure database calls in request handlers run on the
+// request context, so queries are cancelled when the client goes away. Work shared with
+// other requests through singleflight or the cache should use a detached ctx variable
+// instead, which keeps that decision explicit.
+func TestHandlersUseRequestContext(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list handler files: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || !takesGinContext(fn) {
+				continue
+			}
+
+			ast.Inspect(fn.Body, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || !isDBCall(call) || len(call.Args) == 0 || !isDetachedContext(call.Args[0]) {
+					return true
+				}
+				t.Errorf("%s: %s calls the database with a detached context, use c.Request.Context()", fset.Position(call.Pos()), fn.Name.Name)
+				return true
+			})
+		}
+	}
+}
+
+// takesGinContext reports whether the function has a *gin.Context parameter
+func takesGinContext(fn *ast.FuncDecl) bool {
+	for _, param := range fn.Type.Params.List {
+		star, ok := param.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := star.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Context" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "gin" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDBCall matches calls like h.db.FindServiceBy(...)
+func isDBCall(call *ast.CallExpr) bool {
+	method, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	field, ok := method.X.(*ast.SelectorExpr)
+	return ok && field.Sel.Name == "db"
+}
+
+// isDetachedContext matches context.Background() and context.TODO()
+func isDetachedContext(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && (sel.Sel.Name == "Background" || sel.Sel.Name == "TODO")
+}
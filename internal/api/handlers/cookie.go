@@ -0,0 +1,60 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const sessionCookieName = "session"
+
+// sessionCookieOptions holds the attributes applied to every session cookie
+var sessionCookieOptions = struct {
+	domain   string
+	sameSite http.SameSite
+}{
+	sameSite: http.SameSiteLaxMode,
+}
+
+// ConfigureSessionCookie sets the domain and SameSite mode ("lax", "strict" or "none") used for session cookies
+func ConfigureSessionCookie(domain, sameSite string) {
+	sessionCookieOptions.domain = domain
+
+	switch strings.ToLower(sameSite) {
+	case "", "lax":
+		sessionCookieOptions.sameSite = http.SameSiteLaxMode
+	case "strict":
+		sessionCookieOptions.sameSite = http.SameSiteStrictMode
+	case "none":
+		sessionCookieOptions.sameSite = http.SameSiteNoneMode
+	default:
+		log.Warn().Str("sameSite", sameSite).Msg("Unknown cookie SameSite mode, using Lax")
+		sessionCookieOptions.sameSite = http.SameSiteLaxMode
+	}
+}
+
+// setSessionCookie writes the session cookie, pass a negative maxAge to clear it
+func setSessionCookie(c *gin.Context, value string, maxAge int) {
+	isSecure := c.GetHeader("X-Forwarded-Proto") == "https"
+
+	// Browsers reject SameSite=None cookies that aren't Secure
+	if sessionCookieOptions.sameSite == http.SameSiteNoneMode {
+		isSecure = true
+	}
+
+	c.SetSameSite(sessionCookieOptions.sameSite)
+	c.SetCookie(
+		sessionCookieName,
+		value,
+		maxAge,
+		"/",
+		sessionCookieOptions.domain,
+		isSecure, // Secure
+		true,     // HttpOnly
+	)
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/maintainerr"
+	"github.com/autobrr/dashbrr/internal/services/tailscale"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const (
+	badgesCacheKey = "summary:badges"
+	badgesCacheTTL = 10 * time.Second
+)
+
+type DashboardHandler struct {
+	db    *database.DB
+	cache cache.Store
+}
+
+func NewDashboardHandler(db *database.DB, cache cache.Store) *DashboardHandler {
+	return &DashboardHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// GetSummary returns the cached health and headline stat of every configured service
+// in one payload. Nothing is fetched from the services themselves, entries without
+// cached data are returned without health or stat.
+func (h *DashboardHandler) GetSummary(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	services, err := h.db.GetAllServices(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	summary := types.DashboardSummary{
+		Services:    make([]types.DashboardEntry, len(services)),
+		GeneratedAt: time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service models.ServiceConfiguration) {
+			defer wg.Done()
+			summary.Services[i] = h.entry(ctx, service)
+		}(i, service)
+	}
+	wg.Wait()
+
+	health := make(map[string]*models.ServiceHealth, len(summary.Services))
+	for _, entry := range summary.Services {
+		health[entry.InstanceID] = entry.Health
+	}
+	summary.Overall = overallHealth(services, health, summary.GeneratedAt)
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetOverallHealth returns the overall verdict over the cached health of every service
+func (h *DashboardHandler) GetOverallHealth(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	services, err := h.db.GetAllServices(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	health := make(map[string]*models.ServiceHealth, len(services))
+	for _, service := range services {
+		var cached models.ServiceHealth
+		if err := h.cache.Get(ctx, cache.PrefixHealth+service.InstanceID, &cached); err == nil {
+			health[service.InstanceID] = &cached
+		}
+	}
+
+	c.JSON(http.StatusOK, overallHealth(services, health, time.Now()))
+}
+
+// overallHealth rates the services as a whole. It is down when a critical service is
+// down and degraded when any other service is down or any service reports warnings.
+// Disabled and muted services and services without a health result are left out.
+func overallHealth(services []models.ServiceConfiguration, health map[string]*models.ServiceHealth, now time.Time) types.OverallHealth {
+	overall := types.OverallHealth{
+		Status:   types.OverallHealthy,
+		Down:     []string{},
+		Degraded: []string{},
+	}
+
+	for _, service := range services {
+		result := health[service.InstanceID]
+		if service.Disabled || service.IsMuted(now) || result == nil {
+			continue
+		}
+
+		switch {
+		case result.Status.IsDown():
+			overall.Down = append(overall.Down, service.InstanceID)
+			if service.Critical {
+				overall.Status = types.OverallDown
+			} else if overall.Status == types.OverallHealthy {
+				overall.Status = types.OverallDegraded
+			}
+		case result.Status == models.StatusWarning:
+			overall.Degraded = append(overall.Degraded, service.InstanceID)
+			if overall.Status == types.OverallHealthy {
+				overall.Status = types.OverallDegraded
+			}
+		}
+	}
+
+	return overall
+}
+
+// GetBadges returns the counts shown as navigation badges. They are computed from cached
+// data only and cached themselves for a few seconds.
+func (h *DashboardHandler) GetBadges(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var badges types.SummaryBadges
+	if err := h.cache.Get(ctx, badgesCacheKey, &badges); err == nil {
+		c.JSON(http.StatusOK, badges)
+		return
+	}
+
+	services, err := h.db.GetAllServices(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	badges = h.badges(ctx, services, time.Now())
+	if err := h.cache.Set(ctx, badgesCacheKey, badges, badgesCacheTTL); err != nil {
+		log.Warn().Err(err).Msg("Failed to cache summary badges")
+	}
+
+	c.JSON(http.StatusOK, badges)
+}
+
+// badges counts offline services and services with updates, pending Overseerr requests
+// and downloading Sonarr and Radarr queue items. Muted services aren't counted as offline.
+func (h *DashboardHandler) badges(ctx context.Context, services []models.ServiceConfiguration, now time.Time) types.SummaryBadges {
+	var badges types.SummaryBadges
+
+	for _, service := range services {
+		var health models.ServiceHealth
+		if err := h.cache.Get(ctx, cache.PrefixHealth+service.InstanceID, &health); err == nil {
+			if health.Status.IsDown() && !service.IsMuted(now) {
+				badges.Offline++
+			}
+			if health.UpdateAvailable {
+				badges.Updates++
+			}
+		}
+
+		serviceType, _, _ := strings.Cut(service.InstanceID, "-")
+		switch serviceType {
+		case "overseerr":
+			var requests types.RequestsStats
+			if err := h.cache.Get(ctx, overseerrCachePrefix+service.InstanceID, &requests); err == nil {
+				badges.PendingRequests += requests.PendingCount
+			}
+		case "sonarr":
+			var queue types.SonarrQueueResponse
+			if err := h.cache.Get(ctx, sonarrQueuePrefix+service.InstanceID, &queue); err == nil {
+				for _, record := range queue.Records {
+					if record.Status == "downloading" {
+						badges.ActiveDownloads++
+					}
+				}
+			}
+		case "radarr":
+			var queue types.RadarrQueueResponse
+			if err := h.cache.Get(ctx, radarrQueuePrefix+service.InstanceID, &queue); err == nil {
+				for _, record := range queue.Records {
+					if record.Status == "downloading" {
+						badges.ActiveDownloads++
+					}
+				}
+			}
+		}
+	}
+
+	return badges
+}
+
+func (h *DashboardHandler) entry(ctx context.Context, service models.ServiceConfiguration) types.DashboardEntry {
+	serviceType, _, _ := strings.Cut(service.InstanceID, "-")
+	entry := types.DashboardEntry{
+		InstanceID:  service.InstanceID,
+		Type:        serviceType,
+		DisplayName: service.DisplayName,
+		Critical:    service.Critical,
+		Pinned:      service.Pinned,
+	}
+
+	var health models.ServiceHealth
+	if err := h.cache.Get(ctx, cache.PrefixHealth+service.InstanceID, &health); err == nil {
+		entry.Health = &health
+	}
+
+	entry.Stat = h.headlineStat(ctx, serviceType, service.InstanceID)
+	return entry
+}
+
+// headlineStat reads the headline number of a service from the cache its own handler fills
+func (h *DashboardHandler) headlineStat(ctx context.Context, serviceType, instanceID string) *types.DashboardStat {
+	switch serviceType {
+	case "sonarr":
+		var queue types.SonarrQueueResponse
+		if err := h.cache.Get(ctx, sonarrQueuePrefix+instanceID, &queue); err == nil {
+			return &types.DashboardStat{Label: "queue", Value: queue.TotalRecords}
+		}
+	case "radarr":
+		var queue types.RadarrQueueResponse
+		if err := h.cache.Get(ctx, radarrQueuePrefix+instanceID, &queue); err == nil {
+			return &types.DashboardStat{Label: "queue", Value: queue.TotalRecords}
+		}
+	case "overseerr":
+		var requests types.RequestsStats
+		if err := h.cache.Get(ctx, overseerrCachePrefix+instanceID, &requests); err == nil {
+			return &types.DashboardStat{Label: "pending", Value: requests.PendingCount}
+		}
+	case "plex":
+		var sessions types.PlexSessionsResponse
+		if err := h.cache.Get(ctx, plexCachePrefix+instanceID, &sessions); err == nil {
+			return &types.DashboardStat{Label: "streams", Value: sessions.MediaContainer.Size}
+		}
+	case "autobrr":
+		var stats types.AutobrrStats
+		if err := h.cache.Get(ctx, statsPrefix+instanceID, &stats); err == nil {
+			return &types.DashboardStat{Label: "releases", Value: stats.TotalCount}
+		}
+	case "prowlarr":
+		var stats types.ProwlarrStatsResponse
+		if err := h.cache.Get(ctx, prowlarrStatsPrefix+instanceID, &stats); err == nil {
+			return &types.DashboardStat{Label: "indexers", Value: stats.IndexerCount}
+		}
+	case "maintainerr":
+		var collections []maintainerr.Collection
+		if err := h.cache.Get(ctx, cachePrefix+instanceID, &collections); err == nil {
+			return &types.DashboardStat{Label: "collections", Value: len(collections)}
+		}
+	case "tailscale":
+		var response struct {
+			Devices []tailscale.Device `json:"devices"`
+		}
+		if err := h.cache.Get(ctx, devicesCachePrefix+instanceID, &response); err == nil {
+			online := 0
+			for _, device := range response.Devices {
+				if device.Online {
+					online++
+				}
+			}
+			return &types.DashboardStat{Label: "online devices", Value: online}
+		}
+	}
+	return nil
+}
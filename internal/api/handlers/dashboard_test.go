@@ -0,0 +1,175 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestDashboardHandler_GetSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db, store := setupTestDB(t)
+
+	for _, svc := range []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "overseerr-1", DisplayName: "Overseerr", URL: "http://overseerr:5055"},
+	} {
+		svc := svc
+		if err := db.CreateService(ctx, &svc); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	// Only sonarr has cached data
+	if err := store.Set(ctx, cache.PrefixHealth+"sonarr-1", models.ServiceHealth{InstanceID: "sonarr-1", Status: models.StatusOnline}, time.Minute); err != nil {
+		t.Fatalf("Failed to seed health: %v", err)
+	}
+	if err := store.Set(ctx, sonarrQueuePrefix+"sonarr-1", types.SonarrQueueResponse{TotalRecords: 7}, time.Minute); err != nil {
+		t.Fatalf("Failed to seed queue: %v", err)
+	}
+
+	c, w := newTestContext(http.MethodGet, "/api/dashboard", nil)
+
+	NewDashboardHandler(db, store).GetSummary(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var summary types.DashboardSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to decode summary: %v", err)
+	}
+	if len(summary.Services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(summary.Services))
+	}
+
+	entries := make(map[string]types.DashboardEntry)
+	for _, entry := range summary.Services {
+		entries[entry.InstanceID] = entry
+	}
+
+	sonarr := entries["sonarr-1"]
+	if sonarr.Type != "sonarr" || sonarr.Health == nil || sonarr.Health.Status != models.StatusOnline {
+		t.Errorf("Expected cached sonarr health, got %+v", sonarr)
+	}
+	if sonarr.Stat == nil || sonarr.Stat.Label != "queue" || sonarr.Stat.Value != 7 {
+		t.Errorf("Expected sonarr queue stat of 7, got %+v", sonarr.Stat)
+	}
+
+	overseerr := entries["overseerr-1"]
+	if overseerr.Health != nil || overseerr.Stat != nil {
+		t.Errorf("Expected no cached data for overseerr, got %+v", overseerr)
+	}
+}
+
+func TestOverallHealth(t *testing.T) {
+	now := time.Now()
+	muted := now.Add(time.Hour)
+
+	services := []models.ServiceConfiguration{
+		{InstanceID: "plex-1", Critical: true},
+		{InstanceID: "sonarr-1"},
+		{InstanceID: "radarr-1"},
+		{InstanceID: "prowlarr-1", Critical: true, MutedUntil: &muted},
+		{InstanceID: "autobrr-1", Critical: true},
+	}
+	health := func(statuses map[string]models.ServiceStatus) map[string]*models.ServiceHealth {
+		results := make(map[string]*models.ServiceHealth, len(statuses))
+		for id, status := range statuses {
+			results[id] = &models.ServiceHealth{InstanceID: id, Status: status}
+		}
+		return results
+	}
+
+	tests := []struct {
+		name     string
+		statuses map[string]models.ServiceStatus
+		want     types.OverallStatus
+	}{
+		{"all online", map[string]models.ServiceStatus{"plex-1": models.StatusOnline, "sonarr-1": models.StatusOnline}, types.OverallHealthy},
+		{"non-critical down", map[string]models.ServiceStatus{"plex-1": models.StatusOnline, "sonarr-1": models.StatusOffline}, types.OverallDegraded},
+		{"critical warning", map[string]models.ServiceStatus{"plex-1": models.StatusWarning}, types.OverallDegraded},
+		{"critical down", map[string]models.ServiceStatus{"plex-1": models.StatusError, "sonarr-1": models.StatusOffline}, types.OverallDown},
+		{"muted critical down", map[string]models.ServiceStatus{"prowlarr-1": models.StatusOffline}, types.OverallHealthy},
+		{"unknown critical", map[string]models.ServiceStatus{"autobrr-1": models.StatusChecking}, types.OverallHealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overallHealth(services, health(tt.statuses), now); got.Status != tt.want {
+				t.Errorf("Expected %s, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDashboardHandler_GetBadges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db, store := setupTestDB(t)
+
+	for _, svc := range []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://radarr:7878"},
+		{InstanceID: "overseerr-1", DisplayName: "Overseerr", URL: "http://overseerr:5055"},
+		{InstanceID: "plex-1", DisplayName: "Plex", URL: "http://plex:32400"},
+	} {
+		svc := svc
+		if err := db.CreateService(ctx, &svc); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+	if err := db.SetServiceEnabled(ctx, "plex-1", false); err != nil {
+		t.Fatalf("Failed to disable service: %v", err)
+	}
+
+	seed := map[string]interface{}{
+		cache.PrefixHealth + "sonarr-1":      models.ServiceHealth{Status: models.StatusOnline, UpdateAvailable: true},
+		cache.PrefixHealth + "radarr-1":      models.ServiceHealth{Status: models.StatusOffline},
+		cache.PrefixHealth + "plex-1":        models.ServiceHealth{Status: models.StatusOffline},
+		overseerrCachePrefix + "overseerr-1": types.RequestsStats{PendingCount: 3},
+		sonarrQueuePrefix + "sonarr-1": types.SonarrQueueResponse{Records: []types.QueueRecord{
+			{Status: "downloading"}, {Status: "queued"}, {Status: "downloading"},
+		}},
+		radarrQueuePrefix + "radarr-1": types.RadarrQueueResponse{Records: []types.RadarrQueueRecord{{Status: "downloading"}}},
+	}
+	for key, value := range seed {
+		if err := store.Set(ctx, key, value, time.Minute); err != nil {
+			t.Fatalf("Failed to seed %s: %v", key, err)
+		}
+	}
+
+	c, w := newTestContext(http.MethodGet, "/api/summary/badges", nil)
+
+	NewDashboardHandler(db, store).GetBadges(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var badges types.SummaryBadges
+	if err := json.Unmarshal(w.Body.Bytes(), &badges); err != nil {
+		t.Fatalf("Failed to decode badges: %v", err)
+	}
+
+	// The disabled plex instance isn't counted as offline
+	want := types.SummaryBadges{Offline: 1, Updates: 1, PendingRequests: 3, ActiveDownloads: 3}
+	if badges != want {
+		t.Errorf("Expected %+v, got %+v", want, badges)
+	}
+}
@@ -5,29 +5,37 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 
 	"github.com/autobrr/dashbrr/internal/database"
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
 )
 
 type EventsHandler struct {
 	db     *database.DB
 	health *services.HealthService
+	cache  cache.Store
 }
 
-func NewEventsHandler(db *database.DB, health *services.HealthService) *EventsHandler {
+func NewEventsHandler(db *database.DB, health *services.HealthService, store cache.Store) *EventsHandler {
 	handler := &EventsHandler{
 		db:     db,
 		health: health,
+		cache:  store,
 	}
 	return handler
 }
@@ -36,7 +44,8 @@ type client struct {
 	send        chan models.ServiceHealth
 	done        chan struct{}
 	connectedAt time.Time
-	lastActive  time.Time // Track last successful message send
+	lastActive  time.Time    // Track last successful message send
+	skipped     atomic.Int32 // Consecutive broadcasts skipped because the client was too slow
 }
 
 var (
@@ -49,23 +58,86 @@ var (
 	// Reduced concurrent checks from 10 to 5 to prevent overwhelming
 	healthCheckSemaphore = make(chan struct{}, 5)
 
+	// Bounds the first check of each service, see SetInitialCheckConcurrency
+	initialCheckSemaphore atomic.Pointer[chan struct{}]
+
 	// Track last check time per service
 	lastChecks   = make(map[string]time.Time)
 	lastChecksMu sync.RWMutex
 
 	// Client cleanup ticker
 	cleanupTicker *time.Ticker
+
+	// Guards against overlapping background refreshes
+	refreshInFlight atomic.Bool
+
+	// Unix nano timestamp of the most recent full health check
+	lastFullCheck atomic.Int64
+
+	// Last SSE event id handed out, see nextEventID
+	lastEventID atomic.Uint64
+
+	// Set once the server shuts down, streams then end with a shutdown event
+	shuttingDown atomic.Bool
+
+	// How long a broadcast waits on a slow client, see SetBroadcastTimeout
+	broadcastTimeout atomic.Int64
+
+	// Broadcasts skipped for slow clients, and slow clients disconnected, since startup
+	skippedBroadcasts     atomic.Uint64
+	slowClientDisconnects atomic.Uint64
 )
 
+func init() {
+	SetInitialCheckConcurrency(defaultInitialCheckConcurrency)
+	SetBroadcastTimeout(0)
+
+	// Seeding with the current time keeps ids increasing across restarts, so a client
+	// resuming with an id from before a restart doesn't skip the new results. Milliseconds
+	// keep the ids below 2^53, the largest integer JavaScript clients read exactly.
+	lastEventID.Store(uint64(time.Now().UnixMilli()))
+}
+
+// SetInitialCheckConcurrency sets how many services are checked at once when they are
+// checked for the first time, after startup or after being added. Later checks use the
+// smaller steady-state limit. 0 or less uses the default.
+func SetInitialCheckConcurrency(n int) {
+	if n <= 0 {
+		n = defaultInitialCheckConcurrency
+	}
+	sem := make(chan struct{}, n)
+	initialCheckSemaphore.Store(&sem)
+}
+
+// SetBroadcastTimeout sets how many milliseconds a broadcast waits for a slow SSE client
+// before the update is skipped for it. 0 or less uses the default of 2000.
+func SetBroadcastTimeout(ms int) {
+	timeout := defaultBroadcastTimeout
+	if ms > 0 {
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+	broadcastTimeout.Store(int64(timeout))
+}
+
+// nextEventID returns a new monotonic SSE event id
+func nextEventID() uint64 {
+	return lastEventID.Add(1)
+}
+
 const (
-	minCheckInterval  = 30 * time.Second
-	checkTimeout      = 10 * time.Second // Reduced from 15s to 10s
+	defaultInitialCheckConcurrency = 20
+
+	minCheckInterval  = models.MinCheckIntervalSeconds * time.Second
+	checkSlack        = minCheckInterval / 2 // Checks are recorded when they finish, after the pass that started them
+	checkTimeout      = 10 * time.Second     // Reduced from 15s to 10s
 	keepAliveInterval = 15 * time.Second
-	broadcastTimeout  = 2 * time.Second  // Reduced from 5s to 2s
 	clientBufferSize  = 50               // Reduced from 100 to 50
 	cleanupInterval   = 2 * time.Minute  // More frequent cleanup
 	maxClientAge      = 10 * time.Minute // Max time before forcing reconnect
 	maxInactiveTime   = 30 * time.Second // Max time without successful message
+
+	defaultBroadcastTimeout = 2 * time.Second // Reduced from 5s to 2s
+	maxSkippedBroadcasts    = 5               // Consecutive skips before a slow client is disconnected
 )
 
 // safeClose safely closes a channel if it's not already closed
@@ -85,17 +157,37 @@ func safeClose(ch chan struct{}) {
 }
 
 // startClientCleanup starts periodic cleanup of disconnected clients
-func startClientCleanup() {
+func startClientCleanup(ctx context.Context) {
 	if cleanupTicker != nil {
 		return
 	}
 
 	cleanupTicker = time.NewTicker(cleanupInterval)
-	go func() {
-		for range cleanupTicker.C {
-			cleanupClients()
+	go func(ticker *time.Ticker) {
+		for {
+			select {
+			case <-ticker.C:
+				cleanupClients()
+			case <-ctx.Done():
+				return
+			}
 		}
-	}()
+	}(cleanupTicker)
+}
+
+// drainClients ends every open stream. The streams send a final shutdown event first
+// so clients know to reconnect rather than treat the closed connection as an error.
+func drainClients() {
+	shuttingDown.Store(true)
+
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	for client := range clients {
+		safeClose(client.done)
+	}
+
+	log.Info().Int("clients", len(clients)).Msg("Draining SSE clients")
 }
 
 // cleanupClients removes disconnected and stale clients
@@ -146,25 +238,22 @@ func cleanupClients() {
 	}
 }
 
-// processServiceBatch handles health checks for a batch of services
-func (h *EventsHandler) processServiceBatch(ctx context.Context, services []models.ServiceConfiguration, results chan<- models.ServiceHealth, wg *sync.WaitGroup) {
+// processServiceBatch handles health checks for a batch of services, sem bounds how many
+// run at once
+func (h *EventsHandler) processServiceBatch(ctx context.Context, services []models.ServiceConfiguration, sem chan struct{}, results chan<- models.ServiceHealth, wg *sync.WaitGroup) {
 	for _, service := range services {
-		if service.URL == "" {
-			continue
-		}
-
 		select {
 		case <-ctx.Done():
 			return
 		default:
 			wg.Add(1)
-			go h.checkSingleService(ctx, service, results, wg)
+			go h.checkSingleService(ctx, service, sem, results, wg)
 		}
 	}
 }
 
 // checkSingleService performs health check for a single service
-func (h *EventsHandler) checkSingleService(ctx context.Context, svc models.ServiceConfiguration, results chan<- models.ServiceHealth, wg *sync.WaitGroup) {
+func (h *EventsHandler) checkSingleService(ctx context.Context, svc models.ServiceConfiguration, sem chan struct{}, results chan<- models.ServiceHealth, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	// Create timeout context for health check
@@ -172,32 +261,24 @@ func (h *EventsHandler) checkSingleService(ctx context.Context, svc models.Servi
 	defer cancel()
 
 	select {
-	case healthCheckSemaphore <- struct{}{}:
-		defer func() { <-healthCheckSemaphore }()
+	case sem <- struct{}{}:
+		defer func() { <-sem }()
 
 		serviceType := strings.Split(svc.InstanceID, "-")[0]
 		serviceHealth := models.ServiceHealth{
-			ServiceID:   svc.InstanceID,
-			Status:      "checking",
+			InstanceID:  svc.InstanceID,
+			Status:      models.StatusChecking,
 			LastChecked: time.Now(),
+			Color:       svc.Color,
+			Icon:        svc.Icon,
+			Pinned:      svc.Pinned,
 		}
 
-		if serviceChecker := models.NewServiceRegistry().CreateService(serviceType); serviceChecker != nil {
+		serviceChecker, err := models.CreateServiceE(models.NewServiceRegistry(), serviceType)
+		if err == nil {
+			svc.Configure(serviceChecker)
 			health, statusCode := serviceChecker.CheckHealth(checkCtx, svc.URL, svc.APIKey)
-			health.ServiceID = svc.InstanceID
-
-			if statusCode != 200 {
-				log.Debug().
-					Int("status_code", statusCode).
-					Str("service", svc.InstanceID).
-					Msg("Health check failed")
-				health.Status = "error"
-				health.Message = "Service returned non-200 status code"
-			}
-
-			lastChecksMu.Lock()
-			lastChecks[svc.InstanceID] = time.Now()
-			lastChecksMu.Unlock()
+			h.recordHealth(&svc, &health, statusCode)
 
 			select {
 			case results <- health:
@@ -205,8 +286,8 @@ func (h *EventsHandler) checkSingleService(ctx context.Context, svc models.Servi
 				return
 			}
 		} else {
-			serviceHealth.Status = "error"
-			serviceHealth.Message = "Unsupported service type: " + serviceType
+			serviceHealth.Status = models.StatusError
+			serviceHealth.Message = err.Error()
 			select {
 			case results <- serviceHealth:
 			case <-checkCtx.Done():
@@ -219,6 +300,46 @@ func (h *EventsHandler) checkSingleService(ctx context.Context, svc models.Servi
 	}
 }
 
+// recordHealth completes the result of a health check with the settings of the service,
+// records the check and caches the result
+func (h *EventsHandler) recordHealth(svc *models.ServiceConfiguration, health *models.ServiceHealth, statusCode int) {
+	health.InstanceID = svc.InstanceID
+	health.Muted = svc.IsMuted(time.Now())
+	health.Pinned = svc.Pinned
+	health.Color = svc.Color
+	health.Icon = svc.Icon
+
+	if statusCode != 200 {
+		log.Debug().
+			Int("status_code", statusCode).
+			Str("service", svc.InstanceID).
+			Msg("Health check failed")
+		health.Status = models.StatusForResponseCode(statusCode)
+		// Keep the upstream message as the detail behind the status code
+		health.Detail = health.Message
+		health.Message = describeStatusCode(statusCode)
+	}
+
+	lastChecksMu.Lock()
+	lastChecks[svc.InstanceID] = time.Now()
+	lastChecksMu.Unlock()
+
+	svc.CheckExpectedVersion(health)
+
+	if checkSucceeded(*health, statusCode) {
+		now := time.Now()
+		health.LastSuccess = &now
+	} else {
+		health.LastSuccess = h.lastSuccess(svc.InstanceID)
+	}
+
+	// The id is assigned before caching so a resumed stream can tell which
+	// cached results it has already seen
+	health.EventID = nextEventID()
+	h.cacheHealth(*health)
+	notifyHealthCached()
+}
+
 // collectResults gathers health check results with timeout
 func (h *EventsHandler) collectResults(ctx context.Context, results <-chan models.ServiceHealth) []models.ServiceHealth {
 	var allResults []models.ServiceHealth
@@ -245,7 +366,12 @@ func (h *EventsHandler) collectResults(ctx context.Context, results <-chan model
 
 // checkAndBroadcastHealth performs health checks for all services and broadcasts results
 func (h *EventsHandler) checkAndBroadcastHealth(ctx context.Context) []models.ServiceHealth {
-	services, err := h.db.GetAllServices(ctx)
+	if MaintenanceActive() {
+		log.Debug().Msg("Skipping health checks in maintenance mode")
+		return nil
+	}
+
+	services, err := h.db.GetAllServices(ctx, false)
 	if err != nil {
 		log.Error().Err(err).Msg("Error fetching services")
 		return nil
@@ -255,11 +381,31 @@ func (h *EventsHandler) checkAndBroadcastHealth(ctx context.Context) []models.Se
 		return nil
 	}
 
+	now := time.Now()
+	lastFullCheck.Store(now.UnixNano())
+	h.clearExpiredMutes(ctx, services)
+
+	services = dueServices(services, now)
+	if len(services) == 0 {
+		return nil
+	}
+	due := services
+
 	var wg sync.WaitGroup
 	results := make(chan models.ServiceHealth, len(services))
 	checkCtx, cancel := context.WithTimeout(ctx, 15*time.Second) // Overall timeout for batch
 	defer cancel()
 
+	// Services that were never checked go out in one burst on the larger initial
+	// semaphore so a fresh dashboard fills quickly
+	initial, services := splitInitialChecks(services)
+	if len(initial) > 0 {
+		h.processServiceBatch(checkCtx, initial, *initialCheckSemaphore.Load(), results, &wg)
+		if !h.waitForBatch(checkCtx, &wg) {
+			return nil
+		}
+	}
+
 	// Process services in smaller batches
 	batchSize := 3 // Reduced batch size
 	for i := 0; i < len(services); i += batchSize {
@@ -268,7 +414,7 @@ func (h *EventsHandler) checkAndBroadcastHealth(ctx context.Context) []models.Se
 			end = len(services)
 		}
 
-		h.processServiceBatch(checkCtx, services[i:end], results, &wg)
+		h.processServiceBatch(checkCtx, services[i:end], healthCheckSemaphore, results, &wg)
 
 		// Wait for batch completion or context cancellation
 		if !h.waitForBatch(checkCtx, &wg) {
@@ -285,7 +431,253 @@ func (h *EventsHandler) checkAndBroadcastHealth(ctx context.Context) []models.Se
 		close(results)
 	}()
 
-	return h.collectResults(checkCtx, results)
+	checked := h.collectResults(checkCtx, results)
+
+	// Stalled queue items are removed once the health of the services is out
+	h.removeStalledQueueItems(ctx, due)
+
+	return checked
+}
+
+// dueServices returns the services whose check interval has passed since their last check.
+// Services without an override are due on every pass of the monitor.
+func dueServices(services []models.ServiceConfiguration, now time.Time) []models.ServiceConfiguration {
+	lastChecksMu.RLock()
+	defer lastChecksMu.RUnlock()
+
+	due := make([]models.ServiceConfiguration, 0, len(services))
+	for _, svc := range services {
+		last, ok := lastChecks[svc.InstanceID]
+		if ok && now.Sub(last) < svc.CheckInterval(minCheckInterval)-checkSlack {
+			continue
+		}
+		due = append(due, svc)
+	}
+	return due
+}
+
+// splitInitialChecks separates the services that were never checked from the others
+func splitInitialChecks(services []models.ServiceConfiguration) (initial, checked []models.ServiceConfiguration) {
+	lastChecksMu.RLock()
+	defer lastChecksMu.RUnlock()
+
+	for _, svc := range services {
+		if _, ok := lastChecks[svc.InstanceID]; ok {
+			checked = append(checked, svc)
+		} else {
+			initial = append(initial, svc)
+		}
+	}
+	return initial, checked
+}
+
+// clearExpiredMutes unmutes services whose mute time has passed
+func (h *EventsHandler) clearExpiredMutes(ctx context.Context, services []models.ServiceConfiguration) {
+	now := time.Now()
+	for i := range services {
+		svc := &services[i]
+		if svc.MutedUntil == nil || svc.IsMuted(now) {
+			continue
+		}
+
+		if err := h.db.SetServiceMute(ctx, svc.InstanceID, nil); err != nil {
+			log.Error().Err(err).Str("service", svc.InstanceID).Msg("Failed to clear expired mute")
+			continue
+		}
+		svc.MutedUntil = nil
+		log.Info().Str("service", svc.InstanceID).Msg("Service mute expired")
+	}
+}
+
+// cacheHealth stores the latest health result so it can be served without a fresh check
+func (h *EventsHandler) cacheHealth(health models.ServiceHealth) {
+	if h.cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := h.cache.Set(ctx, cache.PrefixHealth+health.InstanceID, health, cache.HealthTTL); err != nil {
+		log.Debug().Err(err).Str("service", health.InstanceID).Msg("Failed to cache health result")
+	}
+}
+
+// checkSucceeded reports whether a live check reached the service and found it usable
+func checkSucceeded(health models.ServiceHealth, statusCode int) bool {
+	return statusCode == http.StatusOK && (health.Status == models.StatusOnline || health.Status == models.StatusWarning)
+}
+
+// lastSuccess returns when the cached health of a service last recorded a successful check
+func (h *EventsHandler) lastSuccess(instanceID string) *time.Time {
+	if h.cache == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var cached models.ServiceHealth
+	if err := h.cache.Get(ctx, cache.PrefixHealth+instanceID, &cached); err != nil {
+		return nil
+	}
+	return cached.LastSuccess
+}
+
+// cachedHealth returns the last known health of every service that has a cached result,
+// including the unconfigured ones so their tiles can ask to be set up
+func (h *EventsHandler) cachedHealth(ctx context.Context) ([]models.ServiceHealth, error) {
+	services, err := h.db.GetAllServices(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ServiceHealth, 0, len(services))
+	if h.cache == nil {
+		return results, nil
+	}
+
+	for _, service := range services {
+		var health models.ServiceHealth
+		if err := h.cache.Get(ctx, cache.PrefixHealth+service.InstanceID, &health); err != nil {
+			continue
+		}
+		results = append(results, health)
+	}
+
+	return results, nil
+}
+
+// refreshHealth starts a background health check unless one is already running
+func (h *EventsHandler) refreshHealth() {
+	if !refreshInFlight.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer refreshInFlight.Store(false)
+
+		ctx := monitorCtx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		h.checkAndBroadcastHealth(ctx)
+	}()
+}
+
+// healthIsFresh reports whether a full health check ran within the minimum check interval
+func healthIsFresh() bool {
+	last := lastFullCheck.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < minCheckInterval
+}
+
+// replayCachedHealth sends the last known health of every service to a single client.
+// When the client resumes a stream, only results newer than afterID are sent. It returns
+// the number of services replayed.
+func (h *EventsHandler) replayCachedHealth(c *gin.Context, client *client, afterID uint64) int {
+	results, err := h.cachedHealth(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load cached health for replay")
+		return 0
+	}
+
+	replayed := 0
+	for _, health := range results {
+		// Results cached without an id predate the client's id tracking and are always sent
+		if afterID != 0 && health.EventID != 0 && health.EventID <= afterID {
+			continue
+		}
+		health.Maintenance = MaintenanceActive()
+		if err := writeHealthEvent(c, health); err != nil {
+			log.Error().Err(err).Msg("Failed to marshal health message")
+			continue
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		c.Writer.Flush()
+		client.lastActive = time.Now()
+	}
+
+	return replayed
+}
+
+// healthSchemaVersion is the version of the health event envelope. Bump it whenever the
+// shape of the envelope or of models.ServiceHealth changes in a way clients notice.
+const healthSchemaVersion = 1
+
+// healthEnvelope wraps a health event so clients can detect an incompatible payload
+type healthEnvelope struct {
+	V    int                  `json:"v"`
+	Type string               `json:"type"`
+	Data models.ServiceHealth `json:"data"`
+}
+
+// wantsLegacyEvents reports whether the client asked for the flat payload that predates
+// the envelope with ?format=legacy
+func wantsLegacyEvents(c *gin.Context) bool {
+	return c.Query("format") == "legacy"
+}
+
+// writeHealthEvent writes a health event, with its id when it has one. Keepalives are
+// written without an id so they don't move the client's Last-Event-ID.
+func writeHealthEvent(c *gin.Context, health models.ServiceHealth) error {
+	var payload interface{} = healthEnvelope{V: healthSchemaVersion, Type: "health", Data: health}
+	if wantsLegacyEvents(c) {
+		payload = health
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	event := sse.Event{Event: "health", Data: string(data)}
+	if health.EventID != 0 {
+		event.Id = strconv.FormatUint(health.EventID, 10)
+	}
+	c.Render(-1, event)
+	return nil
+}
+
+// resumeEventID returns the id a reconnecting client resumes from. Browsers send the
+// Last-Event-ID header when they reconnect by themselves, clients that open a new
+// EventSource pass it as the lastEventId query parameter instead.
+func resumeEventID(c *gin.Context) uint64 {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("lastEventId")
+	}
+	if value == "" {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		log.Debug().Str("lastEventId", value).Msg("Ignoring invalid Last-Event-ID")
+		return 0
+	}
+	return id
+}
+
+// GetAllHealth returns the last known health of every service in one call and triggers a background refresh
+func (h *EventsHandler) GetAllHealth(c *gin.Context) {
+	results, err := h.cachedHealth(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	h.refreshHealth()
+
+	healthMap := make(map[string]models.ServiceHealth, len(results))
+	for _, health := range results {
+		healthMap[health.InstanceID] = health
+	}
+
+	c.JSON(http.StatusOK, healthMap)
 }
 
 // waitForBatch waits for the current batch to complete or context to be canceled
@@ -309,6 +701,11 @@ func (h *EventsHandler) waitForBatch(ctx context.Context, wg *sync.WaitGroup) bo
 
 // StreamHealth handles SSE connections for real-time health updates
 func (h *EventsHandler) StreamHealth(c *gin.Context) {
+	if shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+		return
+	}
+
 	// Set headers for SSE
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -316,6 +713,17 @@ func (h *EventsHandler) StreamHealth(c *gin.Context) {
 	c.Header("Transfer-Encoding", "chunked")
 	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
 
+	// The stream stays open far longer than the server's read and write timeouts. A write
+	// deadline would cut it mid-stream, and an expired read deadline cancels the request
+	// context, so both are cleared for this connection only.
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Debug().Err(err).Msg("Failed to clear SSE write deadline")
+	}
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		log.Debug().Err(err).Msg("Failed to clear SSE read deadline")
+	}
+
 	// Create new client with buffered channel and done signal
 	client := &client{
 		send:        make(chan models.ServiceHealth, clientBufferSize),
@@ -354,8 +762,15 @@ func (h *EventsHandler) StreamHealth(c *gin.Context) {
 			Msg("SSE client disconnected")
 	}()
 
-	// Perform immediate health check for new connection
-	go h.checkAndBroadcastHealth(ctx)
+	// Replay the last known state to this client only, and only run a new check
+	// when the cached results are stale. Replayed messages are not recorded in
+	// lastUpdate so the first real result always goes through. A resumed stream
+	// only gets what it missed, and nothing at all doesn't call for a new check.
+	afterID := resumeEventID(c)
+	replayed := h.replayCachedHealth(c, client, afterID)
+	if (replayed == 0 && afterID == 0) || !healthIsFresh() {
+		h.refreshHealth()
+	}
 
 	lastUpdate := make(map[string]time.Time)
 	keepAliveTicker := time.NewTicker(keepAliveInterval)
@@ -369,6 +784,10 @@ func (h *EventsHandler) StreamHealth(c *gin.Context) {
 		case <-ctx.Done():
 			return
 		case <-client.done:
+			if shuttingDown.Load() {
+				c.SSEvent("shutdown", time.Now().Unix())
+				c.Writer.Flush()
+			}
 			return
 		case msg, ok := <-client.send:
 			if !ok {
@@ -376,18 +795,16 @@ func (h *EventsHandler) StreamHealth(c *gin.Context) {
 			}
 
 			now := time.Now()
-			if lastUpdateTime, exists := lastUpdate[msg.ServiceID]; !exists || now.Sub(lastUpdateTime) >= 5*time.Second {
-				data, err := json.Marshal(msg)
-				if err != nil {
+			if lastUpdateTime, exists := lastUpdate[msg.InstanceID]; !exists || now.Sub(lastUpdateTime) >= 5*time.Second {
+				if err := writeHealthEvent(c, msg); err != nil {
 					log.Error().Err(err).Msg("Failed to marshal health message")
 					continue
 				}
-				lastUpdate[msg.ServiceID] = now
+				lastUpdate[msg.InstanceID] = now
 
 				// Update last active time on successful send
 				client.lastActive = now
 
-				c.SSEvent("health", string(data))
 				c.Writer.Flush()
 			}
 		case <-keepAliveTicker.C:
@@ -397,20 +814,37 @@ func (h *EventsHandler) StreamHealth(c *gin.Context) {
 			default:
 				c.SSEvent("keepalive", time.Now().Unix())
 				c.Writer.Flush()
+				client.lastActive = time.Now()
 			}
 		case <-healthCheckTicker.C:
 			select {
 			case <-ctx.Done():
 				return
 			default:
-				go h.checkAndBroadcastHealth(ctx)
+				if !healthIsFresh() {
+					h.refreshHealth()
+				}
 			}
 		}
 	}
 }
 
-// BroadcastHealth sends health updates to all connected clients
+// BroadcastHealth sends health updates to all connected clients. Updates without an
+// event id get a new one. An update identical to the last one of its service and kind is
+// dropped, unless that was sent more than broadcastRefreshInterval ago. A client that
+// misses maxSkippedBroadcasts updates in a row is disconnected, so it reconnects and
+// resumes from its last event id instead of silently missing every update.
 func BroadcastHealth(health models.ServiceHealth) {
+	if isDuplicateBroadcast(health, time.Now()) {
+		return
+	}
+
+	if health.EventID == 0 {
+		health.EventID = nextEventID()
+	}
+
+	timeout := time.Duration(broadcastTimeout.Load())
+
 	clientsMu.RLock()
 	defer clientsMu.RUnlock()
 
@@ -419,14 +853,104 @@ func BroadcastHealth(health models.ServiceHealth) {
 		case <-client.done:
 			continue
 		case client.send <- health:
-			// Message sent successfully
-		case <-time.After(broadcastTimeout):
-			log.Debug().
-				Str("service", health.ServiceID).
+			client.skipped.Store(0)
+		case <-time.After(timeout):
+			skippedBroadcasts.Add(1)
+			skipped := client.skipped.Add(1)
+			if skipped < maxSkippedBroadcasts {
+				log.Debug().
+					Str("service", health.InstanceID).
+					Time("client_connected_at", client.connectedAt).
+					Msg("Skipped broadcast due to slow client")
+				continue
+			}
+
+			// The stream ends and the cleanup on disconnect removes the client
+			slowClientDisconnects.Add(1)
+			log.Warn().
+				Int32("skipped", skipped).
 				Time("client_connected_at", client.connectedAt).
-				Msg("Skipped broadcast due to slow client")
+				Msg("Disconnecting slow SSE client")
+			safeClose(client.done)
+		}
+	}
+}
+
+// GetStreamStats reports the connected SSE clients and how many broadcasts slow clients
+// missed since startup
+func (h *EventsHandler) GetStreamStats(c *gin.Context) {
+	c.JSON(http.StatusOK, types.EventStreamStats{
+		Clients:               activeClients.Load(),
+		SkippedBroadcasts:     skippedBroadcasts.Load(),
+		SlowClientDisconnects: slowClientDisconnects.Load(),
+		BroadcastTimeoutMs:    time.Duration(broadcastTimeout.Load()).Milliseconds(),
+	})
+}
+
+const (
+	// broadcastRefreshInterval is how long an unchanged update is suppressed before it is
+	// sent again, so clients still see fresh check times on a steady dashboard
+	broadcastRefreshInterval = 5 * time.Minute
+
+	// maxTrackedBroadcasts bounds lastBroadcasts, entries past the refresh interval are
+	// dropped once it is reached
+	maxTrackedBroadcasts = 512
+)
+
+type sentBroadcast struct {
+	fingerprint [sha256.Size]byte
+	sentAt      time.Time
+}
+
+var (
+	// Last update broadcast per service and kind, see isDuplicateBroadcast
+	lastBroadcasts   = make(map[string]sentBroadcast)
+	lastBroadcastsMu sync.Mutex
+)
+
+// broadcastFingerprint hashes what clients render of an update. Check times, response
+// times and event ids differ on every check and are left out.
+func broadcastFingerprint(health models.ServiceHealth) ([sha256.Size]byte, error) {
+	health.EventID = 0
+	health.LastChecked = time.Time{}
+	health.LastSuccess = nil
+	health.ResponseTime = 0
+
+	data, err := json.Marshal(health)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// isDuplicateBroadcast reports whether the update matches the last one broadcast for its
+// service within broadcastRefreshInterval, and records it when it doesn't. Updates are
+// tracked per service and message, as the typed updates, e.g. "autobrr_stats", share the
+// service id with the health results.
+func isDuplicateBroadcast(health models.ServiceHealth, now time.Time) bool {
+	fingerprint, err := broadcastFingerprint(health)
+	if err != nil {
+		return false
+	}
+	key := health.InstanceID + "\x00" + health.Message
+
+	lastBroadcastsMu.Lock()
+	defer lastBroadcastsMu.Unlock()
+
+	if last, ok := lastBroadcasts[key]; ok && last.fingerprint == fingerprint && now.Sub(last.sentAt) < broadcastRefreshInterval {
+		return true
+	}
+
+	if len(lastBroadcasts) >= maxTrackedBroadcasts {
+		for k, last := range lastBroadcasts {
+			if now.Sub(last.sentAt) >= broadcastRefreshInterval {
+				delete(lastBroadcasts, k)
+			}
 		}
 	}
+	lastBroadcasts[key] = sentBroadcast{fingerprint: fingerprint, sentAt: now}
+
+	return false
 }
 
 var (
@@ -442,7 +966,7 @@ func (h *EventsHandler) StartHealthMonitor() {
 		monitorCtx, monitorCancel = context.WithCancel(context.Background())
 
 		// Start client cleanup
-		startClientCleanup()
+		startClientCleanup(monitorCtx)
 
 		go h.checkAndBroadcastHealth(monitorCtx)
 
@@ -476,3 +1000,10 @@ func (h *EventsHandler) StopHealthMonitor() {
 	}
 	log.Info().Msg("Health monitor and client cleanup stopped")
 }
+
+// Shutdown stops the health monitor and drains the connected SSE clients. It runs before
+// the HTTP server shuts down, which would otherwise wait for the open streams to end.
+func (h *EventsHandler) Shutdown() {
+	h.StopHealthMonitor()
+	drainClients()
+}
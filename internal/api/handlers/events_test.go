@@ -0,0 +1,600 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func setupEventsHandler(t *testing.T) (*EventsHandler, cache.Store) {
+	t.Helper()
+
+	db, store := setupTestDB(t)
+
+	return NewEventsHandler(db, nil, store), store
+}
+
+func TestEventsHandler_ReplayCachedHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, store := setupEventsHandler(t)
+	ctx := context.Background()
+
+	for _, svc := range []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://radarr:7878"},
+	} {
+		svc := svc
+		if err := handler.db.CreateService(ctx, &svc); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	// Only sonarr has a cached result
+	if err := store.Set(ctx, cache.PrefixHealth+"sonarr-1", models.ServiceHealth{
+		InstanceID: "sonarr-1",
+		Status:     "online",
+	}, time.Minute); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	c, w := newTestContext("GET", "/api/health/events", nil)
+
+	staleClient := &client{
+		connectedAt: time.Now(),
+		lastActive:  time.Now().Add(-2 * maxInactiveTime),
+	}
+
+	replayed := handler.replayCachedHealth(c, staleClient, 0)
+	if replayed != 1 {
+		t.Errorf("Expected 1 replayed service, got %d", replayed)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "event:health") || !strings.Contains(body, `"serviceId":"sonarr-1"`) {
+		t.Errorf("Expected replayed sonarr health event, got %q", body)
+	}
+	if strings.Contains(body, "radarr-1") {
+		t.Errorf("Did not expect uncached radarr in replay, got %q", body)
+	}
+
+	// A replay counts as activity, so the client must survive the inactivity sweep
+	if time.Since(staleClient.lastActive) > maxInactiveTime {
+		t.Errorf("Expected replay to refresh lastActive, got %v", staleClient.lastActive)
+	}
+}
+
+func TestNextEventID_SafeInteger(t *testing.T) {
+	// JavaScript reads integers above 2^53 rounded, which would break Last-Event-ID
+	first, second := nextEventID(), nextEventID()
+	if second != first+1 {
+		t.Errorf("Expected consecutive ids, got %d and %d", first, second)
+	}
+	if second >= 1<<53 {
+		t.Errorf("Expected ids below 2^53, got %d", second)
+	}
+}
+
+func TestEventsHandler_ReplayAfterLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, store := setupEventsHandler(t)
+	ctx := context.Background()
+
+	for _, svc := range []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://radarr:7878"},
+	} {
+		svc := svc
+		if err := handler.db.CreateService(ctx, &svc); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	seen := nextEventID()
+	newer := nextEventID()
+	for _, health := range []models.ServiceHealth{
+		{InstanceID: "sonarr-1", Status: models.StatusOnline, EventID: seen},
+		{InstanceID: "radarr-1", Status: models.StatusOnline, EventID: newer},
+	} {
+		if err := store.Set(ctx, cache.PrefixHealth+health.InstanceID, health, time.Minute); err != nil {
+			t.Fatalf("Failed to seed cache: %v", err)
+		}
+	}
+
+	c, w := newTestContext("GET", "/api/health/events", nil)
+	c.Request.Header.Set("Last-Event-ID", strconv.FormatUint(seen, 10))
+
+	replayed := handler.replayCachedHealth(c, &client{}, resumeEventID(c))
+	if replayed != 1 {
+		t.Errorf("Expected only the result newer than Last-Event-ID, got %d", replayed)
+	}
+
+	body := w.Body.String()
+	if strings.Contains(body, "sonarr-1") {
+		t.Errorf("Did not expect the already seen sonarr result, got %q", body)
+	}
+	if !strings.Contains(body, "id:"+strconv.FormatUint(newer, 10)) {
+		t.Errorf("Expected the radarr result with its event id, got %q", body)
+	}
+}
+
+func TestEventsHandler_CachedHealthUnconfigured(t *testing.T) {
+	handler, _ := setupEventsHandler(t)
+	ctx := context.Background()
+
+	services := []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr"},
+	}
+	for i := range services {
+		if err := handler.db.CreateService(ctx, &services[i]); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	handler.recordHealth(&services[0], &models.ServiceHealth{Status: models.StatusOnline}, http.StatusOK)
+	handler.recordHealth(&services[1], &models.ServiceHealth{
+		Status:  models.StatusUnconfigured,
+		Message: "Service is not configured",
+	}, http.StatusOK)
+
+	results, err := handler.cachedHealth(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load cached health: %v", err)
+	}
+
+	statuses := make(map[string]models.ServiceStatus)
+	for _, health := range results {
+		statuses[health.InstanceID] = health.Status
+	}
+	if len(statuses) != 2 || statuses["radarr-1"] != models.StatusUnconfigured {
+		t.Errorf("Expected the unconfigured radarr alongside sonarr, got %v", statuses)
+	}
+}
+
+func TestEventsHandler_LastSuccess(t *testing.T) {
+	handler, store := setupEventsHandler(t)
+	ctx := context.Background()
+
+	if !checkSucceeded(models.ServiceHealth{Status: models.StatusWarning}, http.StatusOK) {
+		t.Error("Expected a warning to count as a successful check")
+	}
+	if checkSucceeded(models.ServiceHealth{Status: models.StatusOnline}, http.StatusBadGateway) {
+		t.Error("Did not expect a failed request to count as a successful check")
+	}
+
+	if got := handler.lastSuccess("sonarr-1"); got != nil {
+		t.Errorf("Expected no last success without a cached result, got %v", got)
+	}
+
+	success := time.Now().Add(-time.Hour).Truncate(time.Second)
+	health := models.ServiceHealth{InstanceID: "sonarr-1", Status: models.StatusError, LastChecked: time.Now(), LastSuccess: &success}
+	if err := store.Set(ctx, cache.PrefixHealth+health.InstanceID, health, time.Minute); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	if got := handler.lastSuccess("sonarr-1"); got == nil || !got.Equal(success) {
+		t.Errorf("Expected the cached last success %v, got %v", success, got)
+	}
+}
+
+func TestWriteHealthEvent_Envelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	health := models.ServiceHealth{InstanceID: "sonarr-1", Status: models.StatusOnline}
+
+	c, w := newTestContext("GET", "/api/health/events", nil)
+
+	if err := writeHealthEvent(c, health); err != nil {
+		t.Fatalf("Failed to write health event: %v", err)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `{"v":1,"type":"health","data":{`) {
+		t.Errorf("Expected the versioned envelope, got %q", body)
+	}
+
+	c, w = newTestContext("GET", "/api/health/events?format=legacy", nil)
+
+	if err := writeHealthEvent(c, health); err != nil {
+		t.Fatalf("Failed to write health event: %v", err)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `data:{"status":"online"`) {
+		t.Errorf("Expected the flat legacy payload, got %q", body)
+	}
+}
+
+func TestCleanupClients_InactiveAfterEmptyReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, _ := setupEventsHandler(t)
+
+	c, _ := newTestContext("GET", "/api/health/events", nil)
+
+	inactive := &client{
+		done:        make(chan struct{}),
+		connectedAt: time.Now(),
+		lastActive:  time.Now().Add(-2 * maxInactiveTime),
+	}
+	active := &client{
+		done:        make(chan struct{}),
+		connectedAt: time.Now(),
+		lastActive:  time.Now(),
+	}
+
+	// Nothing is cached, so the replay must not mark the client as active
+	if replayed := handler.replayCachedHealth(c, inactive, 0); replayed != 0 {
+		t.Fatalf("Expected nothing to replay, got %d", replayed)
+	}
+
+	clientsMu.Lock()
+	clients[inactive] = true
+	clients[active] = true
+	activeClients.Add(2)
+	clientsMu.Unlock()
+
+	t.Cleanup(func() {
+		clientsMu.Lock()
+		for cl := range clients {
+			if cl == inactive || cl == active {
+				delete(clients, cl)
+				activeClients.Add(-1)
+			}
+		}
+		clientsMu.Unlock()
+	})
+
+	cleanupClients()
+
+	clientsMu.RLock()
+	_, inactiveKept := clients[inactive]
+	_, activeKept := clients[active]
+	clientsMu.RUnlock()
+
+	if inactiveKept {
+		t.Error("Expected inactive client to be removed")
+	}
+	if !activeKept {
+		t.Error("Expected active client to be kept")
+	}
+
+	select {
+	case <-inactive.done:
+	default:
+		t.Error("Expected inactive client to be signalled to close")
+	}
+}
+
+func TestBroadcastHealth_DisconnectsSlowClient(t *testing.T) {
+	SetBroadcastTimeout(1)
+	t.Cleanup(func() { SetBroadcastTimeout(0) })
+
+	// Nobody reads from the slow client, the fast one has room for every update
+	slow := &client{send: make(chan models.ServiceHealth), done: make(chan struct{}), connectedAt: time.Now()}
+	fast := &client{send: make(chan models.ServiceHealth, clientBufferSize), done: make(chan struct{}), connectedAt: time.Now()}
+
+	clientsMu.Lock()
+	clients[slow] = true
+	clients[fast] = true
+	clientsMu.Unlock()
+
+	t.Cleanup(func() {
+		clientsMu.Lock()
+		delete(clients, slow)
+		delete(clients, fast)
+		clientsMu.Unlock()
+
+		lastBroadcastsMu.Lock()
+		lastBroadcasts = make(map[string]sentBroadcast)
+		lastBroadcastsMu.Unlock()
+	})
+
+	skippedBefore, disconnectsBefore := skippedBroadcasts.Load(), slowClientDisconnects.Load()
+
+	for i := 1; i < maxSkippedBroadcasts; i++ {
+		BroadcastHealth(models.ServiceHealth{InstanceID: "sonarr-slow-" + strconv.Itoa(i), Status: models.StatusOnline})
+	}
+	select {
+	case <-slow.done:
+		t.Fatal("Expected the slow client to be kept until it missed too many updates")
+	default:
+	}
+
+	BroadcastHealth(models.ServiceHealth{InstanceID: "sonarr-slow-last", Status: models.StatusOnline})
+	select {
+	case <-slow.done:
+	default:
+		t.Error("Expected the slow client to be disconnected")
+	}
+	select {
+	case <-fast.done:
+		t.Error("Expected the fast client to stay connected")
+	default:
+	}
+	if len(fast.send) != maxSkippedBroadcasts || fast.skipped.Load() != 0 {
+		t.Errorf("Expected the fast client to get every update, got %d", len(fast.send))
+	}
+
+	if skipped := skippedBroadcasts.Load() - skippedBefore; skipped != maxSkippedBroadcasts {
+		t.Errorf("Expected %d skipped broadcasts, got %d", maxSkippedBroadcasts, skipped)
+	}
+	if disconnects := slowClientDisconnects.Load() - disconnectsBefore; disconnects != 1 {
+		t.Errorf("Expected 1 slow client disconnect, got %d", disconnects)
+	}
+}
+
+func TestBroadcastConstructors_RequiredFields(t *testing.T) {
+	listener := &client{send: make(chan models.ServiceHealth, clientBufferSize), done: make(chan struct{}), connectedAt: time.Now()}
+
+	clientsMu.Lock()
+	clients[listener] = true
+	clientsMu.Unlock()
+
+	t.Cleanup(func() {
+		clientsMu.Lock()
+		delete(clients, listener)
+		clientsMu.Unlock()
+
+		lastBroadcastsMu.Lock()
+		lastBroadcasts = make(map[string]sentBroadcast)
+		lastBroadcastsMu.Unlock()
+	})
+
+	autobrr := &AutobrrHandler{}
+	autobrr.broadcastReleases("autobrr-1", types.ReleasesResponse{})
+	autobrr.broadcastStats("autobrr-1", types.AutobrrStats{})
+	autobrr.broadcastIRCStatus("autobrr-1", []types.IRCStatus{})
+	autobrr.broadcastIRCDetail("autobrr-1", types.IRCDetailResponse{})
+	(&PlexHandler{}).broadcastPlexSessions("plex-1", &types.PlexSessionsResponse{})
+	(&OverseerrHandler{}).broadcastOverseerrRequests("overseerr-1", &types.RequestsStats{})
+	prowlarr := &ProwlarrHandler{}
+	prowlarr.broadcastStats("prowlarr-1", types.ProwlarrStatsResponse{})
+	prowlarr.broadcastIndexers("prowlarr-1", []types.ProwlarrIndexer{}, nil)
+	(&RadarrHandler{}).broadcastRadarrQueue("radarr-1", &types.RadarrQueueResponse{})
+	sonarr := &SonarrHandler{}
+	sonarr.broadcastSonarrQueue("sonarr-1", &types.SonarrQueueResponse{})
+	sonarr.broadcastSonarrStats("sonarr-1", &types.SonarrStatsResponse{}, "4.0.0")
+
+	// The failing indexer alert spans every Prowlarr instance and belongs to none
+	prowlarr.updateFailingAlert(types.FailingIndexersResponse{Alert: true})
+
+	// A stalled item first seen an hour ago is removed by its cleanup rule right away
+	ctx := context.Background()
+	store := setupTestCache(t)
+	if err := SetQueueCleanupRule("sonarr-1", "stalled", time.Minute); err != nil {
+		t.Fatalf("Failed to set queue cleanup rule: %v", err)
+	}
+	t.Cleanup(func() {
+		queueCleanupRulesMu.Lock()
+		delete(queueCleanupRules, "sonarr-1")
+		queueCleanupRulesMu.Unlock()
+	})
+	if err := store.Set(ctx, queueStalledPrefix+"sonarr-1", map[int]time.Time{1: time.Now().Add(-time.Hour)}, queueStalledTTL); err != nil {
+		t.Fatalf("Failed to seed stalled queue item: %v", err)
+	}
+	stalled := []queueCandidate{{ID: 1, Title: "Show.S01E01", Status: "warning", Messages: []string{"stalled"}}}
+	cleanupQueue(ctx, store, "sonarr-1", stalled, func(int) error { return nil })
+
+	// Every broadcast built in place has to be triggered above, so a new one can't go unchecked
+	sites := literalBroadcasts(t)
+	if len(listener.send) != len(sites) {
+		t.Fatalf("Expected %d broadcasts, one for each of %v, got %d", len(sites), sites, len(listener.send))
+	}
+	for range sites {
+		health := <-listener.send
+		if health.Message == "" || health.Status == "" || health.LastChecked.IsZero() || health.EventID == 0 {
+			t.Errorf("Broadcast %q is missing required fields: %+v", health.Message, health)
+		}
+		if health.InstanceID == "" && health.Message != "prowlarr_indexers_failing" {
+			t.Errorf("Broadcast %q is missing the instance id", health.Message)
+		}
+	}
+}
+
+// literalBroadcasts returns the positions of the BroadcastHealth calls in the handlers that
+// build their message in place rather than passing on the result of a health check
+func literalBroadcasts(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list handler sources: %v", err)
+	}
+
+	var sites []string
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "BroadcastHealth" {
+				return true
+			}
+			if _, ok := call.Args[0].(*ast.CompositeLit); ok {
+				sites = append(sites, fset.Position(call.Pos()).String())
+			}
+			return true
+		})
+	}
+	return sites
+}
+
+func TestEventsHandler_Shutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, _ := setupEventsHandler(t)
+	t.Cleanup(func() { shuttingDown.Store(false) })
+
+	handler.StartHealthMonitor()
+	if cleanupTicker == nil || monitorCtx == nil {
+		t.Fatal("Expected the monitor and cleanup tickers to be running")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, w := newTestContext("GET", "/api/health/events", nil)
+	c.Request = c.Request.WithContext(ctx)
+
+	streamDone := make(chan struct{})
+	go func() {
+		handler.StreamHealth(c)
+		close(streamDone)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for activeClients.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	handler.Shutdown()
+
+	select {
+	case <-streamDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the stream to end on shutdown")
+	}
+
+	if monitorCtx.Err() == nil {
+		t.Error("Expected the health monitor to be stopped")
+	}
+	if cleanupTicker != nil {
+		t.Error("Expected the client cleanup ticker to be stopped")
+	}
+	if body := w.Body.String(); !strings.Contains(body, "event:shutdown") {
+		t.Errorf("Expected a final shutdown event, got %q", body)
+	}
+
+	// New streams are refused while shutting down
+	c, w = newTestContext("GET", "/api/health/events", nil)
+	handler.StreamHealth(c)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestDueServices(t *testing.T) {
+	now := time.Now()
+
+	lastChecksMu.Lock()
+	lastChecks["sonarr-recent"] = now.Add(-minCheckInterval)
+	lastChecks["plex-slow"] = now.Add(-time.Minute)
+	lastChecks["radarr-slow"] = now.Add(-5 * time.Minute)
+	lastChecksMu.Unlock()
+	t.Cleanup(func() {
+		lastChecksMu.Lock()
+		delete(lastChecks, "sonarr-recent")
+		delete(lastChecks, "plex-slow")
+		delete(lastChecks, "radarr-slow")
+		lastChecksMu.Unlock()
+	})
+
+	services := []models.ServiceConfiguration{
+		{InstanceID: "sonarr-recent"},
+		{InstanceID: "sonarr-new"},
+		{InstanceID: "plex-slow", CheckIntervalSeconds: 300},
+		{InstanceID: "radarr-slow", CheckIntervalSeconds: 300},
+	}
+
+	var due []string
+	for _, svc := range dueServices(services, now) {
+		due = append(due, svc.InstanceID)
+	}
+
+	want := []string{"sonarr-recent", "sonarr-new", "radarr-slow"}
+	if strings.Join(due, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v to be due, got %v", want, due)
+	}
+}
+
+func TestSplitInitialChecks(t *testing.T) {
+	lastChecksMu.Lock()
+	lastChecks["sonarr-checked"] = time.Now()
+	lastChecksMu.Unlock()
+	t.Cleanup(func() {
+		lastChecksMu.Lock()
+		delete(lastChecks, "sonarr-checked")
+		lastChecksMu.Unlock()
+		SetInitialCheckConcurrency(0)
+	})
+
+	services := []models.ServiceConfiguration{
+		{InstanceID: "sonarr-checked"},
+		{InstanceID: "radarr-new"},
+		{InstanceID: "plex-new"},
+	}
+
+	initial, checked := splitInitialChecks(services)
+	if len(initial) != 2 || initial[0].InstanceID != "radarr-new" || initial[1].InstanceID != "plex-new" {
+		t.Errorf("Expected the never checked services in the initial sweep, got %v", initial)
+	}
+	if len(checked) != 1 || checked[0].InstanceID != "sonarr-checked" {
+		t.Errorf("Expected the checked service to use the steady-state limit, got %v", checked)
+	}
+
+	if got := cap(*initialCheckSemaphore.Load()); got != defaultInitialCheckConcurrency {
+		t.Errorf("Expected the default initial concurrency %d, got %d", defaultInitialCheckConcurrency, got)
+	}
+	SetInitialCheckConcurrency(8)
+	if got := cap(*initialCheckSemaphore.Load()); got != 8 {
+		t.Errorf("Expected an initial concurrency of 8, got %d", got)
+	}
+}
+
+func TestIsDuplicateBroadcast(t *testing.T) {
+	t.Cleanup(func() {
+		lastBroadcastsMu.Lock()
+		lastBroadcasts = make(map[string]sentBroadcast)
+		lastBroadcastsMu.Unlock()
+	})
+
+	now := time.Now()
+	health := models.ServiceHealth{InstanceID: "sonarr-dedup", Status: models.StatusOnline, ResponseTime: 12, LastChecked: now}
+
+	if isDuplicateBroadcast(health, now) {
+		t.Fatal("Expected the first update to be sent")
+	}
+
+	// Only the check and response times changed
+	health.ResponseTime, health.LastChecked = 40, now.Add(minCheckInterval)
+	if !isDuplicateBroadcast(health, now.Add(minCheckInterval)) {
+		t.Error("Expected an unchanged update to be dropped")
+	}
+
+	// Typed updates of the same service are tracked separately
+	stats := models.ServiceHealth{InstanceID: "sonarr-dedup", Status: models.StatusOnline, Message: "sonarr_stats"}
+	if isDuplicateBroadcast(stats, now) {
+		t.Error("Expected a typed update to be sent")
+	}
+
+	health.Status = models.StatusWarning
+	if isDuplicateBroadcast(health, now.Add(2*minCheckInterval)) {
+		t.Error("Expected a changed update to be sent")
+	}
+	if !isDuplicateBroadcast(health, now.Add(3*minCheckInterval)) {
+		t.Error("Expected the repeated update to be dropped")
+	}
+	if isDuplicateBroadcast(health, now.Add(2*minCheckInterval+broadcastRefreshInterval)) {
+		t.Error("Expected an unchanged update to be sent again after the refresh interval")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+)
+
+const (
+	// maxTrackedHashes bounds a tracker, the least recently updated instance is evicted first
+	maxTrackedHashes = 256
+
+	// hashPruneInterval is how often a tracker drops the hashes of deleted instances
+	hashPruneInterval = 10 * time.Minute
+)
+
+type hashEntry struct {
+	hash    string
+	updated time.Time
+}
+
+// hashTracker remembers the last seen hash of a response per instance, so handlers only log
+// and broadcast real changes. It is safe for concurrent use and cleans up after itself,
+// hashes of instances that no longer exist are pruned periodically.
+type hashTracker struct {
+	db *database.DB
+
+	mu        sync.Mutex
+	entries   map[string]hashEntry
+	lastPrune time.Time
+	pruning   bool
+}
+
+func newHashTracker(db *database.DB) *hashTracker {
+	return &hashTracker{
+		db:        db,
+		entries:   make(map[string]hashEntry),
+		lastPrune: time.Now(),
+	}
+}
+
+// Get returns the last hash of the instance, or an empty string when there is none
+func (t *hashTracker) Get(instanceID string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries[instanceID].hash
+}
+
+// Set stores the hash of the instance
+func (t *hashTracker) Set(instanceID, hash string) {
+	t.Swap(instanceID, hash)
+}
+
+// Swap stores the hash of the instance and returns the previous one
+func (t *hashTracker) Swap(instanceID, hash string) string {
+	now := time.Now()
+
+	t.mu.Lock()
+	previous := t.entries[instanceID].hash
+	t.entries[instanceID] = hashEntry{hash: hash, updated: now}
+	if len(t.entries) > maxTrackedHashes {
+		t.evictOldest()
+	}
+
+	prune := t.db != nil && !t.pruning && now.Sub(t.lastPrune) >= hashPruneInterval
+	if prune {
+		t.pruning = true
+		t.lastPrune = now
+	}
+	t.mu.Unlock()
+
+	if prune {
+		go t.prune()
+	}
+
+	return previous
+}
+
+// evictOldest drops the least recently updated entry, t.mu must be held
+func (t *hashTracker) evictOldest() {
+	var oldest string
+	var oldestTime time.Time
+	for instanceID, entry := range t.entries {
+		if oldest == "" || entry.updated.Before(oldestTime) {
+			oldest, oldestTime = instanceID, entry.updated
+		}
+	}
+	delete(t.entries, oldest)
+}
+
+// prune drops the hashes of instances that are no longer configured
+func (t *hashTracker) prune() {
+	defer func() {
+		t.mu.Lock()
+		t.pruning = false
+		t.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	services, err := t.db.GetAllServices(ctx, true)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to list services for hash pruning")
+		return
+	}
+
+	configured := make(map[string]bool, len(services))
+	for _, service := range services {
+		configured[service.InstanceID] = true
+	}
+
+	t.mu.Lock()
+	for instanceID := range t.entries {
+		if !configured[instanceID] {
+			delete(t.entries, instanceID)
+		}
+	}
+	t.mu.Unlock()
+}
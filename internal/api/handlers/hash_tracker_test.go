@@ -0,0 +1,58 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+func TestHashTracker(t *testing.T) {
+	tracker := newHashTracker(nil)
+
+	if previous := tracker.Swap("sonarr-1", "a"); previous != "" {
+		t.Errorf("Expected no previous hash, got %q", previous)
+	}
+	if previous := tracker.Swap("sonarr-1", "b"); previous != "a" {
+		t.Errorf("Expected previous hash %q, got %q", "a", previous)
+	}
+	if hash := tracker.Get("sonarr-1"); hash != "b" {
+		t.Errorf("Expected hash %q, got %q", "b", hash)
+	}
+
+	// The tracker is bounded, the least recently updated instance goes first
+	for i := 0; i < maxTrackedHashes; i++ {
+		tracker.Set(fmt.Sprintf("radarr-%d", i), "hash")
+	}
+	if len(tracker.entries) != maxTrackedHashes {
+		t.Errorf("Expected %d entries, got %d", maxTrackedHashes, len(tracker.entries))
+	}
+	if hash := tracker.Get("sonarr-1"); hash != "" {
+		t.Errorf("Expected the oldest entry to be evicted, got %q", hash)
+	}
+}
+
+func TestHashTrackerPrune(t *testing.T) {
+	_, db := setupSettingsHandler(t)
+
+	if err := db.CreateService(context.Background(), &models.ServiceConfiguration{InstanceID: "sonarr-1", URL: "http://sonarr"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	tracker := newHashTracker(db)
+	tracker.Set("sonarr-1", "a")
+	tracker.Set("sonarr-2", "b")
+
+	tracker.prune()
+
+	if hash := tracker.Get("sonarr-1"); hash != "a" {
+		t.Errorf("Expected the hash of a configured instance to be kept, got %q", hash)
+	}
+	if hash := tracker.Get("sonarr-2"); hash != "" {
+		t.Errorf("Expected the hash of a deleted instance to be pruned, got %q", hash)
+	}
+}
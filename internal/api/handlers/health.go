@@ -5,6 +5,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services"
+	"github.com/autobrr/dashbrr/internal/services/arr"
+	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/types"
 )
 
@@ -25,10 +28,11 @@ type DatabaseService interface {
 type HealthHandler struct {
 	db             DatabaseService
 	health         *services.HealthService
+	cache          cache.Store
 	serviceCreator models.ServiceCreator
 }
 
-func NewHealthHandler(db DatabaseService, health *services.HealthService, creator ...models.ServiceCreator) *HealthHandler {
+func NewHealthHandler(db DatabaseService, health *services.HealthService, store cache.Store, creator ...models.ServiceCreator) *HealthHandler {
 	var sc models.ServiceCreator
 	if len(creator) > 0 {
 		sc = creator[0]
@@ -39,6 +43,7 @@ func NewHealthHandler(db DatabaseService, health *services.HealthService, creato
 	return &HealthHandler{
 		db:             db,
 		health:         health,
+		cache:          store,
 		serviceCreator: sc,
 	}
 }
@@ -91,14 +96,30 @@ func (h *HealthHandler) CheckHealth(c *gin.Context) {
 	// Check if service is configured
 	if service.URL == "" {
 		c.JSON(http.StatusOK, models.ServiceHealth{
-			Status:      "unconfigured",
+			Status:      models.StatusUnconfigured,
 			Message:     "Service is not configured",
-			ServiceID:   serviceID,
+			InstanceID:  serviceID,
 			LastChecked: time.Now(),
 		})
 		return
 	}
 
+	if service.Disabled {
+		c.JSON(http.StatusOK, models.ServiceHealth{
+			Status:      models.StatusDisabled,
+			Message:     "Service is disabled",
+			InstanceID:  serviceID,
+			LastChecked: time.Now(),
+		})
+		return
+	}
+
+	// Upstream checks are paused in maintenance mode, answer with the last known health
+	if MaintenanceActive() {
+		c.JSON(http.StatusOK, h.maintenanceHealth(ctx, serviceID))
+		return
+	}
+
 	// Validate service ID format and extract service type
 	parts := strings.Split(serviceID, "-")
 	if len(parts) == 0 {
@@ -113,15 +134,8 @@ func (h *HealthHandler) CheckHealth(c *gin.Context) {
 		return
 	}
 
-	// For general service, API key is optional
-	// For other services, ensure API key is provided
-	if serviceType != "general" && service.APIKey == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "API key is required for this service type",
-		})
-		return
-	}
+	// Services that need an API key report a missing one as StatusUnconfigured
+	service.Configure(serviceChecker)
 
 	// Use the context with timeout for health check
 	health, statusCode := serviceChecker.CheckHealth(ctx, service.URL, service.APIKey)
@@ -136,26 +150,102 @@ func (h *HealthHandler) CheckHealth(c *gin.Context) {
 		return
 	}
 
-	// Enhance error handling for specific status codes
+	// Report the upstream status code along with whatever the service said about it
 	if statusCode != http.StatusOK {
-		var errorMessage string
-		switch statusCode {
-		case http.StatusUnauthorized:
-			errorMessage = "Invalid API key"
-		case http.StatusNotFound:
-			errorMessage = "Service not found at the specified URL"
-		case http.StatusServiceUnavailable:
-			errorMessage = "Service is unavailable"
-		default:
-			errorMessage = "Failed to check service health"
-		}
-
 		c.JSON(statusCode, gin.H{
-			"status":  "error",
-			"message": errorMessage,
+			"status":  models.StatusForResponseCode(statusCode),
+			"message": describeStatusCode(statusCode),
+			"detail":  health.Message,
 		})
 		return
 	}
 
+	service.CheckExpectedVersion(&health)
+	health.Muted = service.IsMuted(time.Now())
+	health.Pinned = service.Pinned
+	health.Color = service.Color
+	health.Icon = service.Icon
+	health.Maintenance = MaintenanceActive()
+
 	c.JSON(http.StatusOK, health)
 }
+
+// maintenanceHealth returns the cached health of a service marked as under maintenance,
+// or an unknown status when nothing is cached for it
+func (h *HealthHandler) maintenanceHealth(ctx context.Context, instanceID string) models.ServiceHealth {
+	var health models.ServiceHealth
+	if h.cache == nil || h.cache.Get(ctx, cache.PrefixHealth+instanceID, &health) != nil {
+		health = models.ServiceHealth{
+			Status:     models.StatusUnknown,
+			Message:    "Health checks are paused for maintenance",
+			InstanceID: instanceID,
+		}
+	}
+	health.Maintenance = true
+	return health
+}
+
+// healthIssuesReporter is implemented by services that report issues about
+// themselves, i.e. Sonarr, Radarr and Prowlarr
+type healthIssuesReporter interface {
+	GetHealthIssues(ctx context.Context, url, apiKey string) ([]arr.HealthResponse, error)
+}
+
+// GetHealthIssues returns the warnings and errors a service reports about itself
+func (h *HealthHandler) GetHealthIssues(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	serviceID := c.Param("service")
+	serviceType, _, _ := strings.Cut(serviceID, "-")
+
+	serviceChecker := h.serviceCreator.CreateService(serviceType)
+	reporter, ok := serviceChecker.(healthIssuesReporter)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Service type does not report health issues: " + serviceType})
+		return
+	}
+
+	service, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: serviceID})
+	if err != nil {
+		log.Error().Err(err).Str("service", serviceID).Msg("Failed to fetch service configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service configuration"})
+		return
+	}
+	if service == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+	if service.URL == "" || service.APIKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Service is not configured"})
+		return
+	}
+
+	service.Configure(serviceChecker)
+
+	issues, err := reporter.GetHealthIssues(ctx, service.URL, service.APIKey)
+	if err != nil {
+		log.Error().Err(err).Str("service", serviceID).Msg("Failed to fetch health issues")
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, issues)
+}
+
+// describeStatusCode turns a non-200 upstream status code into a message with a hint
+// at the likely cause, e.g. "401 Unauthorized (check API key)"
+func describeStatusCode(statusCode int) string {
+	message := fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+	if http.StatusText(statusCode) == "" {
+		message = fmt.Sprintf("Unexpected status code %d", statusCode)
+	}
+
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return message + " (check API key)"
+	case http.StatusNotFound:
+		return message + " (check the URL)"
+	}
+	return message
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const defaultHealthPollWait = 10 * time.Second
+
+var (
+	// How long a poll waits for a new result, see SetHealthPollWait
+	healthPollWait atomic.Int64
+
+	// Closed and replaced whenever a health result is cached, wakes the waiting polls
+	healthCached   = make(chan struct{})
+	healthCachedMu sync.Mutex
+)
+
+func init() {
+	SetHealthPollWait(0)
+}
+
+// SetHealthPollWait sets how many seconds GET /api/health/poll waits for a new result
+// before it returns empty handed. 0 or less uses the default of 10.
+func SetHealthPollWait(seconds int) {
+	wait := defaultHealthPollWait
+	if seconds > 0 {
+		wait = time.Duration(seconds) * time.Second
+	}
+	healthPollWait.Store(int64(wait))
+}
+
+// healthCachedSignal returns a channel that is closed once the next health result is cached
+func healthCachedSignal() <-chan struct{} {
+	healthCachedMu.Lock()
+	defer healthCachedMu.Unlock()
+	return healthCached
+}
+
+// notifyHealthCached wakes the polls waiting for a new result
+func notifyHealthCached() {
+	healthCachedMu.Lock()
+	defer healthCachedMu.Unlock()
+	close(healthCached)
+	healthCached = make(chan struct{})
+}
+
+// healthSince returns the cached health results with an event id after since. Since 0
+// returns every cached result, including those cached without an id.
+func (h *EventsHandler) healthSince(ctx context.Context, since uint64) ([]models.ServiceHealth, error) {
+	results, err := h.cachedHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if since == 0 {
+		return results, nil
+	}
+
+	updates := results[:0]
+	for _, health := range results {
+		if health.EventID > since {
+			updates = append(updates, health)
+		}
+	}
+	return updates, nil
+}
+
+// PollHealth is the fallback for clients that can't keep the SSE stream open, e.g. behind
+// proxies that buffer responses. It returns the health results cached after the since
+// cursor and the cursor to poll with next. When there are none yet it waits up to the poll
+// wait for one, so a polling client isn't hammering the server. Without a cursor every
+// cached result is returned right away. The cursor is the event id of the newest result
+// rather than a timestamp, as results are stamped when their check starts but cached once
+// it ends, and a timestamp cursor would skip the results of checks still in flight.
+func (h *EventsHandler) PollHealth(c *gin.Context) {
+	var since uint64
+	if value := c.Query("since"); value != "" {
+		cursor, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since cursor"})
+			return
+		}
+		since = cursor
+	}
+
+	// Polling clients keep the results fresh the way stream clients do
+	if !healthIsFresh() {
+		h.refreshHealth()
+	}
+
+	wait := time.Duration(healthPollWait.Load())
+
+	// The wait may outlast the server's write timeout, extend it for this request only
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Now().Add(wait + checkTimeout)); err != nil {
+		log.Debug().Err(err).Msg("Failed to extend health poll write deadline")
+	}
+
+	ctx := c.Request.Context()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	var updates []models.ServiceHealth
+poll:
+	for {
+		// Taken before reading the cache so a result cached in between isn't missed
+		cached := healthCachedSignal()
+
+		var err error
+		updates, err = h.healthSince(ctx, since)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load cached health for poll")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+			return
+		}
+		if len(updates) > 0 || since == 0 || shuttingDown.Load() {
+			break
+		}
+
+		select {
+		case <-cached:
+		case <-timer.C:
+			break poll
+		case <-ctx.Done():
+			// Still answer with the unchanged cursor, in case the client is listening
+			break poll
+		}
+	}
+
+	cursor := since
+	maintenance := MaintenanceActive()
+	for i := range updates {
+		updates[i].Maintenance = maintenance
+		cursor = max(cursor, updates[i].EventID)
+	}
+	// Ids are seeded far above 1, so a client without any result yet waits for the first
+	if cursor == 0 {
+		cursor = 1
+	}
+
+	c.JSON(http.StatusOK, types.HealthPollResponse{
+		Updates: updates,
+		Cursor:  cursor,
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/api/middleware"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestEventsHandler_PollHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, _ := setupEventsHandler(t)
+	ctx := context.Background()
+
+	// Keep the poll from starting a background check against the fake services
+	lastFullCheck.Store(time.Now().UnixNano())
+	SetHealthPollWait(1)
+	t.Cleanup(func() { SetHealthPollWait(0) })
+
+	services := []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://radarr:7878"},
+	}
+	for i := range services {
+		if err := handler.db.CreateService(ctx, &services[i]); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	r := gin.New()
+	r.GET("/api/health/poll", handler.PollHealth)
+	poll := func(since string) (types.HealthPollResponse, time.Duration) {
+		t.Helper()
+		start := time.Now()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/health/poll?since="+since, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response types.HealthPollResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+
+		// Browsers read the cursor as a float64, it has to come back unchanged
+		var browser struct {
+			Cursor float64 `json:"cursor"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &browser); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		if uint64(browser.Cursor) != response.Cursor {
+			t.Fatalf("Expected cursor %d to survive a float64 round trip, got %.0f", response.Cursor, browser.Cursor)
+		}
+		return response, time.Since(start)
+	}
+
+	handler.recordHealth(&services[0], &models.ServiceHealth{Status: models.StatusOnline}, http.StatusOK)
+
+	// Without a cursor everything cached comes back right away
+	response, _ := poll("")
+	if len(response.Updates) != 1 || response.Updates[0].InstanceID != "sonarr-1" {
+		t.Fatalf("Expected the cached sonarr result, got %+v", response.Updates)
+	}
+	cursor := strconv.FormatUint(response.Cursor, 10)
+
+	// Nothing new, the poll waits and comes back empty with the same cursor
+	response, elapsed := poll(cursor)
+	if len(response.Updates) != 0 || strconv.FormatUint(response.Cursor, 10) != cursor {
+		t.Errorf("Expected an empty poll with the same cursor, got %+v", response)
+	}
+	if elapsed < 900*time.Millisecond {
+		t.Errorf("Expected the poll to wait for a new result, returned after %v", elapsed)
+	}
+
+	// A result cached while waiting ends the wait
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		handler.recordHealth(&services[1], &models.ServiceHealth{Status: models.StatusOnline}, http.StatusOK)
+	}()
+	response, elapsed = poll(cursor)
+	if len(response.Updates) != 1 || response.Updates[0].InstanceID != "radarr-1" {
+		t.Errorf("Expected only the new radarr result, got %+v", response.Updates)
+	}
+	if elapsed >= 900*time.Millisecond {
+		t.Errorf("Expected the new result to end the wait, returned after %v", elapsed)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/health/poll?since=yesterday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid cursor, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestEventsHandler_PollHealthBehindAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, store := setupEventsHandler(t)
+	ctx := context.Background()
+
+	lastFullCheck.Store(time.Now().UnixNano())
+	// Longer than the 5 second timeout RequireAuth looks the session up with
+	SetHealthPollWait(6)
+	t.Cleanup(func() { SetHealthPollWait(0) })
+
+	if err := store.Set(ctx, cache.PrefixSession+"token", types.SessionData{
+		AuthType:  "builtin",
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, time.Hour); err != nil {
+		t.Fatalf("Failed to seed session: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/api/health/poll", middleware.NewAuthMiddleware(store).RequireAuth(), handler.PollHealth)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/health/poll?since=42", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response types.HealthPollResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected a JSON body after the wait: %v", err)
+	}
+	if len(response.Updates) != 0 || response.Cursor != 42 {
+		t.Errorf("Expected an empty poll with the unchanged cursor, got %+v", response)
+	}
+	if elapsed := time.Since(start); elapsed < 5900*time.Millisecond {
+		t.Errorf("Expected the poll to wait the full poll wait, returned after %v", elapsed)
+	}
+}
@@ -19,6 +19,8 @@ import (
 	testing_mocks "github.com/autobrr/dashbrr/internal/api/handlers/testing"
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services"
+	"github.com/autobrr/dashbrr/internal/services/arr"
+	"github.com/autobrr/dashbrr/internal/services/cache"
 )
 
 // mockServiceHealthChecker implements models.ServiceHealthChecker interface for testing
@@ -36,6 +38,16 @@ func (m *mockServiceHealthChecker) CheckHealth(ctx context.Context, url, apiKey
 	}, http.StatusOK
 }
 
+// mockIssuesChecker is a health checker that also reports health issues, like the arr services
+type mockIssuesChecker struct {
+	mockServiceHealthChecker
+	issues []arr.HealthResponse
+}
+
+func (m *mockIssuesChecker) GetHealthIssues(ctx context.Context, url, apiKey string) ([]arr.HealthResponse, error) {
+	return m.issues, nil
+}
+
 // mockServiceCreator implements models.ServiceCreator interface for testing
 type mockServiceCreator struct {
 	createServiceFunc func(serviceType string) models.ServiceHealthChecker
@@ -112,6 +124,31 @@ func TestHealthHandler_CheckHealth(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: gin.H{"status": "healthy"},
 		},
+		{
+			name:      "Upstream Unauthorized",
+			serviceID: "autobrr-service",
+			mockDBResponse: func(ctx context.Context, params types.FindServiceParams) (*models.ServiceConfiguration, error) {
+				return &models.ServiceConfiguration{
+					ID:         1,
+					InstanceID: "autobrr-service",
+					URL:        "http://localhost:8080",
+					APIKey:     "wrong-key",
+				}, nil
+			},
+			mockHealth: func(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
+				return models.ServiceHealth{
+					Status:      models.StatusError,
+					Message:     "Unauthorized: invalid api key",
+					LastChecked: time.Now(),
+				}, http.StatusUnauthorized
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: gin.H{
+				"status":  "unauthorized",
+				"message": "401 Unauthorized (check API key)",
+				"detail":  "Unauthorized: invalid api key",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -137,7 +174,7 @@ func TestHealthHandler_CheckHealth(t *testing.T) {
 			}
 
 			// Create the handler with our mocks
-			handler := NewHealthHandler(mockDB, services.NewHealthService(), mockCreator)
+			handler := NewHealthHandler(mockDB, services.NewHealthService(), nil, mockCreator)
 
 			// Setup the router
 			r := gin.New()
@@ -168,3 +205,119 @@ func TestHealthHandler_CheckHealth(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthHandler_GetHealthIssues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	checker := &mockIssuesChecker{issues: []arr.HealthResponse{
+		{Source: "IndexerStatusCheck", Type: "warning", Message: "Indexers unavailable due to failures"},
+	}}
+	mockDB := &testing_mocks.MockDB{
+		FindServiceByFunc: func(ctx context.Context, params types.FindServiceParams) (*models.ServiceConfiguration, error) {
+			return &models.ServiceConfiguration{InstanceID: params.InstanceID, URL: "http://localhost:8989", APIKey: "test-key"}, nil
+		},
+	}
+	mockCreator := &mockServiceCreator{
+		createServiceFunc: func(serviceType string) models.ServiceHealthChecker {
+			if serviceType == "sonarr" {
+				return checker
+			}
+			return &mockServiceHealthChecker{}
+		},
+	}
+
+	handler := NewHealthHandler(mockDB, services.NewHealthService(), nil, mockCreator)
+	r := gin.New()
+	r.GET("/health/:service/issues", handler.GetHealthIssues)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/health/sonarr-1/issues", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	var issues []arr.HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&issues); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Source != "IndexerStatusCheck" {
+		t.Errorf("Expected the reported issue, got %+v", issues)
+	}
+
+	// Services that don't report issues are rejected
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/health/autobrr-1/issues", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestHealthHandler_CheckHealthMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	maintenance.Store(&types.MaintenanceStatus{Enabled: true})
+	t.Cleanup(func() { maintenance.Store(nil) })
+
+	store := setupTestCache(t)
+	if err := store.Set(ctx, cache.PrefixHealth+"sonarr-1", models.ServiceHealth{
+		InstanceID: "sonarr-1",
+		Status:     models.StatusOnline,
+		Version:    "4.0.0",
+	}, cache.HealthTTL); err != nil {
+		t.Fatalf("Failed to seed cached health: %v", err)
+	}
+
+	mockDB := &testing_mocks.MockDB{
+		FindServiceByFunc: func(ctx context.Context, params types.FindServiceParams) (*models.ServiceConfiguration, error) {
+			return &models.ServiceConfiguration{InstanceID: params.InstanceID, URL: "http://localhost:8989", APIKey: "test-key"}, nil
+		},
+	}
+	checked := false
+	mockCreator := &mockServiceCreator{
+		createServiceFunc: func(serviceType string) models.ServiceHealthChecker {
+			return &mockServiceHealthChecker{
+				checkHealthFunc: func(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
+					checked = true
+					return models.ServiceHealth{Status: models.StatusOnline}, http.StatusOK
+				},
+			}
+		},
+	}
+
+	handler := NewHealthHandler(mockDB, services.NewHealthService(), store, mockCreator)
+	r := gin.New()
+	r.GET("/health/:service", handler.CheckHealth)
+
+	check := func(serviceID string) models.ServiceHealth {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/"+serviceID, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var health models.ServiceHealth
+		if err := json.NewDecoder(w.Body).Decode(&health); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		return health
+	}
+
+	health := check("sonarr-1")
+	if health.Status != models.StatusOnline || health.Version != "4.0.0" || !health.Maintenance {
+		t.Errorf("Expected the cached health marked as maintenance, got %+v", health)
+	}
+
+	// Nothing cached yet, still no upstream check
+	health = check("radarr-1")
+	if health.Status != models.StatusUnknown || health.InstanceID != "radarr-1" || !health.Maintenance {
+		t.Errorf("Expected an unknown status marked as maintenance, got %+v", health)
+	}
+
+	if checked {
+		t.Error("Expected no upstream health check in maintenance mode")
+	}
+}
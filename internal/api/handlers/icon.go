@@ -0,0 +1,202 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/core"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const (
+	iconCachePrefix    = "icon:"
+	iconCacheTTL       = 24 * time.Hour
+	iconFetchTimeout   = 10 * time.Second
+	maxIconSize        = 1 << 20 // 1 MiB
+	defaultIconPath    = "/favicon.ico"
+	iconCacheControl   = "private, max-age=86400"
+	iconSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; sandbox"
+)
+
+// iconExtensions lists the file extensions accepted for a custom icon path
+var iconExtensions = map[string]bool{
+	".ico":  true,
+	".png":  true,
+	".svg":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+}
+
+// cachedIcon is the cached form of a fetched service icon
+type cachedIcon struct {
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+type IconHandler struct {
+	db    *database.DB
+	cache cache.Store
+	core  core.ServiceCore
+}
+
+func NewIconHandler(db *database.DB, cache cache.Store) *IconHandler {
+	return &IconHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// GetIcon fetches a service's favicon through the backend using its stored credentials,
+// so tiles can show the app icon for instances the browser can't reach directly.
+// An alternative icon path can be given with the path query parameter.
+func (h *IconHandler) GetIcon(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	iconPath := c.DefaultQuery("path", defaultIconPath)
+	if !validIconPath(iconPath) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid icon path"})
+		return
+	}
+	iconPath = path.Clean(iconPath)
+
+	cacheKey := iconCachePrefix + instanceID + ":" + iconPath
+
+	var icon cachedIcon
+	if err := h.cache.Get(c.Request.Context(), cacheKey, &icon); err == nil && len(icon.Data) > 0 {
+		writeIcon(c, icon)
+		return
+	}
+
+	service, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Failed to fetch service configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service configuration"})
+		return
+	}
+
+	if service == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+
+	if service.URL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Service URL is not configured"})
+		return
+	}
+
+	// Built as a path only, so the icon path can't add a query or fragment to the URL
+	iconURL := strings.TrimRight(service.URL, "/") + (&url.URL{Path: iconPath}).EscapedPath()
+
+	icon, err = h.fetchIcon(c.Request.Context(), iconURL, service)
+	if err != nil {
+		log.Debug().Err(err).Str("instance", instanceID).Str("path", iconPath).Msg("Failed to fetch service icon")
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch icon"})
+		return
+	}
+
+	if err := h.cache.Set(c.Request.Context(), cacheKey, icon, iconCacheTTL); err != nil {
+		log.Warn().Err(err).Str("instance", instanceID).Msg("Failed to cache service icon")
+	}
+
+	writeIcon(c, icon)
+}
+
+// validIconPath reports whether p is an absolute path to an image on the service itself. A
+// query, fragment or parent directory could reach other endpoints of the service with its
+// credentials attached.
+func validIconPath(p string) bool {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") || strings.ContainsAny(p, "?#\\") {
+		return false
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	return iconExtensions[strings.ToLower(path.Ext(p))]
+}
+
+func (h *IconHandler) fetchIcon(ctx context.Context, url string, service *models.ServiceConfiguration) (cachedIcon, error) {
+	ctx, cancel := context.WithTimeout(ctx, iconFetchTimeout)
+	defer cancel()
+
+	headers := map[string]string{
+		"Accept": "image/*",
+	}
+	if service.APIKey != "" {
+		headers["auth_header"], headers["auth_value"] = iconAuthHeader(service.InstanceID, service.APIKey)
+		if service.AuthHeaderName != "" {
+			headers["auth_header"] = service.AuthHeaderName
+		}
+	}
+
+	resp, err := h.core.MakeRequestWithContext(ctx, url, service.APIKey, headers)
+	if err != nil {
+		return cachedIcon{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cachedIcon{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(contentType, "image/") {
+		return cachedIcon{}, fmt.Errorf("unexpected content type: %q", resp.Header.Get("Content-Type"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return cachedIcon{}, err
+	}
+	if len(data) > maxIconSize {
+		return cachedIcon{}, fmt.Errorf("icon exceeds %d bytes", maxIconSize)
+	}
+	if len(data) == 0 {
+		return cachedIcon{}, fmt.Errorf("empty icon")
+	}
+
+	return cachedIcon{ContentType: contentType, Data: data}, nil
+}
+
+// iconAuthHeader returns the credential header a service type expects for its API key
+func iconAuthHeader(instanceID, apiKey string) (string, string) {
+	serviceType, _, _ := strings.Cut(instanceID, "-")
+
+	switch serviceType {
+	case "autobrr":
+		return "X-Api-Token", apiKey
+	case "plex":
+		return "X-Plex-Token", apiKey
+	case "general", "tailscale":
+		return "Authorization", "Bearer " + apiKey
+	default:
+		return "X-Api-Key", apiKey
+	}
+}
+
+func writeIcon(c *gin.Context, icon cachedIcon) {
+	c.Header("Cache-Control", iconCacheControl)
+	c.Header("X-Content-Type-Options", "nosniff")
+	// SVG icons may carry scripts, so never let them run in our origin
+	c.Header("Content-Security-Policy", iconSecurityPolicy)
+	c.Data(http.StatusOK, icon.ContentType, icon.Data)
+}
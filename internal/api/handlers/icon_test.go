@@ -0,0 +1,83 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+func TestIconHandler_GetIcon(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/favicon.ico":
+			w.Header().Set("Content-Type", "image/x-icon")
+			w.Write([]byte("icon-bytes"))
+		case "/logo.png":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+
+	db, store := setupTestDB(t)
+
+	if err := db.CreateService(context.Background(), &models.ServiceConfiguration{
+		InstanceID:  "sonarr-1",
+		DisplayName: "Sonarr",
+		URL:         upstream.URL,
+		APIKey:      "secret",
+	}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/api/services/:instanceId/icon", NewIconHandler(db, store).GetIcon)
+
+	tests := []struct {
+		name         string
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Favicon", "/api/services/sonarr-1/icon", http.StatusOK, "icon-bytes"},
+		{"Non-image content type", "/api/services/sonarr-1/icon?path=/logo.png", http.StatusBadGateway, ""},
+		{"Invalid path", "/api/services/sonarr-1/icon?path=/api/v3/system/status", http.StatusBadRequest, ""},
+		{"Query in path", "/api/services/sonarr-1/icon?path=/api/v3/system/status%3Fx.png", http.StatusBadRequest, ""},
+		{"Fragment in path", "/api/services/sonarr-1/icon?path=/api/v3/system/status%23.png", http.StatusBadRequest, ""},
+		{"Parent directory", "/api/services/sonarr-1/icon?path=/static/../api/v3/logo.png", http.StatusBadRequest, ""},
+		{"Unknown service", "/api/services/sonarr-2/icon", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, w.Code)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedCode == http.StatusOK && w.Header().Get("Cache-Control") == "" {
+				t.Error("Expected caching headers on icon response")
+			}
+		})
+	}
+}
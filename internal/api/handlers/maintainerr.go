@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +16,7 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/maintainerr"
 	"github.com/autobrr/dashbrr/internal/types"
@@ -33,8 +33,7 @@ type MaintainerrHandler struct {
 	cache cache.Store
 	sf    *singleflight.Group
 
-	lastCollectionsHash   map[string]string
-	lastCollectionsHashMu sync.Mutex
+	lastCollectionsHash *hashTracker
 }
 
 func NewMaintainerrHandler(db *database.DB, cache cache.Store) *MaintainerrHandler {
@@ -42,7 +41,7 @@ func NewMaintainerrHandler(db *database.DB, cache cache.Store) *MaintainerrHandl
 		db:                  db,
 		cache:               cache,
 		sf:                  &singleflight.Group{},
-		lastCollectionsHash: make(map[string]string),
+		lastCollectionsHash: newHashTracker(db),
 	}
 }
 
@@ -133,23 +132,18 @@ func (h *MaintainerrHandler) detectCollectionChanges(oldHash, newHash string) st
 
 // compareAndLogCollectionChanges tracks and logs changes in Maintainerr collections
 func (h *MaintainerrHandler) compareAndLogCollectionChanges(instanceId string, collections []maintainerr.Collection) {
-	h.lastCollectionsHashMu.Lock()
-	defer h.lastCollectionsHashMu.Unlock()
-
 	currentHash := createCollectionsHash(collections)
-	lastHash := h.lastCollectionsHash[instanceId]
+	lastHash := h.lastCollectionsHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		// Detect specific changes
 		changes := h.detectCollectionChanges(lastHash, currentHash)
 
-		log.Info().
+		logger.Changes().Info().
 			Str("instanceId", instanceId).
 			Int("count", len(collections)).
 			Str("change", changes).
 			Msg("Maintainerr collections changed")
-
-		h.lastCollectionsHash[instanceId] = currentHash
 	}
 }
 
@@ -212,9 +206,8 @@ func (h *MaintainerrHandler) GetMaintainerrCollections(c *gin.Context) {
 	// Add change detection logging
 	h.compareAndLogCollectionChanges(instanceId, collections)
 
-	h.lastCollectionsHashMu.Lock()
 	currentHash := createCollectionsHash(collections)
-	lastHash := h.lastCollectionsHash[instanceId]
+	lastHash := h.lastCollectionsHash.Swap(instanceId, currentHash)
 
 	// Only log when there are collections and the hash has changed
 	if (lastHash == "" || currentHash != lastHash) && len(collections) > 0 {
@@ -228,9 +221,7 @@ func (h *MaintainerrHandler) GetMaintainerrCollections(c *gin.Context) {
 		log.Debug().
 			Str("instanceId", instanceId).
 			Msg("Maintainerr collections changed")
-		h.lastCollectionsHash[instanceId] = currentHash
 	}
-	h.lastCollectionsHashMu.Unlock()
 
 	c.JSON(http.StatusOK, collections)
 }
@@ -294,9 +285,8 @@ func (h *MaintainerrHandler) refreshCollectionsCache(instanceId, cacheKey string
 	// Add change detection logging
 	h.compareAndLogCollectionChanges(instanceId, collections)
 
-	h.lastCollectionsHashMu.Lock()
 	currentHash := createCollectionsHash(collections)
-	lastHash := h.lastCollectionsHash[instanceId]
+	lastHash := h.lastCollectionsHash.Swap(instanceId, currentHash)
 
 	// Only log when there are collections and the hash has changed
 	if (lastHash == "" || currentHash != lastHash) && len(collections) > 0 {
@@ -310,7 +300,5 @@ func (h *MaintainerrHandler) refreshCollectionsCache(instanceId, cacheKey string
 		log.Debug().
 			Str("instanceId", instanceId).
 			Msg("Maintainerr collections changed")
-		h.lastCollectionsHash[instanceId] = currentHash
 	}
-	h.lastCollectionsHashMu.Unlock()
 }
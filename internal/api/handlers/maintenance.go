@@ -0,0 +1,118 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+// maintenanceSettingKey is the settings key maintenance mode is persisted under
+const maintenanceSettingKey = "maintenance"
+
+// maintenance holds the current maintenance state, nil while dashbrr runs normally
+var maintenance atomic.Pointer[types.MaintenanceStatus]
+
+// MaintenanceActive reports whether dashbrr is in maintenance mode. The health monitor
+// skips its upstream checks while it is.
+func MaintenanceActive() bool {
+	return maintenance.Load() != nil
+}
+
+// maintenanceStatus returns the current maintenance state
+func maintenanceStatus() types.MaintenanceStatus {
+	if status := maintenance.Load(); status != nil {
+		return *status
+	}
+	return types.MaintenanceStatus{}
+}
+
+type MaintenanceHandler struct {
+	db *database.DB
+}
+
+func NewMaintenanceHandler(db *database.DB) *MaintenanceHandler {
+	return &MaintenanceHandler{db: db}
+}
+
+// Load restores the maintenance state persisted before the last restart
+func (h *MaintenanceHandler) Load(ctx context.Context) error {
+	value, ok, err := h.db.GetSetting(ctx, maintenanceSettingKey)
+	if err != nil || !ok {
+		maintenance.Store(nil)
+		return err
+	}
+
+	var status types.MaintenanceStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		return err
+	}
+	status.Enabled = true
+	maintenance.Store(&status)
+
+	log.Warn().Str("reason", status.Reason).Msg("Maintenance mode is on, health checks are paused")
+	return nil
+}
+
+// GetMaintenance reports whether maintenance mode is on
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, maintenanceStatus())
+}
+
+// EnableMaintenance turns maintenance mode on, pausing the health monitor until it is
+// turned off again. Calling it while maintenance mode is on updates the reason.
+func (h *MaintenanceHandler) EnableMaintenance(c *gin.Context) {
+	var req types.MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	since := time.Now()
+	if current := maintenance.Load(); current != nil && current.Since != nil {
+		since = *current.Since
+	}
+	status := types.MaintenanceStatus{Enabled: true, Since: &since, Reason: req.Reason}
+
+	value, err := json.Marshal(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if err := h.db.SetSetting(c.Request.Context(), maintenanceSettingKey, string(value)); err != nil {
+		log.Error().Err(err).Msg("Failed to persist maintenance mode")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable maintenance mode"})
+		return
+	}
+	maintenance.Store(&status)
+
+	log.Warn().Str("reason", status.Reason).Str("clientIP", c.ClientIP()).Msg("Maintenance mode enabled, health checks are paused")
+	c.JSON(http.StatusOK, status)
+}
+
+// DisableMaintenance turns maintenance mode off and resumes the health monitor
+func (h *MaintenanceHandler) DisableMaintenance(c *gin.Context) {
+	if err := h.db.DeleteSetting(c.Request.Context(), maintenanceSettingKey); err != nil {
+		log.Error().Err(err).Msg("Failed to clear maintenance mode")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable maintenance mode"})
+		return
+	}
+
+	if maintenance.Swap(nil) != nil {
+		log.Info().Str("clientIP", c.ClientIP()).Msg("Maintenance mode disabled, health checks resume")
+	}
+
+	c.JSON(http.StatusOK, maintenanceStatus())
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	settings, db := setupSettingsHandler(t)
+	handler := NewMaintenanceHandler(db)
+	t.Cleanup(func() { maintenance.Store(nil) })
+
+	router := gin.New()
+	router.GET("/api/admin/maintenance", handler.GetMaintenance)
+	router.POST("/api/admin/maintenance", handler.EnableMaintenance)
+	router.DELETE("/api/admin/maintenance", handler.DisableMaintenance)
+
+	request := func(method, body string) types.MaintenanceStatus {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/api/admin/maintenance", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", method, w.Code, w.Body.String())
+		}
+		var status types.MaintenanceStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to decode status: %v", err)
+		}
+		return status
+	}
+
+	if status := request(http.MethodGet, ""); status.Enabled {
+		t.Fatal("Expected maintenance mode to be off initially")
+	}
+
+	status := request(http.MethodPost, `{"reason":"upgrading sonarr"}`)
+	if !status.Enabled || status.Since == nil || status.Reason != "upgrading sonarr" {
+		t.Fatalf("Unexpected status after enabling: %+v", status)
+	}
+
+	ctx := context.Background()
+	if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://localhost:1"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	events := NewEventsHandler(db, nil, settings.cache)
+	if results := events.checkAndBroadcastHealth(ctx); results != nil {
+		t.Errorf("Expected no health checks in maintenance mode, got %d results", len(results))
+	}
+
+	// The flag survives a restart
+	maintenance.Store(nil)
+	if err := handler.Load(ctx); err != nil {
+		t.Fatalf("Failed to load maintenance mode: %v", err)
+	}
+	if status := request(http.MethodGet, ""); !status.Enabled || status.Reason != "upgrading sonarr" {
+		t.Errorf("Expected maintenance mode to be restored, got %+v", status)
+	}
+
+	if status := request(http.MethodDelete, ""); status.Enabled {
+		t.Error("Expected maintenance mode to be off after DELETE")
+	}
+	if err := handler.Load(ctx); err != nil {
+		t.Fatalf("Failed to load maintenance mode: %v", err)
+	}
+	if MaintenanceActive() {
+		t.Error("Expected maintenance mode to stay off after a restart")
+	}
+}
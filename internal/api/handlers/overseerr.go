@@ -10,7 +10,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -32,15 +31,14 @@ type OverseerrHandler struct {
 	cache cache.Store
 	sf    singleflight.Group
 
-	lastRequestsHash map[string]string
-	hashMu           sync.Mutex
+	lastRequestsHash *hashTracker
 }
 
 func NewOverseerrHandler(db *database.DB, cache cache.Store) *OverseerrHandler {
 	return &OverseerrHandler{
 		db:               db,
 		cache:            cache,
-		lastRequestsHash: make(map[string]string),
+		lastRequestsHash: newHashTracker(db),
 	}
 }
 
@@ -85,7 +83,7 @@ func (h *OverseerrHandler) UpdateRequestStatus(c *gin.Context) {
 	}
 
 	// Get service configuration
-	overseerrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+	overseerrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		log.Error().Err(err).Str("instanceId", instanceId).Msg("Failed to get service configuration")
 		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
@@ -126,7 +124,7 @@ func (h *OverseerrHandler) UpdateRequestStatus(c *gin.Context) {
 	// Fetch fresh data and broadcast update using singleflight
 	sfKey = fmt.Sprintf("requests:%s", instanceId)
 	statsI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheRequests(instanceId, cacheKey)
+		return h.fetchAndCacheRequests(context.Background(), instanceId, cacheKey)
 	})
 
 	if err == nil && statsI != nil {
@@ -153,6 +151,7 @@ func (h *OverseerrHandler) GetRequests(c *gin.Context) {
 	}
 
 	cacheKey := overseerrCachePrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -163,7 +162,7 @@ func (h *OverseerrHandler) GetRequests(c *gin.Context) {
 			Str("instanceId", instanceId).
 			Int("size", len(response.Requests)).
 			Msg("Serving Overseerr requests from cache")
-		c.JSON(http.StatusOK, response)
+		writeRequests(c, response)
 
 		// Refresh cache in background using singleflight
 		go func() {
@@ -179,13 +178,13 @@ func (h *OverseerrHandler) GetRequests(c *gin.Context) {
 	// If not in cache, fetch from service using singleflight
 	sfKey := fmt.Sprintf("requests:%s", instanceId)
 	statsI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheRequests(instanceId, cacheKey)
+		return h.fetchAndCacheRequests(ctx, instanceId, cacheKey)
 	})
 
 	if err != nil {
 		if err.Error() == "service not configured" {
 			// Return empty response for unconfigured service
-			c.JSON(http.StatusOK, &types.RequestsStats{
+			writeRequests(c, &types.RequestsStats{
 				PendingCount: 0,
 				Requests:     []types.MediaRequest{},
 			})
@@ -206,9 +205,8 @@ func (h *OverseerrHandler) GetRequests(c *gin.Context) {
 	stats := statsI.(*types.RequestsStats)
 
 	if stats != nil {
-		h.hashMu.Lock()
 		currentHash, changes := createOverseerrRequestsHash(stats)
-		lastHash := h.lastRequestsHash[instanceId]
+		lastHash := h.lastRequestsHash.Get(instanceId)
 
 		// Only log and update if there are requests and the hash has changed
 		if len(stats.Requests) > 0 && (lastHash == "" || currentHash != lastHash) {
@@ -226,9 +224,8 @@ func (h *OverseerrHandler) GetRequests(c *gin.Context) {
 			}
 
 			// Update the last hash
-			h.lastRequestsHash[instanceId] = currentHash
+			h.lastRequestsHash.Set(instanceId, currentHash)
 		}
-		h.hashMu.Unlock()
 
 		// Broadcast the fresh data
 		h.broadcastOverseerrRequests(instanceId, stats)
@@ -238,11 +235,31 @@ func (h *OverseerrHandler) GetRequests(c *gin.Context) {
 			Msg("Retrieved empty Overseerr requests")
 	}
 
+	writeRequests(c, stats)
+}
+
+// writeRequests responds with the request statistics, or with a page of the requests
+// when limit or offset is given
+func writeRequests(c *gin.Context, stats *types.RequestsStats) {
+	limit, offset, paged, err := queryPage(c, maxListPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if paged {
+		var requests []types.MediaRequest
+		if stats != nil {
+			requests = stats.Requests
+		}
+		c.JSON(http.StatusOK, types.NewPagedResponse(requests, limit, offset))
+		return
+	}
 	c.JSON(http.StatusOK, stats)
 }
 
-func (h *OverseerrHandler) fetchAndCacheRequests(instanceId, cacheKey string) (*types.RequestsStats, error) {
-	overseerrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+func (h *OverseerrHandler) fetchAndCacheRequests(ctx context.Context, instanceId, cacheKey string) (*types.RequestsStats, error) {
+	overseerrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		return nil, err
 	}
@@ -253,8 +270,9 @@ func (h *OverseerrHandler) fetchAndCacheRequests(instanceId, cacheKey string) (*
 
 	service := &overseerr.OverseerrService{}
 	service.SetDB(h.db)
+	service.SetCache(h.cache)
 
-	stats, err := service.GetRequests(context.Background(), overseerrConfig.URL, overseerrConfig.APIKey)
+	stats, err := service.GetRequests(ctx, overseerrConfig.URL, overseerrConfig.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -269,7 +287,6 @@ func (h *OverseerrHandler) fetchAndCacheRequests(instanceId, cacheKey string) (*
 	}
 
 	// Cache the results using the centralized cache duration
-	ctx := context.Background()
 	if err := h.cache.Set(ctx, cacheKey, stats, middleware.CacheDurations.OverseerrRequests); err != nil {
 		log.Warn().
 			Err(err).
@@ -281,7 +298,7 @@ func (h *OverseerrHandler) fetchAndCacheRequests(instanceId, cacheKey string) (*
 }
 
 func (h *OverseerrHandler) refreshRequestsCache(instanceId, cacheKey string) {
-	stats, err := h.fetchAndCacheRequests(instanceId, cacheKey)
+	stats, err := h.fetchAndCacheRequests(context.Background(), instanceId, cacheKey)
 	if err != nil && err.Error() != "service not configured" {
 		log.Error().
 			Err(err).
@@ -297,18 +314,15 @@ func (h *OverseerrHandler) refreshRequestsCache(instanceId, cacheKey string) {
 			Msg("Successfully refreshed Overseerr requests cache")
 
 		// Add hash-based change detection for refresh
-		h.hashMu.Lock()
 		currentHash, changes := createOverseerrRequestsHash(stats)
-		lastHash := h.lastRequestsHash[instanceId]
+		lastHash := h.lastRequestsHash.Swap(instanceId, currentHash)
 
 		if currentHash != lastHash {
 			log.Debug().
 				Str("instanceId", instanceId).
 				Strs("changes", changes).
 				Msg("Overseerr requests changed during refresh")
-			h.lastRequestsHash[instanceId] = currentHash
 		}
-		h.hashMu.Unlock()
 
 		// Broadcast the updated data
 		h.broadcastOverseerrRequests(instanceId, stats)
@@ -325,8 +339,8 @@ func (h *OverseerrHandler) refreshRequestsCache(instanceId, cacheKey string) {
 func (h *OverseerrHandler) broadcastOverseerrRequests(instanceId string, stats *types.RequestsStats) {
 	// Use the existing BroadcastHealth function with a special message type
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "ok",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
 		Message:     "overseerr_requests",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
@@ -366,7 +380,8 @@ func createOverseerrRequestsHash(stats *types.RequestsStats) (string, []string)
 		reqDetails := fmt.Sprintf("Full Request Details: "+
 			"ID=%d, Status=%d, MediaType=%s, MediaTitle=%s, "+
 			"RequestedBy.ID=%d, RequestedBy.Username=%s, "+
-			"RequestedBy.Email=%s, RequestedBy.PlexUsername=%s",
+			"RequestedBy.Email=%s, RequestedBy.PlexUsername=%s, "+
+			"Requester.Name=%s, Requester.Type=%s",
 			req.ID,
 			req.Status,
 			req.Media.MediaType,
@@ -374,13 +389,15 @@ func createOverseerrRequestsHash(stats *types.RequestsStats) (string, []string)
 			req.RequestedBy.ID,
 			req.RequestedBy.Username,
 			req.RequestedBy.Email,
-			req.RequestedBy.PlexUsername)
+			req.RequestedBy.PlexUsername,
+			req.Requester.Name,
+			req.Requester.Type)
 
 		reqHash := fmt.Sprintf("%d:%d:%s:%s:%s:%s",
 			req.ID,
 			req.Status,
 			req.Media.MediaType,
-			req.RequestedBy.Username,
+			req.Requester.Name,
 			req.Media.Title,
 			reqDetails)
 
@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +17,7 @@ import (
 
 	"github.com/autobrr/dashbrr/internal/api/middleware"
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/plex"
@@ -25,19 +26,27 @@ import (
 
 const plexCachePrefix = "plex:sessions:"
 
+// plexTranscodeLimit is the number of concurrent transcodes above which Plex is reported as warning
+var plexTranscodeLimit atomic.Int64
+
+// SetPlexTranscodeLimit sets the number of concurrent transcodes above which the Plex
+// session broadcast escalates to warning. 0 disables the check.
+func SetPlexTranscodeLimit(limit int) {
+	plexTranscodeLimit.Store(int64(limit))
+}
+
 type PlexHandler struct {
-	db                *database.DB
-	cache             cache.Store
-	sf                singleflight.Group
-	lastSessionHash   map[string]string
-	lastSessionHashMu sync.Mutex
+	db              *database.DB
+	cache           cache.Store
+	sf              singleflight.Group
+	lastSessionHash *hashTracker
 }
 
 func NewPlexHandler(db *database.DB, cache cache.Store) *PlexHandler {
 	return &PlexHandler{
 		db:              db,
 		cache:           cache,
-		lastSessionHash: make(map[string]string),
+		lastSessionHash: newHashTracker(db),
 	}
 }
 
@@ -50,7 +59,7 @@ func (h *PlexHandler) GetPlexSessions(c *gin.Context) {
 	}
 
 	// Verify this is a Plex instance
-	if instanceId[:4] != "plex" {
+	if !isServiceType(instanceId, "plex") {
 		log.Error().Str("instanceId", instanceId).Msg("Invalid Plex instance ID")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Plex instance ID"})
 		return
@@ -121,6 +130,55 @@ func (h *PlexHandler) GetPlexSessions(c *gin.Context) {
 	c.JSON(http.StatusOK, sessions)
 }
 
+// GetSessions returns a summary of what is playing on a Plex instance: per session
+// the title, user, player, playback state and whether it is transcoded
+func (h *PlexHandler) GetSessions(c *gin.Context) {
+	instanceId := c.Query("instanceId")
+	if instanceId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "instanceId is required"})
+		return
+	}
+
+	if !isServiceType(instanceId, "plex") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Plex instance ID"})
+		return
+	}
+
+	cacheKey := plexCachePrefix + instanceId
+	ctx := c.Request.Context()
+
+	var sessions *types.PlexSessionsResponse
+	if err := h.cache.Get(ctx, cacheKey, &sessions); err != nil || sessions == nil {
+		sessionsI, err, _ := h.sf.Do(fmt.Sprintf("sessions:%s", instanceId), func() (interface{}, error) {
+			return h.fetchAndCacheSessions(context.Background(), instanceId, cacheKey)
+		})
+		if err != nil {
+			if err.Error() == "service not configured" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Plex is not configured"})
+				return
+			}
+			log.Error().Err(err).Str("instanceId", instanceId).Msg("Failed to fetch Plex sessions")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		sessions = sessionsI.(*types.PlexSessionsResponse)
+		if sessions != nil {
+			h.compareAndLogSessionChanges(instanceId, sessions)
+			h.broadcastPlexSessions(instanceId, sessions)
+		}
+	}
+
+	response := types.PlexNowPlayingResponse{Sessions: []types.PlexNowPlaying{}}
+	if sessions != nil {
+		for _, session := range sessions.MediaContainer.Metadata {
+			response.Sessions = append(response.Sessions, summarizePlexSession(session))
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *PlexHandler) fetchAndCacheSessions(ctx context.Context, instanceId, cacheKey string) (*types.PlexSessionsResponse, error) {
 	plexConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
@@ -183,12 +241,20 @@ func (h *PlexHandler) refreshSessionsCache(instanceId, cacheKey string) {
 	}
 }
 
-// broadcastPlexSessions broadcasts Plex session updates to all connected SSE clients
+// broadcastPlexSessions broadcasts Plex session updates to all connected SSE clients.
+// Too many concurrent transcodes escalate the status to warning, as the server is likely overloaded.
 func (h *PlexHandler) broadcastPlexSessions(instanceId string, sessions *types.PlexSessionsResponse) {
+	load := plexLoad(sessions.MediaContainer.Metadata)
+
+	status := models.StatusOnline
+	if limit := plexTranscodeLimit.Load(); limit > 0 && int64(load.Transcode) > limit {
+		status = models.StatusWarning
+	}
+
 	// Use the existing BroadcastHealth function with a special message type
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "ok",
+		InstanceID:  instanceId,
+		Status:      status,
 		Message:     "plex_sessions",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
@@ -199,21 +265,107 @@ func (h *PlexHandler) broadcastPlexSessions(instanceId string, sessions *types.P
 		Details: map[string]interface{}{
 			"plex": map[string]interface{}{
 				"activeStreams": len(sessions.MediaContainer.Metadata),
-				"transcoding":   len(filterTranscodingSessions(sessions.MediaContainer.Metadata)),
+				"transcoding":   load.Transcode,
+				"directPlay":    load.DirectPlay,
+				"directStream":  load.DirectStream,
+				"bandwidth":     load.Bandwidth,
+				"quality":       load.Quality,
 			},
 		},
 	})
 }
 
-// filterTranscodingSessions returns sessions that are being transcoded
-func filterTranscodingSessions(sessions []types.PlexSession) []types.PlexSession {
-	transcoding := make([]types.PlexSession, 0)
+// plexLoad sums the bandwidth of the sessions and counts them by resolution and decision
+func plexLoad(sessions []types.PlexSession) types.PlexLoad {
+	load := types.PlexLoad{
+		Quality: map[string]int{"4k": 0, "1080p": 0, "720p": 0, "sd": 0},
+	}
+
 	for _, session := range sessions {
-		if session.TranscodeSession != nil {
-			transcoding = append(transcoding, session)
+		if session.Session != nil {
+			load.Bandwidth += session.Session.Bandwidth
+		}
+
+		if session.Type == "movie" || session.Type == "episode" {
+			load.Quality[plexQuality(session.Media)]++
+		}
+
+		switch summarizePlexSession(session).Decision {
+		case "transcode":
+			load.Transcode++
+		case "directstream":
+			load.DirectStream++
+		default:
+			load.DirectPlay++
+		}
+	}
+
+	return load
+}
+
+// plexQuality buckets the resolution of the selected media, e.g. "1080" becomes "1080p"
+func plexQuality(media []types.PlexMedia) string {
+	if len(media) == 0 {
+		return "sd"
+	}
+
+	selected := media[0]
+	for _, m := range media {
+		if m.Selected {
+			selected = m
+			break
+		}
+	}
+
+	switch strings.ToLower(selected.VideoResolution) {
+	case "4k", "2160":
+		return "4k"
+	case "1080":
+		return "1080p"
+	case "720":
+		return "720p"
+	default:
+		return "sd"
+	}
+}
+
+// summarizePlexSession reduces a Plex session to what the now playing list shows
+func summarizePlexSession(session types.PlexSession) types.PlexNowPlaying {
+	summary := types.PlexNowPlaying{
+		SessionKey: session.SessionKey,
+		Title:      session.Title,
+		Type:       session.Type,
+		Decision:   "directplay",
+	}
+
+	// Episodes and tracks are titled with their show or artist
+	if session.GrandparentTitle != "" {
+		summary.Title = session.GrandparentTitle + " - " + session.Title
+	}
+
+	if session.User != nil {
+		summary.User = session.User.Title
+	}
+	if session.Player != nil {
+		summary.Player = session.Player.Title
+		if summary.Player == "" {
+			summary.Player = session.Player.Product
+		}
+		summary.State = session.Player.State
+	}
+
+	if transcode := session.TranscodeSession; transcode != nil {
+		summary.Decision = "directstream"
+		if transcode.VideoDecision == "transcode" || transcode.AudioDecision == "transcode" {
+			summary.Decision = "transcode"
 		}
 	}
-	return transcoding
+
+	if session.Duration > 0 {
+		summary.Progress = session.ViewOffset * 100 / session.Duration
+	}
+
+	return summary
 }
 
 // createSessionHash generates a unique hash representing the current state of Plex sessions
@@ -229,12 +381,12 @@ func createSessionHash(sessions *types.PlexSessionsResponse) string {
 	var sb strings.Builder
 	for _, session := range sessions.MediaContainer.Metadata {
 		// Include session identity and player state
-		fmt.Fprintf(&sb, "%s:%s:%s:%s:%s,",
-			session.SessionKey,
-			session.GrandparentTitle,
-			session.Title,
-			session.User.Title,
-			session.Player.State)
+		summary := summarizePlexSession(session)
+		fmt.Fprintf(&sb, "%s:%s:%s:%s,",
+			summary.SessionKey,
+			summary.Title,
+			summary.User,
+			summary.State)
 	}
 	return sb.String()
 }
@@ -260,22 +412,17 @@ func (h *PlexHandler) detectSessionChanges(oldHash, newHash string) string {
 // It compares the current session state with the previous state for a specific Plex instance
 // Helps detect session state changes like new streams starting, streams ending, or playback state changes
 func (h *PlexHandler) compareAndLogSessionChanges(instanceId string, sessions *types.PlexSessionsResponse) {
-	h.lastSessionHashMu.Lock()
-	defer h.lastSessionHashMu.Unlock()
-
 	currentHash := createSessionHash(sessions)
-	lastHash := h.lastSessionHash[instanceId]
+	lastHash := h.lastSessionHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		// Detect specific changes
 		changes := h.detectSessionChanges(lastHash, currentHash)
 
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
 			Int("size", sessions.MediaContainer.Size).
 			Str("change", changes).
 			Msg("[Plex] Sessions changed")
-
-		h.lastSessionHash[instanceId] = currentHash
 	}
 }
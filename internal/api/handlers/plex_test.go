@@ -0,0 +1,116 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestSummarizePlexSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  types.PlexSession
+		expected types.PlexNowPlaying
+	}{
+		{
+			name: "direct play movie",
+			session: types.PlexSession{
+				SessionKey: "1",
+				Title:      "Movie",
+				Type:       "movie",
+				Duration:   1000,
+				ViewOffset: 250,
+				User:       &types.PlexUser{Title: "alice"},
+				Player:     &types.PlexPlayer{Title: "Living Room", State: "playing"},
+			},
+			expected: types.PlexNowPlaying{
+				SessionKey: "1", Title: "Movie", Type: "movie", User: "alice",
+				Player: "Living Room", State: "playing", Decision: "directplay", Progress: 25,
+			},
+		},
+		{
+			name: "transcoded episode",
+			session: types.PlexSession{
+				SessionKey:       "2",
+				GrandparentTitle: "Show",
+				Title:            "Pilot",
+				Type:             "episode",
+				Player:           &types.PlexPlayer{Product: "Plex Web", State: "paused"},
+				TranscodeSession: &types.PlexTranscodeSession{VideoDecision: "transcode", AudioDecision: "copy"},
+			},
+			expected: types.PlexNowPlaying{
+				SessionKey: "2", Title: "Show - Pilot", Type: "episode",
+				Player: "Plex Web", State: "paused", Decision: "transcode",
+			},
+		},
+		{
+			name: "direct stream without user or player",
+			session: types.PlexSession{
+				SessionKey:       "3",
+				Title:            "Track",
+				TranscodeSession: &types.PlexTranscodeSession{VideoDecision: "copy", AudioDecision: "copy"},
+			},
+			expected: types.PlexNowPlaying{SessionKey: "3", Title: "Track", Decision: "directstream"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizePlexSession(tt.session); got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsServiceType(t *testing.T) {
+	if !isServiceType("plex-1", "plex") {
+		t.Error("Expected plex-1 to be a plex instance")
+	}
+	for _, id := range []string{"ple", "plexamp-1", "sonarr-1"} {
+		if isServiceType(id, "plex") {
+			t.Errorf("Expected %q not to be a plex instance", id)
+		}
+	}
+}
+
+func TestPlexLoad(t *testing.T) {
+	sessions := []types.PlexSession{
+		{
+			Type:    "movie",
+			Media:   []types.PlexMedia{{VideoResolution: "1080"}, {VideoResolution: "4k", Selected: true}},
+			Session: &types.PlexSessionInfo{Bandwidth: 40000},
+		},
+		{
+			Type:             "episode",
+			Media:            []types.PlexMedia{{VideoResolution: "1080"}},
+			Session:          &types.PlexSessionInfo{Bandwidth: 8000},
+			TranscodeSession: &types.PlexTranscodeSession{VideoDecision: "transcode"},
+		},
+		{
+			Type:             "episode",
+			Media:            []types.PlexMedia{{VideoResolution: "480"}},
+			TranscodeSession: &types.PlexTranscodeSession{VideoDecision: "copy", AudioDecision: "copy"},
+		},
+		{
+			// Music has no video quality
+			Type:    "track",
+			Session: &types.PlexSessionInfo{Bandwidth: 320},
+		},
+	}
+
+	load := plexLoad(sessions)
+
+	if load.Bandwidth != 48320 {
+		t.Errorf("Expected bandwidth 48320, got %d", load.Bandwidth)
+	}
+	if load.Quality["4k"] != 1 || load.Quality["1080p"] != 1 || load.Quality["sd"] != 1 || load.Quality["720p"] != 0 {
+		t.Errorf("Unexpected quality breakdown %v", load.Quality)
+	}
+	if load.DirectPlay != 2 || load.DirectStream != 1 || load.Transcode != 1 {
+		t.Errorf("Expected 2 direct play, 1 direct stream and 1 transcode, got %+v", load)
+	}
+}
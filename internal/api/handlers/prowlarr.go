@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +19,7 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/prowlarr"
@@ -24,28 +27,45 @@ import (
 )
 
 const (
-	prowlarrCacheDuration      = 2 * time.Second // Updated to 2s to match other services
-	prowlarrStatsPrefix        = "prowlarr:stats:"
-	prowlarrIndexerPrefix      = "prowlarr:indexers:"
-	prowlarrIndexerStatsPrefix = "prowlarr:indexerstats:"
+	prowlarrCacheDuration       = 2 * time.Second // Updated to 2s to match other services
+	prowlarrStatsPrefix         = "prowlarr:stats:"
+	prowlarrIndexerPrefix       = "prowlarr:indexers:"
+	prowlarrIndexerStatsPrefix  = "prowlarr:indexerstats:"
+	prowlarrIndexerStatusPrefix = "prowlarr:indexerstatus:"
 )
 
+// prowlarrFailingIndexerThreshold is the number of failing indexers across all Prowlarr
+// instances at which an alert is raised, 0 disables the alert
+var prowlarrFailingIndexerThreshold atomic.Int64
+
+// SetProwlarrFailingIndexerThreshold sets the number of failing indexers across all Prowlarr
+// instances at which an alert is raised. 0 disables the alert.
+func SetProwlarrFailingIndexerThreshold(threshold int) {
+	prowlarrFailingIndexerThreshold.Store(int64(threshold))
+}
+
 type ProwlarrHandler struct {
 	db    *database.DB
 	cache cache.Store
 	sf    *singleflight.Group
 
-	// Single hash map and mutex for all state tracking
-	lastHash   map[string]string // key format: "stats:instanceId", "indexers:instanceId", etc.
-	lastHashMu sync.Mutex
+	lastStatsHash        *hashTracker
+	lastIndexersHash     *hashTracker
+	lastIndexerStatsHash *hashTracker
+
+	// failingAlert is set while the failing indexer threshold is reached, so the alert is
+	// only raised once when it is crossed
+	failingAlert atomic.Bool
 }
 
 func NewProwlarrHandler(db *database.DB, cache cache.Store) *ProwlarrHandler {
 	return &ProwlarrHandler{
-		db:       db,
-		cache:    cache,
-		sf:       &singleflight.Group{},
-		lastHash: make(map[string]string),
+		db:                   db,
+		cache:                cache,
+		sf:                   &singleflight.Group{},
+		lastStatsHash:        newHashTracker(db),
+		lastIndexersHash:     newHashTracker(db),
+		lastIndexerStatsHash: newHashTracker(db),
 	}
 }
 
@@ -70,22 +90,16 @@ func (h *ProwlarrHandler) detectStatsChanges(oldHash, newHash string) string {
 // compareAndLogStatsChanges tracks and logs changes in Prowlarr system stats
 // It compares the current stats state with the previous state for a specific Prowlarr instance
 func (h *ProwlarrHandler) compareAndLogStatsChanges(instanceId string, stats types.ProwlarrStatsResponse) {
-	h.lastHashMu.Lock()
-	defer h.lastHashMu.Unlock()
-
-	key := fmt.Sprintf("stats:%s", instanceId)
 	currentHash := createStatsHash(stats)
-	lastHash := h.lastHash[key]
+	lastHash := h.lastStatsHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		changes := h.detectStatsChanges(lastHash, currentHash)
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
-			Int("grabCount", stats.GrabCount).
+			Int64("grabCount", stats.GrabCount).
 			Str("change", changes).
 			Msg("[Prowlarr] Stats changed")
-
-		h.lastHash[key] = currentHash
 	}
 }
 
@@ -124,22 +138,16 @@ func (h *ProwlarrHandler) detectIndexersChanges(oldHash, newHash string) string
 // compareAndLogIndexersChanges tracks and logs changes in Prowlarr indexers
 // It compares the current indexers state with the previous state for a specific Prowlarr instance
 func (h *ProwlarrHandler) compareAndLogIndexersChanges(instanceId string, indexers []types.ProwlarrIndexer) {
-	h.lastHashMu.Lock()
-	defer h.lastHashMu.Unlock()
-
-	key := fmt.Sprintf("indexers:%s", instanceId)
 	currentHash := createIndexersHash(indexers)
-	lastHash := h.lastHash[key]
+	lastHash := h.lastIndexersHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		changes := h.detectIndexersChanges(lastHash, currentHash)
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
 			Int("indexerCount", len(indexers)).
 			Str("change", changes).
 			Msg("[Prowlarr] Indexers changed")
-
-		h.lastHash[key] = currentHash
 	}
 }
 
@@ -171,22 +179,16 @@ func (h *ProwlarrHandler) detectIndexerStatsChanges(oldHash, newHash string) str
 // compareAndLogIndexerStatsChanges tracks and logs changes in Prowlarr indexer stats
 // It compares the current indexer stats state with the previous state for a specific Prowlarr instance
 func (h *ProwlarrHandler) compareAndLogIndexerStatsChanges(instanceId string, stats types.ProwlarrIndexerStatsResponse) {
-	h.lastHashMu.Lock()
-	defer h.lastHashMu.Unlock()
-
-	key := fmt.Sprintf("indexer_stats:%s", instanceId)
 	currentHash := createIndexerStatsHash(stats)
-	lastHash := h.lastHash[key]
+	lastHash := h.lastIndexerStatsHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		changes := h.detectIndexerStatsChanges(lastHash, currentHash)
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
 			Int("indexerCount", len(stats.Indexers)).
 			Str("change", changes).
 			Msg("[Prowlarr] Indexer stats changed")
-
-		h.lastHash[key] = currentHash
 	}
 }
 
@@ -206,6 +208,7 @@ func (h *ProwlarrHandler) GetStats(c *gin.Context) {
 	}
 
 	cacheKey := prowlarrStatsPrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -214,7 +217,7 @@ func (h *ProwlarrHandler) GetStats(c *gin.Context) {
 	if err == nil {
 		log.Debug().
 			Str("instanceId", instanceId).
-			Int("grabCount", statsResp.GrabCount).
+			Int64("grabCount", statsResp.GrabCount).
 			Msg("[Prowlarr] Serving stats from cache")
 		c.JSON(http.StatusOK, statsResp)
 
@@ -226,7 +229,7 @@ func (h *ProwlarrHandler) GetStats(c *gin.Context) {
 	// Use singleflight to deduplicate concurrent requests
 	sfKey := fmt.Sprintf("stats:%s", instanceId)
 	result, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		prowlarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+		prowlarrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 		if err != nil {
 			return nil, fmt.Errorf("[Prowlarr] failed to get configuration: %w", err)
 		}
@@ -294,9 +297,10 @@ func (h *ProwlarrHandler) GetStats(c *gin.Context) {
 // Helper method to broadcast stats updates
 func (h *ProwlarrHandler) broadcastStats(instanceId string, stats types.ProwlarrStatsResponse) {
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID: instanceId,
-		Status:    "ok",
-		Message:   "prowlarr_stats",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
+		Message:     "prowlarr_stats",
+		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
 			"prowlarr": map[string]interface{}{
 				"stats": stats,
@@ -321,6 +325,7 @@ func (h *ProwlarrHandler) GetIndexers(c *gin.Context) {
 	}
 
 	cacheKey := prowlarrIndexerPrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -333,7 +338,7 @@ func (h *ProwlarrHandler) GetIndexers(c *gin.Context) {
 			Msg("Serving Prowlarr indexers from cache")
 
 		// Broadcast indexers update via SSE
-		h.broadcastIndexers(instanceId, indexers)
+		h.broadcastIndexers(instanceId, indexers, nil)
 
 		c.JSON(http.StatusOK, indexers)
 		return
@@ -342,7 +347,7 @@ func (h *ProwlarrHandler) GetIndexers(c *gin.Context) {
 	// Use singleflight to deduplicate concurrent requests
 	sfKey := fmt.Sprintf("indexers:%s", instanceId)
 	result, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		prowlarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+		prowlarrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get Prowlarr configuration: %w", err)
 		}
@@ -351,51 +356,13 @@ func (h *ProwlarrHandler) GetIndexers(c *gin.Context) {
 			return nil, fmt.Errorf("prowlarr is not configured")
 		}
 
-		// Build Prowlarr API URL
-		apiURL := fmt.Sprintf("%s/api/v1/indexer?apikey=%s", prowlarrConfig.URL, prowlarrConfig.APIKey)
-
-		// Make request to Prowlarr
-		client := &http.Client{Timeout: 10 * time.Second}
-		resp, err := client.Get(apiURL)
+		prowlarrService := prowlarr.NewProwlarrService().(*prowlarr.ProwlarrService)
+		result, err := prowlarrService.GetIndexers(ctx, prowlarrConfig.URL, prowlarrConfig.APIKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch Prowlarr indexers: %w", err)
 		}
 
-		if resp == nil {
-			return nil, fmt.Errorf("received nil response from Prowlarr")
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("prowlarr API returned status: %d", resp.StatusCode)
-		}
-
-		var indexers []types.ProwlarrIndexer
-		if err := json.NewDecoder(resp.Body).Decode(&indexers); err != nil {
-			return nil, fmt.Errorf("failed to parse Prowlarr response: %w", err)
-		}
-
-		// Get indexer stats
-		prowlarrService := prowlarr.NewProwlarrService().(*prowlarr.ProwlarrService)
-		statsResp, err := prowlarrService.GetIndexerStats(ctx, prowlarrConfig.URL, prowlarrConfig.APIKey)
-		if err == nil && statsResp != nil {
-			// Create a map for quick lookup
-			statsMap := make(map[int]types.ProwlarrIndexerStats)
-			for _, stat := range statsResp.Indexers {
-				statsMap[stat.IndexerID] = stat
-			}
-
-			// Enrich indexers with stats
-			for i := range indexers {
-				if stats, ok := statsMap[indexers[i].ID]; ok {
-					indexers[i].AverageResponseTime = stats.AverageResponseTime
-					indexers[i].NumberOfGrabs = stats.NumberOfGrabs
-					indexers[i].NumberOfQueries = stats.NumberOfQueries
-				}
-			}
-		}
-
-		return indexers, nil
+		return result, nil
 	})
 
 	if err != nil {
@@ -408,13 +375,20 @@ func (h *ProwlarrHandler) GetIndexers(c *gin.Context) {
 		return
 	}
 
-	indexers = result.([]types.ProwlarrIndexer)
+	indexersResult := result.(*prowlarr.IndexersResult)
+	indexers = indexersResult.Indexers
 
 	// Add hash-based change detection
 	h.compareAndLogIndexersChanges(instanceId, indexers)
 
-	// Cache the results
-	if err := h.cache.Set(ctx, cacheKey, indexers, prowlarrCacheDuration); err != nil {
+	if indexersResult.Partial() {
+		// Incomplete results aren't cached, so the next request retries the stats
+		log.Warn().
+			Err(indexersResult.StatsErr).
+			Str("instanceId", instanceId).
+			Msg("[Prowlarr] Serving indexers without stats")
+		c.Header("X-Partial-Failure", "indexerStats")
+	} else if err := h.cache.Set(ctx, cacheKey, indexers, prowlarrCacheDuration); err != nil {
 		log.Warn().
 			Err(err).
 			Str("instanceId", instanceId).
@@ -422,17 +396,27 @@ func (h *ProwlarrHandler) GetIndexers(c *gin.Context) {
 	}
 
 	// Broadcast indexers update via SSE
-	h.broadcastIndexers(instanceId, indexers)
+	h.broadcastIndexers(instanceId, indexers, indexersResult.StatsErr)
 
 	c.JSON(http.StatusOK, indexers)
 }
 
-// Helper method to broadcast indexers updates
-func (h *ProwlarrHandler) broadcastIndexers(instanceId string, indexers []types.ProwlarrIndexer) {
+// Helper method to broadcast indexers updates. Indexers without their stats are
+// broadcast as a warning with the stats error as detail.
+func (h *ProwlarrHandler) broadcastIndexers(instanceId string, indexers []types.ProwlarrIndexer, statsErr error) {
+	status := models.StatusOnline
+	var detail string
+	if statsErr != nil {
+		status = models.StatusWarning
+		detail = fmt.Sprintf("Indexer stats unavailable: %v", statsErr)
+	}
+
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID: instanceId,
-		Status:    "ok",
-		Message:   "prowlarr_indexers",
+		InstanceID:  instanceId,
+		Status:      status,
+		Message:     "prowlarr_indexers",
+		Detail:      detail,
+		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
 			"prowlarr": map[string]interface{}{
 				"indexers": indexers,
@@ -457,6 +441,7 @@ func (h *ProwlarrHandler) GetIndexerStats(c *gin.Context) {
 	}
 
 	cacheKey := prowlarrIndexerStatsPrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -474,7 +459,7 @@ func (h *ProwlarrHandler) GetIndexerStats(c *gin.Context) {
 	// Use singleflight to deduplicate concurrent requests
 	sfKey := fmt.Sprintf("indexer_stats:%s", instanceId)
 	result, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		prowlarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+		prowlarrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get Prowlarr configuration: %w", err)
 		}
@@ -518,3 +503,153 @@ func (h *ProwlarrHandler) GetIndexerStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, statsResp)
 }
+
+// GetFailingIndexers returns the failing indexers of all Prowlarr instances in one list and
+// raises a single alert when their number reaches the configured threshold
+func (h *ProwlarrHandler) GetFailingIndexers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	services, err := h.db.GetAllServices(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("[Prowlarr] Failed to fetch services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	resp := types.FailingIndexersResponse{
+		Indexers:  []types.FailingIndexer{},
+		Threshold: int(prowlarrFailingIndexerThreshold.Load()),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, service := range services {
+		if !isServiceType(service.InstanceID, "prowlarr") {
+			continue
+		}
+
+		wg.Add(1)
+		go func(service models.ServiceConfiguration) {
+			defer wg.Done()
+
+			failing, err := h.failingIndexers(ctx, service)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Warn().Err(err).Str("instanceId", service.InstanceID).Msg("[Prowlarr] Failed to fetch indexer status")
+				if resp.Errors == nil {
+					resp.Errors = make(map[string]string)
+				}
+				resp.Errors[service.InstanceID] = err.Error()
+				return
+			}
+			resp.Indexers = append(resp.Indexers, failing...)
+		}(service)
+	}
+	wg.Wait()
+
+	sort.Slice(resp.Indexers, func(i, j int) bool {
+		if resp.Indexers[i].InstanceID != resp.Indexers[j].InstanceID {
+			return resp.Indexers[i].InstanceID < resp.Indexers[j].InstanceID
+		}
+		return resp.Indexers[i].Name < resp.Indexers[j].Name
+	})
+
+	resp.Count = len(resp.Indexers)
+	resp.Alert = resp.Threshold > 0 && resp.Count >= resp.Threshold
+	h.updateFailingAlert(resp)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// failingIndexers returns the disabled indexers of one instance. The status and indexer names
+// are served from the cache when possible.
+func (h *ProwlarrHandler) failingIndexers(ctx context.Context, service models.ServiceConfiguration) ([]types.FailingIndexer, error) {
+	prowlarrService := prowlarr.NewProwlarrService().(*prowlarr.ProwlarrService)
+
+	statusKey := prowlarrIndexerStatusPrefix + service.InstanceID
+	var statuses []types.ProwlarrIndexerStatus
+	if err := h.cache.Get(ctx, statusKey, &statuses); err != nil {
+		statuses, err = prowlarrService.GetIndexerStatus(ctx, service.URL, service.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.cache.Set(ctx, statusKey, statuses, prowlarrCacheDuration); err != nil {
+			log.Warn().Err(err).Str("instanceId", service.InstanceID).Msg("[Prowlarr] Failed to cache indexer status")
+		}
+	}
+
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[int]string)
+	var indexers []types.ProwlarrIndexer
+	if err := h.cache.Get(ctx, prowlarrIndexerPrefix+service.InstanceID, &indexers); err != nil {
+		if result, err := prowlarrService.GetIndexers(ctx, service.URL, service.APIKey); err == nil {
+			indexers = result.Indexers
+		}
+	}
+	for _, indexer := range indexers {
+		names[indexer.ID] = indexer.Name
+	}
+
+	now := time.Now()
+	failing := make([]types.FailingIndexer, 0, len(statuses))
+	for _, status := range statuses {
+		// Prowlarr keeps the status of indexers that recovered until their next failure
+		if status.DisabledTill == nil || status.DisabledTill.Before(now) {
+			continue
+		}
+
+		name, ok := names[status.IndexerID]
+		if !ok {
+			name = fmt.Sprintf("Indexer %d", status.IndexerID)
+		}
+
+		failing = append(failing, types.FailingIndexer{
+			InstanceID:        service.InstanceID,
+			IndexerID:         status.IndexerID,
+			Name:              name,
+			Error:             fmt.Sprintf("Disabled until %s due to failures", status.DisabledTill.Format(time.RFC3339)),
+			DisabledTill:      status.DisabledTill,
+			MostRecentFailure: status.MostRecentFailure,
+		})
+	}
+
+	return failing, nil
+}
+
+// updateFailingAlert raises the failing indexer alert when the threshold is crossed and clears
+// it once the number drops below it again. Nothing changes in maintenance mode, an alert still
+// due afterwards is raised on the next request.
+func (h *ProwlarrHandler) updateFailingAlert(resp types.FailingIndexersResponse) {
+	if MaintenanceActive() {
+		return
+	}
+	if !h.failingAlert.CompareAndSwap(!resp.Alert, resp.Alert) {
+		return
+	}
+
+	if !resp.Alert {
+		log.Info().Int("count", resp.Count).Int("threshold", resp.Threshold).Msg("[Prowlarr] Failing indexers back below the threshold")
+		return
+	}
+
+	log.Warn().Int("count", resp.Count).Int("threshold", resp.Threshold).Msg("[Prowlarr] Failing indexers reached the threshold")
+
+	BroadcastHealth(models.ServiceHealth{
+		Status:      models.StatusWarning,
+		Message:     "prowlarr_indexers_failing",
+		Detail:      fmt.Sprintf("%d indexers are failing across all Prowlarr instances", resp.Count),
+		LastChecked: time.Now(),
+		Details: map[string]interface{}{
+			"prowlarr": map[string]interface{}{
+				"failingIndexers": resp.Indexers,
+			},
+		},
+	})
+}
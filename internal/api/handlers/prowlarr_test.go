@@ -0,0 +1,109 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestProwlarrHandler_GetFailingIndexers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	disabledTill := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	recovered := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/indexerstatus", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"indexerId":1,"disabledTill":%q},{"indexerId":2,"disabledTill":%q}]`, disabledTill, recovered)
+	})
+	mux.HandleFunc("/api/v1/indexer", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":1,"name":"indexer-1"},{"id":2,"name":"indexer-2"}]`))
+	})
+	healthy := httptest.NewServer(mux)
+	t.Cleanup(healthy.Close)
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(broken.Close)
+
+	db, store := setupTestDB(t)
+
+	ctx := context.Background()
+	for _, service := range []*models.ServiceConfiguration{
+		{InstanceID: "prowlarr-1", DisplayName: "Prowlarr", URL: healthy.URL, APIKey: "key"},
+		{InstanceID: "prowlarr-2", DisplayName: "Prowlarr 2", URL: broken.URL, APIKey: "key"},
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: broken.URL, APIKey: "key"},
+	} {
+		if err := db.CreateService(ctx, service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	SetProwlarrFailingIndexerThreshold(1)
+	t.Cleanup(func() { SetProwlarrFailingIndexerThreshold(0) })
+
+	handler := NewProwlarrHandler(db, store)
+
+	c, w := newTestContext(http.MethodGet, "/api/prowlarr/indexers/failing", nil)
+
+	handler.GetFailingIndexers(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp types.FailingIndexersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if resp.Count != 1 || resp.Indexers[0].Name != "indexer-1" || resp.Indexers[0].InstanceID != "prowlarr-1" {
+		t.Errorf("Expected only indexer-1 of prowlarr-1 to be failing, got %+v", resp.Indexers)
+	}
+	if !resp.Alert {
+		t.Error("Expected the alert to be raised at the threshold")
+	}
+	if _, ok := resp.Errors["prowlarr-2"]; !ok || len(resp.Errors) != 1 {
+		t.Errorf("Expected an error for prowlarr-2 only, got %v", resp.Errors)
+	}
+}
+
+func TestProwlarrHandler_FailingAlertInMaintenance(t *testing.T) {
+	handler := NewProwlarrHandler(setupTestDB(t))
+
+	listener := &client{send: make(chan models.ServiceHealth, 1), done: make(chan struct{})}
+	clientsMu.Lock()
+	clients[listener] = true
+	clientsMu.Unlock()
+	t.Cleanup(func() {
+		clientsMu.Lock()
+		delete(clients, listener)
+		clientsMu.Unlock()
+	})
+
+	maintenance.Store(&types.MaintenanceStatus{Enabled: true})
+	handler.updateFailingAlert(types.FailingIndexersResponse{Alert: true})
+	maintenance.Store(nil)
+	if len(listener.send) != 0 {
+		t.Fatal("Expected no alert in maintenance mode")
+	}
+
+	// The alert is raised once maintenance ends
+	handler.updateFailingAlert(types.FailingIndexersResponse{Alert: true})
+	if len(listener.send) != 1 {
+		t.Error("Expected the alert after maintenance")
+	}
+}
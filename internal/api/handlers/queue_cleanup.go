@@ -0,0 +1,273 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/radarr"
+	"github.com/autobrr/dashbrr/internal/services/sonarr"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const (
+	queueStalledPrefix = "queue:stalled:"
+
+	// queueStalledTTL keeps first-seen times around between queue refreshes
+	queueStalledTTL = 24 * time.Hour
+)
+
+// queueCleanupRule removes queue items that have been stalled with a matching message for too long
+type queueCleanupRule struct {
+	pattern *regexp.Regexp
+	after   time.Duration
+}
+
+var (
+	queueCleanupRules   = make(map[string]queueCleanupRule)
+	queueCleanupRulesMu sync.RWMutex
+)
+
+// SetQueueCleanupRule enables automatic removal of stalled Sonarr or Radarr queue items for an
+// instance. Items with a warning status whose error or status messages match the pattern are
+// removed and blocklisted once they have been stalled for longer than after.
+func SetQueueCleanupRule(instanceID, pattern string, after time.Duration) error {
+	if !isServiceType(instanceID, "sonarr") && !isServiceType(instanceID, "radarr") {
+		return fmt.Errorf("queue cleanup is only supported for sonarr and radarr, got %q", instanceID)
+	}
+	if after <= 0 {
+		return fmt.Errorf("queue cleanup for %s needs a positive duration", instanceID)
+	}
+
+	if pattern == "" {
+		return fmt.Errorf("queue cleanup for %s needs a pattern", instanceID)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid queue cleanup pattern for %s: %w", instanceID, err)
+	}
+
+	queueCleanupRulesMu.Lock()
+	queueCleanupRules[instanceID] = queueCleanupRule{pattern: re, after: after}
+	queueCleanupRulesMu.Unlock()
+
+	return nil
+}
+
+func getQueueCleanupRule(instanceID string) (queueCleanupRule, bool) {
+	queueCleanupRulesMu.RLock()
+	defer queueCleanupRulesMu.RUnlock()
+	rule, ok := queueCleanupRules[instanceID]
+	return rule, ok
+}
+
+// queueCandidate is the part of a Sonarr or Radarr queue record the cleanup looks at
+type queueCandidate struct {
+	ID                    int
+	Title                 string
+	Status                string
+	TrackedDownloadStatus string
+	Messages              []string
+}
+
+// stalled reports whether the item is stuck with a message matching the rule
+func (q queueCandidate) stalled(rule queueCleanupRule) bool {
+	if q.Status != "warning" && q.Status != "stalled" && q.TrackedDownloadStatus != "warning" {
+		return false
+	}
+	for _, message := range q.Messages {
+		if message != "" && rule.pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// dueForRemoval returns the candidates that have been stalled for longer than the rule allows.
+// First-seen times are tracked in the cache so they survive between refreshes, items that
+// recovered or left the queue are forgotten.
+func dueForRemoval(ctx context.Context, store cache.Store, instanceID string, rule queueCleanupRule, candidates []queueCandidate, now time.Time) []queueCandidate {
+	cacheKey := queueStalledPrefix + instanceID
+
+	var firstSeen map[int]time.Time
+	if err := store.Get(ctx, cacheKey, &firstSeen); err != nil || firstSeen == nil {
+		firstSeen = make(map[int]time.Time)
+	}
+
+	var due []queueCandidate
+	seen := make(map[int]time.Time)
+	for _, candidate := range candidates {
+		if !candidate.stalled(rule) {
+			continue
+		}
+
+		since, ok := firstSeen[candidate.ID]
+		if !ok {
+			since = now
+		}
+
+		// Due items are kept too so a failed removal is retried on the next refresh
+		seen[candidate.ID] = since
+		if now.Sub(since) >= rule.after {
+			due = append(due, candidate)
+		}
+	}
+
+	if err := store.Set(ctx, cacheKey, seen, queueStalledTTL); err != nil {
+		log.Warn().Err(err).Str("instanceId", instanceID).Msg("Failed to store stalled queue items")
+	}
+
+	return due
+}
+
+// cleanupQueue removes the stalled candidates of an instance with a cleanup rule using remove,
+// returning the ids of the removed items. Every removal is logged and broadcast. Nothing is
+// removed in maintenance mode, when the services are expected to misbehave.
+func cleanupQueue(ctx context.Context, store cache.Store, instanceID string, candidates []queueCandidate, remove func(id int) error) map[int]bool {
+	rule, ok := getQueueCleanupRule(instanceID)
+	if !ok || MaintenanceActive() {
+		return nil
+	}
+
+	removed := make(map[int]bool)
+	for _, candidate := range dueForRemoval(ctx, store, instanceID, rule, candidates, time.Now()) {
+		if err := remove(candidate.ID); err != nil {
+			log.Error().
+				Err(err).
+				Str("instanceId", instanceID).
+				Int("queueId", candidate.ID).
+				Str("title", candidate.Title).
+				Msg("Failed to remove stalled queue item")
+			continue
+		}
+
+		log.Info().
+			Str("instanceId", instanceID).
+			Int("queueId", candidate.ID).
+			Str("title", candidate.Title).
+			Dur("stalledFor", rule.after).
+			Msg("Removed and blocklisted stalled queue item")
+
+		BroadcastHealth(models.ServiceHealth{
+			InstanceID:  instanceID,
+			Status:      models.StatusOnline,
+			Message:     "queue_item_removed",
+			LastChecked: time.Now(),
+			Details: map[string]interface{}{
+				"queueItemRemoved": map[string]interface{}{
+					"id":    candidate.ID,
+					"title": candidate.Title,
+				},
+			},
+		})
+
+		removed[candidate.ID] = true
+	}
+
+	return removed
+}
+
+// removeStalledQueueItems applies the queue cleanup rules of the checked services. It runs on
+// the health monitor tick, so stalled items are removed whether or not anyone is looking at
+// the queue.
+func (h *EventsHandler) removeStalledQueueItems(ctx context.Context, services []models.ServiceConfiguration) {
+	for i := range services {
+		svc := &services[i]
+		if _, ok := getQueueCleanupRule(svc.InstanceID); !ok {
+			continue
+		}
+
+		var removed map[int]bool
+		var queuePrefix string
+		switch {
+		case isServiceType(svc.InstanceID, "sonarr"):
+			removed, queuePrefix = cleanupSonarrQueue(ctx, h.cache, svc), sonarrQueuePrefix
+		case isServiceType(svc.InstanceID, "radarr"):
+			removed, queuePrefix = cleanupRadarrQueue(ctx, h.cache, svc), radarrQueuePrefix
+		}
+
+		// The cached queue still lists the removed items
+		if len(removed) > 0 {
+			if err := h.cache.Delete(ctx, queuePrefix+svc.InstanceID); err != nil {
+				log.Warn().Err(err).Str("instanceId", svc.InstanceID).Msg("Failed to invalidate queue cache")
+			}
+		}
+	}
+}
+
+func cleanupSonarrQueue(ctx context.Context, store cache.Store, config *models.ServiceConfiguration) map[int]bool {
+	service := &sonarr.SonarrService{}
+	config.Configure(service)
+
+	records, err := service.GetQueueForHealth(ctx, config.URL, config.APIKey)
+	if err != nil {
+		log.Warn().Err(err).Str("instanceId", config.InstanceID).Msg("Failed to fetch queue for cleanup")
+		return nil
+	}
+
+	candidates := make([]queueCandidate, 0, len(records))
+	for _, record := range records {
+		messages := []string{record.ErrorMessage}
+		for _, statusMessage := range record.StatusMessages {
+			messages = append(messages, statusMessage.Messages...)
+		}
+		candidates = append(candidates, queueCandidate{
+			ID:                    record.ID,
+			Title:                 record.Title,
+			Status:                record.Status,
+			TrackedDownloadStatus: record.TrackedDownloadStatus,
+			Messages:              messages,
+		})
+	}
+
+	return cleanupQueue(ctx, store, config.InstanceID, candidates, func(id int) error {
+		return service.DeleteQueueItem(ctx, config.URL, config.APIKey, strconv.Itoa(id), types.SonarrQueueDeleteOptions{
+			RemoveFromClient: true,
+			Blocklist:        true,
+		})
+	})
+}
+
+func cleanupRadarrQueue(ctx context.Context, store cache.Store, config *models.ServiceConfiguration) map[int]bool {
+	service := &radarr.RadarrService{}
+	config.Configure(service)
+
+	records, err := service.GetQueueForHealth(ctx, config.URL, config.APIKey)
+	if err != nil {
+		log.Warn().Err(err).Str("instanceId", config.InstanceID).Msg("Failed to fetch queue for cleanup")
+		return nil
+	}
+
+	candidates := make([]queueCandidate, 0, len(records))
+	for _, record := range records {
+		messages := []string{record.ErrorMessage}
+		for _, statusMessage := range record.StatusMessages {
+			messages = append(messages, statusMessage.Messages...)
+		}
+		candidates = append(candidates, queueCandidate{
+			ID:                    record.ID,
+			Title:                 record.Title,
+			Status:                record.Status,
+			TrackedDownloadStatus: record.TrackedDownloadStatus,
+			Messages:              messages,
+		})
+	}
+
+	return cleanupQueue(ctx, store, config.InstanceID, candidates, func(id int) error {
+		return service.DeleteQueueItem(ctx, config.URL, config.APIKey, strconv.Itoa(id), types.RadarrQueueDeleteOptions{
+			RemoveFromClient: true,
+			Blocklist:        true,
+		})
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestDueForRemoval(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestCache(t)
+
+	rule := queueCleanupRule{pattern: regexp.MustCompile(`(?i)stalled`), after: 30 * time.Minute}
+	candidates := []queueCandidate{
+		{ID: 1, Title: "stalled", Status: "warning", Messages: []string{"The download is stalled with no connections"}},
+		{ID: 2, Title: "other warning", Status: "warning", Messages: []string{"Not enough disk space"}},
+		{ID: 3, Title: "downloading", Status: "downloading", Messages: []string{"stalled"}},
+	}
+
+	start := time.Now()
+	if due := dueForRemoval(ctx, store, "sonarr-1", rule, candidates, start); len(due) != 0 {
+		t.Fatalf("Expected nothing to be due on first sight, got %+v", due)
+	}
+
+	due := dueForRemoval(ctx, store, "sonarr-1", rule, candidates, start.Add(31*time.Minute))
+	if len(due) != 1 || due[0].ID != 1 {
+		t.Fatalf("Expected only item 1 to be due, got %+v", due)
+	}
+
+	// An item that recovered starts over when it stalls again
+	dueForRemoval(ctx, store, "sonarr-1", rule, nil, start.Add(32*time.Minute))
+	if due := dueForRemoval(ctx, store, "sonarr-1", rule, candidates, start.Add(33*time.Minute)); len(due) != 0 {
+		t.Errorf("Expected the first-seen time to be reset, got %+v", due)
+	}
+}
+
+func TestSetQueueCleanupRule(t *testing.T) {
+	if err := SetQueueCleanupRule("plex-1", "stalled", time.Hour); err == nil {
+		t.Error("Expected an error for a non-arr instance")
+	}
+	if err := SetQueueCleanupRule("sonarr-1", "(", time.Hour); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+	if err := SetQueueCleanupRule("radarr-1", "stalled", 0); err == nil {
+		t.Error("Expected an error for a zero duration")
+	}
+	if err := SetQueueCleanupRule("radarr-1", "", time.Hour); err == nil {
+		t.Error("Expected an error for an empty pattern")
+	}
+}
+
+func TestQueueCandidate_StalledSkipsEmptyMessages(t *testing.T) {
+	rule := queueCleanupRule{pattern: regexp.MustCompile(`.*`), after: time.Minute}
+
+	candidate := queueCandidate{ID: 1, Status: "warning", Messages: []string{"", ""}}
+	if candidate.stalled(rule) {
+		t.Error("Expected an item without messages not to match")
+	}
+}
+
+func TestEventsHandler_RemoveStalledQueueItems(t *testing.T) {
+	var deleted atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/queue":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"records":[{"id":1,"title":"Show.S01E01","status":"warning","errorMessage":"The download is stalled with no connections"}]}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v3/queue/1":
+			deleted.Add(1)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	handler, store := setupEventsHandler(t)
+	ctx := context.Background()
+
+	if err := SetQueueCleanupRule("sonarr-1", "stalled", time.Minute); err != nil {
+		t.Fatalf("Failed to set queue cleanup rule: %v", err)
+	}
+	t.Cleanup(func() {
+		queueCleanupRulesMu.Lock()
+		delete(queueCleanupRules, "sonarr-1")
+		queueCleanupRulesMu.Unlock()
+	})
+	if err := store.Set(ctx, queueStalledPrefix+"sonarr-1", map[int]time.Time{1: time.Now().Add(-time.Hour)}, queueStalledTTL); err != nil {
+		t.Fatalf("Failed to seed stalled queue item: %v", err)
+	}
+
+	services := []models.ServiceConfiguration{{InstanceID: "sonarr-1", URL: upstream.URL, APIKey: "key"}}
+
+	// Nothing is removed in maintenance mode
+	maintenance.Store(&types.MaintenanceStatus{Enabled: true})
+	handler.removeStalledQueueItems(ctx, services)
+	maintenance.Store(nil)
+	if deleted.Load() != 0 {
+		t.Fatal("Expected no removal in maintenance mode")
+	}
+
+	handler.removeStalledQueueItems(ctx, services)
+	if deleted.Load() != 1 {
+		t.Errorf("Expected the stalled item to be removed once, got %d", deleted.Load())
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestGetQueueItem(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/queue/details" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"id":1,"title":"first","status":"downloading"},
+			{"id":2,"title":"second","status":"warning","downloadClient":"qbit","indexer":"idx","size":2048,
+			 "errorMessage":"stuck","statusMessages":[{"title":"second","messages":["No files found are eligible for import"]}]}
+		]`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	_, db := setupSettingsHandler(t)
+	ctx := context.Background()
+	for _, instanceID := range []string{"sonarr-1", "radarr-1"} {
+		if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: instanceID, URL: upstream.URL, APIKey: "key"}); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	store := setupTestCache(t)
+
+	get := func(handler gin.HandlerFunc, instanceID, id string) *httptest.ResponseRecorder {
+		c, w := newTestContext(http.MethodGet, "/?humanize=true&instanceId="+instanceID, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		handler(c)
+		return w
+	}
+
+	sonarrHandler := NewSonarrHandler(db, store)
+	w := get(sonarrHandler.GetQueueItem, "sonarr-1", "2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var record types.QueueRecord
+	if err := json.Unmarshal(w.Body.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if record.ID != 2 || record.DownloadClient != "qbit" || record.ErrorMessage != "stuck" {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if len(record.StatusMessages) != 1 || len(record.StatusMessages[0].Messages) != 1 {
+		t.Errorf("Expected the full status messages, got %+v", record.StatusMessages)
+	}
+	if record.SizeHuman == "" {
+		t.Error("Expected a humanized size")
+	}
+
+	if w := get(sonarrHandler.GetQueueItem, "sonarr-1", "3"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing item, got %d", http.StatusNotFound, w.Code)
+	}
+
+	radarrHandler := NewRadarrHandler(db, store)
+	w = get(radarrHandler.GetQueueItem, "radarr-1", "2")
+	var radarrRecord types.RadarrQueueRecord
+	if err := json.Unmarshal(w.Body.Bytes(), &radarrRecord); err != nil || radarrRecord.Indexer != "idx" {
+		t.Errorf("Expected the Radarr record, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := get(radarrHandler.GetQueueItem, "radarr-1", "abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid id, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetQueueWithOptions(t *testing.T) {
+	var query string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"records":[{"id":1,"title":"unknown item"}]}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	_, db := setupSettingsHandler(t)
+	ctx := context.Background()
+	if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: "sonarr-1", URL: upstream.URL, APIKey: "key"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	store := setupTestCache(t)
+
+	handler := NewSonarrHandler(db, store)
+
+	get := func(params string) *httptest.ResponseRecorder {
+		c, w := newTestContext(http.MethodGet, "/api/sonarr/queue?instanceId=sonarr-1&"+params, nil)
+		handler.GetQueue(c)
+		return w
+	}
+
+	w := get("includeUnknownSeriesItems=true&includeEpisode=false")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !strings.Contains(query, "includeUnknownSeriesItems=true") || !strings.Contains(query, "includeEpisode=false") || !strings.Contains(query, "includeSeries=true") {
+		t.Errorf("Expected the options to be passed to Sonarr, got %q", query)
+	}
+
+	// Custom queues don't end up in the shared cache
+	var cached types.SonarrQueueResponse
+	if err := store.Get(ctx, sonarrQueuePrefix+"sonarr-1", &cached); err == nil {
+		t.Error("Expected the queue with custom options not to be cached")
+	}
+
+	if w := get("includeSeries=maybe"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid option, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestDeleteQueueItemDryRun(t *testing.T) {
+	var deletes atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deletes.Add(1)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":2,"title":"second","status":"warning","trackedDownloadStatus":"warning"}]`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	_, db := setupSettingsHandler(t)
+	ctx := context.Background()
+	for _, instanceID := range []string{"sonarr-1", "radarr-1"} {
+		if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: instanceID, URL: upstream.URL, APIKey: "key"}); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	store := setupTestCache(t)
+
+	for _, tt := range []struct {
+		instanceID string
+		handler    gin.HandlerFunc
+	}{
+		{"sonarr-1", NewSonarrHandler(db, store).DeleteQueueItem},
+		{"radarr-1", NewRadarrHandler(db, store).DeleteQueueItem},
+	} {
+		t.Run(tt.instanceID, func(t *testing.T) {
+			c, w := newTestContext(http.MethodDelete, "/?dryRun=true&blocklist=true&instanceId="+tt.instanceID, nil)
+			c.Params = gin.Params{{Key: "id", Value: "2"}}
+			tt.handler(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var preview struct {
+				types.QueueDeletePreview
+				Options types.SonarrQueueDeleteOptions `json:"options"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !preview.DryRun || preview.ID != 2 || preview.Title != "second" || preview.Status != "warning" {
+				t.Errorf("Unexpected preview: %+v", preview)
+			}
+			if !preview.Options.Blocklist || preview.Options.RemoveFromClient {
+				t.Errorf("Expected the requested options, got %+v", preview.Options)
+			}
+		})
+	}
+
+	if n := deletes.Load(); n != 0 {
+		t.Errorf("Expected no delete requests, got %d", n)
+	}
+}
@@ -5,9 +5,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,28 +17,29 @@ import (
 
 	"github.com/autobrr/dashbrr/internal/api/middleware"
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/arr"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/radarr"
 	"github.com/autobrr/dashbrr/internal/types"
+	"github.com/autobrr/dashbrr/internal/utils"
 )
 
 const radarrQueuePrefix = "radarr:queue:"
 
 type RadarrHandler struct {
-	db              *database.DB
-	cache           cache.Store
-	sf              singleflight.Group
-	lastQueueHash   map[string]string
-	lastQueueHashMu sync.Mutex
+	db            *database.DB
+	cache         cache.Store
+	sf            singleflight.Group
+	lastQueueHash *hashTracker
 }
 
 func NewRadarrHandler(db *database.DB, cache cache.Store) *RadarrHandler {
 	return &RadarrHandler{
 		db:            db,
 		cache:         cache,
-		lastQueueHash: make(map[string]string),
+		lastQueueHash: newHashTracker(db),
 	}
 }
 
@@ -45,22 +47,17 @@ func NewRadarrHandler(db *database.DB, cache cache.Store) *RadarrHandler {
 // It compares the current queue state with the previous state for a specific Radarr instance
 // Helps detect queue changes like new downloads starting, downloads completing, or status updates
 func (h *RadarrHandler) compareAndLogQueueChanges(instanceId string, queueResp *types.RadarrQueueResponse) {
-	h.lastQueueHashMu.Lock()
-	defer h.lastQueueHashMu.Unlock()
-
 	wrapped := wrapRadarrQueue(queueResp)
 	currentHash := generateQueueHash(wrapped)
-	lastHash := h.lastQueueHash[instanceId]
+	lastHash := h.lastQueueHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		changes := detectQueueChanges(lastHash, currentHash)
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
 			Int("totalRecords", queueResp.TotalRecords).
 			Str("change", changes).
 			Msg("[Radarr] Queue changed")
-
-		h.lastQueueHash[instanceId] = currentHash
 	}
 }
 
@@ -79,7 +76,23 @@ func (h *RadarrHandler) GetQueue(c *gin.Context) {
 		return
 	}
 
+	var options types.RadarrQueueOptions
+	for name, value := range map[string]*bool{
+		"includeUnknownMovieItems": &options.IncludeUnknownMovieItems,
+		"includeMovie":             &options.IncludeMovie,
+	} {
+		if err := queryBool(c, name, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+			return
+		}
+	}
+	if options != (types.RadarrQueueOptions{}) {
+		h.getQueueWithOptions(c, instanceId, options)
+		return
+	}
+
 	cacheKey := radarrQueuePrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -90,7 +103,7 @@ func (h *RadarrHandler) GetQueue(c *gin.Context) {
 			Str("instanceId", instanceId).
 			Int("totalRecords", queueResp.TotalRecords).
 			Msg("[Radarr] Serving queue from cache")
-		c.JSON(http.StatusOK, queueResp)
+		h.writeQueue(c, queueResp)
 
 		// Refresh cache in background using singleflight
 		go func() {
@@ -106,7 +119,7 @@ func (h *RadarrHandler) GetQueue(c *gin.Context) {
 	// If not in cache, fetch from service using singleflight
 	sfKey := fmt.Sprintf("queue:%s", instanceId)
 	queueRespI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheQueue(instanceId, cacheKey)
+		return h.fetchAndCacheQueue(ctx, instanceId, cacheKey)
 	})
 
 	if err != nil {
@@ -139,11 +152,61 @@ func (h *RadarrHandler) GetQueue(c *gin.Context) {
 			Msg("[Radarr] Retrieved empty queue")
 	}
 
+	h.writeQueue(c, queueResp)
+}
+
+// getQueueWithOptions responds with a queue fetched with non-default options, such as one
+// including unknown items. These queues are neither cached nor broadcast.
+func (h *RadarrHandler) getQueueWithOptions(c *gin.Context, instanceId string, options types.RadarrQueueOptions) {
+	radarrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Radarr] Failed to get configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Radarr configuration"})
+		return
+	}
+
+	if radarrConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Radarr is not configured"})
+		return
+	}
+
+	service := &radarr.RadarrService{}
+	radarrConfig.Configure(service)
+	records, err := service.GetQueueWithOptions(c.Request.Context(), radarrConfig.URL, radarrConfig.APIKey, options)
+	if err != nil {
+		var arrErr *arr.ErrArr
+		if errors.As(err, &arrErr) && arrErr.HttpCode > 0 {
+			c.JSON(arrErr.HttpCode, gin.H{"error": arrErr.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to fetch queue: %v", err)})
+		return
+	}
+
+	h.writeQueue(c, types.RadarrQueueResponse{Records: records, TotalRecords: len(records)})
+}
+
+// writeQueue responds with the queue, adding human readable sizes when asked for. The
+// records are paged when limit or offset is given.
+func (h *RadarrHandler) writeQueue(c *gin.Context, queueResp types.RadarrQueueResponse) {
+	limit, offset, paged, err := queryPage(c, maxListPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if wantsHumanized(c) {
+		queueResp = queueResp.Humanized()
+	}
+	if paged {
+		c.JSON(http.StatusOK, types.NewPagedResponse(queueResp.Records, limit, offset))
+		return
+	}
 	c.JSON(http.StatusOK, queueResp)
 }
 
-func (h *RadarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.RadarrQueueResponse, error) {
-	radarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+func (h *RadarrHandler) fetchAndCacheQueue(ctx context.Context, instanceId, cacheKey string) (types.RadarrQueueResponse, error) {
+	radarrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		return types.RadarrQueueResponse{}, err
 	}
@@ -154,9 +217,10 @@ func (h *RadarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.R
 
 	// Create Radarr service instance
 	service := &radarr.RadarrService{}
+	radarrConfig.Configure(service)
 
 	// Get queue records using the service
-	records, err := service.GetQueueForHealth(context.Background(), radarrConfig.URL, radarrConfig.APIKey)
+	records, err := service.GetQueueForHealth(ctx, radarrConfig.URL, radarrConfig.APIKey)
 	if err != nil {
 		return types.RadarrQueueResponse{}, err
 	}
@@ -168,7 +232,6 @@ func (h *RadarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.R
 	}
 
 	// Cache the results using the centralized cache duration
-	ctx := context.Background()
 	if err := h.cache.Set(ctx, cacheKey, queueResp, middleware.CacheDurations.RadarrStatus); err != nil {
 		log.Warn().
 			Err(err).
@@ -180,7 +243,7 @@ func (h *RadarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.R
 }
 
 func (h *RadarrHandler) refreshQueueCache(instanceId, cacheKey string) {
-	queueResp, err := h.fetchAndCacheQueue(instanceId, cacheKey)
+	queueResp, err := h.fetchAndCacheQueue(context.Background(), instanceId, cacheKey)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -220,24 +283,79 @@ func (h *RadarrHandler) broadcastRadarrQueue(instanceId string, queueResp *types
 
 	// Use the existing BroadcastHealth function with a special message type
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "ok",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
 		Message:     "radarr_queue",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
-			"radarr": queueResp,
+			"radarr": queueResp.Humanized(),
 		},
 		Details: map[string]interface{}{
 			"radarr": map[string]interface{}{
 				"totalRecords":     queueResp.TotalRecords,
 				"downloadingCount": downloading,
 				"totalSize":        totalSize,
+				"totalSizeHuman":   utils.FormatBytes(totalSize),
 			},
 		},
 	})
 }
 
 // DeleteQueueItem handles the deletion of a queue item with specified options
+// GetQueueItem returns a single queue item with its full status messages, for drilling into
+// why a download is stuck
+func (h *RadarrHandler) GetQueueItem(c *gin.Context) {
+	instanceId := c.Query("instanceId")
+	if instanceId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "instanceId is required"})
+		return
+	}
+
+	if !isServiceType(instanceId, "radarr") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Radarr instance ID"})
+		return
+	}
+
+	queueId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue item id"})
+		return
+	}
+
+	radarrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Radarr] Failed to get configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Radarr configuration"})
+		return
+	}
+
+	if radarrConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Radarr is not configured"})
+		return
+	}
+
+	service := &radarr.RadarrService{}
+	radarrConfig.Configure(service)
+	record, err := service.GetQueueItem(c.Request.Context(), radarrConfig.URL, radarrConfig.APIKey, queueId)
+	if err != nil {
+		var arrErr *arr.ErrArr
+		if errors.As(err, &arrErr) && arrErr.HttpCode > 0 {
+			c.JSON(arrErr.HttpCode, gin.H{"error": arrErr.Error()})
+			return
+		}
+		log.Error().Err(err).Str("instanceId", instanceId).Int("queueId", queueId).Msg("[Radarr] Failed to get queue item")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if wantsHumanized(c) {
+		record.SizeHuman = utils.FormatBytes(record.Size)
+		record.SizeLeftHuman = utils.FormatBytes(record.SizeLeft)
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
 func (h *RadarrHandler) DeleteQueueItem(c *gin.Context) {
 	instanceId := c.Query("instanceId")
 	if instanceId == "" {
@@ -259,7 +377,7 @@ func (h *RadarrHandler) DeleteQueueItem(c *gin.Context) {
 		ChangeCategory:   c.Query("changeCategory") == "true",
 	}
 
-	radarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+	radarrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Radarr] Failed to get configuration")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Radarr configuration"})
@@ -274,9 +392,41 @@ func (h *RadarrHandler) DeleteQueueItem(c *gin.Context) {
 
 	// Create Radarr service instance
 	service := &radarr.RadarrService{}
+	radarrConfig.Configure(service)
+
+	// Report what would be removed without deleting anything
+	if c.Query("dryRun") == "true" {
+		id, err := strconv.Atoi(queueId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue item id"})
+			return
+		}
+
+		record, err := service.GetQueueItem(c.Request.Context(), radarrConfig.URL, radarrConfig.APIKey, id)
+		if err != nil {
+			var arrErr *arr.ErrArr
+			if errors.As(err, &arrErr) && arrErr.HttpCode > 0 {
+				c.JSON(arrErr.HttpCode, gin.H{"error": arrErr.Error()})
+				return
+			}
+			log.Error().Err(err).Str("instanceId", instanceId).Int("queueId", id).Msg("[Radarr] Failed to get queue item")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, types.QueueDeletePreview{
+			DryRun:                true,
+			ID:                    record.ID,
+			Title:                 record.Title,
+			Status:                record.Status,
+			TrackedDownloadStatus: record.TrackedDownloadStatus,
+			Options:               options,
+		})
+		return
+	}
 
 	// Call the service method to delete the queue item
-	if err := service.DeleteQueueItem(context.Background(), radarrConfig.URL, radarrConfig.APIKey, queueId, options); err != nil {
+	if err := service.DeleteQueueItem(c.Request.Context(), radarrConfig.URL, radarrConfig.APIKey, queueId, options); err != nil {
 		if arrErr, ok := err.(*arr.ErrArr); ok {
 			log.Error().
 				Err(arrErr).
@@ -302,7 +452,7 @@ func (h *RadarrHandler) DeleteQueueItem(c *gin.Context) {
 	// Fetch fresh data and broadcast update using singleflight
 	sfKey := fmt.Sprintf("queue:%s", instanceId)
 	queueRespI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheQueue(instanceId, cacheKey)
+		return h.fetchAndCacheQueue(context.Background(), instanceId, cacheKey)
 	})
 
 	if err == nil {
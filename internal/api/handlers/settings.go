@@ -6,7 +6,10 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,17 +24,14 @@ import (
 	"github.com/autobrr/dashbrr/internal/types"
 )
 
-const (
-	configCacheKey    = "settings:configurations"
-	configCacheTTL    = 5 * time.Minute
-	configDebugLogTTL = 30 * time.Second
-)
+const configDebugLogTTL = 30 * time.Second
 
 type SettingsHandler struct {
 	db             *database.DB
 	health         *services.HealthService
 	cache          cache.Store
 	serviceManager *manager.ServiceManager
+	serviceCreator models.ServiceCreator
 	lastDebugLog   time.Time
 }
 
@@ -41,48 +41,22 @@ func NewSettingsHandler(db *database.DB, health *services.HealthService, cache c
 		health:         health,
 		cache:          cache,
 		serviceManager: manager.NewServiceManager(db, cache),
+		serviceCreator: models.NewServiceRegistry(),
 		lastDebugLog:   time.Now().Add(-configDebugLogTTL), // Initialize to ensure first log happens
 	}
 }
 
+// GetSettings returns every service configuration by instance id. They are read from the
+// database on every request rather than cached, as they hold the decrypted API keys and
+// the cache may be persisted to disk or Redis.
 func (h *SettingsHandler) GetSettings(c *gin.Context) {
-	// Try to get configurations from cache
-	var configurations []models.ServiceConfiguration
-	err := h.cache.Get(context.Background(), configCacheKey, &configurations)
-	if err == nil {
-		// Only log debug messages every 30 seconds to reduce spam
-		if time.Since(h.lastDebugLog) > configDebugLogTTL {
-			for _, config := range configurations {
-				log.Debug().
-					Str("instance", config.InstanceID).
-					Str("display_name", config.DisplayName).
-					Msg("Loading configuration from cache")
-			}
-			log.Info().Int("count", len(configurations)).Msg("Returning cached configurations")
-			h.lastDebugLog = time.Now()
-		}
-
-		configMap := make(map[string]models.ServiceConfiguration)
-		for _, config := range configurations {
-			configMap[config.InstanceID] = config
-		}
-		c.JSON(http.StatusOK, configMap)
-		return
-	}
-
-	// If not in cache, fetch from database
-	configurations, err = h.db.GetAllServices(context.Background())
+	configurations, err := h.db.GetAllServices(c.Request.Context(), true)
 	if err != nil {
 		log.Error().Err(err).Msg("Error fetching configurations")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch settings"})
 		return
 	}
 
-	// Cache the configurations
-	if err := h.cache.Set(context.Background(), configCacheKey, configurations, configCacheTTL); err != nil {
-		log.Warn().Err(err).Msg("Failed to cache configurations")
-	}
-
 	// Log configurations (with rate limiting)
 	if time.Since(h.lastDebugLog) > configDebugLogTTL {
 		for _, config := range configurations {
@@ -102,6 +76,103 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 	c.JSON(http.StatusOK, configMap)
 }
 
+// maxServicesPageSize caps the limit accepted by ListServices
+const maxServicesPageSize = 100
+
+// maxListPageSize caps the limit accepted by the queue and request lists
+const maxListPageSize = 500
+
+// ListServices returns a page of service configurations, optionally filtered by
+// service type and tag, along with the total number of matches
+func (h *SettingsHandler) ListServices(c *gin.Context) {
+	params := types.ListServicesParams{
+		Type: c.Query("type"),
+		Tag:  c.Query("tag"),
+	}
+
+	var err error
+	if params.Limit, params.Offset, _, err = queryPage(c, maxServicesPageSize); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	services, total, err := h.db.ListServices(c.Request.Context(), params)
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list services"})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.PagedResponse[models.ServiceConfiguration]{
+		Items:  services,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+}
+
+// queryInt parses an optional non-negative integer query parameter
+func queryInt(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, strconv.ErrRange
+	}
+	return n, nil
+}
+
+// queryPage parses the optional limit and offset query parameters, paged reports whether
+// either was given. A limit above maxLimit is rejected.
+func queryPage(c *gin.Context, maxLimit int) (limit, offset int, paged bool, err error) {
+	if limit, err = queryInt(c, "limit"); err != nil || limit > maxLimit {
+		return 0, 0, false, fmt.Errorf("limit must be between 0 and %d", maxLimit)
+	}
+	if offset, err = queryInt(c, "offset"); err != nil {
+		return 0, 0, false, errors.New("offset must be a non-negative number")
+	}
+	return limit, offset, c.Query("limit") != "" || c.Query("offset") != "", nil
+}
+
+// queryBool overrides value with an optional boolean query parameter
+func queryBool(c *gin.Context, name string, value *bool) error {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return err
+	}
+	*value = b
+	return nil
+}
+
+// wantsHumanized reports whether the client asked for human readable sizes next to the raw values
+func wantsHumanized(c *gin.Context) bool {
+	humanize, _ := strconv.ParseBool(c.Query("humanize"))
+	return humanize
+}
+
+// abortReadOnly rejects changes to a service defined in the config file
+func abortReadOnly(c *gin.Context, instanceID string) {
+	log.Warn().Str("instance", instanceID).Msg("Refusing to change a service defined in the config file")
+	c.JSON(http.StatusForbidden, gin.H{"error": "Service is defined in the config file and can't be changed through the API"})
+}
+
+// isServiceType reports whether an instance id such as "plex-1" belongs to the service type
+func isServiceType(instanceID, serviceType string) bool {
+	prefix, _, _ := strings.Cut(instanceID, "-")
+	return prefix == serviceType
+}
+
 func (h *SettingsHandler) SaveSettings(c *gin.Context) {
 	instanceID := c.Param("instance")
 
@@ -115,19 +186,53 @@ func (h *SettingsHandler) SaveSettings(c *gin.Context) {
 	config.InstanceID = instanceID
 	config.URL = strings.TrimRight(config.URL, "/")
 
+	if err := config.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Optionally test the connection first, refusing to save a service that can't be reached
+	if c.Query("validate") == "true" {
+		serviceType, _, _ := strings.Cut(instanceID, "-")
+		result, err := h.validateService(c.Request.Context(), types.ValidateServiceRequest{
+			Type:           serviceType,
+			URL:            config.URL,
+			APIKey:         config.APIKey,
+			APIVersion:     config.APIVersion,
+			Method:         config.Method,
+			ExpectedStatus: config.ExpectedStatus,
+			AuthHeaderName: config.AuthHeaderName,
+			ClientCert:     config.ClientCert,
+			ClientKey:      config.ClientKey,
+		})
+		if err != nil {
+			abortUnknownServiceType(c, err)
+			return
+		}
+		if !result.Valid {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Service validation failed", "validation": result})
+			return
+		}
+	}
+
 	log.Debug().
 		Str("instance", instanceID).
 		Interface("config", config).
 		Msg("Saving configuration")
 
 	// Check if configuration exists
-	existing, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceID})
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
 	if err != nil && err != sql.ErrNoRows {
 		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
 		return
 	}
 
+	if existing != nil && existing.ReadOnly {
+		abortReadOnly(c, instanceID)
+		return
+	}
+
 	// If updating, stop health monitoring first
 	if existing != nil && h.health != nil {
 		h.health.StopMonitoring(instanceID)
@@ -153,20 +258,482 @@ func (h *SettingsHandler) SaveSettings(c *gin.Context) {
 	// Initialize service data
 	h.serviceManager.InitializeService(c.Request.Context(), &config)
 
-	// Invalidate cache
-	if err := h.cache.Delete(context.Background(), configCacheKey); err != nil {
-		log.Warn().Err(err).Msg("Failed to delete configuration cache")
-	}
-
 	log.Info().Str("instance", instanceID).Msg("Successfully saved configuration")
 	c.JSON(http.StatusOK, config)
 }
 
+// UpdateServiceFields applies a partial update to a service, e.g. to rotate an API key
+// without resending the URL
+func (h *SettingsHandler) UpdateServiceFields(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	var params types.UpdateServiceParams
+	if err := c.BindJSON(&params); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error binding JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if params.IsEmpty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	if params.URL != nil {
+		url := strings.TrimRight(*params.URL, "/")
+		params.URL = &url
+	}
+
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+		return
+	}
+
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		return
+	}
+
+	if existing.ReadOnly {
+		abortReadOnly(c, instanceID)
+		return
+	}
+
+	// The fields are checked as the service is after the update, e.g. the certificate and
+	// key as the pair they form
+	merged := *existing
+	params.ApplyTo(&merged)
+	if err := merged.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.health != nil {
+		h.health.StopMonitoring(instanceID)
+	}
+
+	if err := h.db.UpdateServiceFields(c.Request.Context(), instanceID, params); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error updating configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update settings"})
+		return
+	}
+
+	updated, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil || updated == nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error fetching updated configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated settings"})
+		return
+	}
+
+	// Initialize service data
+	h.serviceManager.InitializeService(c.Request.Context(), updated)
+
+	log.Info().Str("instance", instanceID).Msg("Successfully updated configuration")
+	c.JSON(http.StatusOK, updated)
+}
+
+// CloneService copies an existing service configuration into a new instance id of the
+// same type. The API key is not copied, so it has to be entered for the new instance.
+func (h *SettingsHandler) CloneService(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	var req types.CloneServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	sourceType, _, _ := strings.Cut(instanceID, "-")
+	targetType, suffix, found := strings.Cut(req.InstanceID, "-")
+	if !found || suffix == "" || targetType != sourceType {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Instance id must be of the form " + sourceType + "-<name>"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	source, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error fetching configuration to clone")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch configuration"})
+		return
+	}
+	if source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		return
+	}
+
+	existing, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: req.InstanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", req.InstanceID).Msg("Error checking existing configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+		return
+	}
+	if existing != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Instance id already exists"})
+		return
+	}
+
+	clone := models.ServiceConfiguration{
+		InstanceID:           req.InstanceID,
+		DisplayName:          source.DisplayName,
+		URL:                  source.URL,
+		AccessURL:            source.AccessURL,
+		Tags:                 source.Tags,
+		APIVersion:           source.APIVersion,
+		Color:                source.Color,
+		Icon:                 source.Icon,
+		CheckIntervalSeconds: source.CheckIntervalSeconds,
+		Method:               source.Method,
+		ExpectedStatus:       source.ExpectedStatus,
+		Critical:             source.Critical,
+		AuthHeaderName:       source.AuthHeaderName,
+		Notes:                source.Notes,
+		ExpectedVersion:      source.ExpectedVersion,
+	}
+	if req.DisplayName != "" {
+		clone.DisplayName = req.DisplayName
+	}
+
+	if err := h.db.CreateService(ctx, &clone); err != nil {
+		log.Error().Err(err).Str("instance", req.InstanceID).Msg("Error saving cloned configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save settings"})
+		return
+	}
+
+	log.Info().Str("source", instanceID).Str("instance", req.InstanceID).Msg("Successfully cloned configuration")
+	c.JSON(http.StatusCreated, clone)
+}
+
+// maxBatchServices bounds the number of services created by one batch request
+const maxBatchServices = 100
+
+// CreateServices creates several services in one request, e.g. when provisioning a fresh
+// instance. Services that fail validation are reported and skipped, the valid ones are
+// created in a single transaction that is rolled back as a whole on a database error.
+func (h *SettingsHandler) CreateServices(c *gin.Context) {
+	var configs []models.ServiceConfiguration
+	if err := c.ShouldBindJSON(&configs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(configs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No services to create"})
+		return
+	}
+	if len(configs) > maxBatchServices {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d services can be created at once", maxBatchServices)})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	response := types.BatchCreateServicesResponse{Results: make([]types.BatchServiceResult, len(configs))}
+	var valid []*models.ServiceConfiguration
+	var validIdx []int
+	seen := make(map[string]bool, len(configs))
+
+	for i := range configs {
+		config := &configs[i]
+		config.URL = strings.TrimRight(config.URL, "/")
+		response.Results[i].InstanceID = config.InstanceID
+
+		serviceType, suffix, _ := strings.Cut(config.InstanceID, "-")
+		_, typeErr := models.CreateServiceE(h.serviceCreator, serviceType)
+		switch {
+		case serviceType == "" || suffix == "":
+			response.Results[i].Error = "Instance id must be of the form <type>-<name>"
+		case typeErr != nil:
+			response.Results[i].Error = typeErr.Error()
+		case seen[config.InstanceID]:
+			response.Results[i].Error = "Duplicate instance id in batch"
+		}
+		if response.Results[i].Error == "" {
+			if err := config.Validate(); err != nil {
+				response.Results[i].Error = err.Error()
+			}
+		}
+		if response.Results[i].Error != "" {
+			continue
+		}
+		seen[config.InstanceID] = true
+
+		existing, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: config.InstanceID})
+		if err != nil {
+			log.Error().Err(err).Str("instance", config.InstanceID).Msg("Error checking existing configuration")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+			return
+		}
+		if existing != nil {
+			response.Results[i].Error = "Instance id already exists"
+			continue
+		}
+
+		valid = append(valid, config)
+		validIdx = append(validIdx, i)
+	}
+
+	if len(valid) > 0 {
+		if err := h.db.CreateServices(ctx, valid); err != nil {
+			log.Error().Err(err).Int("count", len(valid)).Msg("Error saving batch of configurations")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save settings, no services were created"})
+			return
+		}
+
+		for n, config := range valid {
+			response.Results[validIdx[n]].Created = true
+			h.serviceManager.InitializeService(ctx, config)
+		}
+		response.Created = len(valid)
+
+	}
+
+	log.Info().Int("created", response.Created).Int("requested", len(configs)).Msg("Processed batch of configurations")
+	c.JSON(http.StatusOK, response)
+}
+
+// MuteService silences a degraded service until the requested time. The service keeps being
+// checked, but its health is broadcast with the muted flag set.
+func (h *SettingsHandler) MuteService(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	var req types.MuteServiceRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error binding JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	var until time.Time
+	switch {
+	case req.Until != nil:
+		until = *req.Until
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mute duration"})
+			return
+		}
+		until = time.Now().Add(duration)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Either until or duration is required"})
+		return
+	}
+
+	if !until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Mute time must be in the future"})
+		return
+	}
+
+	h.setServiceMute(c, instanceID, &until)
+}
+
+// UnmuteService clears the mute on a service before it expires
+func (h *SettingsHandler) UnmuteService(c *gin.Context) {
+	h.setServiceMute(c, c.Param("instanceId"), nil)
+}
+
+func (h *SettingsHandler) setServiceMute(c *gin.Context, instanceID string, until *time.Time) {
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+		return
+	}
+
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		return
+	}
+
+	if err := h.db.SetServiceMute(c.Request.Context(), instanceID, until); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error updating mute state")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update mute state"})
+		return
+	}
+
+	existing.MutedUntil = until
+	if until != nil {
+		log.Info().Str("instance", instanceID).Time("muted_until", *until).Msg("Muted service")
+	} else {
+		log.Info().Str("instance", instanceID).Msg("Unmuted service")
+	}
+
+	c.JSON(http.StatusOK, existing)
+}
+
+// SetServiceEnabled enables or disables polling for a service. Disabled services keep their
+// configuration but are excluded from health checks and the SSE stream.
+func (h *SettingsHandler) SetServiceEnabled(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	var req types.SetServiceEnabledRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error binding JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+		return
+	}
+
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		return
+	}
+
+	enabled := *req.Enabled
+	if err := h.db.SetServiceEnabled(c.Request.Context(), instanceID, enabled); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error updating enabled state")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update enabled state"})
+		return
+	}
+	existing.Disabled = !enabled
+
+	if enabled {
+		h.serviceManager.InitializeService(c.Request.Context(), existing)
+	} else {
+		if h.health != nil {
+			h.health.StopMonitoring(instanceID)
+		}
+
+		// Drop the last known health so it isn't replayed to new clients
+		if err := h.cache.Delete(context.Background(), cache.PrefixHealth+instanceID); err != nil {
+			log.Debug().Err(err).Str("instance", instanceID).Msg("Failed to delete cached health")
+		}
+	}
+
+	log.Info().Str("instance", instanceID).Bool("enabled", enabled).Msg("Updated service enabled state")
+	c.JSON(http.StatusOK, existing)
+}
+
+// TogglePinned pins a service to the top of the dashboard, or unpins it if it is pinned
+func (h *SettingsHandler) TogglePinned(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+		return
+	}
+
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		return
+	}
+
+	pinned := !existing.Pinned
+	if err := h.db.SetServicePinned(c.Request.Context(), instanceID, pinned); err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error updating pinned state")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pinned state"})
+		return
+	}
+	existing.Pinned = pinned
+
+	// Resend the last known health so clients show the pin without waiting for a check
+	var health models.ServiceHealth
+	if err := h.cache.Get(c.Request.Context(), cache.PrefixHealth+instanceID, &health); err == nil {
+		health.Pinned = pinned
+		if err := h.cache.Set(c.Request.Context(), cache.PrefixHealth+instanceID, health, cache.HealthTTL); err != nil {
+			log.Debug().Err(err).Str("instance", instanceID).Msg("Failed to update cached health")
+		}
+		BroadcastHealth(health)
+	}
+
+	log.Info().Str("instance", instanceID).Bool("pinned", pinned).Msg("Updated service pinned state")
+	c.JSON(http.StatusOK, existing)
+}
+
+// ReorderServices stores the dashboard order of services. Services not listed keep their
+// position, pinned services sort first regardless.
+func (h *SettingsHandler) ReorderServices(c *gin.Context) {
+	var req types.ReorderServicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	services, err := h.db.GetAllServices(c.Request.Context(), true)
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	configured := make(map[string]bool, len(services))
+	for _, service := range services {
+		configured[service.InstanceID] = true
+	}
+
+	seen := make(map[string]bool, len(req.InstanceIDs))
+	for _, instanceID := range req.InstanceIDs {
+		if !configured[instanceID] {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found: " + instanceID})
+			return
+		}
+		if seen[instanceID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Duplicate instance id: " + instanceID})
+			return
+		}
+		seen[instanceID] = true
+	}
+
+	if err := h.db.ReorderServices(c.Request.Context(), req.InstanceIDs); err != nil {
+		log.Error().Err(err).Msg("Error reordering services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder services"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Services reordered"})
+}
+
+// GetServiceLink returns the URL users should open for a service, optionally deep-linking to
+// a path on it
+func (h *SettingsHandler) GetServiceLink(c *gin.Context) {
+	instanceID := c.Param("instanceId")
+
+	linkPath := c.Query("path")
+	if strings.Contains(linkPath, "://") || strings.HasPrefix(linkPath, "//") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "path must be relative to the service"})
+		return
+	}
+
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
+		return
+	}
+
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		return
+	}
+
+	link, source := existing.LinkURL()
+	if link == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service has no URL"})
+		return
+	}
+	if linkPath != "" {
+		link += "/" + strings.TrimLeft(linkPath, "/")
+	}
+
+	c.JSON(http.StatusOK, types.ServiceLinkResponse{InstanceID: instanceID, URL: link, Source: source})
+}
+
 func (h *SettingsHandler) DeleteSettings(c *gin.Context) {
 	instanceID := c.Param("instance")
 
 	// Check if configuration exists before deleting
-	existing, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceID})
+	existing, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceID})
 	if err != nil {
 		log.Error().Err(err).Str("instance", instanceID).Msg("Error checking existing configuration")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
@@ -179,6 +746,11 @@ func (h *SettingsHandler) DeleteSettings(c *gin.Context) {
 		return
 	}
 
+	if existing.ReadOnly {
+		abortReadOnly(c, instanceID)
+		return
+	}
+
 	// Stop health monitoring before deleting
 	if h.health != nil {
 		log.Debug().Str("instance", instanceID).Msg("Stopping health monitoring")
@@ -192,11 +764,6 @@ func (h *SettingsHandler) DeleteSettings(c *gin.Context) {
 		return
 	}
 
-	// Invalidate cache
-	if err := h.cache.Delete(context.Background(), configCacheKey); err != nil {
-		log.Warn().Err(err).Msg("Failed to delete configuration cache")
-	}
-
 	log.Info().Str("instance", instanceID).Msg("Successfully deleted configuration")
 	c.JSON(http.StatusOK, gin.H{"message": "Configuration deleted successfully"})
 }
@@ -0,0 +1,421 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+// setupTestCache returns a memory cache persisting to a temporary directory, closed when the
+// test ends
+func setupTestCache(t *testing.T) cache.Store {
+	t.Helper()
+
+	store := cache.NewMemoryStore(context.Background(), t.TempDir())
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// setupTestDB returns a SQLite database and a memory cache in temporary directories, the
+// dependencies most handlers are created with
+func setupTestDB(t *testing.T) (*database.DB, cache.Store) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to init database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, setupTestCache(t)
+}
+
+// newTestContext returns a gin context for calling a handler directly with the given request
+func newTestContext(method, target string, body io.Reader) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, body)
+	return c, w
+}
+
+func setupSettingsHandler(t *testing.T) (*SettingsHandler, *database.DB) {
+	t.Helper()
+
+	db, store := setupTestDB(t)
+	return NewSettingsHandler(db, nil, store), db
+}
+
+func TestSettingsHandler_GetServiceLink(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+	ctx := context.Background()
+
+	for _, service := range []*models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989/"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://radarr:7878", AccessURL: "https://radarr.example.com"},
+	} {
+		if err := db.CreateService(ctx, service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	link := func(instanceID, query string) (int, types.ServiceLinkResponse) {
+		c, w := newTestContext(http.MethodGet, "/api/services/"+instanceID+"/link"+query, nil)
+		c.Params = gin.Params{{Key: "instanceId", Value: instanceID}}
+
+		handler.GetServiceLink(c)
+
+		var resp types.ServiceLinkResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	if code, resp := link("sonarr-1", ""); code != http.StatusOK || resp.URL != "http://sonarr:8989" || resp.Source != "url" {
+		t.Errorf("Expected the service URL, got %d %+v", code, resp)
+	}
+	if _, resp := link("radarr-1", "?path=/activity/queue"); resp.URL != "https://radarr.example.com/activity/queue" || resp.Source != "accessUrl" {
+		t.Errorf("Expected the access URL with the path, got %+v", resp)
+	}
+	if code, _ := link("radarr-1", "?path=https://evil.example.com"); code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an absolute path, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := link("plex-1", ""); code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown service, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestSettingsHandler_ReadOnlyService(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+
+	if err := db.SyncConfigServices(context.Background(), []*models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989"},
+	}); err != nil {
+		t.Fatalf("Failed to sync services: %v", err)
+	}
+
+	c, w := newTestContext(http.MethodDelete, "/api/settings/sonarr-1", nil)
+	c.Params = gin.Params{{Key: "instance", Value: "sonarr-1"}}
+
+	handler.DeleteSettings(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, w.Code)
+	}
+	if existing, _ := db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: "sonarr-1"}); existing == nil {
+		t.Error("Expected the service defined in the config file to be kept")
+	}
+}
+
+func TestSettingsHandler_ListServicesPaged(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"sonarr-1", "sonarr-2", "radarr-1"} {
+		if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: id, DisplayName: id}); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	list := func(query string) (int, map[string]json.RawMessage) {
+		c, w := newTestContext(http.MethodGet, "/api/services"+query, nil)
+
+		handler.ListServices(c)
+
+		var resp map[string]json.RawMessage
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	// Without paging parameters every service is returned in the same shape
+	code, resp := list("")
+	if code != http.StatusOK || resp["services"] != nil {
+		t.Fatalf("Expected a paged response, got %d %v", code, resp)
+	}
+	var items []models.ServiceConfiguration
+	if err := json.Unmarshal(resp["items"], &items); err != nil || len(items) != 3 {
+		t.Errorf("Expected 3 items, got %s", resp["items"])
+	}
+	if string(resp["total"]) != "3" || string(resp["limit"]) != "0" || string(resp["offset"]) != "0" {
+		t.Errorf("Unexpected paging metadata: %v", resp)
+	}
+
+	// An empty page still has an items array
+	if code, resp = list("?type=lidarr"); code != http.StatusOK || string(resp["items"]) != "[]" || string(resp["total"]) != "0" {
+		t.Errorf("Expected an empty page, got %d %v", code, resp)
+	}
+
+	code, resp = list("?limit=2&offset=1")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+	}
+	items = nil
+	if err := json.Unmarshal(resp["items"], &items); err != nil || len(items) != 2 {
+		t.Errorf("Expected 2 items, got %s", resp["items"])
+	}
+	if string(resp["total"]) != "3" || string(resp["limit"]) != "2" || string(resp["offset"]) != "1" {
+		t.Errorf("Unexpected paging metadata: %v", resp)
+	}
+
+	if code, _ := list("?limit=1000"); code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a limit above the maximum, got %d", http.StatusBadRequest, code)
+	}
+}
+
+func TestSettingsHandler_CloneService(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+	ctx := context.Background()
+
+	certPEM, keyPEM := testClientCertificate(t)
+	for _, service := range []*models.ServiceConfiguration{
+		{
+			InstanceID:  "sonarr-1",
+			DisplayName: "Sonarr",
+			URL:         "http://sonarr:8989",
+			APIKey:      "sonarr-key",
+			Tags:        []string{"tv"},
+			Notes:       "4K library",
+			ClientCert:  certPEM,
+			ClientKey:   keyPEM,
+		},
+		{InstanceID: "sonarr-2", DisplayName: "Sonarr 2", URL: "http://sonarr2:8989"},
+	} {
+		if err := db.CreateService(ctx, service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	clone := func(instanceID, body string) (int, models.ServiceConfiguration) {
+		c, w := newTestContext(http.MethodPost, "/api/services/"+instanceID+"/clone", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "instanceId", Value: instanceID}}
+
+		handler.CloneService(c)
+
+		var resp models.ServiceConfiguration
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	tests := []struct {
+		name         string
+		source       string
+		body         string
+		expectedCode int
+	}{
+		{"Type mismatch", "sonarr-1", `{"instanceId":"radarr-4k"}`, http.StatusBadRequest},
+		{"Target without a name", "sonarr-1", `{"instanceId":"sonarr-"}`, http.StatusBadRequest},
+		{"Missing instance id", "sonarr-1", `{}`, http.StatusBadRequest},
+		{"Missing source", "sonarr-9", `{"instanceId":"sonarr-4k"}`, http.StatusNotFound},
+		{"Existing target", "sonarr-1", `{"instanceId":"sonarr-2"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := clone(tt.source, tt.body); code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, code)
+			}
+		})
+	}
+
+	// The existing target is left as it was
+	if existing, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-2"}); err != nil || existing.DisplayName != "Sonarr 2" {
+		t.Errorf("Expected sonarr-2 to be unchanged, got %+v (%v)", existing, err)
+	}
+
+	code, resp := clone("sonarr-1", `{"instanceId":"sonarr-4k","displayName":"Sonarr 4K"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, code)
+	}
+	if resp.InstanceID != "sonarr-4k" || resp.DisplayName != "Sonarr 4K" || resp.URL != "http://sonarr:8989" || resp.Notes != "4K library" {
+		t.Errorf("Expected the settings to be copied, got %+v", resp)
+	}
+
+	// Credentials are never copied, the clone needs its own
+	stored, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-4k"})
+	if err != nil || stored == nil {
+		t.Fatalf("Expected the clone to be stored, got %v", err)
+	}
+	for _, service := range []models.ServiceConfiguration{resp, *stored} {
+		if service.APIKey != "" || service.ClientKey != "" || service.ClientCert != "" {
+			t.Errorf("Expected the API key and client certificate not to be copied, got %q, %q and %q",
+				service.APIKey, service.ClientKey, service.ClientCert)
+		}
+	}
+	if len(stored.Tags) != 1 || stored.Tags[0] != "tv" {
+		t.Errorf("Expected the tags to be copied, got %v", stored.Tags)
+	}
+}
+
+func TestSettingsHandler_TogglePinned(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+
+	if err := db.CreateService(context.Background(), &models.ServiceConfiguration{
+		InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989",
+	}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	toggle := func(instanceID string) (int, models.ServiceConfiguration) {
+		c, w := newTestContext(http.MethodPost, "/api/services/"+instanceID+"/pin", nil)
+		c.Params = gin.Params{{Key: "instanceId", Value: instanceID}}
+
+		handler.TogglePinned(c)
+
+		var resp models.ServiceConfiguration
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	if code, resp := toggle("sonarr-1"); code != http.StatusOK || !resp.Pinned {
+		t.Errorf("Expected the service to be pinned, got %d %+v", code, resp)
+	}
+	if code, resp := toggle("sonarr-1"); code != http.StatusOK || resp.Pinned {
+		t.Errorf("Expected the service to be unpinned, got %d %+v", code, resp)
+	}
+	if code, _ := toggle("radarr-1"); code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown service, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestSettingsHandler_DatabaseURL(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+
+	if err := db.CreateService(context.Background(), &models.ServiceConfiguration{
+		InstanceID: "database-1", DisplayName: "Postgres", URL: "postgres://dashbrr@db:5432/media",
+	}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	save := func(url string) int {
+		c, w := newTestContext(http.MethodPost, "/api/settings/database-2", strings.NewReader(`{"displayName":"Postgres","url":"`+url+`"}`))
+		c.Params = gin.Params{{Key: "instance", Value: "database-2"}}
+		handler.SaveSettings(c)
+		return w.Code
+	}
+	patch := func(url string) int {
+		c, w := newTestContext(http.MethodPatch, "/api/services/database-1", strings.NewReader(`{"url":"`+url+`"}`))
+		c.Params = gin.Params{{Key: "instanceId", Value: "database-1"}}
+		handler.UpdateServiceFields(c)
+		return w.Code
+	}
+	batch := func(url string) string {
+		c, w := newTestContext(http.MethodPost, "/api/services/batch", strings.NewReader(`[{"instanceId":"database-3","displayName":"Postgres","url":"`+url+`"}]`))
+		handler.CreateServices(c)
+		var resp types.BatchCreateServicesResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if len(resp.Results) != 1 {
+			t.Fatalf("Expected one batch result, got %s", w.Body.String())
+		}
+		return resp.Results[0].Error
+	}
+
+	for _, url := range []string{"postgres://dashbrr:s3cret@db:5432/media", "sqlite:///data/dashbrr.db"} {
+		if code := save(url); code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d saving %s, got %d", http.StatusBadRequest, url, code)
+		}
+		if code := patch(url); code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d patching %s, got %d", http.StatusBadRequest, url, code)
+		}
+		if err := batch(url); err == "" {
+			t.Errorf("Expected the batch to refuse %s", url)
+		}
+	}
+
+	if code := patch("mysql://dashbrr@db:3306/media"); code != http.StatusOK {
+		t.Errorf("Expected status code %d for a supported URL, got %d", http.StatusOK, code)
+	}
+}
+
+// testClientCertificate returns a self-signed client certificate and its key as PEM
+func testClientCertificate(t *testing.T) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dashbrr"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestSettingsHandler_UpdateClientCertificate(t *testing.T) {
+	handler, db := setupSettingsHandler(t)
+
+	certPEM, keyPEM := testClientCertificate(t)
+	otherCert, otherKey := testClientCertificate(t)
+
+	if err := db.CreateService(context.Background(), &models.ServiceConfiguration{
+		InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989", ClientCert: certPEM, ClientKey: keyPEM,
+	}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	patch := func(params types.UpdateServiceParams) (int, string) {
+		body, _ := json.Marshal(params)
+		c, w := newTestContext(http.MethodPatch, "/api/services/sonarr-1", strings.NewReader(string(body)))
+		c.Params = gin.Params{{Key: "instanceId", Value: "sonarr-1"}}
+		handler.UpdateServiceFields(c)
+		return w.Code, w.Body.String()
+	}
+
+	// Either half alone is checked against the other half already stored
+	if code, _ := patch(types.UpdateServiceParams{ClientKey: &otherKey}); code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a key of another certificate, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := patch(types.UpdateServiceParams{ClientCert: &otherCert}); code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a certificate of another key, got %d", http.StatusBadRequest, code)
+	}
+
+	// Paths aren't read, and the error doesn't tell whether the file exists
+	path := "/etc/hostname"
+	code, body := patch(types.UpdateServiceParams{ClientKey: &path})
+	if code != http.StatusBadRequest || strings.Contains(body, path) {
+		t.Errorf("Expected a generic error for a path, got %d %s", code, body)
+	}
+
+	if code, body := patch(types.UpdateServiceParams{ClientCert: &otherCert, ClientKey: &otherKey}); code != http.StatusOK {
+		t.Errorf("Expected status code %d for a matching pair, got %d %s", http.StatusOK, code, body)
+	}
+	updated, err := db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil || updated.ClientKey != otherKey {
+		t.Errorf("Expected the new client key to be stored, got %v", err)
+	}
+}
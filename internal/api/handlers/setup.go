@@ -0,0 +1,158 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/config"
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/services/manager"
+	"github.com/autobrr/dashbrr/internal/types"
+	"github.com/autobrr/dashbrr/internal/utils"
+)
+
+// SetupHandler backs the first-run onboarding flow
+type SetupHandler struct {
+	db             *database.DB
+	cache          cache.Store
+	serviceManager *manager.ServiceManager
+	authMode       string
+}
+
+// NewSetupHandler creates a setup handler. authMode is the auth mode in effect, one of
+// config.AuthModeLocal, config.AuthModeOIDC or config.AuthModeNone.
+func NewSetupHandler(db *database.DB, cache cache.Store, authMode string) *SetupHandler {
+	return &SetupHandler{
+		db:             db,
+		cache:          cache,
+		serviceManager: manager.NewServiceManager(db, cache),
+		authMode:       authMode,
+	}
+}
+
+// GetSetupStatus reports what is still missing before dashbrr is usable
+func (h *SetupHandler) GetSetupStatus(c *gin.Context) {
+	hasUsers, err := h.db.HasUsers(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check existing users")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	services, err := h.db.GetAllServices(c.Request.Context(), true)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch services")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SetupStatus{
+		HasUsers:    hasUsers,
+		HasServices: len(services) > 0,
+		AuthMode:    h.authMode,
+		// Only built-in auth has a first user to create
+		SetupRequired: h.authMode == config.AuthModeLocal && !hasUsers,
+	})
+}
+
+// Setup creates the first admin user and, optionally, an initial service. It is only
+// available with built-in auth and until the first user exists.
+func (h *SetupHandler) Setup(c *gin.Context) {
+	if h.authMode != config.AuthModeLocal {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Setup is only available with built-in authentication"})
+		return
+	}
+
+	hasUsers, err := h.db.HasUsers(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check existing users")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if hasUsers {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Setup is already complete"})
+		return
+	}
+
+	var req types.SetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	if err := utils.ValidatePassword(req.User.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var service *models.ServiceConfiguration
+	if req.Service != nil {
+		service = req.Service
+		service.URL = strings.TrimRight(service.URL, "/")
+
+		serviceType, _, _ := strings.Cut(service.InstanceID, "-")
+		if !slices.Contains(models.SupportedServiceTypes, serviceType) {
+			abortUnknownServiceType(c, &models.ErrUnknownServiceType{Type: serviceType, Supported: models.SupportedServiceTypes})
+			return
+		}
+		if service.DisplayName == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Service display name is required"})
+			return
+		}
+		if err := service.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	hashedPassword, err := utils.HashPassword(req.User.Password)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to hash password")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	user := &types.User{
+		Username:     req.User.Username,
+		Email:        req.User.Email,
+		PasswordHash: hashedPassword,
+	}
+
+	if err := h.db.CompleteSetup(c.Request.Context(), user, service); err != nil {
+		if errors.Is(err, database.ErrSetupComplete) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Setup is already complete"})
+			return
+		}
+		log.Error().Err(err).Msg("failed to complete setup")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete setup"})
+		return
+	}
+
+	response := gin.H{
+		"message": "Setup completed successfully",
+		"user": gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+			"email":    user.Email,
+		},
+	}
+
+	if service != nil {
+		h.serviceManager.InitializeService(c.Request.Context(), service)
+
+		response["service"] = service
+	}
+
+	log.Info().Str("username", user.Username).Bool("service", service != nil).Msg("First-run setup completed")
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/config"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestSetup(t *testing.T) {
+	settings, db := setupSettingsHandler(t)
+	handler := NewSetupHandler(db, settings.cache, config.AuthModeLocal)
+
+	router := gin.New()
+	router.GET("/api/setup/status", handler.GetSetupStatus)
+	router.POST("/api/setup", handler.Setup)
+
+	status := func() types.SetupStatus {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/setup/status", nil))
+		var status types.SetupStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to decode status: %v", err)
+		}
+		return status
+	}
+	setup := func(body string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/setup", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if s := status(); !s.SetupRequired || s.HasUsers || s.HasServices || s.AuthMode != config.AuthModeLocal {
+		t.Fatalf("Unexpected initial status: %+v", s)
+	}
+
+	user := `"user":{"username":"admin","email":"admin@example.com","password":"Sup3r-secret!"}`
+
+	// An unknown service type must not leave a user behind
+	if code := setup(`{` + user + `,"service":{"instanceId":"unknown-1","displayName":"Unknown","url":"http://localhost"}}`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown service type, got %d", code)
+	}
+	if s := status(); s.HasUsers {
+		t.Fatal("Expected no user after a failed setup")
+	}
+
+	if code := setup(`{` + user + `,"service":{"instanceId":"sonarr-1","displayName":"Sonarr","url":"http://sonarr:8989/"}}`); code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", code)
+	}
+	if s := status(); s.SetupRequired || !s.HasUsers || !s.HasServices {
+		t.Errorf("Unexpected status after setup: %+v", s)
+	}
+
+	if code := setup(`{"user":{"username":"other","email":"other@example.com","password":"Sup3r-secret!"}}`); code != http.StatusForbidden {
+		t.Errorf("Expected 403 once a user exists, got %d", code)
+	}
+
+	oidc := NewSetupHandler(db, settings.cache, config.AuthModeOIDC)
+	c, w := newTestContext(http.MethodPost, "/api/setup", strings.NewReader(`{`+user+`}`))
+	oidc.Setup(c)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 with OIDC auth, got %d", w.Code)
+	}
+}
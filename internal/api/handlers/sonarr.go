@@ -5,9 +5,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,11 +17,13 @@ import (
 
 	"github.com/autobrr/dashbrr/internal/api/middleware"
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/arr"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/sonarr"
 	"github.com/autobrr/dashbrr/internal/types"
+	"github.com/autobrr/dashbrr/internal/utils"
 )
 
 const (
@@ -29,21 +32,19 @@ const (
 )
 
 type SonarrHandler struct {
-	db              *database.DB
-	cache           cache.Store
-	sf              singleflight.Group
-	lastQueueHash   map[string]string
-	lastStatsHash   map[string]string
-	lastQueueHashMu sync.Mutex
-	lastStatsHashMu sync.Mutex
+	db            *database.DB
+	cache         cache.Store
+	sf            singleflight.Group
+	lastQueueHash *hashTracker
+	lastStatsHash *hashTracker
 }
 
 func NewSonarrHandler(db *database.DB, cache cache.Store) *SonarrHandler {
 	return &SonarrHandler{
 		db:            db,
 		cache:         cache,
-		lastQueueHash: make(map[string]string),
-		lastStatsHash: make(map[string]string),
+		lastQueueHash: newHashTracker(db),
+		lastStatsHash: newHashTracker(db),
 	}
 }
 
@@ -51,46 +52,90 @@ func NewSonarrHandler(db *database.DB, cache cache.Store) *SonarrHandler {
 // It compares the current queue state with the previous state for a specific Sonarr instance
 // Helps detect queue changes like new downloads starting, downloads completing, or status updates
 func (h *SonarrHandler) compareAndLogQueueChanges(instanceId string, queueResp *types.SonarrQueueResponse) {
-	h.lastQueueHashMu.Lock()
-	defer h.lastQueueHashMu.Unlock()
-
 	wrapped := wrapSonarrQueue(queueResp)
 	currentHash := generateQueueHash(wrapped)
-	lastHash := h.lastQueueHash[instanceId]
+	lastHash := h.lastQueueHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		changes := detectQueueChanges(lastHash, currentHash)
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
 			Int("totalRecords", queueResp.TotalRecords).
 			Str("change", changes).
 			Msg("[Sonarr] Queue changed")
-
-		h.lastQueueHash[instanceId] = currentHash
 	}
 }
 
 // compareAndLogStatsChanges tracks and logs changes in Sonarr stats
 func (h *SonarrHandler) compareAndLogStatsChanges(instanceId string, stats *types.SonarrStatsResponse) {
-	h.lastStatsHashMu.Lock()
-	defer h.lastStatsHashMu.Unlock()
-
 	currentHash := fmt.Sprintf("%d:%d:%d:%d",
 		stats.EpisodeCount,
 		stats.EpisodeFileCount,
 		stats.QueuedCount,
 		stats.MissingCount)
-	lastHash := h.lastStatsHash[instanceId]
+	lastHash := h.lastStatsHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
-		log.Debug().
+		logger.Changes().Debug().
 			Str("instanceId", instanceId).
-			Int("episodeCount", stats.EpisodeCount).
-			Int("queuedCount", stats.QueuedCount).
+			Int64("episodeCount", stats.EpisodeCount).
+			Int64("queuedCount", stats.QueuedCount).
 			Msg("[Sonarr] Stats changed")
+	}
+}
+
+// GetQueueItem returns a single queue item with its full status messages, for drilling into
+// why a download is stuck
+func (h *SonarrHandler) GetQueueItem(c *gin.Context) {
+	instanceId := c.Query("instanceId")
+	if instanceId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "instanceId is required"})
+		return
+	}
+
+	if !isServiceType(instanceId, "sonarr") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Sonarr instance ID"})
+		return
+	}
+
+	queueId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue item id"})
+		return
+	}
+
+	sonarrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Sonarr] Failed to get configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Sonarr configuration"})
+		return
+	}
+
+	if sonarrConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sonarr is not configured"})
+		return
+	}
+
+	service := &sonarr.SonarrService{}
+	sonarrConfig.Configure(service)
+	record, err := service.GetQueueItem(c.Request.Context(), sonarrConfig.URL, sonarrConfig.APIKey, queueId)
+	if err != nil {
+		var sonarrErr *sonarr.ErrSonarr
+		if errors.As(err, &sonarrErr) && sonarrErr.HttpCode > 0 {
+			c.JSON(sonarrErr.HttpCode, gin.H{"error": sonarrErr.Error()})
+			return
+		}
+		log.Error().Err(err).Str("instanceId", instanceId).Int("queueId", queueId).Msg("[Sonarr] Failed to get queue item")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-		h.lastStatsHash[instanceId] = currentHash
+	if wantsHumanized(c) {
+		record.SizeHuman = utils.FormatBytes(record.Size)
+		record.SizeLeftHuman = utils.FormatBytes(record.SizeLeft)
 	}
+
+	c.JSON(http.StatusOK, record)
 }
 
 func (h *SonarrHandler) DeleteQueueItem(c *gin.Context) {
@@ -124,7 +169,7 @@ func (h *SonarrHandler) DeleteQueueItem(c *gin.Context) {
 	}
 
 	// Get Sonarr configuration
-	sonarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+	sonarrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Sonarr] Failed to get configuration")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Sonarr configuration"})
@@ -139,9 +184,41 @@ func (h *SonarrHandler) DeleteQueueItem(c *gin.Context) {
 
 	// Create Sonarr service instance
 	service := &sonarr.SonarrService{}
+	sonarrConfig.Configure(service)
+
+	// Report what would be removed without deleting anything
+	if c.Query("dryRun") == "true" {
+		id, err := strconv.Atoi(queueId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue item id"})
+			return
+		}
+
+		record, err := service.GetQueueItem(c.Request.Context(), sonarrConfig.URL, sonarrConfig.APIKey, id)
+		if err != nil {
+			var sonarrErr *sonarr.ErrSonarr
+			if errors.As(err, &sonarrErr) && sonarrErr.HttpCode > 0 {
+				c.JSON(sonarrErr.HttpCode, gin.H{"error": sonarrErr.Error()})
+				return
+			}
+			log.Error().Err(err).Str("instanceId", instanceId).Int("queueId", id).Msg("[Sonarr] Failed to get queue item")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, types.QueueDeletePreview{
+			DryRun:                true,
+			ID:                    record.ID,
+			Title:                 record.Title,
+			Status:                record.Status,
+			TrackedDownloadStatus: record.TrackedDownloadStatus,
+			Options:               options,
+		})
+		return
+	}
 
 	// Call the service method to delete the queue item
-	if err := service.DeleteQueueItem(context.Background(), sonarrConfig.URL, sonarrConfig.APIKey, queueId, options); err != nil {
+	if err := service.DeleteQueueItem(c.Request.Context(), sonarrConfig.URL, sonarrConfig.APIKey, queueId, options); err != nil {
 		if arrErr, ok := err.(*arr.ErrArr); ok {
 			log.Error().
 				Err(arrErr).
@@ -170,7 +247,7 @@ func (h *SonarrHandler) DeleteQueueItem(c *gin.Context) {
 	// Fetch fresh data and broadcast update using singleflight
 	sfKey := fmt.Sprintf("queue:%s", instanceId)
 	queueRespI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheQueue(instanceId, cacheKey)
+		return h.fetchAndCacheQueue(context.Background(), instanceId, cacheKey)
 	})
 
 	if err == nil {
@@ -196,7 +273,24 @@ func (h *SonarrHandler) GetQueue(c *gin.Context) {
 		return
 	}
 
+	options := types.DefaultSonarrQueueOptions
+	for name, value := range map[string]*bool{
+		"includeUnknownSeriesItems": &options.IncludeUnknownSeriesItems,
+		"includeSeries":             &options.IncludeSeries,
+		"includeEpisode":            &options.IncludeEpisode,
+	} {
+		if err := queryBool(c, name, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+			return
+		}
+	}
+	if options != types.DefaultSonarrQueueOptions {
+		h.getQueueWithOptions(c, instanceId, options)
+		return
+	}
+
 	cacheKey := sonarrQueuePrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -207,7 +301,7 @@ func (h *SonarrHandler) GetQueue(c *gin.Context) {
 			Str("instanceId", instanceId).
 			Int("totalRecords", queueResp.TotalRecords).
 			Msg("[Sonarr] Serving queue from cache")
-		c.JSON(http.StatusOK, queueResp)
+		h.writeQueue(c, queueResp)
 
 		// Refresh cache in background using singleflight
 		go func() {
@@ -223,7 +317,7 @@ func (h *SonarrHandler) GetQueue(c *gin.Context) {
 	// If not in cache, fetch from service using singleflight
 	sfKey := fmt.Sprintf("queue:%s", instanceId)
 	queueRespI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheQueue(instanceId, cacheKey)
+		return h.fetchAndCacheQueue(ctx, instanceId, cacheKey)
 	})
 
 	if err != nil {
@@ -257,11 +351,61 @@ func (h *SonarrHandler) GetQueue(c *gin.Context) {
 		h.broadcastSonarrQueue(instanceId, &queueResp)
 	}
 
+	h.writeQueue(c, queueResp)
+}
+
+// getQueueWithOptions responds with a queue fetched with non-default options, such as one
+// including unknown items. These queues are neither cached nor broadcast.
+func (h *SonarrHandler) getQueueWithOptions(c *gin.Context, instanceId string, options types.SonarrQueueOptions) {
+	sonarrConfig, err := h.db.FindServiceBy(c.Request.Context(), types.FindServiceParams{InstanceID: instanceId})
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Sonarr] Failed to get configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Sonarr configuration"})
+		return
+	}
+
+	if sonarrConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sonarr is not configured"})
+		return
+	}
+
+	service := &sonarr.SonarrService{}
+	sonarrConfig.Configure(service)
+	records, err := service.GetQueueWithOptions(c.Request.Context(), sonarrConfig.URL, sonarrConfig.APIKey, options)
+	if err != nil {
+		var sonarrErr *sonarr.ErrSonarr
+		if errors.As(err, &sonarrErr) && sonarrErr.HttpCode > 0 {
+			c.JSON(sonarrErr.HttpCode, gin.H{"error": sonarrErr.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to fetch queue: %v", err)})
+		return
+	}
+
+	h.writeQueue(c, types.SonarrQueueResponse{Records: records, TotalRecords: len(records)})
+}
+
+// writeQueue responds with the queue, adding human readable sizes when asked for. The
+// records are paged when limit or offset is given.
+func (h *SonarrHandler) writeQueue(c *gin.Context, queueResp types.SonarrQueueResponse) {
+	limit, offset, paged, err := queryPage(c, maxListPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if wantsHumanized(c) {
+		queueResp = queueResp.Humanized()
+	}
+	if paged {
+		c.JSON(http.StatusOK, types.NewPagedResponse(queueResp.Records, limit, offset))
+		return
+	}
 	c.JSON(http.StatusOK, queueResp)
 }
 
-func (h *SonarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.SonarrQueueResponse, error) {
-	sonarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+func (h *SonarrHandler) fetchAndCacheQueue(ctx context.Context, instanceId, cacheKey string) (types.SonarrQueueResponse, error) {
+	sonarrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		return types.SonarrQueueResponse{}, err
 	}
@@ -272,9 +416,10 @@ func (h *SonarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.S
 
 	// Create Sonarr service instance
 	service := &sonarr.SonarrService{}
+	sonarrConfig.Configure(service)
 
 	// Get queue records using the service
-	records, err := service.GetQueueForHealth(context.Background(), sonarrConfig.URL, sonarrConfig.APIKey)
+	records, err := service.GetQueueForHealth(ctx, sonarrConfig.URL, sonarrConfig.APIKey)
 	if err != nil {
 		return types.SonarrQueueResponse{}, err
 	}
@@ -297,7 +442,6 @@ func (h *SonarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.S
 	}
 
 	// Cache the results using the centralized cache duration
-	ctx := context.Background()
 	if err := h.cache.Set(ctx, cacheKey, queueResp, middleware.CacheDurations.SonarrStatus); err != nil {
 		log.Warn().
 			Err(err).
@@ -309,7 +453,7 @@ func (h *SonarrHandler) fetchAndCacheQueue(instanceId, cacheKey string) (types.S
 }
 
 func (h *SonarrHandler) refreshQueueCache(instanceId, cacheKey string) {
-	queueResp, err := h.fetchAndCacheQueue(instanceId, cacheKey)
+	queueResp, err := h.fetchAndCacheQueue(context.Background(), instanceId, cacheKey)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -351,12 +495,12 @@ func (h *SonarrHandler) broadcastSonarrQueue(instanceId string, queueResp *types
 
 	// Use the existing BroadcastHealth function with a special message type
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "ok",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
 		Message:     "sonarr_queue",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
-			"sonarr": queueResp,
+			"sonarr": queueResp.Humanized(),
 		},
 		Details: map[string]interface{}{
 			"sonarr": map[string]interface{}{
@@ -365,6 +509,7 @@ func (h *SonarrHandler) broadcastSonarrQueue(instanceId string, queueResp *types
 				"downloadingCount": downloading,
 				"episodeCount":     episodeCount,
 				"totalSize":        totalSize,
+				"totalSizeHuman":   utils.FormatBytes(totalSize),
 			},
 		},
 	})
@@ -386,6 +531,7 @@ func (h *SonarrHandler) GetStats(c *gin.Context) {
 	}
 
 	cacheKey := sonarrStatsPrefix + instanceId
+	// Detached from the request, the fetch below is shared with concurrent requests
 	ctx := context.Background()
 
 	// Try to get from cache first
@@ -394,12 +540,9 @@ func (h *SonarrHandler) GetStats(c *gin.Context) {
 	if err == nil {
 		log.Debug().
 			Str("instanceId", instanceId).
-			Int("monitored", statsResp.Monitored).
+			Int64("monitored", statsResp.Monitored).
 			Msg("[Sonarr] Serving stats from cache")
-		c.JSON(http.StatusOK, gin.H{
-			"stats":   statsResp,
-			"version": "", // Version will be added by the frontend if needed
-		})
+		h.writeStats(c, statsResp, "") // Version will be added by the frontend if needed
 
 		// Refresh cache in background using singleflight
 		go func() {
@@ -415,7 +558,7 @@ func (h *SonarrHandler) GetStats(c *gin.Context) {
 	// If not in cache, fetch from service using singleflight
 	sfKey := fmt.Sprintf("stats:%s", instanceId)
 	statsRespI, err, _ := h.sf.Do(sfKey, func() (interface{}, error) {
-		return h.fetchAndCacheStats(instanceId, cacheKey)
+		return h.fetchAndCacheStats(ctx, instanceId, cacheKey)
 	})
 
 	if err != nil {
@@ -443,17 +586,25 @@ func (h *SonarrHandler) GetStats(c *gin.Context) {
 	h.broadcastSonarrStats(instanceId, &statsResult.Stats, statsResult.Version)
 
 	// Create response with stats and version
+	h.writeStats(c, statsResult.Stats, statsResult.Version)
+}
+
+// writeStats responds with the stats and version, adding human readable sizes when asked for
+func (h *SonarrHandler) writeStats(c *gin.Context, statsResp types.SonarrStatsResponse, version string) {
+	if wantsHumanized(c) {
+		statsResp = statsResp.Humanized()
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"stats":   statsResult.Stats,
-		"version": statsResult.Version,
+		"stats":   statsResp,
+		"version": version,
 	})
 }
 
-func (h *SonarrHandler) fetchAndCacheStats(instanceId, cacheKey string) (struct {
+func (h *SonarrHandler) fetchAndCacheStats(ctx context.Context, instanceId, cacheKey string) (struct {
 	Stats   types.SonarrStatsResponse
 	Version string
 }, error) {
-	sonarrConfig, err := h.db.FindServiceBy(context.Background(), types.FindServiceParams{InstanceID: instanceId})
+	sonarrConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
 	if err != nil {
 		return struct {
 			Stats   types.SonarrStatsResponse
@@ -470,6 +621,7 @@ func (h *SonarrHandler) fetchAndCacheStats(instanceId, cacheKey string) (struct
 
 	// Create Sonarr service instance
 	service := &sonarr.SonarrService{}
+	sonarrConfig.Configure(service)
 
 	// Get system status using the service
 	version, err := service.GetSystemStatus(sonarrConfig.URL, sonarrConfig.APIKey)
@@ -489,7 +641,6 @@ func (h *SonarrHandler) fetchAndCacheStats(instanceId, cacheKey string) (struct
 	}
 
 	// Cache the results using the centralized cache duration
-	ctx := context.Background()
 	if err := h.cache.Set(ctx, cacheKey, result, middleware.CacheDurations.SonarrStatus); err != nil {
 		log.Warn().
 			Err(err).
@@ -501,7 +652,7 @@ func (h *SonarrHandler) fetchAndCacheStats(instanceId, cacheKey string) (struct
 }
 
 func (h *SonarrHandler) refreshStatsCache(instanceId, cacheKey string) {
-	statsResult, err := h.fetchAndCacheStats(instanceId, cacheKey)
+	statsResult, err := h.fetchAndCacheStats(context.Background(), instanceId, cacheKey)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -523,13 +674,13 @@ func (h *SonarrHandler) refreshStatsCache(instanceId, cacheKey string) {
 // broadcastSonarrStats broadcasts Sonarr stats updates to all connected SSE clients
 func (h *SonarrHandler) broadcastSonarrStats(instanceId string, statsResp *types.SonarrStatsResponse, version string) {
 	BroadcastHealth(models.ServiceHealth{
-		ServiceID:   instanceId,
-		Status:      "ok",
+		InstanceID:  instanceId,
+		Status:      models.StatusOnline,
 		Message:     "sonarr_stats",
 		LastChecked: time.Now(),
 		Stats: map[string]interface{}{
 			"sonarr": map[string]interface{}{
-				"stats":   statsResp,
+				"stats":   statsResp.Humanized(),
 				"version": version,
 			},
 		},
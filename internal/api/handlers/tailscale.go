@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +15,7 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/logger"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/tailscale"
 	"github.com/autobrr/dashbrr/internal/types"
@@ -24,21 +24,21 @@ import (
 const (
 	tailscaleCacheDuration = 60 * time.Second // Longer cache for Tailscale as it changes less frequently
 	devicesCachePrefix     = "tailscale:devices:"
+	tailnetCachePrefix     = "tailscale:tailnet:"
 )
 
 type TailscaleHandler struct {
-	db                *database.DB
-	cache             cache.Store
-	sf                singleflight.Group
-	lastDevicesHash   map[string]string
-	lastDevicesHashMu sync.Mutex
+	db              *database.DB
+	cache           cache.Store
+	sf              singleflight.Group
+	lastDevicesHash *hashTracker
 }
 
 func NewTailscaleHandler(db *database.DB, cache cache.Store) *TailscaleHandler {
 	return &TailscaleHandler{
 		db:              db,
 		cache:           cache,
-		lastDevicesHash: make(map[string]string),
+		lastDevicesHash: newHashTracker(db),
 	}
 }
 
@@ -54,7 +54,7 @@ func (h *TailscaleHandler) GetTailscaleDevices(c *gin.Context) {
 		cacheKey = devicesCachePrefix + instanceId
 	} else {
 		// Try to get the first tailscale instance if no specific instance is requested
-		services, err := h.db.GetAllServices(c.Request.Context())
+		services, err := h.db.GetAllServices(c.Request.Context(), false)
 		if err != nil {
 			log.Error().Err(err).Msg("[Tailscale] Failed to fetch services")
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
@@ -157,6 +157,86 @@ func (h *TailscaleHandler) GetTailscaleDevices(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetTailnet returns a summary of the tailnet, its name, key expiry and DNS settings
+func (h *TailscaleHandler) GetTailnet(c *gin.Context) {
+	instanceId := c.Query("instanceId")
+
+	if instanceId == "" {
+		// Fall back to the first tailscale instance, as the devices endpoint does
+		services, err := h.db.GetAllServices(c.Request.Context(), false)
+		if err != nil {
+			log.Error().Err(err).Msg("[Tailscale] Failed to fetch services")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+			return
+		}
+
+		for _, s := range services {
+			if strings.HasPrefix(s.InstanceID, "tailscale") {
+				instanceId = s.InstanceID
+				break
+			}
+		}
+
+		if instanceId == "" {
+			log.Error().Msg("[Tailscale] No instance found")
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No Tailscale instance configured"})
+			return
+		}
+	}
+
+	cacheKey := tailnetCachePrefix + instanceId
+
+	var info tailscale.TailnetInfo
+	if err := h.cache.Get(c.Request.Context(), cacheKey, &info); err == nil {
+		c.JSON(http.StatusOK, info)
+		return
+	}
+
+	// Detached from the request, the fetch below is shared with concurrent requests
+	ctx := context.Background()
+
+	infoI, err, _ := h.sf.Do("tailnet:"+instanceId, func() (interface{}, error) {
+		return h.fetchAndCacheTailnet(ctx, instanceId, cacheKey)
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == context.DeadlineExceeded || err == context.Canceled {
+			status = http.StatusGatewayTimeout
+		}
+		log.Error().Err(err).Str("instanceId", instanceId).Msg("[Tailscale] Failed to fetch tailnet")
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, infoI.(*tailscale.TailnetInfo))
+}
+
+func (h *TailscaleHandler) fetchAndCacheTailnet(ctx context.Context, instanceId, cacheKey string) (*tailscale.TailnetInfo, error) {
+	tailscaleConfig, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceId})
+	if err != nil {
+		return nil, fmt.Errorf("[Tailscale] failed to fetch configuration: %v", err)
+	}
+
+	if tailscaleConfig == nil {
+		return nil, fmt.Errorf("[Tailscale] is not configured")
+	}
+
+	service := &tailscale.TailscaleService{}
+	info, err := service.GetTailnetInfo(ctx, tailscaleConfig.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.cache.Set(ctx, cacheKey, info, tailscaleCacheDuration); err != nil {
+		log.Warn().
+			Err(err).
+			Str("instanceId", instanceId).
+			Msg("[Tailscale] Failed to cache tailnet")
+	}
+
+	return info, nil
+}
+
 func (h *TailscaleHandler) fetchAndCacheDevices(ctx context.Context, instanceId, apiKey, cacheKey string) ([]tailscale.Device, error) {
 	service := &tailscale.TailscaleService{}
 
@@ -258,24 +338,19 @@ func (h *TailscaleHandler) detectDeviceChanges(oldHash, newHash string) string {
 }
 
 func (h *TailscaleHandler) compareAndLogDeviceChanges(instanceId string, devices []tailscale.Device) {
-	h.lastDevicesHashMu.Lock()
-	defer h.lastDevicesHashMu.Unlock()
-
 	currentHash := createDevicesHash(devices)
-	lastHash := h.lastDevicesHash[instanceId]
+	lastHash := h.lastDevicesHash.Swap(instanceId, currentHash)
 
 	if currentHash != lastHash {
 		// Detect specific changes
 		changes := h.detectDeviceChanges(lastHash, currentHash)
 
-		log.Info().
+		logger.Changes().Info().
 			Str("instanceId", instanceId).
 			Int("total", len(devices)).
 			Int("online", countOnlineDevices(devices)).
 			Str("change", changes).
 			Msg("Tailscale devices retrieved")
-
-		h.lastDevicesHash[instanceId] = currentHash
 	}
 }
 
@@ -0,0 +1,96 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+// updateRefresher is implemented by services whose cached update status can be refreshed
+// on demand, i.e. autobrr, Sonarr, Radarr and Prowlarr
+type updateRefresher interface {
+	RefreshUpdate(ctx context.Context, url, apiKey string) (bool, error)
+}
+
+// CheckUpdate checks a service for updates right away, bypassing the cached result, e.g.
+// right after an upgrade. The service is then health checked again and the fresh result
+// broadcast, so the update badge clears without waiting for the cache to expire. It is
+// mounted as /api/<type>/check-update and takes the instance in the instanceId query.
+func (h *EventsHandler) CheckUpdate(c *gin.Context) {
+	instanceID := c.Query("instanceId")
+	if instanceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Instance ID is required"})
+		return
+	}
+
+	// The route names the service type, e.g. /api/autobrr/check-update
+	routeType := path.Base(path.Dir(c.FullPath()))
+	serviceType, _, _ := strings.Cut(instanceID, "-")
+	if serviceType != routeType {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + routeType + " instance ID"})
+		return
+	}
+
+	checker, err := models.CreateServiceE(models.NewServiceRegistry(), serviceType)
+	if err != nil {
+		abortUnknownServiceType(c, err)
+		return
+	}
+	refresher, ok := checker.(updateRefresher)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Service type does not support update checks: " + serviceType})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*checkTimeout)
+	defer cancel()
+
+	service, err := h.db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceID})
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to fetch service configuration")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service configuration"})
+		return
+	}
+	if service == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+	if service.URL == "" || service.APIKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Service is not configured"})
+		return
+	}
+
+	service.Configure(checker)
+
+	hasUpdate, err := refresher.RefreshUpdate(ctx, service.URL, service.APIKey)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to check for updates")
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The health check reads the update status just cached and fetches the version again
+	health, statusCode := checker.CheckHealth(ctx, service.URL, service.APIKey)
+	h.recordHealth(service, &health, statusCode)
+	BroadcastHealth(health)
+
+	log.Info().Str("instanceId", instanceID).Bool("updateAvailable", hasUpdate).Msg("Checked for updates on demand")
+
+	c.JSON(http.StatusOK, types.UpdateCheckResponse{
+		InstanceID:      instanceID,
+		UpdateAvailable: hasUpdate,
+		Version:         health.Version,
+		CheckedAt:       time.Now(),
+	})
+}
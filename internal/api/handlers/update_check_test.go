@@ -0,0 +1,93 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestEventsHandler_CheckUpdate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, store := setupEventsHandler(t)
+	ctx := context.Background()
+
+	var updates atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/update":
+			updates.Add(1)
+			w.Write([]byte(`[{"version":"4.0.11.2680","installed":false,"installable":true}]`))
+		case "/api/v3/system/status":
+			w.Write([]byte(`{"version":"4.0.10.2544"}`))
+		case "/api/v3/health":
+			w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service := models.ServiceConfiguration{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: server.URL, APIKey: "test-key"}
+	if err := handler.db.CreateService(ctx, &service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/api/sonarr/check-update", handler.CheckUpdate)
+	r.POST("/api/radarr/check-update", handler.CheckUpdate)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/sonarr/check-update?instanceId=sonarr-1", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response types.UpdateCheckResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if !response.UpdateAvailable || response.Version != "4.0.10.2544" {
+		t.Errorf("Expected an available update for 4.0.10.2544, got %+v", response)
+	}
+	if updates.Load() == 0 {
+		t.Error("Expected Sonarr to be asked for updates")
+	}
+
+	// The fresh result replaces the cached health
+	var health models.ServiceHealth
+	if err := store.Get(ctx, cache.PrefixHealth+"sonarr-1", &health); err != nil {
+		t.Fatalf("Expected the health result to be cached: %v", err)
+	}
+	if !health.UpdateAvailable || health.Version != "4.0.10.2544" {
+		t.Errorf("Expected the cached health to carry the update, got %+v", health)
+	}
+
+	// The instance must match the service type of the route
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/radarr/check-update?instanceId=sonarr-1", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a mismatched instance, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/sonarr/check-update?instanceId=sonarr-2", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown instance, got %d", http.StatusNotFound, w.Code)
+	}
+}
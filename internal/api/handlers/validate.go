@@ -0,0 +1,135 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+const (
+	validationCachePrefix = "validate:"
+	validationCacheTTL    = 10 * time.Second
+	validationTimeout     = 10 * time.Second
+)
+
+// validationCacheKey identifies a connection by type, URL and a hash of the API key and
+// client certificate, so the secrets themselves never end up in the cache
+func validationCacheKey(req types.ValidateServiceRequest) string {
+	sum := sha256.Sum256([]byte(req.APIKey + "\x00" + req.ClientCert + "\x00" + req.ClientKey))
+	return validationCachePrefix + strings.ToLower(req.Type) + ":" + req.APIVersion + ":" + strings.ToUpper(req.Method) + ":" +
+		req.ExpectedStatus + ":" + req.AuthHeaderName + ":" + req.URL + ":" + hex.EncodeToString(sum[:8])
+}
+
+// validateService checks whether the service is reachable with the given credentials.
+// Results are cached for validationCacheTTL so rapid retries while editing a service
+// don't hit the upstream every time.
+func (h *SettingsHandler) validateService(ctx context.Context, req types.ValidateServiceRequest) (types.ServiceValidationResult, error) {
+	req.URL = strings.TrimRight(req.URL, "/")
+	cacheKey := validationCacheKey(req)
+
+	var result types.ServiceValidationResult
+	if err := h.cache.Get(ctx, cacheKey, &result); err == nil {
+		result.Age = int64(time.Since(result.CheckedAt).Seconds())
+		return result, nil
+	}
+
+	checker, err := models.CreateServiceE(h.serviceCreator, req.Type)
+	if err != nil {
+		return result, err
+	}
+
+	service := models.ServiceConfiguration{
+		URL:            req.URL,
+		APIKey:         req.APIKey,
+		APIVersion:     req.APIVersion,
+		Method:         req.Method,
+		ExpectedStatus: req.ExpectedStatus,
+		AuthHeaderName: req.AuthHeaderName,
+		ClientCert:     req.ClientCert,
+		ClientKey:      req.ClientKey,
+	}
+	service.Configure(checker)
+
+	checkCtx, cancel := context.WithTimeout(ctx, validationTimeout)
+	defer cancel()
+
+	health, statusCode := checker.CheckHealth(checkCtx, req.URL, req.APIKey)
+	if statusCode != http.StatusOK {
+		health.Status = models.StatusForResponseCode(statusCode)
+		health.Detail = health.Message
+		health.Message = describeStatusCode(statusCode)
+	}
+
+	result = types.ServiceValidationResult{
+		Valid:     health.Status == models.StatusOnline || health.Status == models.StatusWarning,
+		Health:    health,
+		CheckedAt: time.Now(),
+	}
+
+	if err := h.cache.Set(ctx, cacheKey, result, validationCacheTTL); err != nil {
+		log.Warn().Err(err).Str("type", req.Type).Msg("Failed to cache validation result")
+	}
+
+	return result, nil
+}
+
+// ValidateService tests a service connection without saving it
+func (h *SettingsHandler) ValidateService(c *gin.Context) {
+	var req types.ValidateServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := models.ValidateAPIVersion(req.APIVersion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := models.ValidateHTTPCheck(req.Method, req.ExpectedStatus); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := models.ValidateAuthHeaderName(req.AuthHeaderName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := models.ValidateClientCertificate(req.ClientCert, req.ClientKey); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.validateService(c.Request.Context(), req)
+	if err != nil {
+		abortUnknownServiceType(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// abortUnknownServiceType responds with the error and the supported types, so clients can
+// offer the valid options
+func abortUnknownServiceType(c *gin.Context, err error) {
+	response := gin.H{"error": err.Error()}
+	var unknown *models.ErrUnknownServiceType
+	if errors.As(err, &unknown) {
+		response["supportedTypes"] = unknown.Supported
+	}
+	c.JSON(http.StatusBadRequest, response)
+}
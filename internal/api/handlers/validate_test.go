@@ -0,0 +1,82 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestSettingsHandler_ValidateService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := setupTestCache(t)
+
+	calls := 0
+	checker := &mockServiceHealthChecker{
+		checkHealthFunc: func(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
+			calls++
+			if apiKey != "good-key" {
+				return models.ServiceHealth{Status: models.StatusError, Message: "invalid api key"}, http.StatusUnauthorized
+			}
+			return models.ServiceHealth{Status: models.StatusOnline}, http.StatusOK
+		},
+	}
+
+	handler := &SettingsHandler{
+		cache: store,
+		serviceCreator: &mockServiceCreator{
+			createServiceFunc: func(serviceType string) models.ServiceHealthChecker {
+				if serviceType == "sonarr" {
+					return checker
+				}
+				return nil
+			},
+		},
+	}
+
+	validate := func(body string) (int, types.ServiceValidationResult) {
+		c, w := newTestContext(http.MethodPost, "/api/services/validate", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ValidateService(c)
+
+		var result types.ServiceValidationResult
+		_ = json.Unmarshal(w.Body.Bytes(), &result)
+		return w.Code, result
+	}
+
+	code, result := validate(`{"type":"sonarr","url":"http://sonarr:8989/","apiKey":"good-key"}`)
+	if code != http.StatusOK || !result.Valid {
+		t.Fatalf("Expected a valid result, got %d %+v", code, result)
+	}
+
+	// Retries within the TTL are served from the cache, also with a trailing slash difference
+	_, result = validate(`{"type":"sonarr","url":"http://sonarr:8989","apiKey":"good-key"}`)
+	if !result.Valid || calls != 1 {
+		t.Errorf("Expected the cached result without a new check, got %d checks", calls)
+	}
+
+	// A different key is a different connection
+	_, result = validate(`{"type":"sonarr","url":"http://sonarr:8989","apiKey":"bad-key"}`)
+	if result.Valid || calls != 2 {
+		t.Errorf("Expected an uncached invalid result, got %+v after %d checks", result, calls)
+	}
+	if result.Health.Status != models.StatusUnauthorized {
+		t.Errorf("Expected status %q, got %q", models.StatusUnauthorized, result.Health.Status)
+	}
+
+	code, _ = validate(`{"type":"bogus","url":"http://bogus"}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an unknown type, got %d", http.StatusBadRequest, code)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/buildinfo"
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+type VersionHandler struct {
+	db    *database.DB
+	cache cache.Store
+}
+
+func NewVersionHandler(db *database.DB, cache cache.Store) *VersionHandler {
+	return &VersionHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// cacheBackend names the cache implementation in use
+func cacheBackend(store cache.Store) string {
+	switch store.(type) {
+	case *cache.RedisStore:
+		return "redis"
+	case *cache.BoltStore:
+		return "bolt"
+	}
+	return "memory"
+}
+
+// GetVersion reports the build info, uptime and active backends, e.g. for bug reports
+func (h *VersionHandler) GetVersion(c *gin.Context) {
+	uptime := buildinfo.Uptime()
+
+	c.JSON(http.StatusOK, types.BuildInfoResponse{
+		Info:          buildinfo.Get(),
+		Uptime:        uptime.Truncate(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Database:      h.db.Driver(),
+		Cache:         cacheBackend(h.cache),
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/buildinfo"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestVersionHandler_GetVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, store := setupTestDB(t)
+
+	handler := NewVersionHandler(db, store)
+
+	c, w := newTestContext(http.MethodGet, "/api/version", nil)
+
+	handler.GetVersion(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp types.BuildInfoResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if resp.Version != buildinfo.Version || resp.GoVersion != runtime.Version() {
+		t.Errorf("Unexpected build info %+v", resp.Info)
+	}
+	if resp.Database != "sqlite" || resp.Cache != "memory" {
+		t.Errorf("Expected sqlite and memory backends, got %q and %q", resp.Database, resp.Cache)
+	}
+}
@@ -27,7 +27,8 @@ const (
 )
 
 type AuthMiddleware struct {
-	cache cache.Store
+	cache    cache.Store
+	disabled bool
 }
 
 func NewAuthMiddleware(cache cache.Store) *AuthMiddleware {
@@ -36,9 +37,19 @@ func NewAuthMiddleware(cache cache.Store) *AuthMiddleware {
 	}
 }
 
+// Disable turns RequireAuth into a no-op, used when auth.mode is "none"
+func (m *AuthMiddleware) Disable() {
+	m.disabled = true
+}
+
 // RequireAuth middleware checks for valid authentication
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if m.disabled {
+			c.Next()
+			return
+		}
+
 		// Create a context with timeout for auth operations
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
@@ -93,8 +104,10 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			}
 		}
 
-		// Create new context with session data
-		newCtx := context.WithValue(ctx, SessionContextKey, sessionData)
+		// Create new context with session data. It derives from the request, not from the
+		// lookup timeout, which would otherwise end long-lived requests such as the SSE
+		// stream and health polls after 5 seconds.
+		newCtx := context.WithValue(c.Request.Context(), SessionContextKey, sessionData)
 		newCtx = context.WithValue(newCtx, AuthTypeKey, sessionData.AuthType)
 		if sessionData.UserID != 0 {
 			newCtx = context.WithValue(newCtx, UserIDKey, sessionData.UserID)
@@ -150,8 +163,8 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			}
 		}
 
-		// Create new context with session data
-		newCtx := context.WithValue(ctx, SessionContextKey, sessionData)
+		// Create new context with session data, without the lookup timeout
+		newCtx := context.WithValue(c.Request.Context(), SessionContextKey, sessionData)
 		newCtx = context.WithValue(newCtx, AuthTypeKey, sessionData.AuthType)
 		if sessionData.UserID != 0 {
 			newCtx = context.WithValue(newCtx, UserIDKey, sessionData.UserID)
@@ -148,7 +148,7 @@ func (m *CacheMiddleware) getTTL(path string) time.Duration {
 
 	// Service-specific TTLs
 	switch {
-	case strings.Contains(path, "/plex/sessions"):
+	case strings.Contains(path, "/plex/sessions"), strings.Contains(path, "/plex/now-playing"):
 		return CacheDurations.PlexSessions
 	case strings.Contains(path, "/overseerr/requests"):
 		return CacheDurations.OverseerrRequests
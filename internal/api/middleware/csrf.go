@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"net/http"
@@ -42,6 +43,8 @@ type CSRFConfig struct {
 	ExemptMethods []string
 	// Paths that don't require CSRF validation
 	ExemptPaths []string
+	// If true, requests carrying only a Bearer token (no session cookie) skip validation
+	ExemptBearer bool
 }
 
 // DefaultCSRFConfig returns the default CSRF configuration
@@ -65,6 +68,12 @@ func generateCSRFToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// hasSessionCookie reports whether the request carries a session cookie
+func hasSessionCookie(c *gin.Context) bool {
+	session, err := c.Cookie("session")
+	return err == nil && session != ""
+}
+
 // CSRF returns a middleware that provides CSRF protection
 func CSRF(config *CSRFConfig) gin.HandlerFunc {
 	if config == nil {
@@ -80,6 +89,12 @@ func CSRF(config *CSRFConfig) gin.HandlerFunc {
 			}
 		}
 
+		// Requests authenticated with a Bearer header can't be forged cross-site
+		if config.ExemptBearer && strings.HasPrefix(strings.ToLower(c.GetHeader("Authorization")), "bearer ") && !hasSessionCookie(c) {
+			c.Next()
+			return
+		}
+
 		// Check if the method is exempt
 		method := strings.ToUpper(c.Request.Method)
 		for _, m := range config.ExemptMethods {
@@ -119,25 +134,14 @@ func CSRF(config *CSRFConfig) gin.HandlerFunc {
 			return
 		}
 
-		// Compare the cookie token with the header token
-		if cookie != header {
+		// Compare the cookie token with the header token. The token is not rotated per request
+		// so concurrent requests from the frontend don't invalidate each other.
+		if subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
 			c.JSON(http.StatusForbidden, gin.H{"error": ErrTokenMismatch.Error()})
 			c.Abort()
 			return
 		}
 
-		// Generate a new token for the next request
-		newToken, err := generateCSRFToken()
-		if err != nil {
-			c.AbortWithStatus(http.StatusInternalServerError)
-			return
-		}
-
-		// Set the new token in both cookie and header
-		c.SetCookie(csrfTokenCookie, newToken, config.MaxAge, config.Path,
-			config.Domain, config.Secure, config.HttpOnly)
-		c.Header(csrfTokenHeader, newToken)
-
 		c.Next()
 	}
 }
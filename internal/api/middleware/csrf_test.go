@@ -0,0 +1,72 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := DefaultCSRFConfig()
+	config.ExemptBearer = true
+	config.ExemptPaths = []string{"/dashbrr/api/auth/"}
+
+	router := gin.New()
+	router.Use(CSRF(config))
+	router.Any("/*path", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		cookie        string
+		header        string
+		authorization string
+		session       bool
+		expectedCode  int
+	}{
+		{"GET is exempt", http.MethodGet, "/dashbrr/api/settings", "", "", "", false, http.StatusOK},
+		{"Missing token", http.MethodPost, "/dashbrr/api/settings/sonarr-1", "", "", "", false, http.StatusForbidden},
+		{"Missing header", http.MethodPost, "/dashbrr/api/settings/sonarr-1", "token", "", "", false, http.StatusForbidden},
+		{"Mismatched token", http.MethodPost, "/dashbrr/api/settings/sonarr-1", "token", "other", "", false, http.StatusForbidden},
+		{"Matching token", http.MethodPost, "/dashbrr/api/settings/sonarr-1", "token", "token", "", false, http.StatusOK},
+		{"Bearer without session cookie", http.MethodPost, "/dashbrr/api/settings/sonarr-1", "", "", "Bearer key", false, http.StatusOK},
+		{"Bearer with session cookie", http.MethodPost, "/dashbrr/api/settings/sonarr-1", "", "", "Bearer key", true, http.StatusForbidden},
+		{"Exempt path under base path", http.MethodPost, "/dashbrr/api/auth/login", "", "", "", false, http.StatusOK},
+		{"Exempt path without base path", http.MethodPost, "/api/auth/login", "", "", "", false, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: csrfTokenCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(csrfTokenHeader, tt.header)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.session {
+				req.AddCookie(&http.Cookie{Name: "session", Value: "session-id"})
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// MaintenanceHeader is set on every API response while dashbrr is in maintenance mode
+const MaintenanceHeader = "X-Dashbrr-Maintenance"
+
+// Maintenance marks responses served while active reports maintenance mode, so clients
+// can tell the data they get is cached and no longer refreshed
+func Maintenance(active func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if active() {
+			c.Header(MaintenanceHeader, "true")
+		}
+		c.Next()
+	}
+}
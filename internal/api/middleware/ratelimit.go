@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
@@ -29,6 +30,11 @@ func NewRateLimiter(store cache.Store, window time.Duration, limit int, keyPrefi
 	if limit == 0 {
 		limit = 1000
 	}
+
+	// Stores tracking windows in memory would otherwise keep them far longer than needed
+	if configurer, ok := store.(cache.RateWindowConfigurer); ok {
+		configurer.SetRateWindowTTL(keyPrefix, window)
+	}
 	return &RateLimiter{
 		store:     store,
 		window:    window,
@@ -51,8 +57,11 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 		// Create key for this IP and endpoint
 		endpoint := c.Request.URL.Path
 		key := fmt.Sprintf("%s%s:%s", rl.keyPrefix, endpoint, clientIP)
-		now := time.Now().Unix()
-		windowStart := now - int64(rl.window.Seconds())
+		// Nanoseconds, the stores keep one entry per timestamp and whole seconds would
+		// count a burst of requests as one
+		now := time.Now()
+		windowStart := now.Add(-rl.window).UnixNano()
+		reset := fmt.Sprintf("%d", now.Add(rl.window).Unix())
 
 		// Clean up old requests
 		if err := rl.store.CleanAndCount(c, key, windowStart); err != nil {
@@ -71,11 +80,13 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 
 		// Check if limit exceeded
 		if count >= int64(rl.limit) {
-			retryAfter := windowStart + int64(rl.window.Seconds()) - now
+			// The store doesn't tell when the oldest request leaves the window, a full
+			// window is when the client may retry for sure
+			retryAfter := int64(math.Ceil(rl.window.Seconds()))
 			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
 			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rl.limit))
 			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", windowStart+int64(rl.window.Seconds())))
+			c.Header("X-RateLimit-Reset", reset)
 
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Rate limit exceeded",
@@ -88,7 +99,7 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 		}
 
 		// Record this request
-		if err := rl.store.Increment(c, key, now); err != nil {
+		if err := rl.store.Increment(c, key, now.UnixNano()); err != nil {
 			log.Error().Err(err).Msg("Failed to record request")
 			c.Next() // Continue on error
 			return
@@ -106,7 +117,7 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 		}
 		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rl.limit))
 		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", windowStart+int64(rl.window.Seconds())))
+		c.Header("X-RateLimit-Reset", reset)
 
 		c.Next()
 	}
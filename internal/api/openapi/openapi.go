@@ -0,0 +1,282 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package openapi builds an OpenAPI 3 document describing the registered API routes.
+// Paths and methods come from the router itself, so the spec cannot drift from the
+// actual handler list. Summaries, query parameters and response types are maintained
+// by hand in operations.go.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/buildinfo"
+)
+
+const Version = "3.0.3"
+
+// Document is the subset of an OpenAPI 3 document dashbrr produces
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Security   []SecurityReq       `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps a lower case HTTP method to its operation
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	Security    *[]SecurityReq      `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// SecurityReq lists the security schemes an operation accepts
+type SecurityReq map[string][]string
+
+// Build creates the document for every API route registered on the router.
+// basePath is stripped from the route paths and published as the server URL.
+func Build(routes gin.RoutesInfo, basePath string) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "dashbrr",
+			Description: "Dashboard for monitoring and managing media stack services",
+			Version:     buildinfo.Version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{
+				"sessionCookie": {Type: "apiKey", In: "cookie", Name: "session"},
+				"bearerAuth":    {Type: "http", Scheme: "bearer"},
+			},
+		},
+		Security: []SecurityReq{{"sessionCookie": {}}, {"bearerAuth": {}}},
+	}
+
+	if basePath != "" {
+		doc.Servers = []Server{{URL: basePath}}
+	}
+
+	schemas := newSchemaRegistry(doc.Components.Schemas)
+	operationIDs := make(map[string]bool)
+
+	sorted := make(gin.RoutesInfo, len(routes))
+	copy(sorted, routes)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	for _, route := range sorted {
+		routePath := strings.TrimPrefix(route.Path, basePath)
+		if !strings.HasPrefix(routePath, "/api/") {
+			continue
+		}
+
+		meta := operations[route.Method+" "+routePath]
+		specPath, pathParams := convertPath(routePath)
+
+		op := &Operation{
+			OperationID: uniqueOperationID(operationIDs, operationID(route.Handler, route.Method, routePath)),
+			Summary:     meta.Summary,
+			Tags:        []string{tagFor(routePath)},
+			Parameters:  append(pathParams, meta.Query...),
+			Responses:   make(map[string]Response),
+		}
+
+		if meta.Public {
+			// An empty requirement overrides the document level security
+			op.Security = &[]SecurityReq{}
+		}
+
+		if meta.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: schemas.schemaFor(meta.Body)}},
+			}
+		}
+
+		op.Responses["200"] = response(meta, schemas)
+		if !meta.Public {
+			op.Responses["401"] = Response{Description: "Not authenticated"}
+		}
+
+		item, ok := doc.Paths[specPath]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[specPath] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return doc
+}
+
+// Handler serves the document for the router's routes. The document is built on
+// the first request, once every route has been registered.
+func Handler(r *gin.Engine, basePath string) gin.HandlerFunc {
+	var (
+		once sync.Once
+		doc  *Document
+	)
+
+	return func(c *gin.Context) {
+		once.Do(func() {
+			doc = Build(r.Routes(), basePath)
+		})
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+func response(meta operation, schemas *schemaRegistry) Response {
+	description := meta.Description
+	if description == "" {
+		description = "OK"
+	}
+
+	if meta.Stream {
+		return Response{
+			Description: description,
+			Content:     map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}},
+		}
+	}
+
+	schema := &Schema{Type: "object"}
+	if meta.Response != nil {
+		schema = schemas.schemaFor(meta.Response)
+	}
+
+	return Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// convertPath turns gin's :param and *param segments into OpenAPI {param} templates
+func convertPath(routePath string) (string, []Parameter) {
+	segments := strings.Split(routePath, "/")
+	var params []Parameter
+
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// tagFor groups operations by the first path segment after /api
+func tagFor(routePath string) string {
+	rest := strings.TrimPrefix(routePath, "/api/")
+	tag, _, _ := strings.Cut(rest, "/")
+	return tag
+}
+
+// operationID derives an id such as "sonarrGetQueue" from a handler name like
+// "github.com/autobrr/dashbrr/internal/api/handlers.(*SonarrHandler).GetQueue-fm"
+func operationID(handler, method, routePath string) string {
+	name := handler[strings.LastIndex(handler, "/")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+
+	if open, closing := strings.Index(name, "(*"), strings.Index(name, ")."); open >= 0 && closing > open {
+		receiver := strings.TrimSuffix(name[open+2:closing], "Handler")
+		return lowerFirst(receiver) + name[closing+2:]
+	}
+
+	// Anonymous handlers fall back to the method and path
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(strings.TrimPrefix(routePath, "/api/"), "/") {
+		segment = strings.TrimLeft(segment, ":*")
+		if segment != "" {
+			b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+		}
+	}
+	return b.String()
+}
+
+func uniqueOperationID(seen map[string]bool, id string) string {
+	candidate := id
+	for i := 2; seen[candidate]; i++ {
+		candidate = id + "_" + strconv.Itoa(i)
+	}
+	seen[candidate] = true
+	return candidate
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testHandler struct{}
+
+func (h *testHandler) CheckHealth(c *gin.Context) {}
+func (h *testHandler) MuteService(c *gin.Context) {}
+
+func TestBuild(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &testHandler{}
+
+	r := gin.New()
+	root := r.Group("/dashbrr")
+	root.GET("/health", func(c *gin.Context) {})
+	root.GET("/api/health/:service", h.CheckHealth)
+	root.POST("/api/services/:instanceId/mute", h.MuteService)
+	root.GET("/api/auth/config", func(c *gin.Context) {})
+
+	doc := Build(r.Routes(), "/dashbrr")
+
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "/dashbrr" {
+		t.Errorf("Expected base path as server URL, got %v", doc.Servers)
+	}
+	if _, ok := doc.Paths["/health"]; ok {
+		t.Error("Expected routes outside /api to be skipped")
+	}
+
+	health := doc.Paths["/api/health/{service}"]["get"]
+	if health == nil {
+		t.Fatalf("Expected health operation, got paths %v", doc.Paths)
+	}
+	if health.OperationID != "testCheckHealth" {
+		t.Errorf("Expected operationId testCheckHealth, got %s", health.OperationID)
+	}
+	if len(health.Parameters) != 1 || health.Parameters[0].Name != "service" || health.Parameters[0].In != "path" {
+		t.Errorf("Expected service path parameter, got %+v", health.Parameters)
+	}
+	if ref := health.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/ModelsServiceHealth" {
+		t.Errorf("Expected ServiceHealth response reference, got %q", ref)
+	}
+	if schema := doc.Components.Schemas["ModelsServiceHealth"]; schema == nil || schema.Properties["lastChecked"].Format != "date-time" {
+		t.Errorf("Expected ServiceHealth schema with a date-time lastChecked, got %+v", schema)
+	}
+
+	mute := doc.Paths["/api/services/{instanceId}/mute"]["post"]
+	if mute == nil || mute.RequestBody == nil {
+		t.Fatalf("Expected mute operation with a request body, got %+v", mute)
+	}
+
+	// Anonymous handlers are named after the route
+	config := doc.Paths["/api/auth/config"]["get"]
+	if config == nil || config.OperationID != "getAuthConfig" {
+		t.Fatalf("Expected getAuthConfig operation, got %+v", config)
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal operation: %v", err)
+	}
+	if !strings.Contains(string(data), `"security":[]`) {
+		t.Errorf("Expected public operation to clear security, got %s", data)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/api/openapi.json", Handler(r, ""))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+	if doc.OpenAPI != Version {
+		t.Errorf("Expected openapi %s, got %s", Version, doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/api/openapi.json"]["get"]; !ok {
+		t.Error("Expected the document to describe itself")
+	}
+}
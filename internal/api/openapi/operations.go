@@ -0,0 +1,263 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package openapi
+
+import (
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/arr"
+	"github.com/autobrr/dashbrr/internal/services/maintainerr"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+// operation describes a route beyond what the router knows about it.
+// Body and Response hold a zero value of the JSON type.
+type operation struct {
+	Summary     string
+	Description string
+	Query       []Parameter
+	Body        interface{}
+	Response    interface{}
+	Public      bool
+	Stream      bool
+}
+
+func query(name, description string, required bool) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Required: required, Schema: &Schema{Type: "string"}}
+}
+
+func boolQuery(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "boolean"}}
+}
+
+func intQuery(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "integer"}}
+}
+
+// checkUpdateOperation describes the on-demand update check of a service type
+func checkUpdateOperation(service string) operation {
+	return operation{
+		Summary:     "Check " + service + " for updates right away, bypassing the cached result",
+		Description: "The service is health checked again afterwards and the fresh result is broadcast, e.g. to clear the update badge after an upgrade",
+		Query:       instanceQuery,
+		Response:    types.UpdateCheckResponse{},
+	}
+}
+
+var (
+	instanceQuery = []Parameter{query("instanceId", "Service instance id, e.g. sonarr-1", true)}
+
+	humanizeQuery = []Parameter{instanceQuery[0], boolQuery("humanize", "Add human readable sizes such as 12.4 GB next to the raw bytes")}
+
+	pageQuery = []Parameter{
+		intQuery("limit", "Maximum number of items to return, at most 500. Responds with {items, total, limit, offset}"),
+		intQuery("offset", "Number of items to skip. Responds with {items, total, limit, offset}"),
+	}
+
+	queueDeleteQuery = []Parameter{
+		boolQuery("removeFromClient", "Also remove the download from the download client"),
+		boolQuery("blocklist", "Blocklist the release"),
+		boolQuery("skipRedownload", "Don't search for a replacement"),
+		boolQuery("changeCategory", "Change the category in the download client instead of removing it"),
+		boolQuery("dryRun", "Return the item and the options that would be applied without deleting it"),
+	}
+)
+
+// operations is keyed by method and gin route path relative to the base path
+var operations = map[string]operation{
+	"GET /api/auth/config":              {Summary: "Get the configured authentication methods", Public: true},
+	"GET /api/auth/registration-status": {Summary: "Check whether registration of the first user is open", Public: true},
+	"POST /api/auth/register":           {Summary: "Register the first user", Body: types.RegisterRequest{}, Public: true},
+	"POST /api/auth/login":              {Summary: "Log in with username and password", Body: types.LoginRequest{}, Public: true},
+	"POST /api/auth/logout":             {Summary: "Log out of the built-in session", Public: true},
+	"GET /api/auth/verify":              {Summary: "Verify the built-in session", Public: true},
+	"GET /api/auth/userinfo":            {Summary: "Get the logged in user"},
+	"GET /api/auth/callback":            {Summary: "OIDC callback", Query: []Parameter{query("code", "", true), query("state", "", true)}, Public: true},
+	"GET /api/auth/oidc/providers":      {Summary: "List the configured OIDC providers", Public: true},
+	"GET /api/auth/oidc/login":          {Summary: "Start an OIDC login", Query: []Parameter{query("provider", "Provider name, defaults to the first provider", false), query("frontendUrl", "", false)}, Public: true},
+	"POST /api/auth/oidc/logout":        {Summary: "Log out of the OIDC session", Public: true},
+	"POST /api/auth/oidc/refresh":       {Summary: "Refresh the OIDC token"},
+	"GET /api/auth/oidc/verify":         {Summary: "Verify the OIDC session"},
+	"GET /api/auth/oidc/userinfo":       {Summary: "Get the OIDC user"},
+	"GET /api/openapi.json":             {Summary: "Get this OpenAPI document", Public: true},
+	"GET /api/setup/status":             {Summary: "Check whether first-run setup is required", Response: types.SetupStatus{}, Public: true},
+	"POST /api/setup": {
+		Summary:     "Create the first user and an optional initial service",
+		Description: "Only available with built-in auth and until the first user exists",
+		Body:        types.SetupRequest{},
+		Public:      true,
+	},
+	"GET /api/settings": {Summary: "List all service configurations keyed by instance id", Response: map[string]models.ServiceConfiguration{}},
+	"POST /api/settings/:instance": {
+		Summary:  "Create or replace a service configuration",
+		Query:    []Parameter{boolQuery("validate", "Test the connection first and refuse to save the service if it fails")},
+		Body:     models.ServiceConfiguration{},
+		Response: models.ServiceConfiguration{},
+	},
+	"DELETE /api/settings/:instance": {Summary: "Delete a service configuration"},
+	"GET /api/services": {
+		Summary: "List service configurations with paging and filters",
+		Query: []Parameter{
+			intQuery("limit", "Maximum number of services to return, at most 100"),
+			intQuery("offset", "Number of services to skip"),
+			query("type", "Only services of this type, e.g. sonarr", false),
+			query("tag", "Only services with this tag", false),
+		},
+		Response: types.PagedResponse[models.ServiceConfiguration]{},
+	},
+	"POST /api/services/batch": {
+		Summary:  "Create several services at once, reporting the outcome of each",
+		Body:     []models.ServiceConfiguration{},
+		Response: types.BatchCreateServicesResponse{},
+	},
+	"POST /api/services/validate": {
+		Summary:  "Test a service connection without saving it, results are cached for 10 seconds",
+		Body:     types.ValidateServiceRequest{},
+		Response: types.ServiceValidationResult{},
+	},
+	"GET /api/version":   {Summary: "Get the build info, uptime and active database and cache backends", Response: types.BuildInfoResponse{}},
+	"GET /api/dashboard": {Summary: "Get the cached health and headline stat of every service", Response: types.DashboardSummary{}},
+	"GET /api/summary/badges": {
+		Summary:     "Get the number of offline services, services with updates, pending requests and active downloads",
+		Description: "Computed from cached data only and cached for 10 seconds",
+		Response:    types.SummaryBadges{},
+	},
+	"GET /api/activity": {
+		Summary:     "Get the recent autobrr releases, Overseerr requests and Sonarr and Radarr grabs, newest first",
+		Description: "Services that could not be read are listed in errors, the feed is built from the rest",
+		Query:       []Parameter{query("limit", "Maximum number of items, defaults to 50 and may not exceed 200", false)},
+		Response:    types.ActivityFeed{},
+	},
+	"PATCH /api/services/:instanceId": {Summary: "Update individual service fields", Body: types.UpdateServiceParams{}, Response: models.ServiceConfiguration{}},
+	"POST /api/services/:instanceId/clone": {
+		Summary:     "Copy a service configuration into a new instance without its API key",
+		Description: "Created",
+		Body:        types.CloneServiceRequest{},
+		Response:    models.ServiceConfiguration{},
+	},
+	"POST /api/services/:instanceId/mute": {
+		Summary:  "Mute a service's alerts until a time or for a duration",
+		Body:     types.MuteServiceRequest{},
+		Response: models.ServiceConfiguration{},
+	},
+	"DELETE /api/services/:instanceId/mute": {Summary: "Unmute a service", Response: models.ServiceConfiguration{}},
+	"POST /api/services/:instanceId/pin":    {Summary: "Pin a service to the top of the dashboard, or unpin it if pinned", Response: models.ServiceConfiguration{}},
+	"PUT /api/services/order":               {Summary: "Set the dashboard order of services", Body: types.ReorderServicesRequest{}},
+	"PUT /api/services/:instanceId/enabled": {Summary: "Enable or disable polling for a service", Body: types.SetServiceEnabledRequest{}, Response: models.ServiceConfiguration{}},
+	"GET /api/services/:instanceId/icon":    {Summary: "Get a service's icon through the backend", Query: []Parameter{query("path", "Icon path on the service, defaults to /favicon.ico", false)}},
+	"GET /api/services/:instanceId/link": {
+		Summary:  "Get the URL to open a service with, its access URL when set and its URL otherwise",
+		Query:    []Parameter{query("path", "Path on the service to link to, e.g. /activity/queue", false)},
+		Response: types.ServiceLinkResponse{},
+	},
+	"GET /api/services/:instanceId/changelog": {
+		Summary:     "Get the release notes between the installed and the latest version of a service",
+		Description: "Release notes are fetched from GitHub and cached for 6 hours. Responds with 503 and Retry-After while the GitHub rate limit is exhausted.",
+		Response:    types.ChangelogResponse{},
+	},
+	"GET /api/cache/keys":   {Summary: "List cache keys", Query: []Parameter{query("prefix", "Only list keys with this prefix", false)}},
+	"POST /api/cache/prune": {Summary: "Remove cache keys of deleted services"},
+	"GET /api/admin/logs": {
+		Summary: "Get the most recent log entries of this instance",
+		Query: []Parameter{
+			query("level", "Minimum level, e.g. warn", false),
+			{Name: "limit", In: "query", Description: "Only return the newest entries", Schema: &Schema{Type: "integer"}},
+		},
+	},
+	"POST /api/admin/auth/rotate": {Summary: "Revoke every session, forcing everyone including the caller to log in again"},
+	"GET /api/admin/maintenance":  {Summary: "Get the maintenance mode state", Response: types.MaintenanceStatus{}},
+	"POST /api/admin/maintenance": {
+		Summary:     "Turn maintenance mode on",
+		Description: "Pauses the health monitor, cached data is still served and marked with maintenance: true",
+		Body:        types.MaintenanceRequest{},
+		Response:    types.MaintenanceStatus{},
+	},
+	"DELETE /api/admin/maintenance":  {Summary: "Turn maintenance mode off", Response: types.MaintenanceStatus{}},
+	"GET /api/admin/events/stats":    {Summary: "Get the connected SSE clients and the health updates slow clients missed", Response: types.EventStreamStats{}},
+	"GET /api/admin/db/stats":        {Summary: "Get database connection pool statistics", Response: database.PoolStats{}},
+	"GET /api/admin/db/maintenance":  {Summary: "Get the status of the last database maintenance run"},
+	"POST /api/admin/db/maintenance": {Summary: "Start database maintenance in the background", Description: "Accepted"},
+	"GET /api/health/overall": {
+		Summary:     "Get the overall status of all services: healthy, degraded or down",
+		Description: "Down when a critical service is down, degraded when any other service is down or reports warnings",
+		Response:    types.OverallHealth{},
+	},
+	"GET /api/health/all": {Summary: "Get the cached health of all services keyed by instance id", Response: map[string]models.ServiceHealth{}},
+	"GET /api/health/events": {
+		Summary:     "Stream service health as Server-Sent Events",
+		Description: "Each health event is wrapped in a {v, type, data} envelope, v is the schema version",
+		Query:       []Parameter{query("format", "legacy sends the flat health payload without the envelope", false), query("lastEventId", "Resume after this event id", false)},
+		Stream:      true,
+	},
+	"GET /api/health/poll": {
+		Summary:     "Poll for the health results cached since a cursor, the fallback when Server-Sent Events don't work",
+		Description: "Waits up to DASHBRR__HEALTH_POLL_WAIT seconds for a new result when there is none yet. Pass the returned cursor as since on the next poll",
+		Query:       []Parameter{query("since", "Cursor returned by the previous poll, omit it to get every cached result right away", false)},
+		Response:    types.HealthPollResponse{},
+	},
+	"GET /api/health/:service":        {Summary: "Check the health of a service", Response: models.ServiceHealth{}},
+	"GET /api/health/:service/issues": {Summary: "Get the warnings and errors Sonarr, Radarr or Prowlarr report about themselves", Response: []arr.HealthResponse{}},
+	"GET /api/autobrr/stats":          {Summary: "Get autobrr release statistics", Query: instanceQuery, Response: types.AutobrrStats{}},
+	"GET /api/autobrr/irc":            {Summary: "Get autobrr IRC network status", Query: instanceQuery, Response: []types.IRCStatus{}},
+	"GET /api/autobrr/irc/detail": {
+		Summary:  "Get every autobrr IRC network with its health and enabled state",
+		Query:    instanceQuery,
+		Response: types.IRCDetailResponse{},
+	},
+	"GET /api/autobrr/releases":        {Summary: "Get recent autobrr releases", Query: instanceQuery, Response: types.ReleasesResponse{}},
+	"POST /api/autobrr/check-update":   checkUpdateOperation("autobrr"),
+	"GET /api/omegabrr/status":         {Summary: "Get omegabrr status", Query: instanceQuery, Response: models.ServiceHealth{}},
+	"POST /api/omegabrr/webhook/arrs":  {Summary: "Trigger the omegabrr ARRs webhook"},
+	"POST /api/omegabrr/webhook/lists": {Summary: "Trigger the omegabrr lists webhook"},
+	"POST /api/omegabrr/webhook/all":   {Summary: "Trigger all omegabrr webhooks"},
+	"GET /api/maintainerr/collections": {Summary: "Get Maintainerr collections", Query: instanceQuery, Response: []maintainerr.Collection{}},
+	"GET /api/plex/now-playing":        {Summary: "Get a summary of the active Plex sessions", Query: instanceQuery, Response: types.PlexNowPlayingResponse{}},
+	"GET /api/plex/sessions":           {Summary: "Get active Plex sessions", Query: instanceQuery, Response: types.PlexSessionsResponse{}},
+	"GET /api/overseerr/requests": {
+		Summary:     "Get Overseerr request statistics",
+		Description: "Responds with a page of the requests instead when limit or offset is given",
+		Query:       append(instanceQuery, pageQuery...),
+		Response:    types.RequestsStats{},
+	},
+	"GET /api/tailscale/devices": {Summary: "List Tailscale devices", Query: instanceQuery},
+	"GET /api/tailscale/tailnet": {Summary: "Get the tailnet name, key expiry and DNS settings", Query: instanceQuery},
+	"GET /api/sonarr/queue": {
+		Summary:     "Get the Sonarr queue",
+		Description: "Queues requested with non-default include options are fetched on every request. The records are paged when limit or offset is given.",
+		Query: append(humanizeQuery,
+			boolQuery("includeUnknownSeriesItems", "Include items Sonarr can't match to a series, defaults to false"),
+			boolQuery("includeSeries", "Include the series, defaults to true"),
+			boolQuery("includeEpisode", "Include the episode, defaults to true"),
+			pageQuery[0], pageQuery[1],
+		),
+		Response: types.SonarrQueueResponse{},
+	},
+	"GET /api/sonarr/stats":         {Summary: "Get Sonarr statistics", Query: humanizeQuery, Response: types.SonarrStatsResponse{}},
+	"GET /api/sonarr/queue/:id":     {Summary: "Get a Sonarr queue item with its full status messages", Query: humanizeQuery, Response: types.QueueRecord{}},
+	"DELETE /api/sonarr/queue/:id":  {Summary: "Remove an item from the Sonarr queue", Query: append(instanceQuery, queueDeleteQuery...), Response: types.QueueDeletePreview{}},
+	"POST /api/sonarr/check-update": checkUpdateOperation("Sonarr"),
+	"GET /api/radarr/queue": {
+		Summary:     "Get the Radarr queue",
+		Description: "Queues requested with non-default include options are fetched on every request. The records are paged when limit or offset is given.",
+		Query: append(humanizeQuery,
+			boolQuery("includeUnknownMovieItems", "Include items Radarr can't match to a movie, defaults to false"),
+			boolQuery("includeMovie", "Include the movie, defaults to false"),
+			pageQuery[0], pageQuery[1],
+		),
+		Response: types.RadarrQueueResponse{},
+	},
+	"GET /api/radarr/queue/:id":       {Summary: "Get a Radarr queue item with its full status messages", Query: humanizeQuery, Response: types.RadarrQueueRecord{}},
+	"DELETE /api/radarr/queue/:id":    {Summary: "Remove an item from the Radarr queue", Query: append(instanceQuery, queueDeleteQuery...), Response: types.QueueDeletePreview{}},
+	"POST /api/radarr/check-update":   checkUpdateOperation("Radarr"),
+	"GET /api/prowlarr/stats":         {Summary: "Get Prowlarr statistics", Query: instanceQuery, Response: types.ProwlarrStatsResponse{}},
+	"GET /api/prowlarr/indexers":      {Summary: "List Prowlarr indexers", Query: instanceQuery, Response: []types.ProwlarrIndexer{}},
+	"POST /api/prowlarr/check-update": checkUpdateOperation("Prowlarr"),
+	"GET /api/prowlarr/indexers/failing": {
+		Summary:  "List the failing indexers of all Prowlarr instances",
+		Response: types.FailingIndexersResponse{},
+	},
+	"POST /api/services/:instanceId/overseerr/request/:requestId/:status": {
+		Summary: "Approve or decline an Overseerr request",
+	},
+}
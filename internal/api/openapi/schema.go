@@ -0,0 +1,144 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of the OpenAPI schema object needed to describe the API types
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry generates schemas from Go types, registering named structs as components
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry(schemas map[string]*Schema) *schemaRegistry {
+	return &schemaRegistry{schemas: schemas}
+}
+
+// schemaFor returns the schema for the type of v
+func (r *schemaRegistry) schemaFor(v interface{}) *Schema {
+	return r.schemaForType(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schemaForType(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		s := r.schemaForType(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaForType(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	default:
+		// interface{} and anything else accepts any value
+		return &Schema{}
+	}
+}
+
+// structSchema registers named structs as components and references them,
+// which also keeps recursive types finite
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	name := componentName(t)
+	if name != "" {
+		if _, ok := r.schemas[name]; ok {
+			return &Schema{Ref: "#/components/schemas/" + name}
+		}
+		// Reserve the name before walking the fields
+		r.schemas[name] = &Schema{Type: "object"}
+	}
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(s, t)
+
+	if name == "" {
+		return s
+	}
+	r.schemas[name] = s
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (r *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a json name are flattened like encoding/json does
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(s, embedded)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = r.schemaForType(field.Type)
+	}
+}
+
+// componentName qualifies the type name with its package, e.g. "types.SonarrQueueResponse"
+// becomes "TypesSonarrQueueResponse", to avoid collisions between packages
+func componentName(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	if pkg == "" {
+		return t.Name()
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
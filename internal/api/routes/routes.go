@@ -6,7 +6,7 @@ package routes
 import (
 	"context"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,14 +14,22 @@ import (
 
 	"github.com/autobrr/dashbrr/internal/api/handlers"
 	"github.com/autobrr/dashbrr/internal/api/middleware"
+	"github.com/autobrr/dashbrr/internal/api/openapi"
+	"github.com/autobrr/dashbrr/internal/config"
 	"github.com/autobrr/dashbrr/internal/database"
 	"github.com/autobrr/dashbrr/internal/services"
 	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/types"
 )
 
-// SetupRoutes configures all the routes for the application
-func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService) cache.Store {
+const (
+	defaultOIDCRedirectOrigin = "http://localhost:3000"
+	defaultLoginRateLimit     = 5 // login attempts per minute per client IP
+)
+
+// SetupRoutes configures all the routes for the application. The events handler is returned
+// so its health monitor and SSE clients can be shut down with the server.
+func SetupRoutes(r *gin.Engine, cfg *config.Config, db *database.DB, health *services.HealthService) (cache.Store, *handlers.EventsHandler) {
 	// Use custom logger instead of default Gin logger
 	r.Use(middleware.Logger())
 	r.Use(gin.Recovery())
@@ -31,9 +39,12 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 	// Create a root context for cache initialization
 	ctx := context.Background()
 
-	// Initialize cache with database directory for session storage
+	// Initialize cache with the data directory for session storage
 	cacheConfig := cache.Config{
-		DataDir: filepath.Dir(os.Getenv("DASHBRR__DB_PATH")), // Use same directory as database
+		DataDir:         cfg.DataDirectory(),
+		Snapshot:        cfg.Cache.Persist,
+		Compress:        cfg.Cache.Compress,
+		CompressMinSize: cfg.Cache.CompressMinSize,
 	}
 
 	// Configure Redis if enabled
@@ -53,10 +64,13 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 		store = cache.NewMemoryStore(ctx, cacheConfig.DataDir)
 	}
 
-	// Determine cache type based on environment and Redis configuration
+	// Report the backend actually in use, InitCache falls back to memory on failure
 	cacheType := "memory"
-	if os.Getenv("CACHE_TYPE") == "redis" && os.Getenv("REDIS_HOST") != "" {
+	switch store.(type) {
+	case *cache.RedisStore:
 		cacheType = "redis"
+	case *cache.BoltStore:
+		cacheType = "bolt"
 	}
 	log.Debug().Str("type", cacheType).Msg("Cache initialized")
 
@@ -65,6 +79,13 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 	healthRateLimiter := middleware.NewRateLimiter(store, time.Minute, 30, "health:") // 30 health checks per minute
 	authRateLimiter := middleware.NewRateLimiter(store, time.Minute, 30, "auth:")     // 30 auth requests per minute
 
+	// Stricter limiter for login attempts to slow down brute forcing
+	loginLimit := cfg.Auth.LoginRateLimit
+	if loginLimit <= 0 {
+		loginLimit = defaultLoginRateLimit
+	}
+	loginRateLimiter := middleware.NewRateLimiter(store, time.Minute, loginLimit, "login:")
+
 	// Special rate limiter for Tailscale services
 	tailscaleRateLimiter := middleware.NewRateLimiter(store, 2*time.Minute, 20, "tailscale:") // 20 requests per 2 minutes
 
@@ -73,8 +94,8 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 
 	// Initialize handlers with cache
 	settingsHandler := handlers.NewSettingsHandler(db, health, store)
-	healthHandler := handlers.NewHealthHandler(db, health)
-	eventsHandler := handlers.NewEventsHandler(db, health)
+	healthHandler := handlers.NewHealthHandler(db, health, store)
+	eventsHandler := handlers.NewEventsHandler(db, health, store)
 	autobrrHandler := handlers.NewAutobrrHandler(db, store)
 	omegabrrHandler := handlers.NewOmegabrrHandler(db, store)
 	maintainerrHandler := handlers.NewMaintainerrHandler(db, store)
@@ -84,28 +105,62 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 	sonarrHandler := handlers.NewSonarrHandler(db, store)
 	radarrHandler := handlers.NewRadarrHandler(db, store)
 	prowlarrHandler := handlers.NewProwlarrHandler(db, store)
+	cacheHandler := handlers.NewCacheHandler(db, store)
+	iconHandler := handlers.NewIconHandler(db, store)
+	adminHandler := handlers.NewAdminHandler(db, store)
+	maintenanceHandler := handlers.NewMaintenanceHandler(db)
+	if err := maintenanceHandler.Load(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to load maintenance mode")
+	}
+	dashboardHandler := handlers.NewDashboardHandler(db, store)
+	activityHandler := handlers.NewActivityHandler(db, store)
+	versionHandler := handlers.NewVersionHandler(db, store)
+	changelogHandler := handlers.NewChangelogHandler(db, store)
 
 	// Initialize auth handlers and middleware
 	var oidcAuthHandler *handlers.AuthHandler
 	builtinAuthHandler := handlers.NewBuiltinAuthHandler(db, store)
 	authMiddleware := middleware.NewAuthMiddleware(store)
 
+	handlers.ConfigureSessionCookie(cfg.Auth.CookieDomain, cfg.Auth.CookieSameSite)
+	handlers.ConfigureBasePath(cfg.Server.BasePath)
+
+	authMode := strings.ToLower(cfg.Auth.Mode)
+	authDisabled := authMode == config.AuthModeNone
+	if authDisabled {
+		authMiddleware.Disable()
+		log.Warn().Msg("AUTHENTICATION IS DISABLED (auth.mode = \"none\") - anyone who can reach dashbrr has full access. Only use this on a trusted network.")
+	}
+
 	// Initialize OIDC if configuration is provided
-	if hasOIDCConfig() {
-		authConfig := &types.AuthConfig{
-			Issuer:       getEnvOrDefault("OIDC_ISSUER", ""),
-			ClientID:     getEnvOrDefault("OIDC_CLIENT_ID", ""),
-			ClientSecret: getEnvOrDefault("OIDC_CLIENT_SECRET", ""),
-			RedirectURL:  getEnvOrDefault("OIDC_REDIRECT_URL", "http://localhost:3000/api/auth/callback"),
+	var oidcProviderNames []string
+	if !authDisabled && authMode != config.AuthModeLocal {
+		if providers := oidcProviders(cfg); len(providers) > 0 {
+			oidcAuthHandler = handlers.NewAuthHandler(providers[0], store, providers[1:]...)
+			oidcProviderNames = oidcAuthHandler.ProviderNames()
+		} else if authMode == config.AuthModeOIDC {
+			log.Warn().Msg("auth.mode is \"oidc\" but no OIDC provider is configured, falling back to built-in auth")
 		}
-		oidcAuthHandler = handlers.NewAuthHandler(authConfig, store)
 	}
+	authConfigHandler := handlers.NewAuthConfigHandler(authDisabled, oidcProviderNames)
+
+	// The auth mode in effect, OIDC falls back to built-in auth without a provider
+	effectiveAuthMode := config.AuthModeLocal
+	if authDisabled {
+		effectiveAuthMode = config.AuthModeNone
+	} else if oidcAuthHandler != nil {
+		effectiveAuthMode = config.AuthModeOIDC
+	}
+	setupHandler := handlers.NewSetupHandler(db, store, effectiveAuthMode)
 
 	// Start the health monitor
 	eventsHandler.StartHealthMonitor()
 
+	// All routes are served below the configured base path
+	root := r.Group(cfg.Server.BasePath)
+
 	// Public routes (no auth required)
-	public := r.Group("")
+	public := root.Group("")
 	{
 		// Health check endpoint
 		public.GET("/health", func(c *gin.Context) {
@@ -113,7 +168,34 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 		})
 
 		// Auth configuration endpoint
-		public.GET("/api/auth/config", handlers.GetAuthConfig)
+		public.GET("/api/auth/config", authConfigHandler.GetAuthConfig)
+
+		// First-run setup, creating a user is refused once one exists
+		public.GET("/api/setup/status", setupHandler.GetSetupStatus)
+		public.POST("/api/setup", authRateLimiter.RateLimit(), setupHandler.Setup)
+
+		// API description for third-party clients
+		public.GET("/api/openapi.json", openapi.Handler(r, cfg.Server.BasePath))
+
+		// With auth disabled every auth endpoint reports so instead of failing
+		if authDisabled {
+			disabledAuth := public.Group("/api/auth")
+			{
+				disabledAuth.GET("/callback", handlers.AuthDisabled)
+				disabledAuth.GET("/oidc/providers", handlers.AuthDisabled)
+				disabledAuth.GET("/oidc/login", handlers.AuthDisabled)
+				disabledAuth.POST("/oidc/logout", handlers.AuthDisabled)
+				disabledAuth.POST("/oidc/refresh", handlers.AuthDisabled)
+				disabledAuth.GET("/oidc/verify", handlers.AuthDisabled)
+				disabledAuth.GET("/oidc/userinfo", handlers.AuthDisabled)
+				disabledAuth.GET("/registration-status", handlers.AuthDisabled)
+				disabledAuth.POST("/register", handlers.AuthDisabled)
+				disabledAuth.POST("/login", handlers.AuthDisabled)
+				disabledAuth.POST("/logout", handlers.AuthDisabled)
+				disabledAuth.GET("/verify", handlers.AuthDisabled)
+				disabledAuth.GET("/userinfo", handlers.AuthDisabled)
+			}
+		}
 
 		// OIDC auth endpoints (only if OIDC is configured)
 		if oidcAuthHandler != nil {
@@ -121,42 +203,58 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 			oidcAuth := public.Group("/api/auth/oidc")
 			oidcAuth.Use(authRateLimiter.RateLimit())
 			{
-				oidcAuth.GET("/login", oidcAuthHandler.Login)
+				oidcAuth.GET("/providers", oidcAuthHandler.Providers)
+				oidcAuth.GET("/login", loginRateLimiter.RateLimit(), oidcAuthHandler.Login)
 				oidcAuth.POST("/logout", oidcAuthHandler.Logout)
 			}
 		}
 
 		// Built-in auth endpoints
-		builtinAuth := public.Group("/api/auth")
-		builtinAuth.Use(authRateLimiter.RateLimit())
-		{
-			builtinAuth.GET("/registration-status", builtinAuthHandler.CheckRegistrationStatus)
-			builtinAuth.POST("/register", builtinAuthHandler.Register)
-			builtinAuth.POST("/login", builtinAuthHandler.Login)
-			builtinAuth.POST("/logout", builtinAuthHandler.Logout)
-			builtinAuth.GET("/verify", builtinAuthHandler.Verify)
+		if !authDisabled {
+			builtinAuth := public.Group("/api/auth")
+			builtinAuth.Use(authRateLimiter.RateLimit())
+			{
+				builtinAuth.GET("/registration-status", builtinAuthHandler.CheckRegistrationStatus)
+				builtinAuth.POST("/register", builtinAuthHandler.Register)
+				builtinAuth.POST("/login", loginRateLimiter.RateLimit(), builtinAuthHandler.Login)
+				builtinAuth.POST("/logout", builtinAuthHandler.Logout)
+				builtinAuth.GET("/verify", builtinAuthHandler.Verify)
+			}
 		}
 	}
 
 	// Protected auth routes
-	protectedAuth := r.Group("/api/auth")
-	protectedAuth.Use(authMiddleware.RequireAuth())
-	protectedAuth.Use(authRateLimiter.RateLimit())
-	{
-		if oidcAuthHandler != nil {
-			oidc := protectedAuth.Group("/oidc")
-			{
-				oidc.POST("/refresh", oidcAuthHandler.RefreshToken)
-				oidc.GET("/verify", oidcAuthHandler.VerifyToken)
-				oidc.GET("/userinfo", oidcAuthHandler.UserInfo)
+	if !authDisabled {
+		protectedAuth := root.Group("/api/auth")
+		protectedAuth.Use(authMiddleware.RequireAuth())
+		protectedAuth.Use(authRateLimiter.RateLimit())
+		{
+			if oidcAuthHandler != nil {
+				oidc := protectedAuth.Group("/oidc")
+				{
+					oidc.POST("/refresh", oidcAuthHandler.RefreshToken)
+					oidc.GET("/verify", oidcAuthHandler.VerifyToken)
+					oidc.GET("/userinfo", oidcAuthHandler.UserInfo)
+				}
 			}
+			protectedAuth.GET("/userinfo", builtinAuthHandler.GetUserInfo)
 		}
-		protectedAuth.GET("/userinfo", builtinAuthHandler.GetUserInfo)
 	}
 
+	// Double-submit CSRF protection for state-changing API requests. The token cookie must be
+	// readable by the frontend, which echoes it back in the X-CSRF-Token header.
+	csrfConfig := middleware.DefaultCSRFConfig()
+	csrfConfig.HttpOnly = false
+	csrfConfig.Secure = false
+	csrfConfig.Domain = cfg.Auth.CookieDomain
+	csrfConfig.ExemptBearer = true
+	csrfConfig.ExemptPaths = []string{cfg.Server.BasePath + "/api/auth/", cfg.Server.BasePath + "/api/health/events"}
+
 	// API routes group with auth middleware
-	api := r.Group("/api")
+	api := root.Group("/api")
 	api.Use(authMiddleware.RequireAuth())
+	api.Use(middleware.CSRF(csrfConfig))
+	api.Use(middleware.Maintenance(handlers.MaintenanceActive))
 	{
 		// Settings endpoints - no caching to ensure fresh data
 		settings := api.Group("/settings")
@@ -166,12 +264,73 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 			settings.DELETE("/:instance", settingsHandler.DeleteSettings)
 		}
 
+		api.GET("/services", settingsHandler.ListServices)
+		api.POST("/services/batch", settingsHandler.CreateServices)
+		api.POST("/services/validate", settingsHandler.ValidateService)
+		api.PUT("/services/order", settingsHandler.ReorderServices)
+
+		// Build and runtime info, e.g. for bug reports
+		api.GET("/version", versionHandler.GetVersion)
+
+		// Cached health and headline stats of all services in one payload
+		api.GET("/dashboard", dashboardHandler.GetSummary)
+
+		// Counts for the navigation badges, from cached data
+		api.GET("/summary/badges", dashboardHandler.GetBadges)
+
+		// Recent releases, requests and grabs of all services in one feed
+		api.GET("/activity", activityHandler.GetActivity)
+
+		// Partial service updates (e.g. rotating an API key)
+		api.PATCH("/services/:instanceId", settingsHandler.UpdateServiceFields)
+		api.POST("/services/:instanceId/clone", settingsHandler.CloneService)
+		api.POST("/services/:instanceId/mute", settingsHandler.MuteService)
+		api.DELETE("/services/:instanceId/mute", settingsHandler.UnmuteService)
+		api.POST("/services/:instanceId/pin", settingsHandler.TogglePinned)
+		api.PUT("/services/:instanceId/enabled", settingsHandler.SetServiceEnabled)
+		api.GET("/services/:instanceId/icon", iconHandler.GetIcon)
+		api.GET("/services/:instanceId/link", settingsHandler.GetServiceLink)
+		api.GET("/services/:instanceId/changelog", changelogHandler.GetChangelog)
+
+		// Cache maintenance endpoints
+		cacheAdmin := api.Group("/cache")
+		{
+			cacheAdmin.GET("/keys", cacheHandler.ListKeys)
+			cacheAdmin.POST("/prune", cacheHandler.PruneKeys)
+		}
+
+		// Recent logs of this instance
+		api.GET("/admin/logs", adminHandler.GetLogs)
+
+		// SSE clients and the updates slow clients missed
+		api.GET("/admin/events/stats", eventsHandler.GetStreamStats)
+
+		// Revoke all sessions, e.g. after a compromise
+		api.POST("/admin/auth/rotate", adminHandler.RotateAuth)
+
+		// Instance maintenance mode, pauses the health monitor during planned work
+		api.GET("/admin/maintenance", maintenanceHandler.GetMaintenance)
+		api.POST("/admin/maintenance", maintenanceHandler.EnableMaintenance)
+		api.DELETE("/admin/maintenance", maintenanceHandler.DisableMaintenance)
+
+		// Database maintenance endpoints
+		dbAdmin := api.Group("/admin/db")
+		{
+			dbAdmin.GET("/stats", adminHandler.GetDBStats)
+			dbAdmin.GET("/maintenance", adminHandler.GetDBMaintenance)
+			dbAdmin.POST("/maintenance", adminHandler.RunDBMaintenance)
+		}
+
 		// Health check endpoints (no cache for SSE)
 		health := api.Group("/health")
 		health.Use(healthRateLimiter.RateLimit())
 		{
+			health.GET("/all", eventsHandler.GetAllHealth)
+			health.GET("/overall", dashboardHandler.GetOverallHealth)
 			health.GET("/:service", healthHandler.CheckHealth)
+			health.GET("/:service/issues", healthHandler.GetHealthIssues)
 			health.GET("/events", eventsHandler.StreamHealth)
+			health.GET("/poll", eventsHandler.PollHealth)
 		}
 
 		// Service endpoints with specific rate limits and caches
@@ -184,8 +343,11 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 			{
 				regularServices.GET("/autobrr/stats", autobrrHandler.GetAutobrrReleaseStats)
 				regularServices.GET("/autobrr/irc", autobrrHandler.GetAutobrrIRCStatus)
+				regularServices.GET("/autobrr/irc/detail", autobrrHandler.GetAutobrrIRCDetail)
 				regularServices.GET("/autobrr/releases", autobrrHandler.GetAutobrrReleases)
+				regularServices.POST("/autobrr/check-update", eventsHandler.CheckUpdate)
 				regularServices.GET("/plex/sessions", plexHandler.GetPlexSessions)
+				regularServices.GET("/plex/now-playing", plexHandler.GetSessions)
 				regularServices.GET("/maintainerr/collections", maintainerrHandler.GetMaintainerrCollections)
 
 				// Overseerr endpoints
@@ -199,14 +361,18 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 				{
 					sonarr.GET("/queue", sonarrHandler.GetQueue)
 					sonarr.GET("/stats", sonarrHandler.GetStats)
+					sonarr.GET("/queue/:id", sonarrHandler.GetQueueItem)
 					sonarr.DELETE("/queue/:id", sonarrHandler.DeleteQueueItem)
+					sonarr.POST("/check-update", eventsHandler.CheckUpdate)
 				}
 
 				// Radarr endpoints
 				radarr := regularServices.Group("/radarr")
 				{
 					radarr.GET("/queue", radarrHandler.GetQueue)
+					radarr.GET("/queue/:id", radarrHandler.GetQueueItem)
 					radarr.DELETE("/queue/:id", radarrHandler.DeleteQueueItem)
+					radarr.POST("/check-update", eventsHandler.CheckUpdate)
 				}
 
 				// Prowlarr endpoints
@@ -214,6 +380,8 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 				{
 					prowlarr.GET("/stats", prowlarrHandler.GetStats)
 					prowlarr.GET("/indexers", prowlarrHandler.GetIndexers)
+					prowlarr.GET("/indexers/failing", prowlarrHandler.GetFailingIndexers)
+					prowlarr.POST("/check-update", eventsHandler.CheckUpdate)
 				}
 
 				// Omegabrr endpoints
@@ -235,6 +403,7 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 			tailscaleServices.Use(cacheMiddleware.Cache())
 			{
 				tailscaleServices.GET("/tailscale/devices", tailscaleHandler.GetTailscaleDevices)
+				tailscaleServices.GET("/tailscale/tailnet", tailscaleHandler.GetTailnet)
 			}
 
 			// Service action endpoints that require instanceId
@@ -250,19 +419,50 @@ func SetupRoutes(r *gin.Engine, db *database.DB, health *services.HealthService)
 		}
 	}
 
-	return store
+	return store, eventsHandler
 }
 
 // hasOIDCConfig checks if all required OIDC configuration is provided
-func hasOIDCConfig() bool {
-	return os.Getenv("OIDC_ISSUER") != "" &&
-		os.Getenv("OIDC_CLIENT_ID") != "" &&
-		os.Getenv("OIDC_CLIENT_SECRET") != ""
+func hasOIDCConfig(issuer, clientID, clientSecret string) bool {
+	return issuer != "" && clientID != "" && clientSecret != ""
+}
+
+// oidcProviders collects the configured OIDC providers. The [auth.oidc] section (or OIDC_* env vars)
+// becomes the default provider, followed by any [[auth.providers]] entries.
+func oidcProviders(cfg *config.Config) []*types.AuthConfig {
+	var providers []*types.AuthConfig
+	defaultRedirectURL := defaultOIDCRedirectOrigin + cfg.Server.BasePath + "/api/auth/callback"
+
+	oidc := cfg.Auth.OIDC
+	if hasOIDCConfig(oidc.Issuer, oidc.ClientID, oidc.ClientSecret) {
+		providers = append(providers, &types.AuthConfig{
+			Issuer:       oidc.Issuer,
+			ClientID:     oidc.ClientID,
+			ClientSecret: oidc.ClientSecret,
+			RedirectURL:  getValueOrDefault(oidc.RedirectURL, defaultRedirectURL),
+		})
+	}
+
+	for _, p := range cfg.Auth.Providers {
+		if !hasOIDCConfig(p.Issuer, p.ClientID, p.ClientSecret) {
+			log.Warn().Str("provider", p.Name).Msg("Skipping incomplete OIDC provider configuration")
+			continue
+		}
+		providers = append(providers, &types.AuthConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  getValueOrDefault(p.RedirectURL, defaultRedirectURL),
+		})
+	}
+
+	return providers
 }
 
-// getEnvOrDefault returns the value of an environment variable or a default value if not set
-func getEnvOrDefault(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
+// getValueOrDefault returns value or a default value if it is empty
+func getValueOrDefault(value, defaultValue string) string {
+	if value != "" {
 		return value
 	}
 	return defaultValue
@@ -0,0 +1,122 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autobrr/dashbrr/internal/api/openapi"
+	"github.com/autobrr/dashbrr/internal/config"
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/services"
+)
+
+// setupRouter registers every route with a configuration adjusted by configure. The
+// database, sessions.json and the rest of the on-disk state live in temporary directories,
+// so the tests leave nothing behind in the source tree.
+func setupRouter(t *testing.T, configure func(cfg *config.Config)) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dataDir := t.TempDir()
+	t.Setenv("CACHE_TYPE", "memory")
+	t.Setenv("REDIS_HOST", "")
+	t.Setenv("DASHBRR__DATA_DIR", dataDir)
+
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = dataDir
+	cfg.Database.Path = filepath.Join(dataDir, "dashbrr.db")
+	if configure != nil {
+		configure(cfg)
+	}
+
+	db, err := database.InitDB(cfg.Database.Path)
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	r := gin.New()
+	store, events := SetupRoutes(r, cfg, db, services.NewHealthService())
+	t.Cleanup(func() {
+		events.Shutdown()
+		store.Close()
+	})
+	return r
+}
+
+func TestSetupRoutes_OpenAPI(t *testing.T) {
+	for _, basePath := range []string{"", "/dashbrr"} {
+		t.Run("base path "+basePath, func(t *testing.T) {
+			r := setupRouter(t, func(cfg *config.Config) { cfg.Server.BasePath = basePath })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, basePath+"/api/openapi.json", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var doc openapi.Document
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+			if _, ok := doc.Paths["/api/health/{service}"]["get"]; !ok {
+				t.Errorf("Expected the registered health route in the document, got %d paths", len(doc.Paths))
+			}
+		})
+	}
+}
+
+func TestSetupRoutes_AuthModeNone(t *testing.T) {
+	tests := []struct {
+		mode         string
+		expectedCode int
+	}{
+		{config.AuthModeLocal, http.StatusUnauthorized},
+		{config.AuthModeNone, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			r := setupRouter(t, func(cfg *config.Config) { cfg.Auth.Mode = tt.mode })
+
+			// Protected routes are only reachable without a session when auth is disabled
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/services", nil))
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d for /api/services, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	r := setupRouter(t, func(cfg *config.Config) { cfg.Auth.Mode = config.AuthModeNone })
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/api/auth/verify"},
+		{http.MethodPost, "/api/auth/login"},
+		{http.MethodGet, "/api/auth/oidc/login"},
+		{http.MethodGet, "/api/auth/userinfo"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(route.method, route.path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d for %s %s, got %d", http.StatusOK, route.method, route.path, w.Code)
+			continue
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Errorf("Failed to decode response of %s %s: %v", route.method, route.path, err)
+			continue
+		}
+		if response["authDisabled"] != true {
+			t.Errorf("Expected %s %s to report auth as disabled, got %v", route.method, route.path, response)
+		}
+	}
+}
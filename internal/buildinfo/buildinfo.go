@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"time"
 )
 
 var (
@@ -12,6 +13,32 @@ var (
 	Date    = ""
 )
 
+// startTime is when the process started, used for the uptime
+var startTime = time.Now()
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build info of the running binary
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// Uptime returns how long the process has been running
+func Uptime() time.Duration {
+	return time.Since(startTime)
+}
+
 // AttachUserAgentHeader attaches a User-Agent header to the request
 func AttachUserAgentHeader(req *http.Request) {
 	agent := fmt.Sprintf("dashbrr/%s (%s %s)", Version, runtime.GOOS, runtime.GOARCH)
@@ -151,7 +151,7 @@ func (c *ConfigCommand) handleExport(ctx context.Context, args []string) error {
 	}
 
 	// Get all services from database
-	services, err := c.db.GetAllServices(context.Background())
+	services, err := c.db.GetAllServices(context.Background(), true)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve services: %v", err)
 	}
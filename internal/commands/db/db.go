@@ -0,0 +1,178 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/commands/base"
+	"github.com/autobrr/dashbrr/internal/database"
+)
+
+type DBCommand struct {
+	*base.BaseCommand
+	db *database.DB
+}
+
+func NewDBCommand(db *database.DB) *DBCommand {
+	return &DBCommand{
+		BaseCommand: base.NewBaseCommand(
+			"db",
+			"Database maintenance",
+			"<subcommand>\n\n  Subcommands:\n    vacuum                   Checkpoint the WAL and vacuum the SQLite database\n    backup [path]            Write a consistent snapshot of the database, to a timestamped file by default\n    backup --restore <path>  Restore a snapshot into an empty database",
+		),
+		db: db,
+	}
+}
+
+func (c *DBCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("insufficient arguments. %s", c.Usage())
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "vacuum":
+		return c.vacuum(ctx)
+	case "backup":
+		if len(args) > 1 && args[1] == "--restore" {
+			if len(args) < 3 {
+				return fmt.Errorf("missing backup file. %s", c.Usage())
+			}
+			return c.restore(ctx, args[2])
+		}
+		var path string
+		if len(args) > 1 {
+			path = args[1]
+		}
+		return c.backup(ctx, path)
+	default:
+		return fmt.Errorf("unknown subcommand: %s", subcommand)
+	}
+}
+
+func (c *DBCommand) vacuum(ctx context.Context) error {
+	if c.db.Driver() != "sqlite" {
+		fmt.Printf("Nothing to do for %s, maintenance only applies to SQLite\n", c.db.Driver())
+		return nil
+	}
+
+	start := time.Now()
+	if err := c.db.Maintenance(ctx); err != nil {
+		return fmt.Errorf("database maintenance failed: %v", err)
+	}
+
+	fmt.Printf("Database vacuumed in %s\n", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func (c *DBCommand) backup(ctx context.Context, path string) error {
+	timestamp := time.Now().Format("20060102-150405")
+
+	if c.db.Driver() == "postgres" {
+		if path == "" {
+			path = fmt.Sprintf("dashbrr-backup-%s.sql", timestamp)
+		}
+		if err := pgDump(ctx, path); err != nil {
+			return err
+		}
+		fmt.Printf("Database backed up to %s\n", path)
+		return nil
+	}
+
+	if path == "" {
+		path = filepath.Join(filepath.Dir(c.db.Path()), fmt.Sprintf("dashbrr-backup-%s.db", timestamp))
+	}
+
+	start := time.Now()
+	if err := c.db.Backup(ctx, path); err != nil {
+		return fmt.Errorf("database backup failed: %v", err)
+	}
+
+	fmt.Printf("Database backed up to %s in %s\n", path, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func (c *DBCommand) restore(ctx context.Context, path string) error {
+	if c.db.Driver() == "postgres" {
+		empty, err := c.db.IsEmpty(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to inspect database: %v", err)
+		}
+		if !empty {
+			return fmt.Errorf("refusing to restore: %v", database.ErrDatabaseNotEmpty)
+		}
+		if err := psqlRestore(ctx, path); err != nil {
+			return err
+		}
+		fmt.Printf("Database restored from %s\n", path)
+		return nil
+	}
+
+	if err := c.db.Restore(ctx, path); err != nil {
+		if errors.Is(err, database.ErrDatabaseNotEmpty) {
+			return fmt.Errorf("refusing to restore: %v", err)
+		}
+		return fmt.Errorf("database restore failed: %v", err)
+	}
+
+	fmt.Printf("Database restored from %s\n", path)
+	return nil
+}
+
+// pgDump dumps the data of the PostgreSQL database with pg_dump. The schema is left out,
+// dashbrr creates it on startup, so a dump restores into a freshly initialized database.
+func pgDump(ctx context.Context, path string) error {
+	config := database.NewConfig()
+
+	cmd := exec.CommandContext(ctx, "pg_dump",
+		"--data-only",
+		"--host", config.Host,
+		"--port", config.Port,
+		"--username", config.User,
+		"--dbname", config.DBName,
+		"--file", path,
+	)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+config.Password)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_dump failed, make sure it is installed and matches the server version: %v", err)
+	}
+	return nil
+}
+
+// psqlRestore loads a dump made by pgDump with psql, stopping at the first error
+func psqlRestore(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("backup file: %v", err)
+	}
+
+	config := database.NewConfig()
+
+	cmd := exec.CommandContext(ctx, "psql",
+		"--set", "ON_ERROR_STOP=1",
+		"--single-transaction",
+		"--quiet",
+		"--host", config.Host,
+		"--port", config.Port,
+		"--username", config.User,
+		"--dbname", config.DBName,
+		"--file", path,
+	)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+config.Password)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("psql failed, make sure it is installed: %v", err)
+	}
+	return nil
+}
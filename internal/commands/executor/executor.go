@@ -8,6 +8,7 @@ import (
 	"github.com/autobrr/dashbrr/internal/commands/autobrr"
 	"github.com/autobrr/dashbrr/internal/commands/base"
 	"github.com/autobrr/dashbrr/internal/commands/config"
+	dbcmd "github.com/autobrr/dashbrr/internal/commands/db"
 	"github.com/autobrr/dashbrr/internal/commands/general"
 	"github.com/autobrr/dashbrr/internal/commands/health"
 	"github.com/autobrr/dashbrr/internal/commands/help"
@@ -75,6 +76,7 @@ func registerCommands(registry *base.Registry, db *database.DB) error {
 		user.NewUserCommand(db),
 		serviceCmd,
 		configCmd, // Add the config command to top-level commands
+		dbcmd.NewDBCommand(db),
 	}
 
 	serviceCommands := []base.Command{
@@ -10,6 +10,7 @@ import (
 	"github.com/autobrr/dashbrr/internal/commands/base"
 	"github.com/autobrr/dashbrr/internal/config"
 	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/autobrr"
 	"github.com/autobrr/dashbrr/internal/services/general"
 	"github.com/autobrr/dashbrr/internal/services/maintainerr"
@@ -96,7 +97,7 @@ func (c *HealthCommand) Execute(ctx context.Context, args []string) error {
 	// Service health checks
 	if c.checkServices {
 		// Get all configured services
-		services, err := c.db.GetAllServices(context.Background())
+		services, err := c.db.GetAllServices(context.Background(), false)
 		if err != nil {
 			// Log error but continue with empty services map
 			fmt.Printf("Failed to retrieve services: %v\n", err)
@@ -118,34 +119,36 @@ func (c *HealthCommand) Execute(ctx context.Context, args []string) error {
 				switch {
 				case strings.HasPrefix(service.InstanceID, "autobrr-"):
 					health, _ := autobrrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "omegabrr-"):
 					health, _ := omegabrrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "radarr-"):
+					service.Configure(radarrService)
 					health, _ := radarrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "sonarr-"):
+					service.Configure(sonarrService)
 					health, _ := sonarrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "prowlarr-"):
 					health, _ := prowlarrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "plex-"):
 					health, _ := plexService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "overseerr-"):
 					health, _ := overseerrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "maintainerr-"):
 					health, _ := maintainerrService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "tailscale-"):
 					health, _ := tailscaleService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				case strings.HasPrefix(service.InstanceID, "general-"):
 					health, _ := generalService.CheckHealth(ctx, service.URL, service.APIKey)
-					status.Services[service.InstanceID] = health.Status == "online" || health.Status == "warning"
+					status.Services[service.InstanceID] = health.Status == models.StatusOnline || health.Status == models.StatusWarning
 				}
 			}
 		}
@@ -34,7 +34,7 @@ func NewAddCommand(db *database.DB) *AddCommand {
 }
 
 func (c *AddCommand) getNextInstanceID() (string, error) {
-	services, err := c.db.GetAllServices(context.Background())
+	services, err := c.db.GetAllServices(context.Background(), true)
 	if err != nil {
 		return "", fmt.Errorf("failed to get services: %v", err)
 	}
@@ -87,7 +87,7 @@ func (c *AddCommand) Execute(ctx context.Context, args []string) error {
 	// Perform health check to validate connection
 	health, _ := maintainerrService.CheckHealth(ctx, serviceURL, apiKey)
 
-	if health.Status == "error" || health.Status == "offline" {
+	if health.Status == models.StatusError || health.Status == models.StatusOffline {
 		return fmt.Errorf("failed to connect to maintainerr service: %s", health.Message)
 	}
 
@@ -183,7 +183,7 @@ func NewListCommand(db *database.DB) *ListCommand {
 }
 
 func (c *ListCommand) Execute(ctx context.Context, args []string) error {
-	services, err := c.db.GetAllServices(ctx)
+	services, err := c.db.GetAllServices(ctx, true)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve services: %v", err)
 	}
@@ -33,7 +33,7 @@ func NewAddCommand(db *database.DB) *AddCommand {
 }
 
 func (c *AddCommand) getNextInstanceID() (string, error) {
-	services, err := c.db.GetAllServices(context.Background())
+	services, err := c.db.GetAllServices(context.Background(), true)
 	if err != nil {
 		return "", fmt.Errorf("failed to get services: %v", err)
 	}
@@ -86,7 +86,7 @@ func (c *AddCommand) Execute(ctx context.Context, args []string) error {
 	// Perform health check to validate connection
 	health, _ := omegabrrService.CheckHealth(ctx, serviceURL, apiKey)
 
-	if health.Status != "online" {
+	if health.Status != models.StatusOnline {
 		return fmt.Errorf("failed to connect to Omegabrr service: %s", health.Message)
 	}
 
@@ -183,7 +183,7 @@ func NewListCommand(db *database.DB) *ListCommand {
 
 func (c *ListCommand) Execute(ctx context.Context, args []string) error {
 	// Get all configured services
-	services, err := c.db.GetAllServices(context.Background())
+	services, err := c.db.GetAllServices(context.Background(), true)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve services: %v", err)
 	}
@@ -202,7 +202,7 @@ func (c *ListCommand) Execute(ctx context.Context, args []string) error {
 
 			// Try to get health info which includes version
 			omegabrrService := models.NewOmegabrrService()
-			if health, _ := omegabrrService.CheckHealth(ctx, service.URL, service.APIKey); health.Status == "online" {
+			if health, _ := omegabrrService.CheckHealth(ctx, service.URL, service.APIKey); health.Status == models.StatusOnline {
 				fmt.Printf("    Version: %s\n", health.Version)
 				fmt.Printf("    Status: %s\n", health.Status)
 			}
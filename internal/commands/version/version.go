@@ -15,11 +15,8 @@ import (
 
 const githubAPIURL = "https://api.github.com/repos/autobrr/dashbrr/releases/latest"
 
-type VersionInfo struct {
-	Version string `json:"version"`
-	Commit  string `json:"commit"`
-	Date    string `json:"date"`
-}
+// VersionInfo is the same build info the /api/version endpoint reports
+type VersionInfo = buildinfo.Info
 
 type GitHubRelease struct {
 	TagName     string    `json:"tag_name"`
@@ -56,11 +53,7 @@ func (c *VersionCommand) Execute(ctx context.Context, args []string) error {
 	}
 
 	// Get current version info
-	current := VersionInfo{
-		Version: buildinfo.Version,
-		Commit:  buildinfo.Commit,
-		Date:    buildinfo.Date,
-	}
+	current := buildinfo.Get()
 
 	if c.jsonOutput {
 		return c.outputJSON(current)
@@ -70,6 +63,7 @@ func (c *VersionCommand) Execute(ctx context.Context, args []string) error {
 	fmt.Printf("dashbrr version %s\n", current.Version)
 	fmt.Printf("Commit: %s\n", current.Commit)
 	fmt.Printf("Built: %s\n", current.Date)
+	fmt.Printf("Go: %s\n", current.GoVersion)
 
 	// Check GitHub if requested
 	if c.checkGithub {
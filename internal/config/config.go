@@ -9,32 +9,80 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
 )
 
 const (
 	EnvConfigPath = "DASHBRR__CONFIG_PATH"
 )
 
+// Authentication modes
+const (
+	AuthModeOIDC  = "oidc"  // OIDC login (falls back to built-in when no provider is configured)
+	AuthModeLocal = "local" // Built-in username/password login only
+	AuthModeNone  = "none"  // Authentication disabled, for trusted networks only
+)
+
 // Config represents the main configuration structure
 type Config struct {
-	Server   ServerConfig   `toml:"server"`
-	Cache    CacheConfig    `toml:"cache"`
-	Database DatabaseConfig `toml:"database"`
-	Auth     AuthConfig     `toml:"auth"`
+	Server    ServerConfig    `toml:"server"`
+	Cache     CacheConfig     `toml:"cache"`
+	Database  DatabaseConfig  `toml:"database"`
+	Auth      AuthConfig      `toml:"auth"`
+	Health    HealthConfig    `toml:"health"`
+	Log       LogConfig       `toml:"log"`
+	Overseerr OverseerrConfig `toml:"overseerr"`
+	Queue     QueueConfig     `toml:"queue"`
+	HTTP      HTTPConfig      `toml:"http"`
+	Services  []ServiceConfig `toml:"services,omitempty"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
 	ListenAddr string `toml:"listen_addr" env:"DASHBRR__LISTEN_ADDR"`
+	BasePath   string `toml:"base_path,omitempty" env:"DASHBRR__BASE_PATH"` // e.g. "/dashbrr" when served from a reverse proxy sub-path
+	DataDir    string `toml:"data_dir,omitempty" env:"DASHBRR__DATA_DIR"`   // sessions.json and other on-disk state, defaults to the database directory
+
+	// TrustedProxies lists the IP addresses and CIDR ranges of reverse proxies whose
+	// X-Forwarded-* headers are honored, loopback when empty. Comma separated in the env var.
+	TrustedProxies []string `toml:"trusted_proxies,omitempty" env:"DASHBRR__TRUSTED_PROXIES"`
+
+	// Timeouts in seconds, 0 uses the default. The SSE stream always runs without
+	// read and write deadlines so long-lived connections aren't cut.
+	ReadTimeout       int `toml:"read_timeout,omitempty" env:"DASHBRR__SERVER_READ_TIMEOUT"`
+	ReadHeaderTimeout int `toml:"read_header_timeout,omitempty" env:"DASHBRR__SERVER_READ_HEADER_TIMEOUT"`
+	WriteTimeout      int `toml:"write_timeout,omitempty" env:"DASHBRR__SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       int `toml:"idle_timeout,omitempty" env:"DASHBRR__SERVER_IDLE_TIMEOUT"`
+}
+
+// Default server timeouts
+const (
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultWriteTimeout      = 15 * time.Second
+	DefaultIdleTimeout       = 60 * time.Second
+)
+
+// Timeout converts a timeout in seconds to a duration, falling back to the default when unset
+func Timeout(seconds int, fallback time.Duration) time.Duration {
+	if seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
 }
 
 // CacheConfig holds cache-related configuration
 type CacheConfig struct {
-	Type  string      `toml:"type" env:"CACHE_TYPE"`
-	Redis RedisConfig `toml:"redis"`
+	Type            string      `toml:"type" env:"CACHE_TYPE"`
+	Persist         bool        `toml:"persist,omitempty" env:"DASHBRR__CACHE_PERSIST"`                     // Snapshot the memory cache to the data directory so it survives restarts
+	Compress        bool        `toml:"compress,omitempty" env:"DASHBRR__CACHE_COMPRESS"`                   // Store large values gzip compressed
+	CompressMinSize int         `toml:"compress_min_size,omitempty" env:"DASHBRR__CACHE_COMPRESS_MIN_SIZE"` // Bytes from which values are compressed, 0 uses the default of 4096
+	Redis           RedisConfig `toml:"redis"`
 }
 
 // RedisConfig holds Redis-specific configuration
@@ -56,7 +104,116 @@ type DatabaseConfig struct {
 
 // AuthConfig holds authentication-related configuration
 type AuthConfig struct {
-	OIDC OIDCConfig `toml:"oidc"`
+	Mode           string               `toml:"mode,omitempty" env:"DASHBRR__AUTH_MODE"`
+	LoginRateLimit int                  `toml:"login_rate_limit,omitempty" env:"DASHBRR__AUTH_LOGIN_RATE_LIMIT"` // Login attempts per minute per client IP
+	CookieDomain   string               `toml:"cookie_domain,omitempty" env:"DASHBRR__AUTH_COOKIE_DOMAIN"`
+	CookieSameSite string               `toml:"cookie_samesite,omitempty" env:"DASHBRR__AUTH_COOKIE_SAMESITE"` // lax (default), strict or none
+	OIDC           OIDCConfig           `toml:"oidc"`
+	Providers      []OIDCProviderConfig `toml:"providers,omitempty"`
+}
+
+// HealthConfig holds health check configuration
+type HealthConfig struct {
+	SlowResponseThreshold  int `toml:"slow_response_threshold,omitempty" env:"DASHBRR__HEALTH_SLOW_RESPONSE_THRESHOLD"`     // Milliseconds, 0 disables
+	PlexTranscodeLimit     int `toml:"plex_transcode_limit,omitempty" env:"DASHBRR__HEALTH_PLEX_TRANSCODE_LIMIT"`           // Concurrent transcodes before Plex is reported as warning, 0 disables
+	FailingIndexerAlert    int `toml:"failing_indexer_alert,omitempty" env:"DASHBRR__HEALTH_FAILING_INDEXER_ALERT"`         // Failing indexers across all Prowlarr instances before an alert is raised, 0 disables
+	TailscaleKeyExpiryDays int `toml:"tailscale_key_expiry_days,omitempty" env:"DASHBRR__HEALTH_TAILSCALE_KEY_EXPIRY_DAYS"` // Days before a device key expires at which Tailscale is reported as warning, 0 disables
+	InitialConcurrency     int `toml:"initial_concurrency,omitempty" env:"DASHBRR__HEALTH_INITIAL_CONCURRENCY"`             // Services checked at once on their first check, 0 uses the default
+	PollWait               int `toml:"poll_wait,omitempty" env:"DASHBRR__HEALTH_POLL_WAIT"`                                 // Seconds GET /api/health/poll waits for a new result, 0 uses the default of 10
+	BroadcastTimeout       int `toml:"broadcast_timeout,omitempty" env:"DASHBRR__HEALTH_BROADCAST_TIMEOUT"`                 // Milliseconds a broadcast waits on a slow SSE client, 0 uses the default of 2000
+}
+
+// LogConfig holds logging configuration
+type LogConfig struct {
+	ChangeSampleRate int `toml:"change_sample_rate,omitempty" env:"DASHBRR__LOG_CHANGE_SAMPLE_RATE"` // Log 1 of every N change detection events, 0 logs all
+}
+
+// OverseerrConfig holds Overseerr configuration
+type OverseerrConfig struct {
+	TitleLookupConcurrency int `toml:"title_lookup_concurrency,omitempty" env:"DASHBRR__OVERSEERR_TITLE_LOOKUP_CONCURRENCY"` // Request titles looked up in Radarr and Sonarr at once, 0 uses the default of 4
+}
+
+// HTTPConfig holds the connection pool of the HTTP client services are polled with
+type HTTPConfig struct {
+	MaxIdleConns        int `toml:"max_idle_conns,omitempty" env:"DASHBRR__HTTP_MAX_IDLE_CONNS"`                   // Idle connections kept across all hosts, 0 uses the default of 100
+	MaxIdleConnsPerHost int `toml:"max_idle_conns_per_host,omitempty" env:"DASHBRR__HTTP_MAX_IDLE_CONNS_PER_HOST"` // Idle connections kept per host, 0 uses the default of 10
+	IdleConnTimeout     int `toml:"idle_conn_timeout,omitempty" env:"DASHBRR__HTTP_IDLE_CONN_TIMEOUT"`             // Seconds an idle connection is kept, 0 uses the default of 90
+}
+
+// QueueConfig holds Sonarr and Radarr queue configuration
+type QueueConfig struct {
+	AutoRemove []QueueAutoRemoveRule `toml:"auto_remove,omitempty"`
+}
+
+// QueueAutoRemoveRule removes and blocklists stalled queue items of a Sonarr or Radarr instance.
+// Rules are configured as a [[queue.auto_remove]] list in the config file, nothing is removed
+// for instances without one.
+type QueueAutoRemoveRule struct {
+	InstanceID string `toml:"instance_id"`
+	Pattern    string `toml:"pattern"` // Regular expression matched against the item's error and status messages
+	After      int    `toml:"after"`   // Minutes an item has to be stalled before it is removed
+}
+
+// ServiceConfig is a service defined in the config file as a [[services]] list. These services
+// are synced into the database on startup and are read-only in the API.
+type ServiceConfig struct {
+	Type       string `toml:"type"`
+	Name       string `toml:"name"`
+	URL        string `toml:"url"`
+	APIKey     string `toml:"api_key,omitempty"`
+	InstanceID string `toml:"instance_id,omitempty"` // Defaults to <type>-<n>, counting the services of that type in the file
+	AccessURL  string `toml:"access_url,omitempty"`
+}
+
+// ServiceConfigurations converts the services of the config file to service configurations,
+// filling in default instance ids and display names
+func (c *Config) ServiceConfigurations() ([]*models.ServiceConfiguration, error) {
+	configurations := make([]*models.ServiceConfiguration, 0, len(c.Services))
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+
+	for i, service := range c.Services {
+		serviceType := strings.ToLower(strings.TrimSpace(service.Type))
+		if serviceType == "" {
+			return nil, fmt.Errorf("service %d: type is required", i+1)
+		}
+		if service.URL == "" {
+			return nil, fmt.Errorf("service %d: url is required", i+1)
+		}
+
+		counts[serviceType]++
+		instanceID := service.InstanceID
+		if instanceID == "" {
+			instanceID = fmt.Sprintf("%s-%d", serviceType, counts[serviceType])
+		}
+		if !strings.HasPrefix(instanceID, serviceType+"-") {
+			return nil, fmt.Errorf("service %d: instance_id %q must start with %q", i+1, instanceID, serviceType+"-")
+		}
+		if seen[instanceID] {
+			return nil, fmt.Errorf("service %d: duplicate instance_id %q", i+1, instanceID)
+		}
+		seen[instanceID] = true
+
+		name := service.Name
+		if name == "" {
+			name = instanceID
+		}
+
+		configuration := &models.ServiceConfiguration{
+			InstanceID:  instanceID,
+			DisplayName: name,
+			URL:         strings.TrimRight(service.URL, "/"),
+			APIKey:      service.APIKey,
+			AccessURL:   service.AccessURL,
+		}
+		if err := configuration.Validate(); err != nil {
+			return nil, fmt.Errorf("service %d: %w", i+1, err)
+		}
+
+		configurations = append(configurations, configuration)
+	}
+
+	return configurations, nil
 }
 
 // OIDCConfig holds OIDC-specific configuration
@@ -67,6 +224,16 @@ type OIDCConfig struct {
 	RedirectURL  string `toml:"redirect_url" env:"OIDC_REDIRECT_URL"`
 }
 
+// OIDCProviderConfig holds a named OIDC provider that can be selected at login.
+// Providers are configured as a [[auth.providers]] list in the config file.
+type OIDCProviderConfig struct {
+	Name         string `toml:"name"`
+	Issuer       string `toml:"issuer"`
+	ClientID     string `toml:"client_id"`
+	ClientSecret string `toml:"client_secret"`
+	RedirectURL  string `toml:"redirect_url"`
+}
+
 // DefaultConfig returns a configuration with default values
 func DefaultConfig() *Config {
 	return &Config{
@@ -80,6 +247,19 @@ func DefaultConfig() *Config {
 	}
 }
 
+// DataDirectory returns the directory for on-disk state such as sessions.json. Without an
+// explicit data directory the directory of the SQLite database is used, as it was before
+// the data directory could be configured.
+func (c *Config) DataDirectory() string {
+	if c.Server.DataDir != "" {
+		return c.Server.DataDir
+	}
+	if c.Database.Path != "" {
+		return filepath.Dir(c.Database.Path)
+	}
+	return "./data"
+}
+
 // shortenPath replaces the user's home directory with ~ for display purposes
 func shortenPath(path string) string {
 	home, err := os.UserHomeDir()
@@ -89,6 +269,16 @@ func shortenPath(path string) string {
 	return strings.Replace(filepath.Clean(path), home, "~", 1)
 }
 
+// NormalizeBasePath returns the base path with a leading slash and no trailing slash.
+// The root path is returned as an empty string.
+func NormalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
 // HasRequiredEnvVars checks if all required environment variables are set
 func HasRequiredEnvVars() bool {
 	// Check server config
@@ -136,6 +326,9 @@ func LoadConfig(path string) (*Config, error) {
 		if err := LoadEnvOverrides(config); err != nil {
 			return nil, fmt.Errorf("error loading environment variables: %w", err)
 		}
+		if err := config.validateAuthMode(); err != nil {
+			return nil, err
+		}
 		return config, nil
 	}
 
@@ -179,6 +372,9 @@ func LoadConfig(path string) (*Config, error) {
 		if err := toml.Unmarshal(data, config); err != nil {
 			return nil, fmt.Errorf("error decoding config file %s: %w", displayPath, err)
 		}
+		if err := interpolateEnv(config); err != nil {
+			return nil, fmt.Errorf("error resolving environment variables in config file %s: %w", displayPath, err)
+		}
 		log.Debug().Str("path", displayPath).Msg("Loaded existing configuration file")
 	}
 
@@ -187,20 +383,78 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("error loading environment variables: %w", err)
 	}
 
+	if err := config.validateAuthMode(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// validateAuthMode normalizes auth.mode and refuses unknown modes, so a typo doesn't
+// silently start dashbrr with a different kind of authentication than intended
+func (c *Config) validateAuthMode() error {
+	c.Auth.Mode = strings.ToLower(strings.TrimSpace(c.Auth.Mode))
+	switch c.Auth.Mode {
+	case "", AuthModeOIDC, AuthModeLocal, AuthModeNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid auth.mode %q, must be %q, %q or %q", c.Auth.Mode, AuthModeOIDC, AuthModeLocal, AuthModeNone)
+	}
+}
+
 // LoadEnvOverrides loads configuration from environment variables
 func LoadEnvOverrides(config *Config) error {
 	// Server
 	if env := os.Getenv("DASHBRR__LISTEN_ADDR"); env != "" {
 		config.Server.ListenAddr = env
 	}
+	if env := os.Getenv("DASHBRR__BASE_PATH"); env != "" {
+		config.Server.BasePath = env
+	}
+	config.Server.BasePath = NormalizeBasePath(config.Server.BasePath)
+	if env := os.Getenv("DASHBRR__DATA_DIR"); env != "" {
+		config.Server.DataDir = env
+	}
+	if env := os.Getenv("DASHBRR__TRUSTED_PROXIES"); env != "" {
+		config.Server.TrustedProxies = nil
+		for _, proxy := range strings.Split(env, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				config.Server.TrustedProxies = append(config.Server.TrustedProxies, proxy)
+			}
+		}
+	}
+	for env, timeout := range map[string]*int{
+		"DASHBRR__SERVER_READ_TIMEOUT":        &config.Server.ReadTimeout,
+		"DASHBRR__SERVER_READ_HEADER_TIMEOUT": &config.Server.ReadHeaderTimeout,
+		"DASHBRR__SERVER_WRITE_TIMEOUT":       &config.Server.WriteTimeout,
+		"DASHBRR__SERVER_IDLE_TIMEOUT":        &config.Server.IdleTimeout,
+	} {
+		if value := os.Getenv(env); value != "" {
+			if seconds, err := strconv.Atoi(value); err == nil {
+				*timeout = seconds
+			}
+		}
+	}
 
 	// Cache
 	if env := os.Getenv("CACHE_TYPE"); env != "" {
 		config.Cache.Type = env
 	}
+	if env := os.Getenv("DASHBRR__CACHE_PERSIST"); env != "" {
+		if persist, err := strconv.ParseBool(env); err == nil {
+			config.Cache.Persist = persist
+		}
+	}
+	if env := os.Getenv("DASHBRR__CACHE_COMPRESS"); env != "" {
+		if compress, err := strconv.ParseBool(env); err == nil {
+			config.Cache.Compress = compress
+		}
+	}
+	if env := os.Getenv("DASHBRR__CACHE_COMPRESS_MIN_SIZE"); env != "" {
+		if size, err := strconv.Atoi(env); err == nil {
+			config.Cache.CompressMinSize = size
+		}
+	}
 	if env := os.Getenv("REDIS_HOST"); env != "" {
 		config.Cache.Redis.Host = env
 	}
@@ -235,6 +489,86 @@ func LoadEnvOverrides(config *Config) error {
 		config.Database.Name = env
 	}
 
+	// Auth
+	if env := os.Getenv("DASHBRR__AUTH_MODE"); env != "" {
+		config.Auth.Mode = env
+	}
+	if env := os.Getenv("DASHBRR__AUTH_COOKIE_DOMAIN"); env != "" {
+		config.Auth.CookieDomain = env
+	}
+	if env := os.Getenv("DASHBRR__AUTH_COOKIE_SAMESITE"); env != "" {
+		config.Auth.CookieSameSite = env
+	}
+	if env := os.Getenv("DASHBRR__AUTH_LOGIN_RATE_LIMIT"); env != "" {
+		if limit, err := strconv.Atoi(env); err == nil {
+			config.Auth.LoginRateLimit = limit
+		}
+	}
+
+	// Health
+	if env := os.Getenv("DASHBRR__HEALTH_SLOW_RESPONSE_THRESHOLD"); env != "" {
+		if threshold, err := strconv.Atoi(env); err == nil {
+			config.Health.SlowResponseThreshold = threshold
+		}
+	}
+	if env := os.Getenv("DASHBRR__HEALTH_PLEX_TRANSCODE_LIMIT"); env != "" {
+		if limit, err := strconv.Atoi(env); err == nil {
+			config.Health.PlexTranscodeLimit = limit
+		}
+	}
+	if env := os.Getenv("DASHBRR__HEALTH_FAILING_INDEXER_ALERT"); env != "" {
+		if threshold, err := strconv.Atoi(env); err == nil {
+			config.Health.FailingIndexerAlert = threshold
+		}
+	}
+	if env := os.Getenv("DASHBRR__HEALTH_TAILSCALE_KEY_EXPIRY_DAYS"); env != "" {
+		if days, err := strconv.Atoi(env); err == nil {
+			config.Health.TailscaleKeyExpiryDays = days
+		}
+	}
+	if env := os.Getenv("DASHBRR__HEALTH_INITIAL_CONCURRENCY"); env != "" {
+		if concurrency, err := strconv.Atoi(env); err == nil {
+			config.Health.InitialConcurrency = concurrency
+		}
+	}
+	if env := os.Getenv("DASHBRR__HEALTH_POLL_WAIT"); env != "" {
+		if wait, err := strconv.Atoi(env); err == nil {
+			config.Health.PollWait = wait
+		}
+	}
+	if env := os.Getenv("DASHBRR__HEALTH_BROADCAST_TIMEOUT"); env != "" {
+		if timeout, err := strconv.Atoi(env); err == nil {
+			config.Health.BroadcastTimeout = timeout
+		}
+	}
+
+	// Log
+	if env := os.Getenv("DASHBRR__LOG_CHANGE_SAMPLE_RATE"); env != "" {
+		if rate, err := strconv.Atoi(env); err == nil {
+			config.Log.ChangeSampleRate = rate
+		}
+	}
+
+	// Overseerr
+	if env := os.Getenv("DASHBRR__OVERSEERR_TITLE_LOOKUP_CONCURRENCY"); env != "" {
+		if concurrency, err := strconv.Atoi(env); err == nil {
+			config.Overseerr.TitleLookupConcurrency = concurrency
+		}
+	}
+
+	// HTTP client pool
+	for env, value := range map[string]*int{
+		"DASHBRR__HTTP_MAX_IDLE_CONNS":          &config.HTTP.MaxIdleConns,
+		"DASHBRR__HTTP_MAX_IDLE_CONNS_PER_HOST": &config.HTTP.MaxIdleConnsPerHost,
+		"DASHBRR__HTTP_IDLE_CONN_TIMEOUT":       &config.HTTP.IdleConnTimeout,
+	} {
+		if raw := os.Getenv(env); raw != "" {
+			if parsed, err := strconv.Atoi(raw); err == nil {
+				*value = parsed
+			}
+		}
+	}
+
 	// Auth OIDC
 	if env := os.Getenv("OIDC_ISSUER"); env != "" {
 		config.Auth.OIDC.Issuer = env
@@ -0,0 +1,56 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigAuthMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{mode: "", want: ""},
+		{mode: "oidc", want: AuthModeOIDC},
+		{mode: "Local", want: AuthModeLocal},
+		{mode: " none ", want: AuthModeNone},
+		{mode: "off", wantErr: true},
+		{mode: "nnone", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		data := "[auth]\nmode = \"" + tt.mode + "\"\n"
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+
+		cfg, err := LoadConfig(path)
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "auth.mode") {
+				t.Errorf("LoadConfig with mode %q: expected an auth.mode error, got %v", tt.mode, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LoadConfig with mode %q: unexpected error: %v", tt.mode, err)
+			continue
+		}
+		if cfg.Auth.Mode != tt.want {
+			t.Errorf("LoadConfig with mode %q: got mode %q, want %q", tt.mode, cfg.Auth.Mode, tt.want)
+		}
+	}
+
+	// The env var is checked the same way
+	t.Setenv("DASHBRR__AUTH_MODE", "disabled")
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "auth.mode") {
+		t.Errorf("Expected an auth.mode error for DASHBRR__AUTH_MODE, got %v", err)
+	}
+}
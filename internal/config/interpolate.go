@@ -0,0 +1,79 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// envReference matches ${VAR} and ${VAR:-default}
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolate replaces ${VAR} references in value with the environment variable. References
+// with a default, ${VAR:-default}, fall back to it when the variable is unset or empty, any
+// other unset variable is an error.
+func interpolate(value string) (string, error) {
+	var missing []string
+
+	result := envReference.ReplaceAllStringFunc(value, func(reference string) string {
+		match := envReference.FindStringSubmatch(reference)
+		if env := os.Getenv(match[1]); env != "" {
+			return env
+		}
+		if match[2] != "" {
+			return match[3]
+		}
+		missing = append(missing, match[1])
+		return ""
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", missing[0])
+	}
+	return result, nil
+}
+
+// interpolateEnv resolves ${VAR} references in the URLs and secrets of the config file,
+// so API keys and client secrets don't have to be stored in it
+func interpolateEnv(config *Config) error {
+	var errs []error
+
+	field := func(name string, value *string) {
+		resolved, err := interpolate(*value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+		*value = resolved
+	}
+
+	field("database.password", &config.Database.Password)
+
+	field("auth.oidc.issuer", &config.Auth.OIDC.Issuer)
+	field("auth.oidc.client_id", &config.Auth.OIDC.ClientID)
+	field("auth.oidc.client_secret", &config.Auth.OIDC.ClientSecret)
+	field("auth.oidc.redirect_url", &config.Auth.OIDC.RedirectURL)
+
+	for i := range config.Auth.Providers {
+		provider := &config.Auth.Providers[i]
+		prefix := fmt.Sprintf("auth.providers[%d].", i)
+		field(prefix+"issuer", &provider.Issuer)
+		field(prefix+"client_id", &provider.ClientID)
+		field(prefix+"client_secret", &provider.ClientSecret)
+		field(prefix+"redirect_url", &provider.RedirectURL)
+	}
+
+	for i := range config.Services {
+		service := &config.Services[i]
+		prefix := fmt.Sprintf("services[%d].", i)
+		field(prefix+"url", &service.URL)
+		field(prefix+"access_url", &service.AccessURL)
+		field(prefix+"api_key", &service.APIKey)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("SONARR_API_KEY", "secret")
+	t.Setenv("EMPTY_VAR", "")
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "plain", want: "plain"},
+		{value: "${SONARR_API_KEY}", want: "secret"},
+		{value: "http://${HOST:-sonarr}:8989", want: "http://sonarr:8989"},
+		{value: "${EMPTY_VAR:-fallback}", want: "fallback"},
+		{value: "${MISSING_VAR}", wantErr: true},
+		{value: "$NOT_A_REFERENCE", want: "$NOT_A_REFERENCE"},
+	}
+
+	for _, tt := range tests {
+		got, err := interpolate(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("interpolate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigInterpolation(t *testing.T) {
+	t.Setenv("SONARR_API_KEY", "secret")
+	t.Setenv("OIDC_SECRET", "oidc-secret")
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	data := `
+[auth.oidc]
+client_secret = "${OIDC_SECRET}"
+
+[[services]]
+type = "sonarr"
+url = "http://sonarr:8989"
+api_key = "${SONARR_API_KEY}"
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Services[0].APIKey != "secret" || cfg.Auth.OIDC.ClientSecret != "oidc-secret" {
+		t.Errorf("Expected resolved secrets, got %q and %q", cfg.Services[0].APIKey, cfg.Auth.OIDC.ClientSecret)
+	}
+
+	data = strings.Replace(data, "${SONARR_API_KEY}", "${RADARR_API_KEY}", 1)
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "RADARR_API_KEY") {
+		t.Errorf("Expected an error naming the missing variable, got %v", err)
+	}
+}
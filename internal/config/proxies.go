@@ -0,0 +1,47 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"net"
+	"strings"
+)
+
+// DefaultTrustedProxies are trusted when no proxies are configured
+var DefaultTrustedProxies = []string{"127.0.0.1", "::1"}
+
+// ParseTrustedProxies parses a list of IP addresses and CIDR ranges such as 10.0.0.0/8
+// or fd00::/8. A single address becomes a range holding only that address. Entries that
+// are neither are returned as invalid, so they can be reported.
+func ParseTrustedProxies(entries []string) (proxies []*net.IPNet, invalid []string) {
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				invalid = append(invalid, entry)
+				continue
+			}
+			proxies = append(proxies, network)
+			continue
+		}
+
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+
+	return proxies, invalid
+}
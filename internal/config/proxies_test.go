@@ -0,0 +1,38 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, invalid := ParseTrustedProxies([]string{"127.0.0.1", " ::1 ", "10.0.0.0/8", "fd00::/8", "172.18.0.5/16", "", "proxy.local", "10.0.0.0/33"})
+
+	var got []string
+	for _, proxy := range proxies {
+		got = append(got, proxy.String())
+	}
+
+	want := []string{"127.0.0.1/32", "::1/128", "10.0.0.0/8", "fd00::/8", "172.18.0.0/16"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected proxies %v, got %v", want, got)
+	}
+	if !slices.Equal(invalid, []string{"proxy.local", "10.0.0.0/33"}) {
+		t.Errorf("Expected the hostname and the bad range to be invalid, got %v", invalid)
+	}
+}
+
+func TestTrustedProxiesEnv(t *testing.T) {
+	t.Setenv("DASHBRR__TRUSTED_PROXIES", "10.0.0.0/8, fd00::/8")
+
+	config := &Config{}
+	if err := LoadEnvOverrides(config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(config.Server.TrustedProxies, []string{"10.0.0.0/8", "fd00::/8"}) {
+		t.Errorf("Unexpected trusted proxies %v", config.Server.TrustedProxies)
+	}
+}
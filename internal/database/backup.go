@@ -0,0 +1,145 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// backupTables lists every table created by initSchema, in restore order
+var backupTables = []string{"service_configurations", "users", "settings"}
+
+var (
+	// ErrBackupUnsupported is returned by Backup and Restore for drivers other than SQLite,
+	// PostgreSQL databases are backed up with pg_dump instead
+	ErrBackupUnsupported = errors.New("backup is only supported for SQLite")
+
+	// ErrDatabaseNotEmpty is returned by Restore when the target database already holds data
+	ErrDatabaseNotEmpty = errors.New("database is not empty")
+)
+
+// Backup writes a consistent copy of the SQLite database to dest while it stays in use.
+// dest must not exist yet.
+func (db *DB) Backup(ctx context.Context, dest string) error {
+	if db.driver != "sqlite" {
+		return ErrBackupUnsupported
+	}
+
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup file %s already exists", dest)
+	}
+
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, dest); err != nil {
+		return errors.Wrap(err, "vacuum into")
+	}
+
+	return nil
+}
+
+// IsEmpty reports whether none of the dashbrr tables hold any rows
+func (db *DB) IsEmpty(ctx context.Context) (bool, error) {
+	for _, table := range backupTables {
+		query, args, err := db.squirrel.Select("COUNT(*)").From(table).ToSql()
+		if err != nil {
+			return false, err
+		}
+
+		var count int
+		if err := db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+			return false, errors.Wrapf(err, "count %s", table)
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Restore copies every row of a SQLite backup made with Backup into the database, which
+// must be empty. Backups from older versions are accepted, columns they lack keep their
+// defaults.
+func (db *DB) Restore(ctx context.Context, src string) error {
+	if db.driver != "sqlite" {
+		return ErrBackupUnsupported
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		return errors.Wrap(err, "backup file")
+	}
+
+	empty, err := db.IsEmpty(ctx)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return ErrDatabaseNotEmpty
+	}
+
+	// ATTACH is scoped to a connection, keep the whole restore on one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS backup`, src); err != nil {
+		return errors.Wrap(err, "attach backup")
+	}
+	defer conn.ExecContext(context.Background(), `DETACH DATABASE backup`)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range backupTables {
+		columns, err := sharedColumns(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		list := strings.Join(columns, ", ")
+		query := fmt.Sprintf(`INSERT INTO main.%s (%s) SELECT %s FROM backup.%s`, table, list, list, table)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return errors.Wrapf(err, "restore %s", table)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// sharedColumns returns the columns of table present in both the database and the attached backup
+func sharedColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT m.name
+		FROM pragma_table_info(?, 'main') m
+		JOIN pragma_table_info(?, 'backup') b ON b.name = m.name
+		ORDER BY m.cid`, table, table)
+	if err != nil {
+		return nil, errors.Wrapf(err, "columns of %s", table)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
@@ -5,14 +5,19 @@ package database
 
 import (
 	"context"
+	"crypto/cipher"
 	"database/sql"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	_ "modernc.org/sqlite"
@@ -28,8 +33,19 @@ type DB struct {
 	path   string
 
 	squirrel sq.StatementBuilderType
+
+	// secrets encrypts the API keys of services, see secret.key
+	secrets cipher.AEAD
+
+	maintenanceRunning atomic.Bool
 }
 
+// ErrMaintenanceRunning is returned when a maintenance run is already in progress
+var ErrMaintenanceRunning = errors.New("database maintenance already running")
+
+// ErrSetupComplete is returned by CompleteSetup once a user exists
+var ErrSetupComplete = errors.New("setup already completed")
+
 // Config holds database configuration
 type Config struct {
 	Driver   string
@@ -39,6 +55,13 @@ type Config struct {
 	Password string
 	DBName   string
 	Path     string // For SQLite
+
+	// DataDir keeps secret.key for PostgreSQL, see secretKeyDir
+	DataDir string
+
+	// Connection pool limits, 0 keeps the driver default
+	MaxOpenConns int
+	MaxIdleConns int
 }
 
 // NewConfig creates a new database configuration from environment variables
@@ -49,7 +72,10 @@ func NewConfig() *Config {
 	}
 
 	config := &Config{
-		Driver: dbType,
+		Driver:       dbType,
+		DataDir:      os.Getenv("DASHBRR__DATA_DIR"),
+		MaxOpenConns: getEnvInt("DASHBRR__DB_MAX_OPEN_CONNS", 0),
+		MaxIdleConns: getEnvInt("DASHBRR__DB_MAX_IDLE_CONNS", 0),
 	}
 
 	if dbType == "postgres" {
@@ -121,6 +147,12 @@ func InitDBWithConfig(config *Config) (*DB, error) {
 		database.SetMaxOpenConns(25)
 		database.SetMaxIdleConns(25)
 		database.SetConnMaxLifetime(5 * time.Minute)
+		if config.MaxOpenConns > 0 {
+			database.SetMaxOpenConns(config.MaxOpenConns)
+		}
+		if config.MaxIdleConns > 0 {
+			database.SetMaxIdleConns(config.MaxIdleConns)
+		}
 	} else {
 		// SQLite connection
 		dbDir := filepath.Dir(config.Path)
@@ -135,6 +167,16 @@ func InitDBWithConfig(config *Config) (*DB, error) {
 			return nil, fmt.Errorf("error opening database: %w", err)
 		}
 
+		// SQLite allows a single writer at a time. The pool is left unlimited by default,
+		// setting DASHBRR__DB_MAX_OPEN_CONNS=1 serializes writes in the pool instead of
+		// failing with "database is locked" when many health checks write concurrently.
+		if config.MaxOpenConns > 0 {
+			database.SetMaxOpenConns(config.MaxOpenConns)
+		}
+		if config.MaxIdleConns > 0 {
+			database.SetMaxIdleConns(config.MaxIdleConns)
+		}
+
 		// Force SQLite to create the database file by pinging it
 		if err := database.Ping(); err != nil {
 			return nil, fmt.Errorf("error creating database file: %w", err)
@@ -190,12 +232,19 @@ func InitDBWithConfig(config *Config) (*DB, error) {
 		Str("driver", config.Driver).
 		Msg("Successfully connected to database")
 
+	keyDir := secretKeyDir(config)
+	secrets, created, err := loadSecretKey(keyDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading secret key: %w", err)
+	}
+
 	db := &DB{
 		DB:     database,
 		driver: config.Driver,
 		path:   config.Path,
 		// set default placeholder for squirrel to support both sqlite and postgres
 		squirrel: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		secrets:  secrets,
 	}
 
 	// Initialize schema
@@ -203,6 +252,19 @@ func InitDBWithConfig(config *Config) (*DB, error) {
 		return nil, fmt.Errorf("error initializing schema: %w", err)
 	}
 
+	// Refuse to start with a key the stored secrets weren't written with, e.g. when
+	// secret.key was lost, instead of every service losing its API key
+	if err := db.checkSecrets(context.Background()); err != nil {
+		if created {
+			// Don't leave the new key behind for the restored one to be mixed up with
+			if err := os.Remove(filepath.Join(keyDir, secretKeyFile)); err != nil {
+				log.Warn().Err(err).Msg("Failed to remove generated secret key")
+			}
+		}
+		database.Close()
+		return nil, err
+	}
+
 	return db, nil
 }
 
@@ -211,6 +273,73 @@ func (db *DB) Path() string {
 	return db.path
 }
 
+// Driver returns the database driver name
+func (db *DB) Driver() string {
+	return db.driver
+}
+
+// PoolStats is a JSON friendly snapshot of the connection pool statistics
+type PoolStats struct {
+	Driver             string `json:"driver"`
+	MaxOpenConnections int    `json:"maxOpenConnections"`
+	OpenConnections    int    `json:"openConnections"`
+	InUse              int    `json:"inUse"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"waitCount"`
+	WaitDuration       string `json:"waitDuration"`
+	MaxIdleClosed      int64  `json:"maxIdleClosed"`
+	MaxIdleTimeClosed  int64  `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed  int64  `json:"maxLifetimeClosed"`
+}
+
+// PoolStats returns the current connection pool statistics. A growing wait count
+// points at lock contention.
+func (db *DB) PoolStats() PoolStats {
+	stats := db.Stats()
+	return PoolStats{
+		Driver:             db.driver,
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// MaintenanceRunning reports whether a maintenance run is in progress
+func (db *DB) MaintenanceRunning() bool {
+	return db.maintenanceRunning.Load()
+}
+
+// Maintenance checkpoints the WAL and vacuums a SQLite database to reclaim space
+// left behind by long-running deployments. It is a no-op for PostgreSQL, which
+// handles this with autovacuum. Only one run may be in progress at a time.
+func (db *DB) Maintenance(ctx context.Context) error {
+	if !db.maintenanceRunning.CompareAndSwap(false, true) {
+		return ErrMaintenanceRunning
+	}
+	defer db.maintenanceRunning.Store(false)
+
+	if db.driver != "sqlite" {
+		log.Debug().Str("driver", db.driver).Msg("Skipping database maintenance")
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE);`); err != nil {
+		return errors.Wrap(err, "commit wal")
+	}
+
+	if _, err := db.ExecContext(ctx, `VACUUM;`); err != nil {
+		return errors.Wrap(err, "vacuum")
+	}
+
+	return nil
+}
+
 // initSchema creates the necessary database tables
 func (db *DB) initSchema() error {
 	var autoIncrement string
@@ -234,36 +363,31 @@ func (db *DB) initSchema() error {
 		return err
 	}
 
-	// Add access_url column if it doesn't exist
-	if db.driver == "postgres" {
-		_, err = db.Exec(`
-			DO $$ 
-			BEGIN 
-				BEGIN
-					ALTER TABLE service_configurations ADD COLUMN access_url TEXT;
-				EXCEPTION 
-					WHEN duplicate_column THEN 
-						NULL;
-				END;
-			END $$;
-		`)
-	} else {
-		// For SQLite, check if column exists first
-		var count int
-		err = db.QueryRow(`
-			SELECT COUNT(*) 
-			FROM pragma_table_info('service_configurations') 
-			WHERE name='access_url'
-		`).Scan(&count)
-		if err != nil {
+	// Add columns introduced after the initial schema
+	for _, column := range []struct{ name, definition string }{
+		{"access_url", "TEXT"},
+		{"muted_until", "TIMESTAMP"},
+		{"enabled", "BOOLEAN NOT NULL DEFAULT TRUE"},
+		{"tags", "TEXT"},
+		{"api_version", "TEXT"},
+		{"read_only", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"color", "TEXT"},
+		{"icon", "TEXT"},
+		{"check_interval_seconds", "INTEGER"},
+		{"method", "TEXT"},
+		{"expected_status", "TEXT"},
+		{"is_critical", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"auth_header_name", "TEXT"},
+		{"notes", "TEXT"},
+		{"client_cert", "TEXT"},
+		{"client_key", "TEXT"},
+		{"pinned", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"position", "INTEGER NOT NULL DEFAULT 0"},
+		{"expected_version", "TEXT"},
+	} {
+		if err := db.addColumnIfNotExists("service_configurations", column.name, column.definition); err != nil {
 			return err
 		}
-		if count == 0 {
-			_, err = db.Exec(`ALTER TABLE service_configurations ADD COLUMN access_url TEXT`)
-		}
-	}
-	if err != nil {
-		return err
 	}
 
 	// Create the users table
@@ -280,9 +404,53 @@ func (db *DB) initSchema() error {
 		return err
 	}
 
+	// Create the settings table for instance wide state
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// addColumnIfNotExists adds a column to an existing table, ignoring it if it is already present
+func (db *DB) addColumnIfNotExists(table, column, definition string) error {
+	if db.driver == "postgres" {
+		_, err := db.Exec(fmt.Sprintf(`
+			DO $$ 
+			BEGIN 
+				BEGIN
+					ALTER TABLE %s ADD COLUMN %s %s;
+				EXCEPTION 
+					WHEN duplicate_column THEN 
+						NULL;
+				END;
+			END $$;
+		`, table, column, definition))
+		return err
+	}
+
+	// For SQLite, check if column exists first
+	var count int
+	err := db.QueryRow(fmt.Sprintf(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('%s') 
+		WHERE name='%s'
+	`, table, column)).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+	}
+	return err
+}
+
 // getEnv retrieves an environment variable with a fallback value
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -291,6 +459,14 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvInt retrieves an integer environment variable with a fallback value
+func getEnvInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
 // HasUsers checks if any users exist in the database
 func (db *DB) HasUsers(ctx context.Context) (bool, error) {
 	qb := db.squirrel.Select("COUNT(*)").From("users")
@@ -329,6 +505,85 @@ func (db *DB) CreateUser(ctx context.Context, user *types.User) error {
 	return nil
 }
 
+// CompleteSetup creates the first user and optionally an initial service in a single
+// transaction. It fails with ErrSetupComplete when a user already exists. The transaction
+// is serializable, so of two concurrent setups that both find no user on Postgres one
+// fails to serialize and gets ErrSetupComplete as well. SQLite serializes writers anyway.
+func (db *DB) CompleteSetup(ctx context.Context, user *types.User, service *models.ServiceConfiguration) error {
+	err := db.completeSetup(ctx, user, service)
+	if isSerializationFailure(err) {
+		return ErrSetupComplete
+	}
+	return err
+}
+
+func (db *DB) completeSetup(ctx context.Context, user *types.User, service *models.ServiceConfiguration) error {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return errors.Wrap(err, "error starting transaction")
+	}
+	defer tx.Rollback()
+
+	var count int
+	query, args, err := db.squirrel.Select("COUNT(*)").From("users").ToSql()
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return errors.Wrap(err, "error counting users")
+	}
+	if count > 0 {
+		return ErrSetupComplete
+	}
+
+	now := time.Now()
+	userQuery := db.squirrel.Insert("users").
+		Columns("username", "email", "password_hash", "created_at", "updated_at").
+		Values(user.Username, user.Email, user.PasswordHash, now, now).
+		Suffix("RETURNING id").RunWith(tx)
+	if err := userQuery.QueryRowContext(ctx).Scan(&user.ID); err != nil {
+		return errors.Wrap(err, "error creating user")
+	}
+
+	if service != nil {
+		apiKey, err := db.sealSecret(service.APIKey)
+		if err != nil {
+			return err
+		}
+		clientKey, err := db.sealSecret(service.ClientKey)
+		if err != nil {
+			return err
+		}
+		serviceQuery := db.squirrel.Insert("service_configurations").
+			Columns("instance_id", "display_name", "url", "api_key", "access_url", "tags", "api_version", "color", "icon", "check_interval_seconds",
+				"method", "expected_status", "is_critical", "auth_header_name", "notes", "client_cert", "client_key", "expected_version").
+			Values(service.InstanceID, service.DisplayName, service.URL, apiKey, service.AccessURL, encodeTags(service.Tags),
+				sql.NullString{String: service.APIVersion, Valid: service.APIVersion != ""}, nullString(service.Color), nullString(service.Icon),
+				nullInt(service.CheckIntervalSeconds), nullString(service.Method), nullString(service.ExpectedStatus), service.Critical, nullString(service.AuthHeaderName), nullString(service.Notes),
+				nullString(service.ClientCert), clientKey, nullString(service.ExpectedVersion)).
+			Suffix("RETURNING id").RunWith(tx)
+		if err := serviceQuery.QueryRowContext(ctx).Scan(&service.ID); err != nil {
+			return errors.Wrapf(err, "error creating service %s", service.InstanceID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error committing transaction")
+	}
+
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	return nil
+}
+
+// isSerializationFailure reports whether Postgres aborted a serializable transaction
+// because of a concurrent one, SQLSTATE 40001
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "40001"
+}
+
 // FindUser retrieves a user by FindUserParams
 func (db *DB) FindUser(ctx context.Context, params types.FindUserParams) (*types.User, error) {
 	queryBuilder := db.squirrel.Select("id", "username", "email", "password_hash", "created_at", "updated_at").From("users")
@@ -389,9 +644,124 @@ func (db *DB) UpdateUserPassword(ctx context.Context, userID int64, newPasswordH
 
 // Service Management Functions
 
+// serviceColumns lists the service_configurations columns read by scanService, in scan order
+var serviceColumns = []string{"id", "instance_id", "display_name", "url", "api_key", "access_url", "muted_until", "enabled", "tags", "api_version", "read_only", "color", "icon", "check_interval_seconds", "method", "expected_status", "is_critical", "auth_header_name", "notes", "pinned", "position", "client_cert", "client_key", "expected_version"}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanService scans a row selected with serviceColumns into a service configuration
+func (db *DB) scanService(row rowScanner) (*models.ServiceConfiguration, error) {
+	var service models.ServiceConfiguration
+	var url, apiKey, accessURL, tags, apiVersion, color, icon, method, expectedStatus, authHeaderName, notes, clientCert, clientKey, expectedVersion sql.NullString
+	var mutedUntil sql.NullTime
+	var checkInterval sql.NullInt64
+	var enabled bool
+
+	err := row.Scan(
+		&service.ID,
+		&service.InstanceID,
+		&service.DisplayName,
+		&url,
+		&apiKey,
+		&accessURL,
+		&mutedUntil,
+		&enabled,
+		&tags,
+		&apiVersion,
+		&service.ReadOnly,
+		&color,
+		&icon,
+		&checkInterval,
+		&method,
+		&expectedStatus,
+		&service.Critical,
+		&authHeaderName,
+		&notes,
+		&service.Pinned,
+		&service.Position,
+		&clientCert,
+		&clientKey,
+		&expectedVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only set optional fields if they're not NULL
+	if url.Valid {
+		service.URL = url.String
+	}
+	if apiKey.Valid {
+		// A key that can't be decrypted reads as missing, so the service asks for it again
+		// instead of the whole list failing
+		if service.APIKey, err = db.openSecret(apiKey.String); err != nil {
+			log.Warn().Err(err).Str("service", service.InstanceID).Msg("Failed to decrypt API key, was secret.key replaced?")
+		}
+	}
+	if accessURL.Valid {
+		service.AccessURL = accessURL.String
+	}
+	if mutedUntil.Valid {
+		service.MutedUntil = &mutedUntil.Time
+	}
+	service.Disabled = !enabled
+	service.Tags = decodeTags(tags.String)
+	service.APIVersion = apiVersion.String
+	service.Color = color.String
+	service.Icon = icon.String
+	service.CheckIntervalSeconds = int(checkInterval.Int64)
+	service.Method = method.String
+	service.ExpectedStatus = expectedStatus.String
+	service.AuthHeaderName = authHeaderName.String
+	service.Notes = notes.String
+	service.ClientCert = clientCert.String
+	if clientKey.Valid {
+		if service.ClientKey, err = db.openSecret(clientKey.String); err != nil {
+			log.Warn().Err(err).Str("service", service.InstanceID).Msg("Failed to decrypt client key, was secret.key replaced?")
+		}
+	}
+	service.ExpectedVersion = expectedVersion.String
+
+	return &service, nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullInt stores zero as NULL
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+// encodeTags stores tags as a comma separated list with surrounding commas,
+// so a single tag can be matched with LIKE '%,tag,%'
+func encodeTags(tags []string) sql.NullString {
+	tags = models.NormalizeTags(tags)
+	if len(tags) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: "," + strings.Join(tags, ",") + ",", Valid: true}
+}
+
+func decodeTags(value string) []string {
+	value = strings.Trim(value, ",")
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// likeEscaper escapes LIKE wildcards so user input only matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // FindServiceBy retrieves a service configuration by FindServiceParams
 func (db *DB) FindServiceBy(ctx context.Context, params types.FindServiceParams) (*models.ServiceConfiguration, error) {
-	queryBuilder := db.squirrel.Select("id", "instance_id", "display_name", "url", "api_key", "access_url").
+	queryBuilder := db.squirrel.Select(serviceColumns...).
 		From("service_configurations")
 
 	if params.InstanceID != "" {
@@ -415,18 +785,7 @@ func (db *DB) FindServiceBy(ctx context.Context, params types.FindServiceParams)
 		return nil, err
 	}
 
-	var service models.ServiceConfiguration
-	var url, apiKey, accessURL sql.NullString
-
-	err = db.QueryRowContext(ctx, query, args...).Scan(
-		&service.ID,
-		&service.InstanceID,
-		&service.DisplayName,
-		&url,
-		&apiKey,
-		&accessURL,
-	)
-
+	service, err := db.scanService(db.QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -434,49 +793,27 @@ func (db *DB) FindServiceBy(ctx context.Context, params types.FindServiceParams)
 		return nil, err
 	}
 
-	// Only set optional fields if they're not NULL
-	if url.Valid {
-		service.URL = url.String
-	}
-	if apiKey.Valid {
-		service.APIKey = apiKey.String
-	}
-	if accessURL.Valid {
-		service.AccessURL = accessURL.String
-	}
-
-	return &service, nil
+	return service, nil
 }
 
 // GetServiceByInstancePrefix retrieves a service configuration by its instance ID prefix
 func (db *DB) GetServiceByInstancePrefix(ctx context.Context, prefix string) (*models.ServiceConfiguration, error) {
-	var service models.ServiceConfiguration
-	var url, apiKey, accessURL sql.NullString
-
 	var query string
 	if db.driver == "postgres" {
 		query = `
-			SELECT id, instance_id, display_name, url, api_key, access_url
+			SELECT ` + strings.Join(serviceColumns, ", ") + `
 			FROM service_configurations 
 			WHERE instance_id LIKE $1 || '%'
 			LIMIT 1`
 	} else {
 		query = `
-			SELECT id, instance_id, display_name, url, api_key, access_url
+			SELECT ` + strings.Join(serviceColumns, ", ") + `
 			FROM service_configurations 
 			WHERE instance_id LIKE ? || '%'
 			LIMIT 1`
 	}
 
-	err := db.QueryRowContext(ctx, query, prefix).Scan(
-		&service.ID,
-		&service.InstanceID,
-		&service.DisplayName,
-		&url,
-		&apiKey,
-		&accessURL,
-	)
-
+	service, err := db.scanService(db.QueryRowContext(ctx, query, prefix))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -484,24 +821,20 @@ func (db *DB) GetServiceByInstancePrefix(ctx context.Context, prefix string) (*m
 		return nil, err
 	}
 
-	// Only set optional fields if they're not NULL
-	if url.Valid {
-		service.URL = url.String
-	}
-	if apiKey.Valid {
-		service.APIKey = apiKey.String
-	}
-	if accessURL.Valid {
-		service.AccessURL = accessURL.String
-	}
-
-	return &service, nil
+	return service, nil
 }
 
-// GetAllServices retrieves all service configurations
-func (db *DB) GetAllServices(ctx context.Context) ([]models.ServiceConfiguration, error) {
-	queryBuilder := db.squirrel.Select("id", "instance_id", "display_name", "url", "api_key", "access_url").
-		From("service_configurations")
+// GetAllServices retrieves all service configurations, pinned services first and then in
+// the order set with ReorderServices. Disabled services are only included when
+// includeDisabled is set.
+func (db *DB) GetAllServices(ctx context.Context, includeDisabled bool) ([]models.ServiceConfiguration, error) {
+	queryBuilder := db.squirrel.Select(serviceColumns...).
+		From("service_configurations").
+		OrderBy("pinned DESC", "position ASC", "instance_id")
+
+	if !includeDisabled {
+		queryBuilder = queryBuilder.Where(sq.Eq{"enabled": true})
+	}
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -516,42 +849,100 @@ func (db *DB) GetAllServices(ctx context.Context) ([]models.ServiceConfiguration
 
 	var services []models.ServiceConfiguration
 	for rows.Next() {
-		var service models.ServiceConfiguration
-		var url, apiKey, accessURL sql.NullString
-
-		err := rows.Scan(
-			&service.ID,
-			&service.InstanceID,
-			&service.DisplayName,
-			&url,
-			&apiKey,
-			&accessURL,
-		)
+		service, err := db.scanService(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		if url.Valid {
-			service.URL = url.String
-		}
-		if apiKey.Valid {
-			service.APIKey = apiKey.String
+		services = append(services, *service)
+	}
+
+	return services, nil
+}
+
+// ListServices returns a page of service configurations matching params, ordered by
+// instance id, along with the total number of matches. Disabled services are included.
+func (db *DB) ListServices(ctx context.Context, params types.ListServicesParams) ([]models.ServiceConfiguration, int, error) {
+	var filters sq.And
+	if params.Type != "" {
+		filters = append(filters, sq.Expr(`instance_id LIKE ? ESCAPE '\'`, likeEscaper.Replace(strings.ToLower(params.Type))+"-%"))
+	}
+	if tags := models.NormalizeTags([]string{params.Tag}); len(tags) == 1 {
+		filters = append(filters, sq.Expr(`tags LIKE ? ESCAPE '\'`, "%,"+likeEscaper.Replace(tags[0])+",%"))
+	}
+
+	countBuilder := db.squirrel.Select("COUNT(*)").From("service_configurations")
+	queryBuilder := db.squirrel.Select(serviceColumns...).
+		From("service_configurations").
+		OrderBy("instance_id")
+
+	if len(filters) > 0 {
+		countBuilder = countBuilder.Where(filters)
+		queryBuilder = queryBuilder.Where(filters)
+	}
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(params.Limit))
+	}
+	if params.Offset > 0 {
+		if params.Limit <= 0 {
+			// SQLite only accepts OFFSET after a LIMIT
+			queryBuilder = queryBuilder.Limit(math.MaxInt64)
 		}
-		if accessURL.Valid {
-			service.AccessURL = accessURL.String
+		queryBuilder = queryBuilder.Offset(uint64(params.Offset))
+	}
+
+	countQuery, countArgs, err := countBuilder.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	services := []models.ServiceConfiguration{}
+	for rows.Next() {
+		service, err := db.scanService(rows)
+		if err != nil {
+			return nil, 0, err
 		}
 
-		services = append(services, service)
+		services = append(services, *service)
 	}
 
-	return services, nil
+	return services, total, rows.Err()
 }
 
 // CreateService creates a new service configuration
 func (db *DB) CreateService(ctx context.Context, service *models.ServiceConfiguration) error {
+	apiKey, err := db.sealSecret(service.APIKey)
+	if err != nil {
+		return err
+	}
+	clientKey, err := db.sealSecret(service.ClientKey)
+	if err != nil {
+		return err
+	}
+
 	queryBuilder := db.squirrel.Insert("service_configurations").
-		Columns("instance_id", "display_name", "url", "api_key", "access_url").
-		Values(service.InstanceID, service.DisplayName, service.URL, service.APIKey, service.AccessURL).
+		Columns("instance_id", "display_name", "url", "api_key", "access_url", "tags", "api_version", "color", "icon", "check_interval_seconds",
+			"method", "expected_status", "is_critical", "auth_header_name", "notes", "client_cert", "client_key", "expected_version").
+		Values(service.InstanceID, service.DisplayName, service.URL, apiKey, service.AccessURL, encodeTags(service.Tags),
+			sql.NullString{String: service.APIVersion, Valid: service.APIVersion != ""}, nullString(service.Color), nullString(service.Icon),
+			nullInt(service.CheckIntervalSeconds), nullString(service.Method), nullString(service.ExpectedStatus), service.Critical, nullString(service.AuthHeaderName), nullString(service.Notes),
+			nullString(service.ClientCert), clientKey, nullString(service.ExpectedVersion)).
 		Suffix("RETURNING id").RunWith(db.DB)
 
 	if err := queryBuilder.QueryRowContext(ctx).Scan(&service.ID); err != nil {
@@ -561,15 +952,174 @@ func (db *DB) CreateService(ctx context.Context, service *models.ServiceConfigur
 	return nil
 }
 
+// CreateServices creates all services in a single transaction. If any insert fails
+// the transaction is rolled back and none of the services are created.
+func (db *DB) CreateServices(ctx context.Context, services []*models.ServiceConfiguration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error starting transaction")
+	}
+	defer tx.Rollback()
+
+	for _, service := range services {
+		apiKey, err := db.sealSecret(service.APIKey)
+		if err != nil {
+			return err
+		}
+		clientKey, err := db.sealSecret(service.ClientKey)
+		if err != nil {
+			return err
+		}
+		queryBuilder := db.squirrel.Insert("service_configurations").
+			Columns("instance_id", "display_name", "url", "api_key", "access_url", "tags", "api_version", "color", "icon", "check_interval_seconds",
+				"method", "expected_status", "is_critical", "auth_header_name", "notes", "client_cert", "client_key", "expected_version").
+			Values(service.InstanceID, service.DisplayName, service.URL, apiKey, service.AccessURL, encodeTags(service.Tags),
+				sql.NullString{String: service.APIVersion, Valid: service.APIVersion != ""}, nullString(service.Color), nullString(service.Icon),
+				nullInt(service.CheckIntervalSeconds), nullString(service.Method), nullString(service.ExpectedStatus), service.Critical, nullString(service.AuthHeaderName), nullString(service.Notes),
+				nullString(service.ClientCert), clientKey, nullString(service.ExpectedVersion)).
+			Suffix("RETURNING id").RunWith(tx)
+
+		if err := queryBuilder.QueryRowContext(ctx).Scan(&service.ID); err != nil {
+			return errors.Wrapf(err, "error creating service %s", service.InstanceID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error committing transaction")
+	}
+
+	return nil
+}
+
+// SyncConfigServices upserts the services defined in the config file and marks them read-only.
+// Services that were read-only but are no longer in the config file are kept and become
+// editable again, so removing a service from the file never loses data.
+func (db *DB) SyncConfigServices(ctx context.Context, services []*models.ServiceConfiguration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error starting transaction")
+	}
+	defer tx.Rollback()
+
+	instanceIDs := make([]string, 0, len(services))
+	for _, service := range services {
+		instanceIDs = append(instanceIDs, service.InstanceID)
+	}
+
+	release := db.squirrel.Update("service_configurations").
+		Set("read_only", false).
+		Where(sq.Eq{"read_only": true})
+	if len(instanceIDs) > 0 {
+		release = release.Where(sq.NotEq{"instance_id": instanceIDs})
+	}
+	if _, err := release.RunWith(tx).ExecContext(ctx); err != nil {
+		return errors.Wrap(err, "error releasing removed config services")
+	}
+
+	for _, service := range services {
+		apiKey, err := db.sealSecret(service.APIKey)
+		if err != nil {
+			return err
+		}
+
+		res, err := db.squirrel.Update("service_configurations").
+			Set("display_name", service.DisplayName).
+			Set("url", sql.NullString{String: service.URL, Valid: service.URL != ""}).
+			Set("api_key", apiKey).
+			Set("access_url", sql.NullString{String: service.AccessURL, Valid: service.AccessURL != ""}).
+			Set("read_only", true).
+			Where(sq.Eq{"instance_id": service.InstanceID}).
+			RunWith(tx).ExecContext(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "error updating service %s", service.InstanceID)
+		}
+
+		if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected > 0 {
+			service.ReadOnly = true
+			continue
+		}
+
+		queryBuilder := db.squirrel.Insert("service_configurations").
+			Columns("instance_id", "display_name", "url", "api_key", "access_url", "read_only").
+			Values(service.InstanceID, service.DisplayName, service.URL, apiKey, service.AccessURL, true).
+			Suffix("RETURNING id").RunWith(tx)
+
+		if err := queryBuilder.QueryRowContext(ctx).Scan(&service.ID); err != nil {
+			return errors.Wrapf(err, "error creating service %s", service.InstanceID)
+		}
+		service.ReadOnly = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error committing transaction")
+	}
+
+	return nil
+}
+
 // UpdateService updates an existing service configuration
 func (db *DB) UpdateService(ctx context.Context, service *models.ServiceConfiguration) error {
+	apiKey, err := db.sealSecret(service.APIKey)
+	if err != nil {
+		return err
+	}
+
 	queryBuilder := db.squirrel.Update("service_configurations").
 		Set("display_name", service.DisplayName).
 		Set("url", sql.NullString{String: service.URL, Valid: service.URL != ""}).
-		Set("api_key", sql.NullString{String: service.APIKey, Valid: service.APIKey != ""}).
+		Set("api_key", apiKey).
 		Set("access_url", sql.NullString{String: service.AccessURL, Valid: service.AccessURL != ""}).
 		Where(sq.Eq{"instance_id": service.InstanceID})
 
+	// Clients that don't know about tags omit them, which must not clear existing tags
+	if service.Tags != nil {
+		queryBuilder = queryBuilder.Set("tags", encodeTags(service.Tags))
+	}
+	// Same for the API version, it is reset to the default through UpdateServiceFields
+	if service.APIVersion != "" {
+		queryBuilder = queryBuilder.Set("api_version", service.APIVersion)
+	}
+	// Same for the color and icon
+	if service.Color != "" {
+		queryBuilder = queryBuilder.Set("color", service.Color)
+	}
+	if service.Icon != "" {
+		queryBuilder = queryBuilder.Set("icon", service.Icon)
+	}
+	// And the check interval, 0 keeps the current override
+	if service.CheckIntervalSeconds != 0 {
+		queryBuilder = queryBuilder.Set("check_interval_seconds", service.CheckIntervalSeconds)
+	}
+	if service.Method != "" {
+		queryBuilder = queryBuilder.Set("method", service.Method)
+	}
+	if service.ExpectedStatus != "" {
+		queryBuilder = queryBuilder.Set("expected_status", service.ExpectedStatus)
+	}
+	if service.AuthHeaderName != "" {
+		queryBuilder = queryBuilder.Set("auth_header_name", service.AuthHeaderName)
+	}
+	if service.Notes != "" {
+		queryBuilder = queryBuilder.Set("notes", service.Notes)
+	}
+	if service.ClientCert != "" {
+		queryBuilder = queryBuilder.Set("client_cert", service.ClientCert)
+	}
+	if service.ClientKey != "" {
+		clientKey, err := db.sealSecret(service.ClientKey)
+		if err != nil {
+			return err
+		}
+		queryBuilder = queryBuilder.Set("client_key", clientKey)
+	}
+	if service.ExpectedVersion != "" {
+		queryBuilder = queryBuilder.Set("expected_version", service.ExpectedVersion)
+	}
+	// The critical flag is only ever set here, it is cleared through UpdateServiceFields
+	if service.Critical {
+		queryBuilder = queryBuilder.Set("is_critical", true)
+	}
+
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return err
@@ -579,6 +1129,160 @@ func (db *DB) UpdateService(ctx context.Context, service *models.ServiceConfigur
 	return err
 }
 
+// UpdateServiceFields updates only the fields set in params, leaving the rest untouched
+func (db *DB) UpdateServiceFields(ctx context.Context, instanceID string, params types.UpdateServiceParams) error {
+	if params.IsEmpty() {
+		return nil
+	}
+
+	queryBuilder := db.squirrel.Update("service_configurations").
+		Where(sq.Eq{"instance_id": instanceID})
+
+	if params.DisplayName != nil {
+		queryBuilder = queryBuilder.Set("display_name", *params.DisplayName)
+	}
+	if params.URL != nil {
+		queryBuilder = queryBuilder.Set("url", sql.NullString{String: *params.URL, Valid: *params.URL != ""})
+	}
+	if params.APIKey != nil {
+		apiKey, err := db.sealSecret(*params.APIKey)
+		if err != nil {
+			return err
+		}
+		queryBuilder = queryBuilder.Set("api_key", apiKey)
+	}
+	if params.AccessURL != nil {
+		queryBuilder = queryBuilder.Set("access_url", sql.NullString{String: *params.AccessURL, Valid: *params.AccessURL != ""})
+	}
+	if params.Tags != nil {
+		queryBuilder = queryBuilder.Set("tags", encodeTags(*params.Tags))
+	}
+	if params.APIVersion != nil {
+		queryBuilder = queryBuilder.Set("api_version", sql.NullString{String: *params.APIVersion, Valid: *params.APIVersion != ""})
+	}
+	if params.Color != nil {
+		queryBuilder = queryBuilder.Set("color", nullString(*params.Color))
+	}
+	if params.Icon != nil {
+		queryBuilder = queryBuilder.Set("icon", nullString(*params.Icon))
+	}
+	if params.CheckIntervalSeconds != nil {
+		queryBuilder = queryBuilder.Set("check_interval_seconds", nullInt(*params.CheckIntervalSeconds))
+	}
+	if params.Method != nil {
+		queryBuilder = queryBuilder.Set("method", nullString(*params.Method))
+	}
+	if params.ExpectedStatus != nil {
+		queryBuilder = queryBuilder.Set("expected_status", nullString(*params.ExpectedStatus))
+	}
+	if params.AuthHeaderName != nil {
+		queryBuilder = queryBuilder.Set("auth_header_name", nullString(*params.AuthHeaderName))
+	}
+	if params.Notes != nil {
+		queryBuilder = queryBuilder.Set("notes", nullString(*params.Notes))
+	}
+	if params.ClientCert != nil {
+		queryBuilder = queryBuilder.Set("client_cert", nullString(*params.ClientCert))
+	}
+	if params.ClientKey != nil {
+		clientKey, err := db.sealSecret(*params.ClientKey)
+		if err != nil {
+			return err
+		}
+		queryBuilder = queryBuilder.Set("client_key", clientKey)
+	}
+	if params.ExpectedVersion != nil {
+		queryBuilder = queryBuilder.Set("expected_version", nullString(*params.ExpectedVersion))
+	}
+	if params.Critical != nil {
+		queryBuilder = queryBuilder.Set("is_critical", *params.Critical)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// SetServiceMute mutes a service until the given time. A nil time unmutes the service.
+func (db *DB) SetServiceMute(ctx context.Context, instanceID string, until *time.Time) error {
+	mutedUntil := sql.NullTime{}
+	if until != nil {
+		mutedUntil = sql.NullTime{Time: until.UTC(), Valid: true}
+	}
+
+	queryBuilder := db.squirrel.Update("service_configurations").
+		Set("muted_until", mutedUntil).
+		Where(sq.Eq{"instance_id": instanceID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// SetServiceEnabled enables or disables polling for a service
+func (db *DB) SetServiceEnabled(ctx context.Context, instanceID string, enabled bool) error {
+	queryBuilder := db.squirrel.Update("service_configurations").
+		Set("enabled", enabled).
+		Where(sq.Eq{"instance_id": instanceID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// SetServicePinned pins a service to the top of the dashboard or unpins it
+func (db *DB) SetServicePinned(ctx context.Context, instanceID string, pinned bool) error {
+	queryBuilder := db.squirrel.Update("service_configurations").
+		Set("pinned", pinned).
+		Where(sq.Eq{"instance_id": instanceID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// ReorderServices stores the dashboard order of services, instanceIDs lists them from first
+// to last. Services not listed keep their position.
+func (db *DB) ReorderServices(ctx context.Context, instanceIDs []string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, instanceID := range instanceIDs {
+		query, args, err := db.squirrel.Update("service_configurations").
+			Set("position", i+1).
+			Where(sq.Eq{"instance_id": instanceID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrapf(err, "error positioning service %s", instanceID)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // DeleteService deletes a service configuration by its instance ID
 func (db *DB) DeleteService(ctx context.Context, instanceID string) error {
 	queryBuilder := db.squirrel.Delete("service_configurations").Where(sq.Eq{"instance_id": instanceID})
@@ -8,6 +8,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -198,7 +199,7 @@ func TestPostgresServiceOperations(t *testing.T) {
 	}
 
 	// Test GetAllServices
-	services, err := db.GetAllServices(ctx)
+	services, err := db.GetAllServices(ctx, true)
 	if err != nil {
 		t.Fatalf("Failed to get all services: %v", err)
 	}
@@ -253,7 +254,7 @@ func TestPostgresConcurrentOperations(t *testing.T) {
 	}
 
 	// Verify all services were created
-	services, err := db.GetAllServices(ctx)
+	services, err := db.GetAllServices(ctx, true)
 	if err != nil {
 		t.Fatalf("Failed to get all services: %v", err)
 	}
@@ -317,3 +318,40 @@ func TestPostgresErrorHandling(t *testing.T) {
 		t.Error("Expected error when creating duplicate service, got nil")
 	}
 }
+
+func TestPostgresConcurrentSetup(t *testing.T) {
+	db, cleanup := setupPostgresDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Only one of the concurrent setups may create the first user
+	numSetups := 5
+	errChan := make(chan error, numSetups)
+
+	for i := 0; i < numSetups; i++ {
+		go func(i int) {
+			user := &types.User{
+				Username:     fmt.Sprintf("admin%d", i),
+				Email:        fmt.Sprintf("admin%d@example.com", i),
+				PasswordHash: "hashedpassword",
+			}
+			errChan <- db.CompleteSetup(ctx, user, nil)
+		}(i)
+	}
+
+	completed := 0
+	for i := 0; i < numSetups; i++ {
+		err := <-errChan
+		switch {
+		case err == nil:
+			completed++
+		case !errors.Is(err, ErrSetupComplete):
+			t.Errorf("Expected ErrSetupComplete, got %v", err)
+		}
+	}
+
+	if completed != 1 {
+		t.Errorf("Expected exactly one setup to complete, got %d", completed)
+	}
+}
@@ -5,7 +5,11 @@ package database
 
 import (
 	"context"
+	"encoding/base64"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -203,7 +207,7 @@ func TestServiceOperations(t *testing.T) {
 	}
 
 	// Test GetAllServices
-	services, err := db.GetAllServices(ctx)
+	services, err := db.GetAllServices(ctx, true)
 	if err != nil {
 		t.Fatalf("Failed to get all services: %v", err)
 	}
@@ -242,6 +246,372 @@ func TestServiceOperations(t *testing.T) {
 	}
 }
 
+func TestUpdateServiceFields(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{
+		InstanceID:  "sonarr-1",
+		DisplayName: "Sonarr",
+		URL:         "http://localhost:8989",
+		APIKey:      "old-api-key",
+		AccessURL:   "https://sonarr.example.com",
+	}
+
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Only rotate the API key
+	newKey := "new-api-key"
+	if err := db.UpdateServiceFields(ctx, "sonarr-1", types.UpdateServiceParams{APIKey: &newKey}); err != nil {
+		t.Fatalf("Failed to update service fields: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get updated service: %v", err)
+	}
+
+	if retrieved == nil {
+		t.Fatal("Expected to find updated service, got nil")
+	}
+
+	if retrieved.APIKey != newKey {
+		t.Errorf("Expected API key %s, got %s", newKey, retrieved.APIKey)
+	}
+
+	// Omitted fields must be left unchanged
+	if retrieved.URL != service.URL {
+		t.Errorf("Expected URL %s to be unchanged, got %s", service.URL, retrieved.URL)
+	}
+	if retrieved.DisplayName != service.DisplayName {
+		t.Errorf("Expected display name %s to be unchanged, got %s", service.DisplayName, retrieved.DisplayName)
+	}
+	if retrieved.AccessURL != service.AccessURL {
+		t.Errorf("Expected access URL %s to be unchanged, got %s", service.AccessURL, retrieved.AccessURL)
+	}
+}
+
+func TestServiceAppearance(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{
+		InstanceID:  "radarr-4k",
+		DisplayName: "Radarr 4K",
+		URL:         "http://localhost:7878",
+		Color:       "#e5484d",
+		Icon:        "radarr-4k",
+	}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// A client unaware of the fields must not clear them
+	update := *service
+	update.Color, update.Icon = "", ""
+	if err := db.UpdateService(ctx, &update); err != nil {
+		t.Fatalf("Failed to update service: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-4k"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.Color != "#e5484d" || retrieved.Icon != "radarr-4k" {
+		t.Errorf("Expected color and icon to be kept, got %q and %q", retrieved.Color, retrieved.Icon)
+	}
+
+	empty := ""
+	if err := db.UpdateServiceFields(ctx, "radarr-4k", types.UpdateServiceParams{Color: &empty}); err != nil {
+		t.Fatalf("Failed to update service fields: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-4k"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.Color != "" || retrieved.Icon != "radarr-4k" {
+		t.Errorf("Expected only the color to be cleared, got %q and %q", retrieved.Color, retrieved.Icon)
+	}
+}
+
+func TestServiceCheckInterval(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{
+		InstanceID:           "plex-main",
+		DisplayName:          "Plex",
+		URL:                  "http://localhost:32400",
+		CheckIntervalSeconds: 300,
+	}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "plex-main"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.CheckIntervalSeconds != 300 {
+		t.Errorf("Expected a check interval of 300, got %d", retrieved.CheckIntervalSeconds)
+	}
+
+	reset := 0
+	if err := db.UpdateServiceFields(ctx, "plex-main", types.UpdateServiceParams{CheckIntervalSeconds: &reset}); err != nil {
+		t.Fatalf("Failed to update service fields: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "plex-main"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.CheckIntervalSeconds != 0 {
+		t.Errorf("Expected the check interval to be reset, got %d", retrieved.CheckIntervalSeconds)
+	}
+}
+
+func TestServiceNotes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	notes := "4K library, restart weekly"
+	service := &models.ServiceConfiguration{
+		InstanceID:  "plex-main",
+		DisplayName: "Plex",
+		URL:         "http://localhost:32400",
+		Notes:       notes,
+	}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "plex-main"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.Notes != notes {
+		t.Errorf("Expected notes %q, got %q", notes, retrieved.Notes)
+	}
+
+	services, err := db.GetAllServices(ctx, true)
+	if err != nil || len(services) != 1 {
+		t.Fatalf("Failed to list services: %v", err)
+	}
+	if services[0].Notes != notes {
+		t.Errorf("Expected listed notes %q, got %q", notes, services[0].Notes)
+	}
+
+	cleared := ""
+	if err := db.UpdateServiceFields(ctx, "plex-main", types.UpdateServiceParams{Notes: &cleared}); err != nil {
+		t.Fatalf("Failed to update service fields: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "plex-main"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.Notes != "" {
+		t.Errorf("Expected the notes to be cleared, got %q", retrieved.Notes)
+	}
+}
+
+func TestSetServiceMute(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{
+		InstanceID:  "radarr-1",
+		DisplayName: "Radarr",
+		URL:         "http://localhost:7878",
+	}
+
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := db.SetServiceMute(ctx, "radarr-1", &until); err != nil {
+		t.Fatalf("Failed to mute service: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get muted service: %v", err)
+	}
+
+	if retrieved.MutedUntil == nil || !retrieved.MutedUntil.Equal(until) {
+		t.Errorf("Expected muted until %v, got %v", until, retrieved.MutedUntil)
+	}
+	if !retrieved.IsMuted(time.Now()) {
+		t.Error("Expected service to be muted")
+	}
+	if retrieved.IsMuted(until.Add(time.Second)) {
+		t.Error("Expected mute to expire after the muted until time")
+	}
+
+	if err := db.SetServiceMute(ctx, "radarr-1", nil); err != nil {
+		t.Fatalf("Failed to unmute service: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get unmuted service: %v", err)
+	}
+
+	if retrieved.MutedUntil != nil {
+		t.Errorf("Expected service to be unmuted, got muted until %v", retrieved.MutedUntil)
+	}
+}
+
+func TestSetServiceEnabled(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, instanceID := range []string{"sonarr-1", "sonarr-2"} {
+		service := &models.ServiceConfiguration{
+			InstanceID:  instanceID,
+			DisplayName: "Sonarr",
+			URL:         "http://localhost:8989",
+		}
+		if err := db.CreateService(ctx, service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	if err := db.SetServiceEnabled(ctx, "sonarr-2", false); err != nil {
+		t.Fatalf("Failed to disable service: %v", err)
+	}
+
+	enabled, err := db.GetAllServices(ctx, false)
+	if err != nil {
+		t.Fatalf("Failed to get enabled services: %v", err)
+	}
+	if len(enabled) != 1 || enabled[0].InstanceID != "sonarr-1" {
+		t.Errorf("Expected only sonarr-1 to be returned, got %v", enabled)
+	}
+
+	all, err := db.GetAllServices(ctx, true)
+	if err != nil {
+		t.Fatalf("Failed to get all services: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 services including disabled, got %d", len(all))
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-2"})
+	if err != nil {
+		t.Fatalf("Failed to get disabled service: %v", err)
+	}
+	if !retrieved.Disabled {
+		t.Error("Expected sonarr-2 to be disabled")
+	}
+}
+
+func TestServiceOrder(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, instanceID := range []string{"plex-1", "radarr-1", "sonarr-1"} {
+		service := &models.ServiceConfiguration{
+			InstanceID:  instanceID,
+			DisplayName: instanceID,
+			URL:         "http://localhost",
+		}
+		if err := db.CreateService(ctx, service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	order := func() []string {
+		t.Helper()
+		services, err := db.GetAllServices(ctx, true)
+		if err != nil {
+			t.Fatalf("Failed to get services: %v", err)
+		}
+		var ids []string
+		for _, service := range services {
+			ids = append(ids, service.InstanceID)
+		}
+		return ids
+	}
+
+	if err := db.ReorderServices(ctx, []string{"sonarr-1", "plex-1", "radarr-1"}); err != nil {
+		t.Fatalf("Failed to reorder services: %v", err)
+	}
+	if got, want := order(), []string{"sonarr-1", "plex-1", "radarr-1"}; !slices.Equal(got, want) {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+
+	if err := db.SetServicePinned(ctx, "radarr-1", true); err != nil {
+		t.Fatalf("Failed to pin service: %v", err)
+	}
+	if got, want := order(), []string{"radarr-1", "sonarr-1", "plex-1"}; !slices.Equal(got, want) {
+		t.Errorf("Expected the pinned service first, got %v", got)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-1"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if !retrieved.Pinned || retrieved.Position != 3 {
+		t.Errorf("Expected radarr-1 pinned at position 3, got pinned %t at %d", retrieved.Pinned, retrieved.Position)
+	}
+}
+
+func TestCreateServices(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	services := []*models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://localhost:8989"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://localhost:7878", Tags: []string{"media"}},
+	}
+	if err := db.CreateServices(ctx, services); err != nil {
+		t.Fatalf("Failed to create services: %v", err)
+	}
+	for _, service := range services {
+		if service.ID == 0 {
+			t.Errorf("Expected %s to have an id", service.InstanceID)
+		}
+	}
+
+	// A failing insert rolls back the whole batch
+	err := db.CreateServices(ctx, []*models.ServiceConfiguration{
+		{InstanceID: "prowlarr-1", DisplayName: "Prowlarr"},
+		{InstanceID: "sonarr-1", DisplayName: "Duplicate"},
+	})
+	if err == nil {
+		t.Fatal("Expected an error for a duplicate instance id")
+	}
+
+	all, err := db.GetAllServices(ctx, true)
+	if err != nil {
+		t.Fatalf("Failed to get services: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected the failed batch to be rolled back, got %d services", len(all))
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -301,8 +671,438 @@ func TestErrorHandling(t *testing.T) {
 	defer cancel()
 
 	time.Sleep(time.Millisecond) // Ensure context is cancelled
-	_, err = db.GetAllServices(cancelCtx)
+	_, err = db.GetAllServices(cancelCtx, true)
 	if err == nil {
 		t.Error("Expected error when using cancelled context, got nil")
 	}
 }
+
+func TestMaintenance(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{
+		InstanceID:  "sonarr-1",
+		DisplayName: "Sonarr",
+		URL:         "http://localhost:8989",
+	}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	if err := db.Maintenance(ctx); err != nil {
+		t.Fatalf("Failed to run maintenance: %v", err)
+	}
+	if db.MaintenanceRunning() {
+		t.Error("Expected maintenance to be finished")
+	}
+
+	// A run already in progress must not be started again
+	db.maintenanceRunning.Store(true)
+	if err := db.Maintenance(ctx); err != ErrMaintenanceRunning {
+		t.Errorf("Expected ErrMaintenanceRunning, got %v", err)
+	}
+	db.maintenanceRunning.Store(false)
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Expected service to survive maintenance, got %v (err %v)", retrieved, err)
+	}
+}
+
+func TestPoolSettings(t *testing.T) {
+	os.Setenv("DASHBRR__DB_MAX_OPEN_CONNS", "1")
+	defer os.Unsetenv("DASHBRR__DB_MAX_OPEN_CONNS")
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	stats := db.PoolStats()
+	if stats.MaxOpenConnections != 1 {
+		t.Errorf("Expected max open connections 1, got %d", stats.MaxOpenConnections)
+	}
+	if stats.Driver != "sqlite" {
+		t.Errorf("Expected sqlite driver, got %s", stats.Driver)
+	}
+
+	// A single connection must still serve sequential queries without deadlocking
+	ctx := context.Background()
+	if _, err := db.GetAllServices(ctx, true); err != nil {
+		t.Fatalf("Failed to get services: %v", err)
+	}
+	if _, err := db.HasUsers(ctx); err != nil {
+		t.Fatalf("Failed to check users: %v", err)
+	}
+}
+
+func TestListServices(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, service := range []models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", Tags: []string{"4K", "anime"}},
+		{InstanceID: "sonarr-2", DisplayName: "Sonarr HD", Tags: []string{"hd"}},
+		{InstanceID: "radarr-1", DisplayName: "Radarr 4K", Tags: []string{"4k"}},
+		{InstanceID: "radarr-2", DisplayName: "Radarr"},
+		{InstanceID: "plex-1", DisplayName: "Plex", Tags: []string{"a_b"}},
+	} {
+		service := service
+		if err := db.CreateService(ctx, &service); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		params   types.ListServicesParams
+		expected []string
+		total    int
+	}{
+		{"All", types.ListServicesParams{}, []string{"plex-1", "radarr-1", "radarr-2", "sonarr-1", "sonarr-2"}, 5},
+		{"Type", types.ListServicesParams{Type: "sonarr"}, []string{"sonarr-1", "sonarr-2"}, 2},
+		{"Tag", types.ListServicesParams{Tag: "4k"}, []string{"radarr-1", "sonarr-1"}, 2},
+		{"Type and tag", types.ListServicesParams{Type: "radarr", Tag: "4K"}, []string{"radarr-1"}, 1},
+		{"Limit", types.ListServicesParams{Limit: 2}, []string{"plex-1", "radarr-1"}, 5},
+		{"Limit and offset", types.ListServicesParams{Limit: 2, Offset: 2}, []string{"radarr-2", "sonarr-1"}, 5},
+		{"Offset past the end", types.ListServicesParams{Offset: 10}, []string{}, 5},
+		{"Type with paging", types.ListServicesParams{Type: "radarr", Limit: 1, Offset: 1}, []string{"radarr-2"}, 2},
+		{"Tag wildcard is literal", types.ListServicesParams{Tag: "a%"}, []string{}, 0},
+		{"Tag underscore is literal", types.ListServicesParams{Tag: "axb"}, []string{}, 0},
+		{"Partial tag does not match", types.ListServicesParams{Tag: "anim"}, []string{}, 0},
+		{"Unknown type", types.ListServicesParams{Type: "lidarr"}, []string{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, total, err := db.ListServices(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("Failed to list services: %v", err)
+			}
+			if total != tt.total {
+				t.Errorf("Expected total %d, got %d", tt.total, total)
+			}
+
+			got := make([]string, 0, len(services))
+			for _, service := range services {
+				got = append(got, service.InstanceID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestServiceTags(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{
+		InstanceID:  "sonarr-1",
+		DisplayName: "Sonarr",
+		Tags:        []string{" 4K ", "anime", "4k", ""},
+	}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if strings.Join(retrieved.Tags, ",") != "4k,anime" {
+		t.Errorf("Expected normalized tags [4k anime], got %v", retrieved.Tags)
+	}
+
+	// A full update without tags keeps them
+	retrieved.Tags = nil
+	retrieved.DisplayName = "Sonarr 4K"
+	if err := db.UpdateService(ctx, retrieved); err != nil {
+		t.Fatalf("Failed to update service: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if len(retrieved.Tags) != 2 {
+		t.Errorf("Expected tags to be kept, got %v", retrieved.Tags)
+	}
+
+	// An explicit empty list clears them
+	if err := db.UpdateServiceFields(ctx, "sonarr-1", types.UpdateServiceParams{Tags: &[]string{}}); err != nil {
+		t.Fatalf("Failed to clear tags: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if len(retrieved.Tags) != 0 {
+		t.Errorf("Expected tags to be cleared, got %v", retrieved.Tags)
+	}
+}
+
+func TestServiceAPIVersion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{InstanceID: "radarr-1", DisplayName: "Radarr", APIVersion: "v4"}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// A full update from a client that doesn't send the version keeps it
+	service.APIVersion = ""
+	if err := db.UpdateService(ctx, service); err != nil {
+		t.Fatalf("Failed to update service: %v", err)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.APIVersion != "v4" {
+		t.Errorf("Expected api version v4, got %q", retrieved.APIVersion)
+	}
+
+	// Clearing it explicitly falls back to the default
+	empty := ""
+	if err := db.UpdateServiceFields(ctx, "radarr-1", types.UpdateServiceParams{APIVersion: &empty}); err != nil {
+		t.Fatalf("Failed to clear api version: %v", err)
+	}
+
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.APIVersion != "" {
+		t.Errorf("Expected api version to be cleared, got %q", retrieved.APIVersion)
+	}
+}
+
+func TestServiceAPIKeyEncryption(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	service := &models.ServiceConfiguration{InstanceID: "database-1", DisplayName: "Postgres", URL: "postgres://dashbrr@db/media", APIKey: "s3cret"}
+	if err := db.CreateService(ctx, service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	var stored string
+	if err := db.QueryRowContext(ctx, `SELECT api_key FROM service_configurations WHERE instance_id = ?`, "database-1").Scan(&stored); err != nil {
+		t.Fatalf("Failed to read stored api key: %v", err)
+	}
+	if !strings.HasPrefix(stored, secretPrefix) || strings.Contains(stored, "s3cret") {
+		t.Errorf("Expected the api key to be stored encrypted, got %q", stored)
+	}
+
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "database-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.APIKey != "s3cret" {
+		t.Errorf("Expected the decrypted api key, got %q", retrieved.APIKey)
+	}
+
+	// Keys stored before they were encrypted are still read
+	if _, err := db.ExecContext(ctx, `UPDATE service_configurations SET api_key = ? WHERE instance_id = ?`, "plain", "database-1"); err != nil {
+		t.Fatalf("Failed to store plain api key: %v", err)
+	}
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "database-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.APIKey != "plain" {
+		t.Errorf("Expected the plain api key, got %q", retrieved.APIKey)
+	}
+
+	// The key of a client certificate is a secret as well
+	clientKey := "client-key"
+	if err := db.UpdateServiceFields(ctx, "database-1", types.UpdateServiceParams{ClientKey: &clientKey}); err != nil {
+		t.Fatalf("Failed to update client key: %v", err)
+	}
+	if err := db.QueryRowContext(ctx, `SELECT client_key FROM service_configurations WHERE instance_id = ?`, "database-1").Scan(&stored); err != nil {
+		t.Fatalf("Failed to read stored client key: %v", err)
+	}
+	if !strings.HasPrefix(stored, secretPrefix) || strings.Contains(stored, "client-key") {
+		t.Errorf("Expected the client key to be stored encrypted, got %q", stored)
+	}
+	retrieved, err = db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "database-1"})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if retrieved.ClientKey != "client-key" {
+		t.Errorf("Expected the decrypted client key, got %q", retrieved.ClientKey)
+	}
+
+	info, err := os.Stat(filepath.Join(filepath.Dir(os.Getenv("DASHBRR__DB_PATH")), secretKeyFile))
+	if err != nil {
+		t.Fatalf("Expected the secret key next to the database: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the secret key to be readable by the owner only, got %v", info.Mode().Perm())
+	}
+}
+
+func TestInitDB_WrongSecretKey(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+	keyPath := filepath.Join(dir, secretKeyFile)
+
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to init database: %v", err)
+	}
+	service := &models.ServiceConfiguration{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989", APIKey: "s3cret"}
+	if err := db.CreateService(context.Background(), service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	db.Close()
+
+	// A replaced key can't decrypt the stored API key
+	if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(make([]byte, 32))), 0600); err != nil {
+		t.Fatalf("Failed to replace secret key: %v", err)
+	}
+	if db, err := InitDB(dbPath); err == nil {
+		db.Close()
+		t.Fatal("Expected the start to fail with the wrong secret key")
+	}
+
+	// A lost key isn't silently replaced by a new one
+	if err := os.Remove(keyPath); err != nil {
+		t.Fatalf("Failed to remove secret key: %v", err)
+	}
+	if db, err := InitDB(dbPath); err == nil {
+		db.Close()
+		t.Fatal("Expected the start to fail without the secret key")
+	}
+	if _, err := os.Stat(keyPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the generated secret key to be removed, got %v", err)
+	}
+}
+
+func TestSecretKeyDir(t *testing.T) {
+	tests := []struct {
+		config *Config
+		want   string
+	}{
+		{&Config{Driver: "sqlite", Path: "/srv/dashbrr/dashbrr.db", DataDir: "/data"}, "/srv/dashbrr"},
+		{&Config{Driver: "postgres", DataDir: "/data"}, "/data"},
+		{&Config{Driver: "postgres"}, "./data"},
+	}
+
+	for _, tt := range tests {
+		if got := secretKeyDir(tt.config); got != tt.want {
+			t.Errorf("secretKeyDir(%+v) = %q, want %q", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestSyncConfigServices(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// A service created through the API is taken over by the config file
+	if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: "sonarr-1", DisplayName: "Old", URL: "http://old"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	services := []*models.ServiceConfiguration{
+		{InstanceID: "sonarr-1", DisplayName: "Sonarr", URL: "http://sonarr:8989", APIKey: "key"},
+		{InstanceID: "radarr-1", DisplayName: "Radarr", URL: "http://radarr:7878"},
+	}
+	if err := db.SyncConfigServices(ctx, services); err != nil {
+		t.Fatalf("Failed to sync services: %v", err)
+	}
+
+	for _, instanceID := range []string{"sonarr-1", "radarr-1"} {
+		retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: instanceID})
+		if err != nil || retrieved == nil {
+			t.Fatalf("Failed to get %s: %v", instanceID, err)
+		}
+		if !retrieved.ReadOnly {
+			t.Errorf("Expected %s to be read-only", instanceID)
+		}
+	}
+
+	retrieved, _ := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if retrieved.DisplayName != "Sonarr" || retrieved.URL != "http://sonarr:8989" || retrieved.APIKey != "key" {
+		t.Errorf("Expected the config file values, got %+v", retrieved)
+	}
+
+	// Dropping a service from the file keeps it, but makes it editable again
+	if err := db.SyncConfigServices(ctx, services[:1]); err != nil {
+		t.Fatalf("Failed to sync services: %v", err)
+	}
+	retrieved, err := db.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "radarr-1"})
+	if err != nil || retrieved == nil {
+		t.Fatalf("Expected radarr-1 to be kept, got %v", err)
+	}
+	if retrieved.ReadOnly {
+		t.Error("Expected radarr-1 to be editable after removing it from the config file")
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := db.CreateService(ctx, &models.ServiceConfiguration{
+		InstanceID:  "sonarr-1",
+		DisplayName: "Sonarr",
+		URL:         "http://localhost:8989",
+	}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := db.CreateUser(ctx, &types.User{Username: "admin", Email: "admin@example.com", PasswordHash: "hash"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := db.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+	if err := db.Backup(ctx, backupPath); err == nil {
+		t.Error("Expected an existing backup file not to be overwritten")
+	}
+
+	// The source still holds data, a restore must be refused
+	if err := db.Restore(ctx, backupPath); err != ErrDatabaseNotEmpty {
+		t.Errorf("Expected ErrDatabaseNotEmpty, got %v", err)
+	}
+
+	target, err := InitDBWithConfig(&Config{Driver: "sqlite", Path: filepath.Join(t.TempDir(), "target.db")})
+	if err != nil {
+		t.Fatalf("Failed to initialize target database: %v", err)
+	}
+	defer target.Close()
+
+	if err := target.Restore(ctx, backupPath); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+
+	service, err := target.FindServiceBy(ctx, types.FindServiceParams{InstanceID: "sonarr-1"})
+	if err != nil || service == nil {
+		t.Fatalf("Expected service to be restored, got %v (err %v)", service, err)
+	}
+	user, err := target.FindUser(ctx, types.FindUserParams{Username: "admin"})
+	if err != nil || user == nil {
+		t.Fatalf("Expected user to be restored, got %v (err %v)", user, err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// secretKeyFile holds the key the API keys and client certificate keys of services are
+// encrypted with. It lives next to the SQLite database and has to be kept along with its
+// backups, without it the stored keys can't be read.
+const secretKeyFile = "secret.key"
+
+// secretPrefix marks encrypted values. Values without it are read as they are, e.g. the
+// API keys stored before they were encrypted.
+const secretPrefix = "enc:v1:"
+
+// secretKeyDir returns the directory secret.key is kept in, the directory of the SQLite
+// database or the data directory for PostgreSQL
+func secretKeyDir(config *Config) string {
+	if config.Driver != "postgres" && config.Path != "" {
+		return filepath.Dir(config.Path)
+	}
+	if config.DataDir != "" {
+		return config.DataDir
+	}
+	return "./data"
+}
+
+// loadSecretKey returns the cipher for stored secrets and whether the key was generated just now.
+// DASHBRR__SECRET_KEY takes a base64 encoded 32 byte key, e.g. for PostgreSQL setups
+// without persistent storage. Otherwise the key is read from secret.key in dir and
+// generated there on first start.
+func loadSecretKey(dir string) (cipher.AEAD, bool, error) {
+	var key []byte
+	var created bool
+	if env := os.Getenv("DASHBRR__SECRET_KEY"); env != "" {
+		decoded, err := base64.StdEncoding.DecodeString(env)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "decode DASHBRR__SECRET_KEY")
+		}
+		key = decoded
+	} else {
+		path := filepath.Join(dir, secretKeyFile)
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "decode %s", path)
+			}
+			key = decoded
+		case os.IsNotExist(err):
+			key = make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return nil, false, errors.Wrap(err, "generate secret key")
+			}
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return nil, false, err
+			}
+			if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+				return nil, false, errors.Wrap(err, "write secret key")
+			}
+			created = true
+		default:
+			return nil, false, errors.Wrap(err, "read secret key")
+		}
+	}
+
+	if len(key) != 32 {
+		return nil, false, fmt.Errorf("secret key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, false, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, false, err
+	}
+	return aead, created, nil
+}
+
+// checkSecrets makes sure every stored secret can be decrypted with the loaded key
+func (db *DB) checkSecrets(ctx context.Context) error {
+	query, args, err := db.squirrel.Select("instance_id", "api_key", "client_key").
+		From("service_configurations").
+		Where(sq.Or{
+			sq.Like{"api_key": secretPrefix + "%"},
+			sq.Like{"client_key": secretPrefix + "%"},
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error reading stored secrets")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var instanceID string
+		var apiKey, clientKey sql.NullString
+		if err := rows.Scan(&instanceID, &apiKey, &clientKey); err != nil {
+			return errors.Wrap(err, "error reading stored secrets")
+		}
+		for _, value := range []string{apiKey.String, clientKey.String} {
+			if _, err := db.openSecret(value); err != nil {
+				return fmt.Errorf("the secrets of %s can't be decrypted with the secret key, restore the %s they were stored with or set DASHBRR__SECRET_KEY to it", instanceID, secretKeyFile)
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// sealSecret encrypts value for storage, empty values are stored as NULL
+func (db *DB) sealSecret(value string) (sql.NullString, error) {
+	if value == "" {
+		return sql.NullString{}, nil
+	}
+
+	nonce := make([]byte, db.secrets.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return sql.NullString{}, errors.Wrap(err, "generate nonce")
+	}
+	sealed := db.secrets.Seal(nonce, nonce, []byte(value), nil)
+
+	return sql.NullString{String: secretPrefix + base64.StdEncoding.EncodeToString(sealed), Valid: true}, nil
+}
+
+// openSecret decrypts a value stored by sealSecret, values stored unencrypted are returned
+// as they are
+func (db *DB) openSecret(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, secretPrefix)
+	if !ok {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	nonceSize := db.secrets.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("encrypted value too short")
+	}
+
+	plain, err := db.secrets.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
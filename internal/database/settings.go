@@ -0,0 +1,63 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// GetSetting returns the value of an instance setting and whether it is set
+func (db *DB) GetSetting(ctx context.Context, key string) (string, bool, error) {
+	query, args, err := db.squirrel.Select("value").From("settings").Where(sq.Eq{"key": key}).ToSql()
+	if err != nil {
+		return "", false, err
+	}
+
+	var value string
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, errors.Wrapf(err, "get setting %s", key)
+	}
+
+	return value, true, nil
+}
+
+// SetSetting stores the value of an instance setting, replacing any previous value
+func (db *DB) SetSetting(ctx context.Context, key, value string) error {
+	query, args, err := db.squirrel.Insert("settings").
+		Columns("key", "value", "updated_at").
+		Values(key, value, time.Now()).
+		Suffix("ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrapf(err, "set setting %s", key)
+	}
+
+	return nil
+}
+
+// DeleteSetting removes an instance setting, deleting a missing setting is not an error
+func (db *DB) DeleteSetting(ctx context.Context, key string) error {
+	query, args, err := db.squirrel.Delete("settings").Where(sq.Eq{"key": key}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrapf(err, "delete setting %s", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package logger
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultBufferSize is the number of log entries kept in memory
+const DefaultBufferSize = 1000
+
+// Entry is a log line kept in the in-memory buffer
+type Entry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// RingBuffer is a zerolog writer that keeps the most recent log entries in memory
+type RingBuffer struct {
+	mu      sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates a buffer holding up to size entries
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &RingBuffer{entries: make([]Entry, size)}
+}
+
+// Write parses a JSON log event written by zerolog and stores it, replacing the
+// oldest entry once the buffer is full
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(p, &fields); err != nil {
+		// Never fail the other writers because of a malformed line
+		return len(p), nil
+	}
+
+	entry := Entry{}
+	if level, ok := fields[zerolog.LevelFieldName].(string); ok {
+		entry.Level = level
+	}
+	if message, ok := fields[zerolog.MessageFieldName].(string); ok {
+		entry.Message = message
+	}
+	if timestamp, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		entry.Time, _ = time.Parse(zerolog.TimeFieldFormat, timestamp)
+	}
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.MessageFieldName)
+	delete(fields, zerolog.TimestampFieldName)
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+
+	b.mu.Lock()
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	b.mu.Unlock()
+
+	return len(p), nil
+}
+
+// Entries returns the buffered entries at or above minLevel, oldest first
+func (b *RingBuffer) Entries(minLevel zerolog.Level) []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ordered := b.entries[:b.next]
+	if b.full {
+		ordered = append(append([]Entry{}, b.entries[b.next:]...), b.entries[:b.next]...)
+	}
+
+	result := make([]Entry, 0, len(ordered))
+	for _, entry := range ordered {
+		level, err := zerolog.ParseLevel(entry.Level)
+		if err != nil || level >= minLevel {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+var recent = NewRingBuffer(DefaultBufferSize)
+
+// Recent returns the buffer the global logger writes to
+func Recent() *RingBuffer {
+	return recent
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package logger
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRingBuffer(t *testing.T) {
+	buffer := NewRingBuffer(3)
+	logger := zerolog.New(buffer).With().Timestamp().Logger()
+
+	for i := 1; i <= 5; i++ {
+		logger.Info().Int("n", i).Msg("message " + strconv.Itoa(i))
+	}
+	logger.Warn().Msg("warning")
+
+	entries := buffer.Entries(zerolog.TraceLevel)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, expected := range []string{"message 4", "message 5", "warning"} {
+		if entries[i].Message != expected {
+			t.Errorf("Expected entry %d to be %q, got %q", i, expected, entries[i].Message)
+		}
+	}
+	if entries[0].Fields["n"] != float64(4) {
+		t.Errorf("Expected field n to be kept, got %v", entries[0].Fields)
+	}
+	if entries[0].Time.IsZero() {
+		t.Error("Expected timestamp to be parsed")
+	}
+
+	warnings := buffer.Entries(zerolog.WarnLevel)
+	if len(warnings) != 1 || warnings[0].Level != "warn" {
+		t.Errorf("Expected only the warning, got %+v", warnings)
+	}
+}
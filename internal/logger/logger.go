@@ -11,7 +11,8 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// Init initializes the global logger with colored output
+// Init initializes the global logger with colored output. Log entries are also
+// kept in the in-memory buffer returned by Recent.
 func Init() {
 	colors := map[string]string{
 		"trace": "\033[36m", // Cyan
@@ -38,5 +39,5 @@ func Init() {
 			return color + strings.ToUpper(level) + "\033[0m"
 		},
 	}
-	log.Logger = zerolog.New(output).With().Timestamp().Logger()
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(output, recent)).With().Timestamp().Logger()
 }
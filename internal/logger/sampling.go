@@ -0,0 +1,39 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+var changeSampler atomic.Pointer[zerolog.LevelSampler]
+
+// SetChangeSampleRate makes Changes log only 1 of every n trace, debug and info
+// events. Warnings and errors are never sampled. 0 or 1 logs every event.
+func SetChangeSampleRate(n int) {
+	if n <= 1 {
+		changeSampler.Store(nil)
+		return
+	}
+
+	sampler := &zerolog.BasicSampler{N: uint32(n)}
+	changeSampler.Store(&zerolog.LevelSampler{
+		TraceSampler: sampler,
+		DebugSampler: sampler,
+		InfoSampler:  sampler,
+	})
+}
+
+// Changes returns the logger for the high-frequency change detection logs,
+// e.g. queue and session changes, sampled at the configured rate
+func Changes() *zerolog.Logger {
+	logger := log.Logger
+	if sampler := changeSampler.Load(); sampler != nil {
+		logger = logger.Sample(sampler)
+	}
+	return &logger
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package logger
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func TestChangesSampling(t *testing.T) {
+	previous := log.Logger
+	defer func() {
+		log.Logger = previous
+		SetChangeSampleRate(0)
+	}()
+
+	buffer := NewRingBuffer(100)
+	log.Logger = zerolog.New(buffer)
+
+	SetChangeSampleRate(5)
+	for i := 0; i < 10; i++ {
+		Changes().Debug().Msg("changed")
+		Changes().Error().Msg("failed")
+	}
+
+	if debug := len(buffer.Entries(zerolog.TraceLevel)) - len(buffer.Entries(zerolog.ErrorLevel)); debug != 2 {
+		t.Errorf("Expected 2 sampled debug entries, got %d", debug)
+	}
+	if errors := len(buffer.Entries(zerolog.ErrorLevel)); errors != 10 {
+		t.Errorf("Expected all 10 errors to be logged, got %d", errors)
+	}
+
+	SetChangeSampleRate(0)
+	Changes().Debug().Msg("changed")
+	if total := len(buffer.Entries(zerolog.TraceLevel)); total != 13 {
+		t.Errorf("Expected sampling to be disabled, got %d entries", total)
+	}
+}
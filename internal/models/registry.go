@@ -7,6 +7,33 @@ import (
 	"strings"
 )
 
+// SupportedServiceTypes lists the service types the registry can create
+var SupportedServiceTypes = []string{
+	"autobrr",
+	"database",
+	"general",
+	"maintainerr",
+	"omegabrr",
+	"overseerr",
+	"plex",
+	"prowlarr",
+	"radarr",
+	"redis",
+	"sonarr",
+	"tailscale",
+	"tcp",
+}
+
+// ErrUnknownServiceType is returned for a service type the registry can't create
+type ErrUnknownServiceType struct {
+	Type      string
+	Supported []string
+}
+
+func (e *ErrUnknownServiceType) Error() string {
+	return "Unsupported service type: " + e.Type + " (supported: " + strings.Join(e.Supported, ", ") + ")"
+}
+
 // ServiceCreator is responsible for creating service instances
 type ServiceCreator interface {
 	CreateService(serviceType string) ServiceHealthChecker
@@ -58,11 +85,38 @@ func (r *ServiceRegistry) CreateService(serviceType string) ServiceHealthChecker
 		if NewGeneralService != nil {
 			return NewGeneralService()
 		}
+	case "tcp":
+		if NewTCPService != nil {
+			return NewTCPService()
+		}
+	case "redis":
+		if NewRedisService != nil {
+			return NewRedisService()
+		}
+	case "database":
+		if NewDatabaseService != nil {
+			return NewDatabaseService()
+		}
 	}
 	// Return nil for unknown service types
 	return nil
 }
 
+// CreateServiceE is like CreateService but returns an *ErrUnknownServiceType listing the
+// supported types instead of nil for an unknown service type
+func (r *ServiceRegistry) CreateServiceE(serviceType string) (ServiceHealthChecker, error) {
+	return CreateServiceE(r, serviceType)
+}
+
+// CreateServiceE creates a service with any ServiceCreator, turning a nil service into an
+// *ErrUnknownServiceType
+func CreateServiceE(creator ServiceCreator, serviceType string) (ServiceHealthChecker, error) {
+	if service := creator.CreateService(serviceType); service != nil {
+		return service, nil
+	}
+	return nil, &ErrUnknownServiceType{Type: serviceType, Supported: SupportedServiceTypes}
+}
+
 // NewServiceRegistry creates a new instance of ServiceRegistry
 func NewServiceRegistry() ServiceCreator {
 	return &ServiceRegistry{}
@@ -4,6 +4,9 @@
 package models
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -46,3 +49,33 @@ func TestCreateService(t *testing.T) {
 		t.Error("Service creator not called for lowercase service type")
 	}
 }
+
+func TestCreateServiceE(t *testing.T) {
+	registry := &ServiceRegistry{}
+
+	_, err := registry.CreateServiceE("nonexistent")
+	var unknown *ErrUnknownServiceType
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected ErrUnknownServiceType, got %v", err)
+	}
+	if unknown.Type != "nonexistent" || len(unknown.Supported) != len(SupportedServiceTypes) {
+		t.Errorf("Unexpected error details: %+v", unknown)
+	}
+	if !strings.Contains(err.Error(), "sonarr") {
+		t.Errorf("Expected the supported types in the message, got %q", err.Error())
+	}
+
+	original := NewSonarrService
+	defer func() { NewSonarrService = original }()
+	NewSonarrService = func() ServiceHealthChecker { return stubChecker{} }
+
+	if service, err := registry.CreateServiceE("sonarr"); err != nil || service == nil {
+		t.Errorf("Expected a sonarr service, got %v, %v", service, err)
+	}
+}
+
+type stubChecker struct{}
+
+func (stubChecker) CheckHealth(ctx context.Context, url, apiKey string) (ServiceHealth, int) {
+	return ServiceHealth{}, 200
+}
@@ -5,6 +5,7 @@ package models
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 )
 
@@ -22,15 +23,53 @@ type Service struct {
 
 // ServiceHealth represents the health status of a service
 type ServiceHealth struct {
-	Status          string                 `json:"status"`
+	Status          ServiceStatus          `json:"status"`
 	ResponseTime    int64                  `json:"responseTime"`
 	LastChecked     time.Time              `json:"lastChecked"`
+	LastSuccess     *time.Time             `json:"lastSuccess,omitempty"` // Last live check that succeeded, LastChecked is also set for failures
 	Message         string                 `json:"message,omitempty"`
+	Detail          string                 `json:"detail,omitempty"`
 	Version         string                 `json:"version,omitempty"`
 	UpdateAvailable bool                   `json:"updateAvailable,omitempty"`
-	ServiceID       string                 `json:"serviceId"`
+	InstanceID      string                 `json:"instanceId"` // Instance the result belongs to, e.g. sonarr-1
+	ServiceID       string                 `json:"serviceId"`  // Deprecated: same as InstanceID, kept for clients that read serviceId
+	Muted           bool                   `json:"muted,omitempty"`
+	Pinned          bool                   `json:"pinned,omitempty"`
+	Color           string                 `json:"color,omitempty"`
+	Icon            string                 `json:"icon,omitempty"`
+	Maintenance     bool                   `json:"maintenance,omitempty"` // Set while dashbrr is in maintenance mode and the health monitor is paused
 	Stats           map[string]interface{} `json:"stats,omitempty"`
 	Details         map[string]interface{} `json:"details,omitempty"`
+
+	// EventID is the SSE event id the result was broadcast with, used to resume streams
+	EventID uint64 `json:"eventId,omitempty"`
+}
+
+// MarshalJSON writes the instance id under both instanceId and the legacy serviceId
+func (h ServiceHealth) MarshalJSON() ([]byte, error) {
+	type plain ServiceHealth
+	h.syncInstanceID()
+	return json.Marshal(plain(h))
+}
+
+// UnmarshalJSON reads the instance id from either name, results cached before instanceId
+// was added only have serviceId
+func (h *ServiceHealth) UnmarshalJSON(data []byte) error {
+	type plain ServiceHealth
+	if err := json.Unmarshal(data, (*plain)(h)); err != nil {
+		return err
+	}
+	h.syncInstanceID()
+	return nil
+}
+
+// syncInstanceID sets InstanceID and its ServiceID alias to the same value, InstanceID
+// wins when both are set
+func (h *ServiceHealth) syncInstanceID() {
+	if h.InstanceID == "" {
+		h.InstanceID = h.ServiceID
+	}
+	h.ServiceID = h.InstanceID
 }
 
 // ServiceHealthChecker defines the interface for service health checking
@@ -50,4 +89,7 @@ var (
 	NewTailscaleService   func() ServiceHealthChecker
 	NewMaintainerrService func() ServiceHealthChecker
 	NewGeneralService     func() ServiceHealthChecker
+	NewTCPService         func() ServiceHealthChecker
+	NewRedisService       func() ServiceHealthChecker
+	NewDatabaseService    func() ServiceHealthChecker
 )
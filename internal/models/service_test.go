@@ -0,0 +1,36 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestServiceHealthInstanceID(t *testing.T) {
+	data, err := json.Marshal(ServiceHealth{InstanceID: "sonarr-1", Status: StatusOnline})
+	if err != nil {
+		t.Fatalf("Failed to marshal health: %v", err)
+	}
+	if !strings.Contains(string(data), `"instanceId":"sonarr-1"`) || !strings.Contains(string(data), `"serviceId":"sonarr-1"`) {
+		t.Errorf("Expected the instance id under both names, got %s", data)
+	}
+
+	// Results cached before instanceId was added only carry serviceId
+	var health ServiceHealth
+	if err := json.Unmarshal([]byte(`{"status":"online","serviceId":"radarr-1"}`), &health); err != nil {
+		t.Fatalf("Failed to unmarshal health: %v", err)
+	}
+	if health.InstanceID != "radarr-1" || health.ServiceID != "radarr-1" {
+		t.Errorf("Expected the legacy serviceId to fill InstanceID, got %q/%q", health.InstanceID, health.ServiceID)
+	}
+
+	if err := json.Unmarshal([]byte(`{"instanceId":"plex-1","serviceId":"plex-old"}`), &health); err != nil {
+		t.Fatalf("Failed to unmarshal health: %v", err)
+	}
+	if health.InstanceID != "plex-1" || health.ServiceID != "plex-1" {
+		t.Errorf("Expected instanceId to win, got %q/%q", health.InstanceID, health.ServiceID)
+	}
+}
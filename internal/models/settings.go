@@ -3,12 +3,412 @@
 
 package models
 
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/http/httpguts"
+)
+
 // ServiceConfiguration is the database model
 type ServiceConfiguration struct {
-	ID          int64  `json:"-"` // Hide ID from JSON response
-	InstanceID  string `json:"instanceId" gorm:"uniqueIndex"`
-	DisplayName string `json:"displayName"`
-	URL         string `json:"url"`
-	APIKey      string `json:"apiKey,omitempty"`
-	AccessURL   string `json:"accessUrl,omitempty"`
+	ID          int64      `json:"-"` // Hide ID from JSON response
+	InstanceID  string     `json:"instanceId" gorm:"uniqueIndex"`
+	DisplayName string     `json:"displayName"`
+	URL         string     `json:"url"`
+	APIKey      string     `json:"apiKey,omitempty"`
+	AccessURL   string     `json:"accessUrl,omitempty"`
+	MutedUntil  *time.Time `json:"mutedUntil,omitempty"`
+	Disabled    bool       `json:"disabled,omitempty"` // Stored as the enabled column, inverted so the zero value is enabled
+	Tags        []string   `json:"tags,omitempty"`
+	APIVersion  string     `json:"apiVersion,omitempty"` // API path version for Sonarr and Radarr, empty uses DefaultArrAPIVersion
+	ReadOnly    bool       `json:"readOnly,omitempty"`   // Defined in the config file, can't be changed or deleted through the API
+	Color       string     `json:"color,omitempty"`      // Hex tile color, e.g. #e5484d
+	Icon        string     `json:"icon,omitempty"`       // Icon URL or identifier overriding the default icon of the type
+
+	// CheckIntervalSeconds is the time between background health checks, 0 uses the global interval
+	CheckIntervalSeconds int `json:"checkIntervalSeconds,omitempty"`
+
+	// Method and ExpectedStatus configure the request of the general service, empty values
+	// check with GET and accept any 2xx status. ExpectedStatus is a list such as "200,204".
+	Method         string `json:"method,omitempty"`
+	ExpectedStatus string `json:"expectedStatus,omitempty"`
+
+	// Critical services take the overall status down when they are down, other services
+	// only degrade it
+	Critical bool `json:"critical,omitempty"`
+
+	// AuthHeaderName overrides the header the API key is sent in, for forks and proxies
+	// with non-standard auth headers. Empty keeps the default of the service type.
+	AuthHeaderName string `json:"authHeaderName,omitempty"`
+
+	// Notes is free text for operators, e.g. why the instance exists. It is only stored.
+	Notes string `json:"notes,omitempty"`
+
+	// ClientCert and ClientKey are a client certificate and its key as PEM, presented to
+	// services behind proxies that require mutual TLS
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+
+	// Pinned services sort first on the dashboard, then services follow Position
+	Pinned   bool `json:"pinned,omitempty"`
+	Position int  `json:"position,omitempty"`
+
+	// ExpectedVersion is the approved version of the service, e.g. "4.0.x". A healthy
+	// service running any other version is reported as warning, empty disables the check.
+	ExpectedVersion string `json:"expectedVersion,omitempty"`
+}
+
+// DefaultArrAPIVersion is the API path version used for Sonarr and Radarr when none is configured
+const DefaultArrAPIVersion = "v3"
+
+// arrAPIVersions lists the API path versions Sonarr and Radarr can be configured with
+var arrAPIVersions = map[string]bool{
+	"v3": true,
+	"v4": true,
+}
+
+// ValidateAPIVersion checks an api_version value. Empty is valid and means the default.
+func ValidateAPIVersion(version string) error {
+	if version == "" || arrAPIVersions[version] {
+		return nil
+	}
+	return fmt.Errorf("unsupported api version %q", version)
+}
+
+var (
+	colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	iconPattern  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,63}$`)
+)
+
+// ValidateColor checks a tile color, a hex string such as #fff or #e5484d. Empty is valid
+// and means the default color.
+func ValidateColor(color string) error {
+	if color == "" || colorPattern.MatchString(color) {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q, expected a hex color such as #e5484d", color)
+}
+
+// ValidateIcon checks an icon override, either an http(s) URL or an identifier such as
+// "radarr-4k". Empty is valid and means the default icon of the service type.
+func ValidateIcon(icon string) error {
+	if icon == "" || iconPattern.MatchString(icon) {
+		return nil
+	}
+	if u, err := url.Parse(icon); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return nil
+	}
+	return fmt.Errorf("invalid icon %q, expected an http(s) URL or an identifier", icon)
+}
+
+// ValidateAppearance checks the color and icon of the configuration
+func (s *ServiceConfiguration) ValidateAppearance() error {
+	if err := ValidateColor(s.Color); err != nil {
+		return err
+	}
+	return ValidateIcon(s.Icon)
+}
+
+const (
+	// MinCheckIntervalSeconds is the global health check interval, overrides can only slow checks down
+	MinCheckIntervalSeconds = 30
+
+	// MaxCheckIntervalSeconds keeps an override below the lifetime of cached health results,
+	// so a service doesn't drop off the dashboard between two checks
+	MaxCheckIntervalSeconds = 30 * 60
+)
+
+// ValidateCheckInterval checks a check_interval_seconds value. 0 is valid and means the
+// global interval.
+func ValidateCheckInterval(seconds int) error {
+	if seconds == 0 || (seconds >= MinCheckIntervalSeconds && seconds <= MaxCheckIntervalSeconds) {
+		return nil
+	}
+	return fmt.Errorf("check interval must be between %d and %d seconds, or 0 for the default", MinCheckIntervalSeconds, MaxCheckIntervalSeconds)
+}
+
+// CheckInterval returns the time between background health checks of the service, or
+// fallback when the service has no override
+func (s *ServiceConfiguration) CheckInterval(fallback time.Duration) time.Duration {
+	if s.CheckIntervalSeconds <= 0 {
+		return fallback
+	}
+	return time.Duration(s.CheckIntervalSeconds) * time.Second
+}
+
+// httpCheckMethods lists the methods the general service can check an endpoint with
+var httpCheckMethods = map[string]bool{
+	http.MethodGet:  true,
+	http.MethodHead: true,
+	http.MethodPost: true,
+}
+
+// ParseExpectedStatus parses a comma separated list of status codes such as "200,204".
+// Empty returns no codes, which means any 2xx status.
+func ParseExpectedStatus(value string) ([]int, error) {
+	var codes []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid expected status %q, expected a list of status codes such as 200,204", value)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// ValidateHTTPCheck checks the method and expected status of a general service. Empty
+// values are valid and mean GET and any 2xx status.
+func ValidateHTTPCheck(method, expectedStatus string) error {
+	if method != "" && !httpCheckMethods[strings.ToUpper(method)] {
+		return fmt.Errorf("unsupported check method %q, expected GET, HEAD or POST", method)
+	}
+	_, err := ParseExpectedStatus(expectedStatus)
+	return err
+}
+
+// HTTPCheckSetter is implemented by services whose health check request can be configured
+type HTTPCheckSetter interface {
+	SetHTTPCheck(method string, expectedStatus []int)
+}
+
+// ValidateAuthHeaderName checks an auth_header_name value. Empty is valid and means the
+// default header of the service type.
+func ValidateAuthHeaderName(name string) error {
+	if name == "" || httpguts.ValidHeaderFieldName(name) {
+		return nil
+	}
+	return fmt.Errorf("invalid auth header name %q", name)
+}
+
+// MaxNotesLength is the maximum length of the notes of a service, in characters
+const MaxNotesLength = 1000
+
+// ValidateNotes checks the notes of a service
+func ValidateNotes(notes string) error {
+	if utf8.RuneCountInString(notes) > MaxNotesLength {
+		return fmt.Errorf("notes must be at most %d characters", MaxNotesLength)
+	}
+	return nil
+}
+
+// maxExpectedVersionLength bounds the expected version of a service
+const maxExpectedVersionLength = 64
+
+// ValidateExpectedVersion checks an expected version. Empty is valid and disables the check.
+func ValidateExpectedVersion(version string) error {
+	if len(version) > maxExpectedVersionLength {
+		return fmt.Errorf("expected version must be at most %d characters", maxExpectedVersionLength)
+	}
+	if strings.ContainsFunc(version, unicode.IsSpace) {
+		return fmt.Errorf("invalid expected version %q", version)
+	}
+	return nil
+}
+
+// isVersionWildcard reports whether a segment of an expected version matches any value
+func isVersionWildcard(segment string) bool {
+	return segment == "x" || segment == "X" || segment == "*"
+}
+
+// VersionMatches reports whether version satisfies expected. Dot separated segments of
+// expected that are "x" or "*" match any value, a trailing one also matches any further
+// segments, so "4.0.x" accepts 4.0.10.2544. A leading "v" is ignored on both sides.
+func VersionMatches(expected, version string) bool {
+	expected = strings.TrimPrefix(strings.TrimSpace(expected), "v")
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	want := strings.Split(expected, ".")
+	got := strings.Split(version, ".")
+	for i, segment := range want {
+		if i >= len(got) {
+			return false
+		}
+		if isVersionWildcard(segment) {
+			if i == len(want)-1 {
+				return true
+			}
+			continue
+		}
+		if segment != got[i] {
+			return false
+		}
+	}
+	return len(want) == len(got)
+}
+
+// CheckExpectedVersion downgrades a healthy result to warning when the version the check
+// detected is not the expected version. Results without a version are left alone.
+func (s *ServiceConfiguration) CheckExpectedVersion(health *ServiceHealth) {
+	if s.ExpectedVersion == "" || health.Version == "" || VersionMatches(s.ExpectedVersion, health.Version) {
+		return
+	}
+
+	if health.Details == nil {
+		health.Details = make(map[string]interface{})
+	}
+	health.Details["versionMismatch"] = map[string]interface{}{
+		"expected": s.ExpectedVersion,
+		"actual":   health.Version,
+	}
+
+	if health.Status == StatusOnline {
+		health.Status = StatusWarning
+	}
+	if health.Detail == "" {
+		health.Detail = fmt.Sprintf("Version mismatch: running %s, expected %s", health.Version, s.ExpectedVersion)
+	}
+}
+
+// LoadClientCertificate loads a client certificate and its key, each given as PEM. It returns
+// nil when neither is set. File paths aren't read, the values come from the API and a path
+// would let its callers probe the files of the server.
+func LoadClientCertificate(cert, key string) (*tls.Certificate, error) {
+	if cert == "" && key == "" {
+		return nil, nil
+	}
+	if cert == "" || key == "" {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if !isPEM(cert) || !isPEM(key) {
+		return nil, errors.New("client certificate and key must be PEM encoded")
+	}
+
+	pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return nil, errors.New("invalid client certificate or key")
+	}
+	return &pair, nil
+}
+
+// isPEM reports whether value holds PEM data
+func isPEM(value string) bool {
+	return strings.Contains(value, "-----BEGIN")
+}
+
+// ValidateClientCertificate checks that a client certificate and key can be loaded and
+// belong together. Both empty is valid and means no client certificate.
+func ValidateClientCertificate(cert, key string) error {
+	_, err := LoadClientCertificate(cert, key)
+	return err
+}
+
+// ValidateDatabaseURL checks the URL of a database service. It is set by the database
+// service, so a URL it can't connect with is refused when the service is saved.
+var ValidateDatabaseURL func(raw string) error
+
+// Validate checks the fields of the configuration, and the URL of database services
+func (s *ServiceConfiguration) Validate() error {
+	if ValidateDatabaseURL != nil && strings.HasPrefix(s.InstanceID, "database-") {
+		if err := ValidateDatabaseURL(s.URL); err != nil {
+			return err
+		}
+	}
+	if err := ValidateAPIVersion(s.APIVersion); err != nil {
+		return err
+	}
+	if err := s.ValidateAppearance(); err != nil {
+		return err
+	}
+	if err := ValidateCheckInterval(s.CheckIntervalSeconds); err != nil {
+		return err
+	}
+	if err := ValidateHTTPCheck(s.Method, s.ExpectedStatus); err != nil {
+		return err
+	}
+	if err := ValidateAuthHeaderName(s.AuthHeaderName); err != nil {
+		return err
+	}
+	if err := ValidateNotes(s.Notes); err != nil {
+		return err
+	}
+	if err := ValidateExpectedVersion(s.ExpectedVersion); err != nil {
+		return err
+	}
+	return ValidateClientCertificate(s.ClientCert, s.ClientKey)
+}
+
+// ClientCertificateSetter is implemented by services that can present a client certificate
+type ClientCertificateSetter interface {
+	SetClientCertificate(cert *tls.Certificate)
+}
+
+// AuthHeaderSetter is implemented by services that send an API key in a request header
+type AuthHeaderSetter interface {
+	SetAuthHeader(name string)
+}
+
+// APIVersionSetter is implemented by services whose API paths depend on the configured api_version
+type APIVersionSetter interface {
+	SetAPIVersion(version string)
+}
+
+// Configure applies per-instance settings of the configuration to a service created by the
+// registry, or to the request client of one
+func (s *ServiceConfiguration) Configure(checker interface{}) {
+	if setter, ok := checker.(APIVersionSetter); ok {
+		setter.SetAPIVersion(s.APIVersion)
+	}
+	if setter, ok := checker.(HTTPCheckSetter); ok {
+		// Validated when the configuration is saved
+		codes, _ := ParseExpectedStatus(s.ExpectedStatus)
+		setter.SetHTTPCheck(strings.ToUpper(s.Method), codes)
+	}
+	if setter, ok := checker.(AuthHeaderSetter); ok {
+		setter.SetAuthHeader(s.AuthHeaderName)
+	}
+	if setter, ok := checker.(ClientCertificateSetter); ok {
+		// Validated when the configuration is saved, should the files have gone missing
+		// since, the check fails the TLS handshake instead
+		cert, _ := LoadClientCertificate(s.ClientCert, s.ClientKey)
+		setter.SetClientCertificate(cert)
+	}
+}
+
+// LinkURL returns the URL users should open for the service, the access URL when one is
+// set and the URL dashbrr polls otherwise. The source is "accessUrl" or "url".
+func (s *ServiceConfiguration) LinkURL() (link, source string) {
+	if s.AccessURL != "" {
+		return strings.TrimRight(s.AccessURL, "/"), "accessUrl"
+	}
+	return strings.TrimRight(s.URL, "/"), "url"
+}
+
+// IsMuted reports whether the service is muted at the given time
+func (s *ServiceConfiguration) IsMuted(now time.Time) bool {
+	return s.MutedUntil != nil && now.Before(*s.MutedUntil)
+}
+
+// NormalizeTags lower-cases and trims tags, dropping empty and duplicate entries.
+// Commas are the storage separator, so they split a tag in two.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		for _, part := range strings.Split(tag, ",") {
+			part = strings.ToLower(strings.TrimSpace(part))
+			if part == "" || seen[part] {
+				continue
+			}
+			seen[part] = true
+			normalized = append(normalized, part)
+		}
+	}
+
+	return normalized
 }
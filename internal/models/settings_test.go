@@ -0,0 +1,233 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateAppearance(t *testing.T) {
+	tests := []struct {
+		color, icon string
+		valid       bool
+	}{
+		{"", "", true},
+		{"#fff", "", true},
+		{"#E5484D", "radarr-4k", true},
+		{"#e5484d80", "https://example.com/icon.png", true},
+		{"red", "", false},
+		{"#12345", "", false},
+		{"", "javascript:alert(1)", false},
+		{"", "../icons/radarr.png", false},
+	}
+
+	for _, tt := range tests {
+		config := ServiceConfiguration{Color: tt.color, Icon: tt.icon}
+		if err := config.ValidateAppearance(); (err == nil) != tt.valid {
+			t.Errorf("ValidateAppearance(%q, %q) = %v, want valid %t", tt.color, tt.icon, err, tt.valid)
+		}
+	}
+}
+
+func TestServiceConfiguration_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ServiceConfiguration
+		valid  bool
+	}{
+		{"empty", ServiceConfiguration{}, true},
+		{"all set", ServiceConfiguration{APIVersion: "v4", Color: "#fff", CheckIntervalSeconds: 60, Method: "HEAD", ExpectedStatus: "200", AuthHeaderName: "X-Api-Key", ExpectedVersion: "4.0"}, true},
+		{"api version", ServiceConfiguration{APIVersion: "v9"}, false},
+		{"color", ServiceConfiguration{Color: "red"}, false},
+		{"check interval", ServiceConfiguration{CheckIntervalSeconds: 10}, false},
+		{"method", ServiceConfiguration{Method: "DELETE"}, false},
+		{"auth header", ServiceConfiguration{AuthHeaderName: "X Api Key"}, false},
+		{"client key without certificate", ServiceConfiguration{ClientKey: "key"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate() = %v, want valid %t", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateCheckInterval(t *testing.T) {
+	for seconds, valid := range map[int]bool{0: true, 30: true, 300: true, 1800: true, -1: false, 10: false, 3600: false} {
+		if err := ValidateCheckInterval(seconds); (err == nil) != valid {
+			t.Errorf("ValidateCheckInterval(%d) = %v, want valid %t", seconds, err, valid)
+		}
+	}
+}
+
+func TestValidateHTTPCheck(t *testing.T) {
+	tests := []struct {
+		method, expectedStatus string
+		valid                  bool
+	}{
+		{"", "", true},
+		{"head", "", true},
+		{"POST", "200, 204", true},
+		{"DELETE", "", false},
+		{"", "2xx", false},
+		{"", "200,999", false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateHTTPCheck(tt.method, tt.expectedStatus); (err == nil) != tt.valid {
+			t.Errorf("ValidateHTTPCheck(%q, %q) = %v, want valid %t", tt.method, tt.expectedStatus, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateNotes(t *testing.T) {
+	for notes, valid := range map[string]bool{
+		"":                                    true,
+		"4K library, restart weekly":          true,
+		strings.Repeat("é", MaxNotesLength):   true,
+		strings.Repeat("a", MaxNotesLength+1): false,
+	} {
+		if err := ValidateNotes(notes); (err == nil) != valid {
+			t.Errorf("ValidateNotes(%d characters) = %v, want valid %t", len([]rune(notes)), err, valid)
+		}
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		expected, version string
+		want              bool
+	}{
+		{"4.0.x", "4.0.10.2544", true},
+		{"4.0.*", "4.0.0", true},
+		{"v4.0.x", "4.0.10", true},
+		{"4.0.x", "4.1.0.1", false},
+		{"4.0.x", "4", false},
+		{"4.x.2", "4.7.2", true},
+		{"4.x.2", "4.7.3", false},
+		{"1.2.3", "v1.2.3", true},
+		{"1.2.3", "1.2.3.4", false},
+		{"1.2.3", "1.2.4", false},
+	}
+	for _, tt := range tests {
+		if got := VersionMatches(tt.expected, tt.version); got != tt.want {
+			t.Errorf("VersionMatches(%q, %q) = %t, want %t", tt.expected, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestCheckExpectedVersion(t *testing.T) {
+	service := ServiceConfiguration{ExpectedVersion: "4.0.x"}
+
+	health := ServiceHealth{Status: StatusOnline, Version: "4.1.0.1"}
+	service.CheckExpectedVersion(&health)
+	if health.Status != StatusWarning || health.Detail == "" {
+		t.Errorf("Expected a version mismatch warning, got %q (%q)", health.Status, health.Detail)
+	}
+	if _, ok := health.Details["versionMismatch"]; !ok {
+		t.Errorf("Expected the mismatch in the details, got %v", health.Details)
+	}
+
+	health = ServiceHealth{Status: StatusOnline, Version: "4.0.10.2544"}
+	service.CheckExpectedVersion(&health)
+	if health.Status != StatusOnline || health.Details != nil {
+		t.Errorf("Expected a matching version to stay online, got %q %v", health.Status, health.Details)
+	}
+
+	health = ServiceHealth{Status: StatusOffline, Version: "4.1.0.1"}
+	service.CheckExpectedVersion(&health)
+	if health.Status != StatusOffline {
+		t.Errorf("Expected an offline service to stay offline, got %q", health.Status)
+	}
+
+	if err := ValidateExpectedVersion("4.0 x"); err == nil {
+		t.Error("Expected an expected version with spaces to be rejected")
+	}
+}
+
+func TestValidateAuthHeaderName(t *testing.T) {
+	for name, valid := range map[string]bool{"": true, "X-Api-Key": true, "Authorization": true, "X Api Key": false, "X-Api-Key:": false} {
+		if err := ValidateAuthHeaderName(name); (err == nil) != valid {
+			t.Errorf("ValidateAuthHeaderName(%q) = %v, want valid %t", name, err, valid)
+		}
+	}
+}
+
+// testClientCertificate returns a self-signed certificate and its key as PEM
+func testClientCertificate(t *testing.T) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dashbrr"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	certPEM, keyPEM := testClientCertificate(t)
+	otherCert, _ := testClientCertificate(t)
+
+	// Paths are refused, whether the file exists or not
+	keyPath := filepath.Join(t.TempDir(), "client.key")
+	if err := os.WriteFile(keyPath, []byte(keyPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, cert, key string
+		valid           bool
+	}{
+		{"none", "", "", true},
+		{"pem", certPEM, keyPEM, true},
+		{"missing key", certPEM, "", false},
+		{"path", certPEM, keyPath, false},
+		{"missing file", "/nonexistent/client.crt", keyPEM, false},
+		{"mismatched pair", otherCert, keyPEM, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := LoadClientCertificate(tt.cert, tt.key)
+			if (err == nil) != tt.valid {
+				t.Fatalf("LoadClientCertificate() = %v, want valid %t", err, tt.valid)
+			}
+			if tt.valid && (cert == nil) != (tt.cert == "") {
+				t.Errorf("LoadClientCertificate() returned certificate %v for %q", cert != nil, tt.name)
+			}
+			// Nothing about the paths is revealed
+			if err != nil && (strings.Contains(err.Error(), "client.") || strings.Contains(err.Error(), "file")) {
+				t.Errorf("LoadClientCertificate() = %v, want a generic error", err)
+			}
+		})
+	}
+}
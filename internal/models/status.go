@@ -0,0 +1,88 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ServiceStatus is the health state reported for a service
+type ServiceStatus string
+
+const (
+	StatusOnline       ServiceStatus = "online"
+	StatusOffline      ServiceStatus = "offline"
+	StatusWarning      ServiceStatus = "warning"
+	StatusError        ServiceStatus = "error"
+	StatusPending      ServiceStatus = "pending"
+	StatusChecking     ServiceStatus = "checking"
+	StatusUnknown      ServiceStatus = "unknown"
+	StatusUnconfigured ServiceStatus = "unconfigured"
+	StatusDisabled     ServiceStatus = "disabled"
+	StatusUnauthorized ServiceStatus = "unauthorized"
+)
+
+// statusAliases maps legacy and upstream spellings onto a known status
+var statusAliases = map[string]ServiceStatus{
+	"ok":        StatusOnline,
+	"healthy":   StatusOnline,
+	"up":        StatusOnline,
+	"unhealthy": StatusOffline,
+	"down":      StatusOffline,
+}
+
+// IsValid reports whether s is one of the known statuses
+func (s ServiceStatus) IsValid() bool {
+	switch s {
+	case StatusOnline, StatusOffline, StatusWarning, StatusError, StatusPending,
+		StatusChecking, StatusUnknown, StatusUnconfigured, StatusDisabled, StatusUnauthorized:
+		return true
+	}
+	return false
+}
+
+// IsDown reports whether the status means the service can't be used
+func (s ServiceStatus) IsDown() bool {
+	return s == StatusOffline || s == StatusError || s == StatusUnauthorized
+}
+
+// ParseServiceStatus normalizes a status string, mapping aliases such as "ok"
+// to their canonical status. Unrecognized values become StatusUnknown.
+func ParseServiceStatus(value string) ServiceStatus {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+
+	if status := ServiceStatus(normalized); status.IsValid() {
+		return status
+	}
+	if status, ok := statusAliases[normalized]; ok {
+		return status
+	}
+	return StatusUnknown
+}
+
+// StatusForResponseCode maps the status code of an upstream response onto a status.
+// Rejected credentials (401/403) are reported as StatusUnauthorized rather than as an
+// outage, so the user is pointed at the API key instead of the service.
+func StatusForResponseCode(statusCode int) ServiceStatus {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return StatusUnauthorized
+	case statusCode >= 200 && statusCode < 300:
+		return StatusOnline
+	}
+	return StatusError
+}
+
+// UnmarshalJSON normalizes statuses on decode, so health results cached before
+// the statuses were unified (e.g. "ok") are read back as their canonical value.
+func (s *ServiceStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = ParseServiceStatus(value)
+	return nil
+}
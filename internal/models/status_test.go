@@ -0,0 +1,85 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestParseServiceStatus(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ServiceStatus
+	}{
+		{"online", StatusOnline},
+		{"ok", StatusOnline},
+		{"OK", StatusOnline},
+		{"healthy", StatusOnline},
+		{" Warning ", StatusWarning},
+		{"unhealthy", StatusOffline},
+		{"disabled", StatusDisabled},
+		{"", StatusUnknown},
+		{"bogus", StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseServiceStatus(tt.input); got != tt.expected {
+			t.Errorf("ParseServiceStatus(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestServiceStatusIsValid(t *testing.T) {
+	if !StatusChecking.IsValid() {
+		t.Error("Expected checking to be valid")
+	}
+	if ServiceStatus("ok").IsValid() {
+		t.Error("Expected ok to be invalid, it is an alias")
+	}
+}
+
+func TestStatusForResponseCode(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected ServiceStatus
+	}{
+		{http.StatusOK, StatusOnline},
+		{http.StatusNoContent, StatusOnline},
+		{http.StatusUnauthorized, StatusUnauthorized},
+		{http.StatusForbidden, StatusUnauthorized},
+		{http.StatusNotFound, StatusError},
+		{http.StatusServiceUnavailable, StatusError},
+	}
+
+	for _, tt := range tests {
+		if got := StatusForResponseCode(tt.code); got != tt.expected {
+			t.Errorf("StatusForResponseCode(%d) = %q, expected %q", tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestServiceHealthUnmarshalNormalizesStatus(t *testing.T) {
+	// Health results cached before the statuses were unified used "ok"
+	var health ServiceHealth
+	if err := json.Unmarshal([]byte(`{"status":"ok","serviceId":"sonarr-1"}`), &health); err != nil {
+		t.Fatalf("Failed to unmarshal health: %v", err)
+	}
+	if health.Status != StatusOnline {
+		t.Errorf("Expected status %q, got %q", StatusOnline, health.Status)
+	}
+
+	data, err := json.Marshal(health)
+	if err != nil {
+		t.Fatalf("Failed to marshal health: %v", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Failed to unmarshal raw health: %v", err)
+	}
+	if raw["status"] != "online" {
+		t.Errorf("Expected status to marshal as online, got %v", raw["status"])
+	}
+}
@@ -4,21 +4,17 @@
 package arr
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/core"
 )
 
-// Global HTTP client pool
-var httpClients sync.Map
-
 // Custom error type for *arr services
 type ErrArr struct {
 	Service  string // Service name (e.g., "radarr", "sonarr")
@@ -45,72 +41,29 @@ type SystemStatusResponse struct {
 	Version string `json:"version"`
 }
 
-// getHTTPClient returns a client with the specified timeout
-func getHTTPClient(timeout time.Duration) *http.Client {
-	// Use the timeout as the key
-	if client, ok := httpClients.Load(timeout); ok {
-		return client.(*http.Client)
-	}
-
-	// Create new client if not found
-	client := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 10,
-			IdleConnTimeout:     90 * time.Second,
-			DisableKeepAlives:   false,
-		},
-		Timeout: timeout,
+// APIURL builds the URL of an API endpoint for the configured API path version,
+// e.g. APIURL("http://sonarr:8989/", "v3", "/queue") is "http://sonarr:8989/api/v3/queue"
+func APIURL(baseURL, apiVersion, path string) string {
+	if apiVersion == "" {
+		apiVersion = models.DefaultArrAPIVersion
 	}
-
-	// Store in pool
-	httpClients.Store(timeout, client)
-	return client
+	return strings.TrimRight(baseURL, "/") + "/api/" + apiVersion + path
 }
 
-// MakeArrRequest is a helper function to make requests with proper headers
-func MakeArrRequest(ctx context.Context, method, url, apiKey string, body []byte) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
+// MakeArrRequest makes a request with proper headers through the pooled client of
+// ServiceCore. client carries the auth header override and client certificate of the
+// instance, nil sends the API key in X-Api-Key without a certificate.
+func MakeArrRequest(ctx context.Context, method, url, apiKey string, client *core.ServiceCore, body []byte) (*http.Response, error) {
+	if client == nil {
+		client = &core.ServiceCore{}
 	}
-
-	// Set headers correctly
-	req.Header.Set("X-Api-Key", apiKey)
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Content-Type", "application/json")
-
-	// Get timeout from context or use default
-	timeout := core.DefaultTimeout
-	if deadline, ok := ctx.Deadline(); ok {
-		timeout = time.Until(deadline)
-	}
-
-	// Track request start time
-	startTime := time.Now()
-
-	// Get client with appropriate timeout
-	client := getHTTPClient(timeout)
-	resp, err := client.Do(req)
-	if err != nil {
-		if err == context.Canceled {
-			return nil, fmt.Errorf("request canceled: %w", err)
-		}
-		return nil, err
-	}
-
-	if resp == nil {
-		return nil, fmt.Errorf("received nil response from server")
-	}
-
-	// Store the response time in milliseconds
-	resp.Header.Set("X-Response-Time", fmt.Sprintf("%d", time.Since(startTime).Milliseconds()))
-
-	return resp, nil
+	return client.MakeRequestWithBody(ctx, method, url, body, map[string]string{
+		"X-Api-Key": apiKey,
+	})
 }
 
 // GetArrSystemStatus provides a common implementation for getting system status
-func GetArrSystemStatus(service, url, apiKey string, getVersionFromCache func(string) string, cacheVersion func(string, string, time.Duration) error) (string, error) {
+func GetArrSystemStatus(service, url, apiKey, apiVersion string, client *core.ServiceCore, getVersionFromCache func(string) string, cacheVersion func(string, string, time.Duration) error) (string, error) {
 	if url == "" {
 		return "", &ErrArr{Service: service, Op: "get_system_status", Err: fmt.Errorf("URL is required")}
 	}
@@ -120,11 +73,11 @@ func GetArrSystemStatus(service, url, apiKey string, getVersionFromCache func(st
 		return version, nil
 	}
 
-	statusURL := fmt.Sprintf("%s/api/v3/system/status", strings.TrimRight(url, "/"))
+	statusURL := APIURL(url, apiVersion, "/system/status")
 	ctx, cancel := context.WithTimeout(context.Background(), core.DefaultTimeout)
 	defer cancel()
 
-	resp, err := MakeArrRequest(ctx, http.MethodGet, statusURL, apiKey, nil)
+	resp, err := MakeArrRequest(ctx, http.MethodGet, statusURL, apiKey, client, nil)
 	if err != nil {
 		return "", &ErrArr{Service: service, Op: "get_system_status", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
@@ -149,16 +102,16 @@ func GetArrSystemStatus(service, url, apiKey string, getVersionFromCache func(st
 }
 
 // CheckArrForUpdates provides a common implementation for checking updates
-func CheckArrForUpdates(service, url, apiKey string) (bool, error) {
+func CheckArrForUpdates(service, url, apiKey, apiVersion string, client *core.ServiceCore) (bool, error) {
 	if url == "" {
 		return false, &ErrArr{Service: service, Op: "check_for_updates", Err: fmt.Errorf("URL is required")}
 	}
 
-	updateURL := fmt.Sprintf("%s/api/v3/update", strings.TrimRight(url, "/"))
+	updateURL := APIURL(url, apiVersion, "/update")
 	ctx, cancel := context.WithTimeout(context.Background(), core.DefaultTimeout)
 	defer cancel()
 
-	resp, err := MakeArrRequest(ctx, http.MethodGet, updateURL, apiKey, nil)
+	resp, err := MakeArrRequest(ctx, http.MethodGet, updateURL, apiKey, client, nil)
 	if err != nil {
 		return false, &ErrArr{Service: service, Op: "check_for_updates", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
@@ -0,0 +1,33 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/services/core"
+)
+
+func TestMakeArrRequest(t *testing.T) {
+	var method, contentType, apiKey, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, contentType, apiKey, body = r.Method, r.Header.Get("Content-Type"), r.Header.Get("X-Custom-Key"), string(data)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := MakeArrRequest(context.Background(), http.MethodPost, server.URL, "key", &core.ServiceCore{AuthHeader: "X-Custom-Key"}, []byte(`{"name":"RefreshMonitoredDownloads"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if method != http.MethodPost || contentType != "application/json" || apiKey != "key" || body != `{"name":"RefreshMonitoredDownloads"}` {
+		t.Errorf("Unexpected request %s %q %q %q", method, contentType, apiKey, body)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// ErrTruncatedResponse is returned by DecodeJSON when the response ends mid-document,
+// e.g. because the connection dropped while a large queue was being sent
+var ErrTruncatedResponse = errors.New("truncated response")
+
+var (
+	maxInt64 = big.NewFloat(math.MaxInt64)
+	minInt64 = big.NewFloat(math.MinInt64)
+)
+
+// DecodeJSON decodes an *arr API response into v. The *arrs serialize large sizes and
+// counts in ways a plain decode into int64 rejects, multi-terabyte sizes in exponent
+// notation such as 1.2345678901234E+13 and cumulative counters beyond the int64 range,
+// and a single such value would otherwise fail the whole response. Those numbers are
+// normalized, integral exponent notation to plain integers and out of range integers
+// clamped to the int64 range, and the response decoded again.
+func DecodeJSON(body []byte, v interface{}) error {
+	err := json.Unmarshal(body, v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset >= int64(len(bytes.TrimRight(body, " \t\r\n"))) {
+		return fmt.Errorf("%w: %v", ErrTruncatedResponse, err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || !strings.HasPrefix(typeErr.Value, "number") {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalized, err := json.Marshal(normalizeNumbers(raw))
+	if err != nil {
+		return err
+	}
+
+	// Unmarshal keeps what it decoded before the type error, start over from the zero value
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	}
+	return json.Unmarshal(normalized, v)
+}
+
+// normalizeNumbers rewrites the numbers in a UseNumber decoded document so they decode
+// into int64 fields where that is possible without changing their value, out of range
+// integers are clamped. Fractional numbers are left alone.
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return v
+		}
+		f, _, err := big.ParseFloat(v.String(), 10, 256, big.ToNearestEven)
+		if err != nil || !f.IsInt() {
+			return v
+		}
+		switch {
+		case f.Cmp(maxInt64) > 0:
+			return json.Number("9223372036854775807")
+		case f.Cmp(minInt64) < 0:
+			return json.Number("-9223372036854775808")
+		}
+		i, _ := f.Int(nil)
+		return json.Number(i.String())
+	}
+	return value
+}
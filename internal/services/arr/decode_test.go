@@ -0,0 +1,57 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	// A 12 TB season pack, once as a plain integer and once the way some *arr
+	// versions serialize it, in exponent notation
+	body := []byte(`{"page":1,"totalRecords":2,"records":[
+		{"id":1,"title":"Show.S01.2160p","size":12345678901234,"sizeleft":6172839450617},
+		{"id":2,"title":"Show.S02.2160p","size":1.2345678901234E+13,"sizeleft":0}
+	]}`)
+
+	var queue types.SonarrQueueResponse
+	if err := DecodeJSON(body, &queue); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(queue.Records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(queue.Records))
+	}
+	for _, record := range queue.Records {
+		if record.Size != 12345678901234 {
+			t.Errorf("Record %d: expected size 12345678901234, got %d", record.ID, record.Size)
+		}
+	}
+	if queue.Records[0].SizeLeft != 6172839450617 {
+		t.Errorf("Expected sizeleft 6172839450617, got %d", queue.Records[0].SizeLeft)
+	}
+
+	// Counters beyond the int64 range are clamped rather than failing the response
+	var stats types.ProwlarrStatsResponse
+	if err := DecodeJSON([]byte(`{"grabCount":18446744073709551615,"failCount":3,"indexerCount":12}`), &stats); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.GrabCount != math.MaxInt64 || stats.FailCount != 3 || stats.IndexerCount != 12 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Fractional values can't be made to fit and still fail
+	if err := DecodeJSON([]byte(`{"grabCount":1.5}`), &stats); err == nil {
+		t.Error("Expected an error for a fractional count")
+	}
+
+	// A response cut off mid-document is reported as such
+	err := DecodeJSON(body[:len(body)/2], &queue)
+	if !errors.Is(err, ErrTruncatedResponse) {
+		t.Errorf("Expected ErrTruncatedResponse, got %v", err)
+	}
+}
@@ -8,8 +8,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -19,15 +19,10 @@ import (
 	"github.com/autobrr/dashbrr/internal/services/core"
 )
 
-const (
-	healthCacheDuration = 30 * time.Second
-	arrCachePrefix      = "arr:"
-)
+// updateCheckInterval is how long the result of an update check is reused
+const updateCheckInterval = time.Hour
 
-var (
-	sf singleflight.Group
-	mu sync.RWMutex
-)
+var sf singleflight.Group
 
 // HealthResponse represents a common health check response structure
 type HealthResponse struct {
@@ -44,59 +39,66 @@ type HealthChecker interface {
 	GetHealthEndpoint(baseURL string) string
 }
 
-// ArrHealthCheck provides a common implementation of health checking for *arr services
+// RefreshArrUpdate checks for updates right away instead of waiting for the cached result to
+// expire, and drops the cached version so the next health check picks up an upgrade
+func RefreshArrUpdate(s *core.ServiceCore, url, apiKey string, checker HealthChecker) (bool, error) {
+	s.InvalidateVersion(url)
+
+	hasUpdate, err := checker.CheckForUpdates(url, apiKey)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.CacheVersion(url+":update", strconv.FormatBool(hasUpdate), updateCheckInterval); err != nil {
+		log.Debug().Err(err).Str("url", url).Msg("Failed to cache update status")
+	}
+
+	return hasUpdate, nil
+}
+
+// ArrHealthCheck provides a common implementation of health checking for *arr services.
+// The result always carries the version, from cache when the service can't be reached,
+// and details.arr lists the warnings and errors the service reports about itself.
 func ArrHealthCheck(s *core.ServiceCore, url, apiKey string, checker HealthChecker) (models.ServiceHealth, int) {
+	startTime := time.Now()
+
 	if url == "" {
-		return s.CreateHealthResponse(time.Now(), "error", "URL is required"), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+	if apiKey == "" {
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
-	startTime := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), core.DefaultTimeout)
-	defer cancel()
-
-	// Try to get cached health response
-	cacheKey := arrCachePrefix + "health:" + url
-	var cachedHealth models.ServiceHealth
-	if _, err := s.GetCachedVersion(ctx, cacheKey, "", func(_, _ string) (string, error) {
-		return "", nil // Cache miss, will handle below
-	}); err == nil && cachedHealth.Status != "" {
-		// Refresh cache in background
-		go func() {
-			refreshKey := fmt.Sprintf("refresh:%s", url)
-			_, _, _ = sf.Do(refreshKey, func() (interface{}, error) {
-				return performHealthCheck(ctx, s, url, apiKey, checker)
-			})
-		}()
-		return cachedHealth, http.StatusOK
-	}
-
-	// Use singleflight for health check
-	healthKey := fmt.Sprintf("health:%s", url)
-	result, err, _ := sf.Do(healthKey, func() (interface{}, error) {
+	// Concurrent checks of the same instance share one request
+	result, err, _ := sf.Do("health:"+url, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), core.DefaultTimeout)
+		defer cancel()
 		return performHealthCheck(ctx, s, url, apiKey, checker)
 	})
-
 	if err != nil {
 		log.Error().Err(err).Str("url", url).Msg("Health check failed")
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Health check failed: %v", err)), http.StatusOK
+		extras := map[string]interface{}{}
+		if version := s.GetVersionFromCache(url); version != "" {
+			extras["version"] = version
+			extras["details"] = map[string]interface{}{
+				"arr": map[string]interface{}{"version": version},
+			}
+		}
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Health check failed: %v", err), extras), http.StatusOK
 	}
 
-	health := result.(models.ServiceHealth)
-	return health, http.StatusOK
+	return result.(models.ServiceHealth), http.StatusOK
 }
 
 // performHealthCheck executes the actual health check
 func performHealthCheck(ctx context.Context, s *core.ServiceCore, url, apiKey string, checker HealthChecker) (models.ServiceHealth, error) {
 	startTime := time.Now()
 
-	// Get version synchronously first
-	version := s.GetVersionFromCache(url)
-	if version == "" {
-		var err error
-		version, err = checker.GetSystemStatus(url, apiKey)
-		if err == nil {
-			s.CacheVersion(url, version, time.Hour)
-		}
+	// GetSystemStatus caches the version itself
+	version, err := checker.GetSystemStatus(url, apiKey)
+	if err != nil {
+		log.Debug().Err(err).Str("url", url).Msg("Failed to fetch version")
+		version = s.GetVersionFromCache(url)
 	}
 
 	// Make health check request
@@ -114,16 +116,10 @@ func performHealthCheck(ctx context.Context, s *core.ServiceCore, url, apiKey st
 	}
 
 	defer resp.Body.Close()
-	body, err := s.ReadBody(resp)
-	if err != nil {
-		return models.ServiceHealth{}, fmt.Errorf("failed to read response: %v", err)
-	}
 
-	// Get response time
-	respTimeStr := resp.Header.Get("X-Response-Time")
-	var respTime time.Duration
-	if respTimeStr != "" {
-		respTime, _ = time.ParseDuration(respTimeStr)
+	// ReadBody fails on error status codes, so rejected credentials are handled first
+	if models.StatusForResponseCode(resp.StatusCode) == models.StatusUnauthorized {
+		return s.CreateUnauthorizedResponse(startTime, resp.StatusCode), nil
 	}
 
 	// Handle error status codes
@@ -131,67 +127,129 @@ func performHealthCheck(ctx context.Context, s *core.ServiceCore, url, apiKey st
 		statusText := http.StatusText(resp.StatusCode)
 		switch resp.StatusCode {
 		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
-			return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Service is temporarily unavailable (%d %s)", resp.StatusCode, statusText)), nil
-		case http.StatusUnauthorized:
-			return s.CreateHealthResponse(startTime, "error", "Invalid API key"), nil
-		case http.StatusForbidden:
-			return s.CreateHealthResponse(startTime, "error", "Access forbidden"), nil
+			return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Service is temporarily unavailable (%d %s)", resp.StatusCode, statusText)), nil
 		case http.StatusNotFound:
-			return s.CreateHealthResponse(startTime, "error", "Service endpoint not found"), nil
+			return s.CreateHealthResponse(startTime, models.StatusError, "Service endpoint not found"), nil
 		default:
-			return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Server returned %s (%d)", statusText, resp.StatusCode)), nil
+			return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Server returned %s (%d)", statusText, resp.StatusCode)), nil
 		}
 	}
 
-	// Process health response
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return models.ServiceHealth{}, fmt.Errorf("failed to read response: %v", err)
+	}
+
 	var healthIssues []HealthResponse
 	if err := json.Unmarshal(body, &healthIssues); err != nil {
 		return models.ServiceHealth{}, fmt.Errorf("failed to parse response: %v", err)
 	}
 
-	// Build response
+	// MakeRequestWithContext reports the response time in milliseconds
+	respTime, _ := strconv.ParseInt(resp.Header.Get("X-Response-Time"), 10, 64)
+
+	// The update check is slow, report the last result and refresh it in the background
+	updateKey := url + ":update"
+	go func() {
+		_, _, _ = sf.Do("update:"+url, func() (interface{}, error) {
+			hasUpdate, err := checker.CheckForUpdates(url, apiKey)
+			if err == nil {
+				s.CacheVersion(updateKey, strconv.FormatBool(hasUpdate), updateCheckInterval)
+			}
+			return nil, err
+		})
+	}()
+
 	extras := map[string]interface{}{
-		"responseTime": respTime.Milliseconds(),
+		"responseTime":    respTime,
+		"updateAvailable": s.GetVersionFromCache(updateKey) == "true",
+		"details": map[string]interface{}{
+			"arr": healthDetails(version, healthIssues),
+		},
 	}
-
-	// Set version in extras
 	if version != "" {
 		extras["version"] = version
 	}
 
-	// Check for updates in background
-	go func() {
-		if hasUpdate, err := checker.CheckForUpdates(url, apiKey); err == nil && hasUpdate {
-			updateKey := fmt.Sprintf("%s:update", url)
-			s.CacheVersion(updateKey, "true", time.Hour)
-			extras["updateAvailable"] = true
-		}
-	}()
+	message := "Healthy"
+	if warnings := issueMessages(healthIssues); len(warnings) > 0 {
+		message = strings.Join(warnings, "\n\n")
+	}
 
-	// Determine status and message
-	status := "online"
-	var warnings []string
-	for _, issue := range healthIssues {
+	return s.CreateHealthResponse(startTime, IssueStatus(healthIssues), message, extras), nil
+}
+
+// reportedIssues returns the warnings and errors among the issues a service reports,
+// notices are left out
+func reportedIssues(issues []HealthResponse) []HealthResponse {
+	reported := []HealthResponse{}
+	for _, issue := range issues {
 		if issue.Type == "warning" || issue.Type == "error" {
-			warnings = append(warnings, fmt.Sprintf("[%s] %s", issue.Source, issue.Message))
-			status = "warning"
+			reported = append(reported, issue)
 		}
 	}
+	return reported
+}
 
-	message := "Healthy"
-	if len(warnings) > 0 {
-		message = strings.Join(warnings, "\n\n")
+// issueMessages formats the warnings and errors a service reports for the health message
+func issueMessages(issues []HealthResponse) []string {
+	var messages []string
+	for _, issue := range reportedIssues(issues) {
+		messages = append(messages, fmt.Sprintf("[%s] %s", issue.Source, issue.Message))
 	}
+	return messages
+}
 
-	health := s.CreateHealthResponse(startTime, status, message, extras)
+// healthDetails builds details.arr of a health result. The issue count and list are
+// always sent, so they clear once the issues are resolved.
+func healthDetails(version string, issues []HealthResponse) map[string]interface{} {
+	reported := reportedIssues(issues)
+	details := map[string]interface{}{
+		"healthIssues": len(reported),
+		"issues":       reported,
+	}
+	if version != "" {
+		details["version"] = version
+	}
+	return details
+}
 
-	// Cache the health response
-	if status != "error" {
-		cacheKey := arrCachePrefix + "health:" + url
-		if err := s.CacheVersion(cacheKey, fmt.Sprintf("%+v", health), healthCacheDuration); err != nil {
-			log.Warn().Err(err).Str("url", url).Msg("Failed to cache health response")
+// IssueStatus maps the issues a service reports about itself onto a status. Any
+// error issue makes the service StatusError, warnings make it StatusWarning.
+func IssueStatus(issues []HealthResponse) models.ServiceStatus {
+	status := models.StatusOnline
+	for _, issue := range issues {
+		switch issue.Type {
+		case "error":
+			return models.StatusError
+		case "warning":
+			status = models.StatusWarning
 		}
 	}
+	return status
+}
+
+// GetHealthIssues fetches the issues the service reports about itself, such as an
+// unavailable indexer or download client
+func GetHealthIssues(ctx context.Context, service, url, apiKey string, client *core.ServiceCore, checker HealthChecker) ([]HealthResponse, error) {
+	if url == "" {
+		return nil, &ErrArr{Service: service, Op: "get_health_issues", Err: fmt.Errorf("URL is required")}
+	}
+
+	resp, err := MakeArrRequest(ctx, http.MethodGet, checker.GetHealthEndpoint(url), apiKey, client, nil)
+	if err != nil {
+		return nil, &ErrArr{Service: service, Op: "get_health_issues", Err: fmt.Errorf("failed to make request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErrArr{Service: service, Op: "get_health_issues", HttpCode: resp.StatusCode}
+	}
+
+	issues := []HealthResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, &ErrArr{Service: service, Op: "get_health_issues", Err: fmt.Errorf("failed to parse response: %w", err)}
+	}
 
-	return health, nil
+	return issues, nil
 }
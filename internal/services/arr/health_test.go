@@ -0,0 +1,128 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/core"
+)
+
+type testChecker struct {
+	core.ServiceCore
+}
+
+func (c *testChecker) GetSystemStatus(url, apiKey string) (string, error) {
+	return GetArrSystemStatus("sonarr", url, apiKey, "", &c.ServiceCore, c.GetVersionFromCache, c.CacheVersion)
+}
+
+func (c *testChecker) CheckForUpdates(url, apiKey string) (bool, error) {
+	return CheckArrForUpdates("sonarr", url, apiKey, "", &c.ServiceCore)
+}
+
+func (c *testChecker) GetHealthEndpoint(baseURL string) string {
+	return APIURL(baseURL, "", "/health")
+}
+
+// newTestServer serves a system status with version 4.0.0 and the given health issues
+func newTestServer(t *testing.T, issues string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/system/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"4.0.0"}`))
+	})
+	mux.HandleFunc("/api/v3/health", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(issues))
+	})
+	mux.HandleFunc("/api/v3/update", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func arrDetails(t *testing.T, health models.ServiceHealth) map[string]interface{} {
+	t.Helper()
+
+	details, ok := health.Details["arr"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected details.arr, got %+v", health.Details)
+	}
+	return details
+}
+
+func TestArrHealthCheckHealthy(t *testing.T) {
+	server := newTestServer(t, `[{"source":"IndexerCheck","type":"notice","message":"Just so you know"}]`)
+	checker := &testChecker{}
+
+	health, statusCode := ArrHealthCheck(&checker.ServiceCore, server.URL, "key", checker)
+	if statusCode != http.StatusOK || health.Status != models.StatusOnline {
+		t.Fatalf("Expected an online service, got %d %+v", statusCode, health)
+	}
+	if health.Message != "Healthy" || health.Version != "4.0.0" {
+		t.Errorf("Expected a healthy message and version 4.0.0, got %q and %q", health.Message, health.Version)
+	}
+
+	details := arrDetails(t, health)
+	if details["healthIssues"] != 0 || details["version"] != "4.0.0" {
+		t.Errorf("Expected no issues and the version in the details, got %+v", details)
+	}
+	if issues, ok := details["issues"].([]HealthResponse); !ok || len(issues) != 0 {
+		t.Errorf("Expected an empty issue list, got %+v", details["issues"])
+	}
+}
+
+func TestArrHealthCheckWarnings(t *testing.T) {
+	server := newTestServer(t, `[
+		{"source":"IndexerStatusCheck","type":"warning","message":"Indexers unavailable"},
+		{"source":"UpdateCheck","type":"notice","message":"Update available"}
+	]`)
+	checker := &testChecker{}
+
+	health, _ := ArrHealthCheck(&checker.ServiceCore, server.URL, "key", checker)
+	if health.Status != models.StatusWarning {
+		t.Fatalf("Expected a warning, got %+v", health)
+	}
+	if health.Message != "[IndexerStatusCheck] Indexers unavailable" {
+		t.Errorf("Unexpected message %q", health.Message)
+	}
+
+	details := arrDetails(t, health)
+	issues, ok := details["issues"].([]HealthResponse)
+	if !ok || len(issues) != 1 || issues[0].Source != "IndexerStatusCheck" {
+		t.Errorf("Expected only the warning in the issue list, got %+v", details["issues"])
+	}
+	if details["healthIssues"] != 1 || details["version"] != "4.0.0" {
+		t.Errorf("Expected one issue and the version in the details, got %+v", details)
+	}
+}
+
+func TestArrHealthCheckUnreachable(t *testing.T) {
+	server := newTestServer(t, `[]`)
+	checker := &testChecker{}
+
+	// A successful check first, so the version is cached
+	if health, _ := ArrHealthCheck(&checker.ServiceCore, server.URL, "key", checker); health.Status != models.StatusOnline {
+		t.Fatalf("Expected an online service, got %+v", health)
+	}
+	server.Close()
+
+	health, statusCode := ArrHealthCheck(&checker.ServiceCore, server.URL, "key", checker)
+	if statusCode != http.StatusOK || health.Status != models.StatusError {
+		t.Fatalf("Expected an error status, got %d %+v", statusCode, health)
+	}
+	if !strings.HasPrefix(health.Message, "Health check failed") {
+		t.Errorf("Unexpected message %q", health.Message)
+	}
+	if health.Version != "4.0.0" || arrDetails(t, health)["version"] != "4.0.0" {
+		t.Errorf("Expected the cached version, got %+v", health)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/services/core"
+)
+
+// HistoryRecord is an entry of the Sonarr or Radarr history
+type HistoryRecord struct {
+	ID          int               `json:"id"`
+	Date        time.Time         `json:"date"`
+	EventType   string            `json:"eventType"`
+	SourceTitle string            `json:"sourceTitle"`
+	Data        map[string]string `json:"data,omitempty"` // e.g. indexer and downloadClient of a grab
+}
+
+type historyResponse struct {
+	Records []HistoryRecord `json:"records"`
+}
+
+// GetGrabHistory returns the most recent grabs of a Sonarr or Radarr instance, newest first
+func GetGrabHistory(ctx context.Context, service, url, apiKey, apiVersion string, client *core.ServiceCore, pageSize int) ([]HistoryRecord, error) {
+	if url == "" {
+		return nil, &ErrArr{Service: service, Op: "get_history", Err: fmt.Errorf("URL is required")}
+	}
+
+	// eventType 1 is a grab in both Sonarr and Radarr
+	historyURL := APIURL(url, apiVersion, fmt.Sprintf("/history?page=1&pageSize=%d&sortKey=date&sortDirection=descending&eventType=1", pageSize))
+
+	resp, err := MakeArrRequest(ctx, http.MethodGet, historyURL, apiKey, client, nil)
+	if err != nil {
+		return nil, &ErrArr{Service: service, Op: "get_history", Err: fmt.Errorf("failed to make request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErrArr{Service: service, Op: "get_history", HttpCode: resp.StatusCode}
+	}
+
+	var history historyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, &ErrArr{Service: service, Op: "get_history", Err: fmt.Errorf("failed to parse response: %w", err)}
+	}
+
+	if history.Records == nil {
+		history.Records = []HistoryRecord{}
+	}
+
+	return history.Records, nil
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/core"
 	"github.com/autobrr/dashbrr/internal/types"
@@ -135,6 +137,36 @@ func (s *AutobrrService) GetIRCStatus(ctx context.Context, url, apiKey string) (
 		}
 	}
 
+	networks, err := s.GetIRCNetworks(ctx, url, apiKey)
+	if err != nil {
+		return []types.IRCStatus{{Name: "IRC", Healthy: false}}, err
+	}
+
+	// Only networks that are enabled but unhealthy are of interest to the health check
+	unhealthyStatus := []types.IRCStatus{}
+	for _, status := range networks {
+		if !status.Healthy && status.Enabled {
+			unhealthyStatus = append(unhealthyStatus, status)
+		}
+	}
+
+	// Cache the result
+	if cached, err := json.Marshal(unhealthyStatus); err == nil {
+		if err := s.CacheIRCStatus(url, string(cached)); err != nil {
+			fmt.Printf("Failed to cache IRC status: %v\n", err)
+		}
+	}
+
+	return unhealthyStatus, nil
+}
+
+// GetIRCNetworks returns every IRC network configured in autobrr with its health and
+// enabled state, unlike GetIRCStatus it isn't cached or filtered
+func (s *AutobrrService) GetIRCNetworks(ctx context.Context, url, apiKey string) ([]types.IRCStatus, error) {
+	if url == "" || apiKey == "" {
+		return nil, fmt.Errorf("service not configured: missing URL or API key")
+	}
+
 	ircURL := s.getEndpoint(url, "/api/irc")
 	headers := map[string]string{
 		"auth_header": "X-Api-Token",
@@ -143,59 +175,87 @@ func (s *AutobrrService) GetIRCStatus(ctx context.Context, url, apiKey string) (
 
 	resp, err := s.MakeRequestWithContext(ctx, ircURL, apiKey, headers)
 	if err != nil {
-		return []types.IRCStatus{{Name: "IRC", Healthy: false}}, fmt.Errorf("request failed: %v", err)
+		return nil, fmt.Errorf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return []types.IRCStatus{{Name: "IRC", Healthy: false}}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	body, err := s.ReadBody(resp)
 	if err != nil {
-		return []types.IRCStatus{{Name: "IRC", Healthy: false}}, fmt.Errorf("failed to read response body: %v", err)
+		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
 	// Try to decode as array first
-	var allStatus []types.IRCStatus
-	if err := json.Unmarshal(body, &allStatus); err == nil {
-		var unhealthyStatus []types.IRCStatus
-		for _, status := range allStatus {
-			if !status.Healthy && status.Enabled {
-				unhealthyStatus = append(unhealthyStatus, status)
-			}
-		}
-		// Cache the result
-		if cached, err := json.Marshal(unhealthyStatus); err == nil {
-			if err := s.CacheIRCStatus(url, string(cached)); err != nil {
-				fmt.Printf("Failed to cache IRC status: %v\n", err)
-			}
+	var networks []types.IRCStatus
+	if err := json.Unmarshal(body, &networks); err == nil {
+		if networks == nil {
+			networks = []types.IRCStatus{}
 		}
-		return unhealthyStatus, nil
+		return networks, nil
 	}
 
 	// If array decode fails, try to decode as single object
-	var singleStatus types.IRCStatus
-	if err := json.Unmarshal(body, &singleStatus); err == nil {
-		// Only return if unhealthy AND enabled
-		if !singleStatus.Healthy && singleStatus.Enabled {
-			status := []types.IRCStatus{singleStatus}
-			// Cache the result
-			if cached, err := json.Marshal(status); err == nil {
-				if err := s.CacheIRCStatus(url, string(cached)); err != nil {
-					fmt.Printf("Failed to cache IRC status: %v\n", err)
-				}
-			}
-			return status, nil
+	var single types.IRCStatus
+	if err := json.Unmarshal(body, &single); err == nil {
+		return []types.IRCStatus{single}, nil
+	}
+
+	return nil, fmt.Errorf("failed to decode response: %s", string(body))
+}
+
+// GetFilterSummary counts the configured filters and how many of them are enabled.
+// The summary is cached like the version since filters rarely change.
+func (s *AutobrrService) GetFilterSummary(ctx context.Context, url, apiKey string) (types.AutobrrFilterSummary, error) {
+	var summary types.AutobrrFilterSummary
+	if url == "" || apiKey == "" {
+		return summary, fmt.Errorf("service not configured: missing URL or API key")
+	}
+
+	cacheKey := url + "_filters"
+	if cached := s.GetVersionFromCache(cacheKey); cached != "" {
+		if err := json.Unmarshal([]byte(cached), &summary); err == nil {
+			return summary, nil
 		}
-		// Cache empty result
-		if err := s.CacheIRCStatus(url, "[]"); err != nil {
-			fmt.Printf("Failed to cache IRC status: %v\n", err)
+	}
+
+	filtersURL := s.getEndpoint(url, "/api/filters")
+	headers := map[string]string{
+		"auth_header": "X-Api-Token",
+		"auth_value":  apiKey,
+	}
+
+	resp, err := s.MakeRequestWithContext(ctx, filtersURL, apiKey, headers)
+	if err != nil {
+		return summary, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return summary, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var filters []types.AutobrrFilter
+	if err := json.NewDecoder(resp.Body).Decode(&filters); err != nil {
+		return summary, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	summary.Total = len(filters)
+	for _, filter := range filters {
+		if filter.Enabled {
+			summary.Enabled++
+		}
+	}
+
+	if cached, err := json.Marshal(summary); err == nil {
+		if err := s.CacheVersion(cacheKey, string(cached), 10*time.Minute); err != nil {
+			log.Warn().Err(err).Str("url", url).Msg("Failed to cache autobrr filter summary")
 		}
-		return []types.IRCStatus{}, nil
 	}
 
-	return []types.IRCStatus{{Name: "IRC", Healthy: false}}, fmt.Errorf("failed to decode response: %s", string(body))
+	return summary, nil
 }
 
 func (s *AutobrrService) GetVersion(ctx context.Context, url, apiKey string) (string, error) {
@@ -253,6 +313,18 @@ func (s *AutobrrService) CheckUpdate(ctx context.Context, url, apiKey string) (b
 		return status == "true", nil
 	}
 
+	return s.fetchUpdate(ctx, url, apiKey)
+}
+
+// RefreshUpdate asks autobrr for updates right away instead of waiting for the cached result
+// to expire, and drops the cached version so the next health check picks up an upgrade
+func (s *AutobrrService) RefreshUpdate(ctx context.Context, url, apiKey string) (bool, error) {
+	s.InvalidateVersion(url)
+	return s.fetchUpdate(ctx, url, apiKey)
+}
+
+// fetchUpdate asks autobrr whether an update is available and caches the answer
+func (s *AutobrrService) fetchUpdate(ctx context.Context, url, apiKey string) (bool, error) {
 	updateURL := s.getEndpoint(url, "/api/updates/latest")
 	headers := map[string]string{
 		"auth_header": "X-Api-Token",
@@ -284,8 +356,11 @@ func (s *AutobrrService) CheckUpdate(ctx context.Context, url, apiKey string) (b
 func (s *AutobrrService) CheckHealth(ctx context.Context, url string, apiKey string) (models.ServiceHealth, int) {
 	startTime := time.Now()
 
-	if url == "" || apiKey == "" {
-		return s.CreateHealthResponse(startTime, "pending", "Autobrr not configured"), http.StatusOK
+	if url == "" {
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+	if apiKey == "" {
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
 	// Create a context with timeout for the entire health check
@@ -327,6 +402,14 @@ func (s *AutobrrService) CheckHealth(ctx context.Context, url string, apiKey str
 		// Continue without stats, don't fail the health check
 	}
 
+	// Get the filter summary, which is optional like the stats
+	autobrrDetails := map[string]interface{}{}
+	if filters, err := s.GetFilterSummary(ctx, url, apiKey); err != nil {
+		log.Warn().Err(err).Str("url", url).Msg("Failed to get autobrr filter summary")
+	} else {
+		autobrrDetails["filters"] = filters
+	}
+
 	// Perform health check
 	livenessURL := s.getEndpoint(url, "/api/healthz/liveness")
 	headers := map[string]string{
@@ -336,27 +419,31 @@ func (s *AutobrrService) CheckHealth(ctx context.Context, url string, apiKey str
 
 	resp, err := s.MakeRequestWithContext(ctx, livenessURL, apiKey, headers)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "offline", fmt.Sprintf("Failed to connect: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusOK
 	}
 	defer resp.Body.Close()
 
 	// Calculate response time directly
 	responseTime := time.Since(startTime).Milliseconds()
 
+	if models.StatusForResponseCode(resp.StatusCode) == models.StatusUnauthorized {
+		return s.CreateUnauthorizedResponse(startTime, resp.StatusCode), http.StatusOK
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Unexpected status code: %d", resp.StatusCode)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Unexpected status code: %d", resp.StatusCode)), http.StatusOK
 	}
 
 	body, err := s.ReadBody(resp)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Failed to read response: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Failed to read response: %v", err)), http.StatusOK
 	}
 
 	trimmedBody := strings.TrimSpace(string(body))
 	trimmedBody = strings.Trim(trimmedBody, "\"")
 
 	if trimmedBody != "healthy" && trimmedBody != "OK" {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Autobrr reported unhealthy status: %s", trimmedBody)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Autobrr reported unhealthy status: %s", trimmedBody)), http.StatusOK
 	}
 
 	// Wait for version and update status with timeout
@@ -387,11 +474,10 @@ func (s *AutobrrService) CheckHealth(ctx context.Context, url string, apiKey str
 			"stats": map[string]interface{}{
 				"autobrr": stats,
 			},
-			"details": map[string]interface{}{
-				"autobrr": map[string]interface{}{
-					"irc": ircStatus,
-				},
-			},
+		}
+		autobrrDetails["irc"] = ircStatus
+		extras["details"] = map[string]interface{}{
+			"autobrr": autobrrDetails,
 		}
 		if version != "" {
 			extras["version"] = version
@@ -405,7 +491,7 @@ func (s *AutobrrService) CheckHealth(ctx context.Context, url string, apiKey str
 			extras["updateAvailable"] = hasUpdate
 		}
 
-		return s.CreateHealthResponse(startTime, "warning", fmt.Sprintf("Autobrr is running but IRC status check failed: %v", err), extras), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusWarning, fmt.Sprintf("Autobrr is running but IRC status check failed: %v", err), extras), http.StatusOK
 	}
 
 	// Check if any IRC connections are healthy
@@ -442,15 +528,15 @@ func (s *AutobrrService) CheckHealth(ctx context.Context, url string, apiKey str
 		extras["updateAvailable"] = hasUpdate
 	}
 
+	extras["details"] = map[string]interface{}{
+		"autobrr": autobrrDetails,
+	}
+
 	// Only include IRC status in details if there are unhealthy connections
 	if !ircHealthy {
-		extras["details"] = map[string]interface{}{
-			"autobrr": map[string]interface{}{
-				"irc": ircStatus,
-			},
-		}
-		return s.CreateHealthResponse(startTime, "warning", "Autobrr is running but reports unhealthy IRC connections", extras), http.StatusOK
+		autobrrDetails["irc"] = ircStatus
+		return s.CreateHealthResponse(startTime, models.StatusWarning, "Autobrr is running but reports unhealthy IRC connections", extras), http.StatusOK
 	}
 
-	return s.CreateHealthResponse(startTime, "online", "Autobrr is running", extras), http.StatusOK
+	return s.CreateHealthResponse(startTime, models.StatusOnline, "Autobrr is running", extras), http.StatusOK
 }
@@ -0,0 +1,80 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package autobrr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+// newTestServer serves a healthy autobrr without IRC networks, answering the filters
+// endpoint with filtersStatus
+func newTestServer(t *testing.T, filtersStatus int) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/healthz/liveness", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/api/config", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"v1.50.0"}`))
+	})
+	mux.HandleFunc("/api/updates/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/release/stats", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"total_count":3,"filtered_count":2}`))
+	})
+	mux.HandleFunc("/api/irc", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/api/filters", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(filtersStatus)
+		_, _ = w.Write([]byte(`[{"id":1,"enabled":true},{"id":2,"enabled":false}]`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckHealthFilterSummary(t *testing.T) {
+	t.Setenv("REDIS_HOST", "")
+	t.Setenv("DASHBRR__DATA_DIR", t.TempDir())
+
+	tests := []struct {
+		name          string
+		filtersStatus int
+		expectFilters bool
+	}{
+		{"Filters available", http.StatusOK, true},
+		{"Filters failing", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.filtersStatus)
+			service := NewAutobrrService().(*AutobrrService)
+
+			health, code := service.CheckHealth(context.Background(), server.URL, "key")
+			if code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+			}
+
+			// The filter summary is optional, a failing filters endpoint keeps the check online
+			if health.Status != models.StatusOnline {
+				t.Fatalf("Expected status %s, got %s: %s", models.StatusOnline, health.Status, health.Message)
+			}
+
+			details, _ := health.Details["autobrr"].(map[string]interface{})
+			if _, ok := details["filters"]; ok != tt.expectFilters {
+				t.Errorf("Expected filters in the details to be %v, got %v", tt.expectFilters, details)
+			}
+		})
+	}
+}
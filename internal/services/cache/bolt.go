@@ -0,0 +1,438 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	ratesBucket   = []byte("rates")
+)
+
+// BoltStore implements Store on a bbolt database in the data directory. Everything it holds,
+// sessions, rate limit windows and cached stats, survives restarts. Expired entries are
+// ignored on read and removed by a cleanup goroutine.
+type BoltStore struct {
+	db     *bolt.DB
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+	mu     sync.RWMutex
+
+	rateTTLs rateWindowTTLs
+
+	// Values of at least this many bytes are stored compressed, 0 disables compression
+	compressMinSize int
+}
+
+// boltRateWindow is the stored form of a rate limit window
+type boltRateWindow struct {
+	Timestamps map[string]int64 `json:"timestamps"`
+	Expiration time.Time        `json:"expiration"`
+}
+
+// NewBoltStore opens, or creates, cache.db in dataDir
+func NewBoltStore(ctx context.Context, dataDir string) (*BoltStore, error) {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return nil, fmt.Errorf("create data directory: %w", err)
+	}
+
+	// The timeout keeps a second instance on the same data directory from blocking forever
+	db, err := bolt.Open(filepath.Join(dataDir, "cache.db"), 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open cache database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{entriesBucket, ratesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create cache buckets: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	store := &BoltStore{
+		db:     db,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	// Start cleanup goroutine
+	store.wg.Add(1)
+	go func() {
+		defer store.wg.Done()
+		store.cleanup()
+	}()
+
+	return store, nil
+}
+
+// checkOpen returns ErrClosed once the store is closed
+func (s *BoltStore) checkOpen() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// Get retrieves a value from cache
+func (s *BoltStore) Get(ctx context.Context, key string, value interface{}) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	var item persistedItem
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(entriesBucket).Get([]byte(key))
+		if data == nil {
+			return ErrKeyNotFound
+		}
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		if !time.Now().Before(item.Expiration) {
+			return ErrKeyNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return decodeValue(item.Value, value)
+}
+
+// Set stores a value in cache
+func (s *BoltStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	if expiration == 0 {
+		expiration = DefaultTTL
+	}
+
+	data, err := encodeValue(value, s.compressMinSize)
+	if err != nil {
+		log.Error().Err(err).Str("key", key).Msg("Failed to marshal value for cache")
+		return err
+	}
+
+	item, err := json.Marshal(persistedItem{Value: data, Expiration: time.Now().Add(expiration)})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).Put([]byte(key), item)
+	})
+}
+
+// Delete removes a value from cache
+func (s *BoltStore) Delete(ctx context.Context, key string) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).Delete([]byte(key))
+	})
+}
+
+// SetRateWindowTTL sets how long the rate limit windows of keys with the given prefix are
+// kept, the longest matching prefix wins. Keys without one use DefaultRateWindowTTL.
+func (s *BoltStore) SetRateWindowTTL(prefix string, ttl time.Duration) {
+	s.rateTTLs.set(prefix, ttl)
+}
+
+// getWindow reads the rate limit window of key, nil when there is none
+func getWindow(bucket *bolt.Bucket, key string) (*boltRateWindow, error) {
+	data := bucket.Get([]byte(key))
+	if data == nil {
+		return nil, nil
+	}
+
+	var w boltRateWindow
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// putWindow writes the rate limit window of key
+func putWindow(bucket *bolt.Bucket, key string, w *boltRateWindow) error {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), data)
+}
+
+// Increment adds a timestamp to the rate limit window
+func (s *BoltStore) Increment(ctx context.Context, key string, timestamp int64) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	ttl := s.rateTTLs.lookup(key)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ratesBucket)
+		w, err := getWindow(bucket, key)
+		if err != nil {
+			return err
+		}
+
+		// Start a new window when there is none or it has expired
+		now := time.Now()
+		if w == nil || now.After(w.Expiration) {
+			w = &boltRateWindow{
+				Timestamps: make(map[string]int64),
+				Expiration: now.Add(ttl),
+			}
+		}
+
+		w.Timestamps[strconv.FormatInt(timestamp, 10)] = timestamp
+		return putWindow(bucket, key, w)
+	})
+}
+
+// CleanAndCount removes old timestamps and returns the count of remaining ones
+func (s *BoltStore) CleanAndCount(ctx context.Context, key string, windowStart int64) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ratesBucket)
+		w, err := getWindow(bucket, key)
+		if err != nil || w == nil {
+			return err
+		}
+
+		// Drop expired windows, the next Increment starts a new one
+		if time.Now().After(w.Expiration) {
+			return bucket.Delete([]byte(key))
+		}
+
+		for ts, timestamp := range w.Timestamps {
+			if timestamp < windowStart {
+				delete(w.Timestamps, ts)
+			}
+		}
+		return putWindow(bucket, key, w)
+	})
+}
+
+// GetCount returns the number of timestamps in the current window
+func (s *BoltStore) GetCount(ctx context.Context, key string) (int64, error) {
+	if err := s.checkOpen(); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		w, err := getWindow(tx.Bucket(ratesBucket), key)
+		if err != nil || w == nil {
+			return err
+		}
+		if !time.Now().After(w.Expiration) {
+			count = int64(len(w.Timestamps))
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Expire updates the expiration time for a key
+func (s *BoltStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		// Handle rate limit windows
+		rates := tx.Bucket(ratesBucket)
+		w, err := getWindow(rates, key)
+		if err != nil {
+			return err
+		}
+		if w != nil {
+			w.Expiration = time.Now().Add(expiration)
+			return putWindow(rates, key, w)
+		}
+
+		// Handle regular cache items
+		entries := tx.Bucket(entriesBucket)
+		data := entries.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+
+		var item persistedItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		item.Expiration = time.Now().Add(expiration)
+
+		data, err = json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		return entries.Put([]byte(key), data)
+	})
+}
+
+// Keys returns all non-expired keys that start with the given prefix
+func (s *BoltStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		now := time.Now()
+		p := []byte(prefix)
+		c := tx.Bucket(entriesBucket).Cursor()
+		// bbolt keeps keys sorted, so the matches are one contiguous, ordered run
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			var item persistedItem
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			if now.After(item.Expiration) {
+				continue
+			}
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Close stops the cleanup goroutine and closes the database
+func (s *BoltStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+
+	return s.db.Close()
+}
+
+func (s *BoltStore) cleanup() {
+	ticker := time.NewTicker(CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.removeExpired(time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to clean up bolt cache")
+			}
+
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// removeExpired deletes expired entries, expired rate limit windows and windows whose
+// timestamps all fell out of their TTL
+func (s *BoltStore) removeExpired(now time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		var expired [][]byte
+		err := entries.ForEach(func(k, v []byte) error {
+			var item persistedItem
+			if err := json.Unmarshal(v, &item); err != nil || now.After(item.Expiration) {
+				expired = append(expired, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := entries.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		rates := tx.Bucket(ratesBucket)
+		expired = expired[:0]
+		updated := make(map[string]*boltRateWindow)
+		err = rates.ForEach(func(k, v []byte) error {
+			var w boltRateWindow
+			if err := json.Unmarshal(v, &w); err != nil {
+				expired = append(expired, k)
+				return nil
+			}
+
+			cutoff := now.Add(-s.rateTTLs.lookup(string(k))).Unix()
+			pruned := false
+			for ts, timestamp := range w.Timestamps {
+				if timestamp < cutoff {
+					delete(w.Timestamps, ts)
+					pruned = true
+				}
+			}
+
+			switch {
+			case now.After(w.Expiration) || len(w.Timestamps) == 0:
+				expired = append(expired, k)
+			case pruned:
+				updated[string(k)] = &w
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := rates.Delete(k); err != nil {
+				return err
+			}
+		}
+		for key, w := range updated {
+			if err := putWindow(rates, key, w); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
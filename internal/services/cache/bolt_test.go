@@ -0,0 +1,253 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var (
+	_ Store                = (*BoltStore)(nil)
+	_ RateWindowConfigurer = (*BoltStore)(nil)
+)
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open bolt store: %v", err)
+	}
+	defer store.Close()
+
+	t.Run("Basic Operations", func(t *testing.T) {
+		key := "test_key"
+		value := "test_value"
+		if err := store.Set(ctx, key, value, time.Minute); err != nil {
+			t.Errorf("Failed to set value: %v", err)
+		}
+
+		var result string
+		if err := store.Get(ctx, key, &result); err != nil {
+			t.Errorf("Failed to get value: %v", err)
+		}
+		if result != value {
+			t.Errorf("Expected %v, got %v", value, result)
+		}
+
+		if err := store.Delete(ctx, key); err != nil {
+			t.Errorf("Failed to delete value: %v", err)
+		}
+
+		if err := store.Get(ctx, key, &result); err != ErrKeyNotFound {
+			t.Errorf("Expected ErrKeyNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Expiration", func(t *testing.T) {
+		if err := store.Set(ctx, "expiring_key", "expiring_value", 50*time.Millisecond); err != nil {
+			t.Errorf("Failed to set value: %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		var result string
+		if err := store.Get(ctx, "expiring_key", &result); err != ErrKeyNotFound {
+			t.Errorf("Expected ErrKeyNotFound for expired key, got %v", err)
+		}
+	})
+
+	t.Run("Rate Limiting", func(t *testing.T) {
+		key := "rate_key"
+		now := time.Now().Unix()
+
+		for i := int64(0); i < 5; i++ {
+			if err := store.Increment(ctx, key, now+i); err != nil {
+				t.Errorf("Failed to increment: %v", err)
+			}
+		}
+
+		count, err := store.GetCount(ctx, key)
+		if err != nil {
+			t.Errorf("Failed to get count: %v", err)
+		}
+		if count != 5 {
+			t.Errorf("Expected count 5, got %d", count)
+		}
+
+		if err := store.CleanAndCount(ctx, key, now+3); err != nil {
+			t.Errorf("Failed to clean and count: %v", err)
+		}
+
+		count, err = store.GetCount(ctx, key)
+		if err != nil {
+			t.Errorf("Failed to get count after cleaning: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected count 2 after cleaning, got %d", count)
+		}
+	})
+
+	t.Run("Expire", func(t *testing.T) {
+		if err := store.Set(ctx, "expire_key", "expire_value", time.Minute); err != nil {
+			t.Errorf("Failed to set value: %v", err)
+		}
+
+		if err := store.Expire(ctx, "expire_key", 50*time.Millisecond); err != nil {
+			t.Errorf("Failed to update expiration: %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		var result string
+		if err := store.Get(ctx, "expire_key", &result); err != ErrKeyNotFound {
+			t.Errorf("Expected ErrKeyNotFound for expired key, got %v", err)
+		}
+	})
+
+	t.Run("Concurrent Operations", func(t *testing.T) {
+		key := "concurrent_key"
+		done := make(chan bool)
+
+		go func() {
+			for i := 0; i < 100; i++ {
+				store.Set(ctx, key, i, time.Minute)
+			}
+			done <- true
+		}()
+
+		go func() {
+			var result int
+			for i := 0; i < 100; i++ {
+				store.Get(ctx, key, &result)
+			}
+			done <- true
+		}()
+
+		<-done
+		<-done
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		store.Set(ctx, "sonarr:queue:sonarr-1", "a", time.Minute)
+		store.Set(ctx, "sonarr:queue:sonarr-2", "b", time.Minute)
+		store.Set(ctx, "radarr:queue:radarr-1", "c", time.Minute)
+		store.Set(ctx, "sonarr:queue:sonarr-3", "d", -time.Minute)
+
+		keys, err := store.Keys(ctx, "sonarr:queue:")
+		if err != nil {
+			t.Errorf("Failed to list keys: %v", err)
+		}
+		if len(keys) != 2 || keys[0] != "sonarr:queue:sonarr-1" || keys[1] != "sonarr:queue:sonarr-2" {
+			t.Errorf("Expected two live sonarr queue keys, got %v", keys)
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		store.Set(ctx, "cleanup:expired", "a", -time.Minute)
+		store.Increment(ctx, "cleanup:rate", time.Now().Add(-2*DefaultRateWindowTTL).Unix())
+
+		if err := store.removeExpired(time.Now()); err != nil {
+			t.Fatalf("Failed to remove expired entries: %v", err)
+		}
+
+		keys, _ := store.Keys(ctx, "cleanup:")
+		if len(keys) != 0 {
+			t.Errorf("Expected expired entries to be removed, got %v", keys)
+		}
+		if count, _ := store.GetCount(ctx, "cleanup:rate"); count != 0 {
+			t.Errorf("Expected the stale rate window to be removed, got count %d", count)
+		}
+	})
+}
+
+func TestBoltStoreClose(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open bolt store: %v", err)
+	}
+
+	if err := store.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Errorf("Failed to set value before close: %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Errorf("Failed to close store: %v", err)
+	}
+
+	if err := store.Set(ctx, "key2", "value2", time.Minute); err != ErrClosed {
+		t.Errorf("Expected ErrClosed after close, got %v", err)
+	}
+
+	var result string
+	if err := store.Get(ctx, "key", &result); err != ErrClosed {
+		t.Errorf("Expected ErrClosed after close, got %v", err)
+	}
+
+	if err := store.Close(); err != ErrClosed {
+		t.Errorf("Expected ErrClosed on second close, got %v", err)
+	}
+}
+
+func TestBoltStorePersistence(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+
+	store, err := NewBoltStore(ctx, tempDir)
+	if err != nil {
+		t.Fatalf("Failed to open bolt store: %v", err)
+	}
+
+	now := time.Now().Unix()
+	store.Set(ctx, "session:test", "session_value", time.Hour)
+	store.Set(ctx, "stats:sonarr-1", "cached", time.Hour)
+	store.Set(ctx, "queue:sonarr-1", "expiring", 50*time.Millisecond)
+	store.Increment(ctx, "auth:127.0.0.1", now)
+	store.Increment(ctx, "auth:127.0.0.1", now+1)
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Failed to close store: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	store2, err := NewBoltStore(ctx, tempDir)
+	if err != nil {
+		t.Fatalf("Failed to reopen bolt store: %v", err)
+	}
+	defer store2.Close()
+
+	var result string
+	if err := store2.Get(ctx, "session:test", &result); err != nil || result != "session_value" {
+		t.Errorf("Expected the persisted session, got %q (%v)", result, err)
+	}
+	if err := store2.Get(ctx, "stats:sonarr-1", &result); err != nil || result != "cached" {
+		t.Errorf("Expected the persisted stats, got %q (%v)", result, err)
+	}
+	if err := store2.Get(ctx, "queue:sonarr-1", &result); err != ErrKeyNotFound {
+		t.Errorf("Expected the expired entry to be gone, got %v", err)
+	}
+	if count, err := store2.GetCount(ctx, "auth:127.0.0.1"); err != nil || count != 2 {
+		t.Errorf("Expected the persisted rate window with 2 hits, got %d (%v)", count, err)
+	}
+}
+
+func TestInitCacheBolt(t *testing.T) {
+	t.Setenv("CACHE_TYPE", "bolt")
+	t.Setenv("REDIS_HOST", "")
+
+	store, err := InitCache(context.Background(), Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("InitCache failed: %v", err)
+	}
+	defer store.Close()
+
+	if _, ok := store.(*BoltStore); !ok {
+		t.Errorf("Expected BoltStore, got %T", store)
+	}
+}
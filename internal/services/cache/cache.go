@@ -5,8 +5,8 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,6 +36,9 @@ const (
 	StatsTTL    = 5 * time.Minute
 	SessionsTTL = 1 * time.Minute
 
+	// DefaultRateWindowTTL is how long a rate limit window without a configured TTL is kept
+	DefaultRateWindowTTL = 24 * time.Hour
+
 	CleanupInterval = 1 * time.Minute // Increased to reduce cleanup frequency
 )
 
@@ -48,6 +51,9 @@ type RedisStore struct {
 	wg     sync.WaitGroup // Added WaitGroup for graceful shutdown
 	closed bool
 	mu     sync.RWMutex
+
+	// Values of at least this many bytes are stored compressed, 0 disables compression
+	compressMinSize int
 }
 
 // LocalCache provides in-memory caching to reduce Redis hits
@@ -72,7 +78,7 @@ func (s *RedisStore) Get(ctx context.Context, key string, value interface{}) err
 
 	// Try local cache first
 	if data, ok := s.getFromLocalCache(key); ok {
-		if err := json.Unmarshal(data, value); err != nil {
+		if err := decodeValue(data, value); err != nil {
 			log.Error().Err(err).Str("key", key).Msg("Failed to unmarshal local cached value")
 		} else {
 			return nil
@@ -104,7 +110,7 @@ func (s *RedisStore) Get(ctx context.Context, key string, value interface{}) err
 					}
 				}
 				s.setInLocalCache(key, data, ttl)
-				return json.Unmarshal(data, value)
+				return decodeValue(data, value)
 			}
 
 			lastErr = err
@@ -145,7 +151,7 @@ func (s *RedisStore) Set(ctx context.Context, key string, value interface{}, exp
 		}
 	}
 
-	data, err := json.Marshal(value)
+	data, err := encodeValue(value, s.compressMinSize)
 	if err != nil {
 		log.Error().Err(err).Str("key", key).Msg("Failed to marshal value for cache")
 		return err
@@ -347,6 +353,42 @@ func (s *RedisStore) Expire(ctx context.Context, key string, expiration time.Dur
 	return lastErr
 }
 
+// Keys returns all keys starting with the given prefix using SCAN
+func (s *RedisStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, ErrClosed
+	}
+	s.mu.RUnlock()
+
+	var keys []string
+	var cursor uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+		batch, next, err := s.client.Scan(timeoutCtx, cursor, prefix+"*", 100).Result()
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, batch...)
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
 // Local cache methods
 func (s *RedisStore) getFromLocalCache(key string) ([]byte, bool) {
 	s.local.RLock()
@@ -284,6 +284,29 @@ func TestRateLimitOperations(t *testing.T) {
 	assert.Equal(t, int64(0), count)
 }
 
+func TestRateWindowTTL(t *testing.T) {
+	store := NewMemoryStore(context.Background(), t.TempDir()).(*MemoryStore)
+	defer store.Close()
+
+	store.SetRateWindowTTL("login:", 50*time.Millisecond)
+	store.SetRateWindowTTL("login:/api/auth/slow", time.Hour)
+
+	assert.Equal(t, 50*time.Millisecond, store.rateWindowTTL("login:/api/auth/login:1.2.3.4"))
+	assert.Equal(t, time.Hour, store.rateWindowTTL("login:/api/auth/slow:1.2.3.4"), "Expected the longest prefix to win")
+	assert.Equal(t, DefaultRateWindowTTL, store.rateWindowTTL("api:/api/settings:1.2.3.4"))
+
+	ctx := context.Background()
+	key := "login:/api/auth/login:1.2.3.4"
+	require.NoError(t, store.Increment(ctx, key, time.Now().Unix()))
+
+	time.Sleep(100 * time.Millisecond)
+
+	// Expired windows are dropped instead of being renewed for a day
+	require.NoError(t, store.CleanAndCount(ctx, key, 0))
+	_, exists := store.rateLimits.Load(key)
+	assert.False(t, exists, "Expected the expired window to be removed")
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	cache := setupTestCache(t)
 	defer cache.Close()
@@ -403,3 +426,17 @@ func TestClosedCache(t *testing.T) {
 	err = cache.Delete(ctx, key)
 	assert.Equal(t, ErrClosed, err)
 }
+
+func TestDataDir(t *testing.T) {
+	t.Cleanup(func() { SetDataDir("") })
+
+	t.Setenv("DASHBRR__DATA_DIR", "")
+	t.Setenv("DASHBRR__DB_PATH", "/db/dashbrr.db")
+	assert.Equal(t, "/db", DataDir(), "Expected the database directory as fallback")
+
+	t.Setenv("DASHBRR__DATA_DIR", "/state")
+	assert.Equal(t, "/state", DataDir())
+
+	SetDataDir("/configured")
+	assert.Equal(t, "/configured", DataDir(), "Expected the configured directory to win")
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultCompressMinSize is the size in bytes from which values are compressed when
+// compression is enabled without a threshold. Smaller values, e.g. health results and
+// sessions, barely shrink and aren't worth the CPU.
+const DefaultCompressMinSize = 4096
+
+// gzipMagic starts every gzip stream. JSON never starts with these bytes, so they tell
+// compressed values apart from the plain ones stored before compression was enabled.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		// Speed over ratio, cached JSON compresses well either way
+		w, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+		return w
+	},
+}
+
+// compressMinSize returns the size from which values are stored compressed, 0 when
+// compression is off
+func (c Config) compressMinSize() int {
+	if !c.Compress {
+		return 0
+	}
+	if c.CompressMinSize > 0 {
+		return c.CompressMinSize
+	}
+	return DefaultCompressMinSize
+}
+
+// encodeValue marshals value for storage. The JSON is gzip compressed when minSize is
+// above 0 and the JSON is at least minSize bytes, unless compressing doesn't make it smaller.
+func encodeValue(value interface{}, minSize int) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil || minSize <= 0 || len(data) < minSize {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data) / 4)
+
+	w := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(w)
+	w.Reset(&buf)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("compress value: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("compress value: %w", err)
+	}
+
+	if buf.Len() >= len(data) {
+		return data, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeValue unmarshals a value stored by encodeValue, compressed or not
+func decodeValue(data []byte, value interface{}) error {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return json.Unmarshal(data, value)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decompress value: %w", err)
+	}
+	defer r.Close()
+
+	data, err = io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("decompress value: %w", err)
+	}
+	return json.Unmarshal(data, value)
+}
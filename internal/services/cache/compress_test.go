@@ -0,0 +1,180 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type queueRecord struct {
+	ID             int    `json:"id"`
+	Title          string `json:"title"`
+	Status         string `json:"status"`
+	Size           int64  `json:"size"`
+	SizeLeft       int64  `json:"sizeleft"`
+	DownloadClient string `json:"downloadClient"`
+	Indexer        string `json:"indexer"`
+	OutputPath     string `json:"outputPath"`
+}
+
+// largeQueue resembles a cached Sonarr queue of n records
+func largeQueue(n int) []queueRecord {
+	records := make([]queueRecord, n)
+	for i := range records {
+		records[i] = queueRecord{
+			ID:             i,
+			Title:          fmt.Sprintf("Show.Name.S%02dE%02d.1080p.WEB.H264-GROUP", i/20+1, i%20+1),
+			Status:         "downloading",
+			Size:           int64(i+1) * 1_500_000_000,
+			SizeLeft:       int64(i) * 700_000_000,
+			DownloadClient: "qBittorrent",
+			Indexer:        "Indexer (Prowlarr)",
+			OutputPath:     fmt.Sprintf("/downloads/tv/Show.Name.S%02dE%02d.1080p.WEB.H264-GROUP", i/20+1, i%20+1),
+		}
+	}
+	return records
+}
+
+func TestEncodeValue(t *testing.T) {
+	queue := largeQueue(100)
+
+	plain, err := encodeValue(queue, 0)
+	if err != nil {
+		t.Fatalf("Failed to encode value: %v", err)
+	}
+	if bytes.HasPrefix(plain, gzipMagic) {
+		t.Error("Expected a plain value with compression disabled")
+	}
+
+	compressed, err := encodeValue(queue, DefaultCompressMinSize)
+	if err != nil {
+		t.Fatalf("Failed to encode value: %v", err)
+	}
+	if !bytes.HasPrefix(compressed, gzipMagic) || len(compressed) >= len(plain) {
+		t.Errorf("Expected a compressed value smaller than %d bytes, got %d", len(plain), len(compressed))
+	}
+
+	// Values below the threshold stay plain
+	small, err := encodeValue(queue[:1], DefaultCompressMinSize)
+	if err != nil {
+		t.Fatalf("Failed to encode value: %v", err)
+	}
+	if bytes.HasPrefix(small, gzipMagic) {
+		t.Error("Expected a value below the threshold to stay plain")
+	}
+
+	// Both forms decode, so values stored before compression was enabled stay readable
+	for name, data := range map[string][]byte{"plain": plain, "compressed": compressed} {
+		var result []queueRecord
+		if err := decodeValue(data, &result); err != nil {
+			t.Fatalf("Failed to decode %s value: %v", name, err)
+		}
+		if len(result) != len(queue) || result[99] != queue[99] {
+			t.Errorf("Expected the %s value to round trip", name)
+		}
+	}
+
+	if err := decodeValue(gzipMagic, &queue); err == nil {
+		t.Error("Expected an error for a corrupt compressed value")
+	}
+}
+
+func TestStoreCompression(t *testing.T) {
+	t.Setenv("REDIS_HOST", "")
+	ctx := context.Background()
+	queue := largeQueue(100)
+
+	for _, cacheType := range []string{"memory", "bolt"} {
+		t.Run(cacheType, func(t *testing.T) {
+			t.Setenv("CACHE_TYPE", cacheType)
+
+			dir := t.TempDir()
+			store, err := InitCache(ctx, Config{DataDir: dir, Compress: true})
+			if err != nil {
+				t.Fatalf("InitCache failed: %v", err)
+			}
+
+			if err := store.Set(ctx, "sonarr:queue:sonarr-1", queue, time.Minute); err != nil {
+				t.Fatalf("Failed to set value: %v", err)
+			}
+			var result []queueRecord
+			if err := store.Get(ctx, "sonarr:queue:sonarr-1", &result); err != nil {
+				t.Fatalf("Failed to get value: %v", err)
+			}
+			if len(result) != len(queue) || result[0] != queue[0] {
+				t.Errorf("Expected the queue to round trip, got %d records", len(result))
+			}
+			store.Close()
+
+			// The values stay readable once compression is turned off again
+			if cacheType != "bolt" {
+				return
+			}
+			store, err = InitCache(ctx, Config{DataDir: dir})
+			if err != nil {
+				t.Fatalf("InitCache failed: %v", err)
+			}
+			defer store.Close()
+
+			result = nil
+			if err := store.Get(ctx, "sonarr:queue:sonarr-1", &result); err != nil || len(result) != len(queue) {
+				t.Errorf("Expected the compressed queue to be read without compression, got %d records (%v)", len(result), err)
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeValue compares the CPU and space cost of storing a cached queue plain and
+// compressed, see the bytes/value metric
+func BenchmarkEncodeValue(b *testing.B) {
+	for _, records := range []int{10, 100, 1000} {
+		queue := largeQueue(records)
+		for _, bench := range []struct {
+			name    string
+			minSize int
+		}{
+			{"plain", 0},
+			{"gzip", 1},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", bench.name, records), func(b *testing.B) {
+				var data []byte
+				for i := 0; i < b.N; i++ {
+					var err error
+					if data, err = encodeValue(queue, bench.minSize); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(len(data)), "bytes/value")
+			})
+		}
+	}
+}
+
+func BenchmarkDecodeValue(b *testing.B) {
+	queue := largeQueue(100)
+	for _, bench := range []struct {
+		name    string
+		minSize int
+	}{
+		{"plain", 0},
+		{"gzip", 1},
+	} {
+		data, err := encodeValue(queue, bench.minSize)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var result []queueRecord
+				if err := decodeValue(data, &result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
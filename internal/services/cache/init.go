@@ -6,7 +6,9 @@ package cache
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -19,7 +21,36 @@ type Config struct {
 	RedisAddr string
 
 	// Memory cache configuration
-	DataDir string // Directory for persistent storage (derived from DB path)
+	DataDir  string // Directory for persistent storage, see DataDir
+	Snapshot bool   // Write all entries to disk every cleanup interval and load them on start
+
+	// Store large values gzip compressed, in every backend. Values stored before
+	// compression was enabled, or below the threshold, are read as they are.
+	Compress        bool
+	CompressMinSize int // Bytes, 0 uses DefaultCompressMinSize
+}
+
+// dataDir is the configured data directory, set once at startup
+var dataDir atomic.Value
+
+// SetDataDir sets the directory used for persistent storage such as sessions.json
+func SetDataDir(dir string) {
+	dataDir.Store(dir)
+}
+
+// DataDir returns the directory for persistent storage. When none was set it falls back to
+// DASHBRR__DATA_DIR and then to the directory of the database.
+func DataDir() string {
+	if dir, ok := dataDir.Load().(string); ok && dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("DASHBRR__DATA_DIR"); dir != "" {
+		return dir
+	}
+	if path := os.Getenv("DASHBRR__DB_PATH"); path != "" {
+		return filepath.Dir(path)
+	}
+	return "./data"
 }
 
 // CacheType represents the type of cache to use
@@ -28,6 +59,7 @@ type CacheType string
 const (
 	CacheTypeRedis  CacheType = "redis"
 	CacheTypeMemory CacheType = "memory"
+	CacheTypeBolt   CacheType = "bolt"
 )
 
 // getRedisOptions returns Redis configuration optimized for the current environment
@@ -80,6 +112,8 @@ func getCacheType() CacheType {
 		return CacheTypeRedis
 	case "memory":
 		return CacheTypeMemory
+	case "bolt":
+		return CacheTypeBolt
 	default:
 		log.Warn().Str("type", cacheType).Msg("Unknown cache type specified, using memory cache")
 		return CacheTypeMemory
@@ -87,7 +121,7 @@ func getCacheType() CacheType {
 }
 
 // InitCache initializes a cache instance based on configuration.
-// It always returns a valid cache store, falling back to memory cache if Redis fails.
+// It always returns a valid cache store, falling back to memory cache if Redis or bbolt fails.
 func InitCache(ctx context.Context, cfg Config) (Store, error) {
 	cacheType := getCacheType()
 
@@ -96,7 +130,7 @@ func InitCache(ctx context.Context, cfg Config) (Store, error) {
 		// Only attempt Redis connection if Redis address is configured
 		if cfg.RedisAddr == "" {
 			// Silently fall back to memory cache when Redis isn't configured
-			return NewMemoryStore(ctx, cfg.DataDir), nil
+			return newMemoryStore(ctx, cfg), nil
 		}
 
 		isDev := os.Getenv("GIN_MODE") != "release"
@@ -124,7 +158,7 @@ func InitCache(ctx context.Context, cfg Config) (Store, error) {
 				// Only log error if Redis was explicitly requested
 				log.Error().Err(err).Str("addr", opts.Addr).Msg("Failed to connect to explicitly configured Redis, falling back to memory cache")
 			}
-			return NewMemoryStore(ctx, cfg.DataDir), err
+			return newMemoryStore(ctx, cfg), err
 		}
 
 		// Create a new context with cancel for the store
@@ -136,8 +170,9 @@ func InitCache(ctx context.Context, cfg Config) (Store, error) {
 			local: &LocalCache{
 				items: make(map[string]*localCacheItem),
 			},
-			ctx:    storeCtx,
-			cancel: storeCancel,
+			ctx:             storeCtx,
+			cancel:          storeCancel,
+			compressMinSize: cfg.compressMinSize(),
 		}
 
 		// Start cleanup goroutine
@@ -149,11 +184,20 @@ func InitCache(ctx context.Context, cfg Config) (Store, error) {
 
 		return store, nil
 
+	case CacheTypeBolt:
+		store, err := NewBoltStore(ctx, cfg.DataDir)
+		if err != nil {
+			log.Error().Err(err).Str("dir", cfg.DataDir).Msg("Failed to open bolt cache, falling back to memory cache")
+			return newMemoryStore(ctx, cfg), err
+		}
+		store.compressMinSize = cfg.compressMinSize()
+		return store, nil
+
 	case CacheTypeMemory:
-		return NewMemoryStore(ctx, cfg.DataDir), nil
+		return newMemoryStore(ctx, cfg), nil
 
 	default:
 		// This shouldn't happen due to getCacheType's default
-		return NewMemoryStore(ctx, cfg.DataDir), nil
+		return newMemoryStore(ctx, cfg), nil
 	}
 }
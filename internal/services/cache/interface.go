@@ -5,6 +5,8 @@ package cache
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,5 +20,42 @@ type Store interface {
 	CleanAndCount(ctx context.Context, key string, windowStart int64) error
 	GetCount(ctx context.Context, key string) (int64, error)
 	Expire(ctx context.Context, key string, expiration time.Duration) error
+	// Keys returns all live keys starting with prefix. An empty prefix matches every key.
+	Keys(ctx context.Context, prefix string) ([]string, error)
 	Close() error
 }
+
+// RateWindowConfigurer is implemented by stores that track rate limit windows themselves and
+// need to know how long the windows of a key prefix are kept.
+type RateWindowConfigurer interface {
+	SetRateWindowTTL(prefix string, ttl time.Duration)
+}
+
+// rateWindowTTLs holds the rate window TTLs set through SetRateWindowTTL, by key prefix
+type rateWindowTTLs struct {
+	ttls sync.Map // map[string]time.Duration
+}
+
+// set stores the TTL of a prefix, a TTL <= 0 removes it
+func (r *rateWindowTTLs) set(prefix string, ttl time.Duration) {
+	if ttl <= 0 {
+		r.ttls.Delete(prefix)
+		return
+	}
+	r.ttls.Store(prefix, ttl)
+}
+
+// lookup returns the TTL of the longest prefix matching the key, or DefaultRateWindowTTL
+func (r *rateWindowTTLs) lookup(key string) time.Duration {
+	ttl := DefaultRateWindowTTL
+	longest := -1
+	r.ttls.Range(func(prefix, value interface{}) bool {
+		p := prefix.(string)
+		if len(p) > longest && strings.HasPrefix(key, p) {
+			longest = len(p)
+			ttl = value.(time.Duration)
+		}
+		return true
+	})
+	return ttl
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,9 +28,16 @@ type MemoryStore struct {
 
 	// Additional maps for rate limiting functionality
 	rateLimits sync.Map // map[string]*rateWindow
+	rateTTLs   rateWindowTTLs
 
 	// Session persistence
 	persistPath string
+
+	// Snapshot of the other entries, empty when disabled
+	snapshotPath string
+
+	// Values of at least this many bytes are stored compressed, 0 disables compression
+	compressMinSize int
 }
 
 type rateWindow struct {
@@ -45,15 +53,24 @@ type persistedItem struct {
 
 // NewMemoryStore creates a new in-memory cache instance
 func NewMemoryStore(ctx context.Context, dataDir string) Store {
+	return newMemoryStore(ctx, Config{DataDir: dataDir})
+}
+
+func newMemoryStore(ctx context.Context, cfg Config) *MemoryStore {
+	dataDir := cfg.DataDir
 	ctx, cancel := context.WithCancel(ctx)
 
 	store := &MemoryStore{
 		local: &LocalCache{
 			items: make(map[string]*localCacheItem),
 		},
-		ctx:         ctx,
-		cancel:      cancel,
-		persistPath: filepath.Join(dataDir, "sessions.json"),
+		ctx:             ctx,
+		cancel:          cancel,
+		persistPath:     filepath.Join(dataDir, "sessions.json"),
+		compressMinSize: cfg.compressMinSize(),
+	}
+	if cfg.Snapshot {
+		store.snapshotPath = filepath.Join(dataDir, "cache.json")
 	}
 
 	// Ensure directory exists with proper permissions
@@ -70,6 +87,7 @@ func NewMemoryStore(ctx context.Context, dataDir string) Store {
 
 	// Load persisted sessions
 	store.loadSessions()
+	store.loadSnapshot()
 
 	// Start cleanup goroutine
 	store.wg.Add(1)
@@ -152,6 +170,89 @@ func (s *MemoryStore) persistSessions() {
 	}
 }
 
+// isSessionKey reports whether the key holds session data, which is persisted on every change
+func isSessionKey(key string) bool {
+	return strings.HasPrefix(key, "session:") || strings.HasPrefix(key, "oidc:session:")
+}
+
+// loadSnapshot loads the non-expired entries of the last snapshot so cached stats and
+// queues are available right after a restart
+func (s *MemoryStore) loadSnapshot() {
+	if s.snapshotPath == "" {
+		return
+	}
+
+	data, err := os.ReadFile(s.snapshotPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error().Err(err).Msg("Failed to read cache snapshot")
+		}
+		return
+	}
+
+	var items map[string]persistedItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal cache snapshot")
+		return
+	}
+
+	now := time.Now()
+	loaded := 0
+	s.local.Lock()
+	for key, item := range items {
+		if isSessionKey(key) || !now.Before(item.Expiration) {
+			continue
+		}
+		if _, exists := s.local.items[key]; !exists {
+			s.local.items[key] = &localCacheItem{
+				value:      item.Value,
+				expiration: item.Expiration,
+			}
+			loaded++
+		}
+	}
+	s.local.Unlock()
+
+	log.Debug().Int("entries", loaded).Int("expired", len(items)-loaded).Msg("Loaded cache snapshot")
+}
+
+// snapshot writes the non-session entries to disk. Sessions have their own file.
+func (s *MemoryStore) snapshot() {
+	if s.snapshotPath == "" {
+		return
+	}
+
+	s.local.RLock()
+	items := make(map[string]persistedItem)
+	now := time.Now()
+	for key, item := range s.local.items {
+		if !isSessionKey(key) && now.Before(item.expiration) {
+			items[key] = persistedItem{
+				Value:      item.value,
+				Expiration: item.expiration,
+			}
+		}
+	}
+	s.local.RUnlock()
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal cache snapshot")
+		return
+	}
+
+	tempFile := s.snapshotPath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		log.Error().Err(err).Msg("Failed to write temporary cache snapshot")
+		return
+	}
+
+	if err := os.Rename(tempFile, s.snapshotPath); err != nil {
+		log.Error().Err(err).Msg("Failed to rename temporary cache snapshot")
+		_ = os.Remove(tempFile)
+	}
+}
+
 // Get retrieves a value from cache
 func (s *MemoryStore) Get(ctx context.Context, key string, value interface{}) error {
 	s.mu.RLock()
@@ -165,7 +266,7 @@ func (s *MemoryStore) Get(ctx context.Context, key string, value interface{}) er
 	item, exists := s.local.items[key]
 	if exists && time.Now().Before(item.expiration) {
 		s.local.RUnlock()
-		return json.Unmarshal(item.value, value)
+		return decodeValue(item.value, value)
 	}
 	if exists {
 		delete(s.local.items, key)
@@ -188,7 +289,7 @@ func (s *MemoryStore) Set(ctx context.Context, key string, value interface{}, ex
 		expiration = DefaultTTL
 	}
 
-	data, err := json.Marshal(value)
+	data, err := encodeValue(value, s.compressMinSize)
 	if err != nil {
 		log.Error().Err(err).Str("key", key).Msg("Failed to marshal value for cache")
 		return err
@@ -230,6 +331,17 @@ func (s *MemoryStore) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// SetRateWindowTTL sets how long the rate limit windows of keys with the given prefix are
+// kept, the longest matching prefix wins. Keys without one use DefaultRateWindowTTL.
+func (s *MemoryStore) SetRateWindowTTL(prefix string, ttl time.Duration) {
+	s.rateTTLs.set(prefix, ttl)
+}
+
+// rateWindowTTL returns the window TTL of the longest prefix matching the key
+func (s *MemoryStore) rateWindowTTL(key string) time.Duration {
+	return s.rateTTLs.lookup(key)
+}
+
 // Increment adds a timestamp to the rate limit window
 func (s *MemoryStore) Increment(ctx context.Context, key string, timestamp int64) error {
 	s.mu.RLock()
@@ -239,9 +351,10 @@ func (s *MemoryStore) Increment(ctx context.Context, key string, timestamp int64
 	}
 	s.mu.RUnlock()
 
+	ttl := s.rateWindowTTL(key)
 	window, _ := s.rateLimits.LoadOrStore(key, &rateWindow{
 		timestamps: make(map[string]int64),
-		expiration: time.Now().Add(24 * time.Hour),
+		expiration: time.Now().Add(ttl),
 	})
 	w := window.(*rateWindow)
 
@@ -251,7 +364,7 @@ func (s *MemoryStore) Increment(ctx context.Context, key string, timestamp int64
 	// Check if window has expired
 	if time.Now().After(w.expiration) {
 		w.timestamps = make(map[string]int64)
-		w.expiration = time.Now().Add(24 * time.Hour)
+		w.expiration = time.Now().Add(ttl)
 	}
 
 	w.timestamps[strconv.FormatInt(timestamp, 10)] = timestamp
@@ -272,10 +385,10 @@ func (s *MemoryStore) CleanAndCount(ctx context.Context, key string, windowStart
 		w.Lock()
 		defer w.Unlock()
 
-		// Check if window has expired
+		// Drop expired windows, the next Increment starts a new one
 		if time.Now().After(w.expiration) {
 			w.timestamps = make(map[string]int64)
-			w.expiration = time.Now().Add(24 * time.Hour)
+			s.rateLimits.Delete(key)
 			return nil
 		}
 
@@ -347,6 +460,33 @@ func (s *MemoryStore) Expire(ctx context.Context, key string, expiration time.Du
 	return nil
 }
 
+// Keys returns all non-expired keys that start with the given prefix
+func (s *MemoryStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, ErrClosed
+	}
+	s.mu.RUnlock()
+
+	s.local.RLock()
+	defer s.local.RUnlock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(s.local.items))
+	for key, item := range s.local.items {
+		if now.After(item.expiration) {
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
 // Close cleans up resources
 func (s *MemoryStore) Close() error {
 	s.mu.Lock()
@@ -360,8 +500,9 @@ func (s *MemoryStore) Close() error {
 	s.cancel()
 	s.wg.Wait()
 
-	// Persist sessions before clearing the cache
+	// Persist sessions and the snapshot before clearing the cache
 	s.persistSessions()
+	s.snapshot()
 
 	s.local.Lock()
 	s.local.items = make(map[string]*localCacheItem)
@@ -396,12 +537,20 @@ func (s *MemoryStore) localCacheCleanup() {
 			if needsPersist {
 				s.persistSessions()
 			}
+			s.snapshot()
 
-			// Cleanup expired rate limiting windows
+			// Cleanup expired rate limiting windows and windows whose timestamps all fell
+			// out of their TTL
 			s.rateLimits.Range(func(key, value interface{}) bool {
 				w := value.(*rateWindow)
+				cutoff := now.Add(-s.rateWindowTTL(key.(string))).Unix()
 				w.Lock()
-				if now.After(w.expiration) {
+				for ts, timestamp := range w.timestamps {
+					if timestamp < cutoff {
+						delete(w.timestamps, ts)
+					}
+				}
+				if now.After(w.expiration) || len(w.timestamps) == 0 {
 					s.rateLimits.Delete(key)
 				}
 				w.Unlock()
@@ -147,6 +147,21 @@ func TestMemoryStore(t *testing.T) {
 		<-done
 		<-done
 	})
+
+	t.Run("Keys", func(t *testing.T) {
+		store.Set(ctx, "sonarr:queue:sonarr-1", "a", time.Minute)
+		store.Set(ctx, "sonarr:queue:sonarr-2", "b", time.Minute)
+		store.Set(ctx, "radarr:queue:radarr-1", "c", time.Minute)
+		store.Set(ctx, "sonarr:queue:sonarr-3", "d", -time.Minute)
+
+		keys, err := store.Keys(ctx, "sonarr:queue:")
+		if err != nil {
+			t.Errorf("Failed to list keys: %v", err)
+		}
+		if len(keys) != 2 || keys[0] != "sonarr:queue:sonarr-1" || keys[1] != "sonarr:queue:sonarr-2" {
+			t.Errorf("Expected two live sonarr queue keys, got %v", keys)
+		}
+	})
 }
 
 func TestMemoryStoreClose(t *testing.T) {
@@ -214,3 +229,39 @@ func TestMemoryStorePersistence(t *testing.T) {
 		t.Errorf("Expected 'test_value', got '%v'", result)
 	}
 }
+
+func TestMemoryStoreSnapshot(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+
+	store := newMemoryStore(ctx, Config{DataDir: tempDir, Snapshot: true})
+	if err := store.Set(ctx, "stats:sonarr-1", "cached", time.Hour); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+	if err := store.Set(ctx, "queue:sonarr-1", "expiring", 50*time.Millisecond); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Failed to close store: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	store2 := newMemoryStore(ctx, Config{DataDir: tempDir, Snapshot: true})
+	defer store2.Close()
+
+	var result string
+	if err := store2.Get(ctx, "stats:sonarr-1", &result); err != nil || result != "cached" {
+		t.Errorf("Expected the snapshotted value, got %q (%v)", result, err)
+	}
+	if err := store2.Get(ctx, "queue:sonarr-1", &result); err != ErrKeyNotFound {
+		t.Errorf("Expected the expired entry to be discarded, got %v", err)
+	}
+
+	// Without the option nothing but sessions is loaded
+	store3 := NewMemoryStore(ctx, tempDir)
+	defer store3.Close()
+	if err := store3.Get(ctx, "stats:sonarr-1", &result); err != ErrKeyNotFound {
+		t.Errorf("Expected no snapshot to be loaded, got %v", err)
+	}
+}
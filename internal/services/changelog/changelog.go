@@ -0,0 +1,155 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package changelog fetches the GitHub release notes of the services dashbrr monitors
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/buildinfo"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+// apiURL is the GitHub API, overridden in tests
+var apiURL = "https://api.github.com"
+
+// releasesPerPage is the number of releases fetched, enough to cover a few months of updates
+const releasesPerPage = 30
+
+// repos maps service types to the GitHub repository publishing their releases. Plex and
+// the general and tcp types have none.
+var repos = map[string]string{
+	"autobrr":     "autobrr/autobrr",
+	"maintainerr": "jorenn92/Maintainerr",
+	"omegabrr":    "autobrr/omegabrr",
+	"overseerr":   "sct/overseerr",
+	"prowlarr":    "Prowlarr/Prowlarr",
+	"radarr":      "Radarr/Radarr",
+	"sonarr":      "Sonarr/Sonarr",
+	"tailscale":   "tailscale/tailscale",
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// ErrUnknownRepo is returned for service types without a known GitHub repository
+var ErrUnknownRepo = errors.New("no known release repository")
+
+// ErrRateLimited is returned when the GitHub API rate limit is exhausted
+type ErrRateLimited struct {
+	Reset time.Time // When the limit resets, zero when GitHub didn't say
+}
+
+func (e *ErrRateLimited) Error() string {
+	if e.Reset.IsZero() {
+		return "GitHub API rate limit exceeded"
+	}
+	return fmt.Sprintf("GitHub API rate limit exceeded until %s", e.Reset.Format(time.RFC3339))
+}
+
+// Repo returns the GitHub repository of a service type, e.g. "Sonarr/Sonarr"
+func Repo(serviceType string) (string, error) {
+	repo, ok := repos[serviceType]
+	if !ok {
+		return "", ErrUnknownRepo
+	}
+	return repo, nil
+}
+
+// FetchReleases returns the most recent stable releases of a repository, newest first.
+// Drafts and pre-releases are skipped.
+func FetchReleases(ctx context.Context, repo string) ([]types.ReleaseNote, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=%d", apiURL, repo, releasesPerPage)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	buildinfo.AttachUserAgentHeader(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if isRateLimited(resp) {
+		return nil, &ErrRateLimited{Reset: rateLimitReset(resp)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %d for %s", resp.StatusCode, repo)
+	}
+
+	var ghReleases []struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		Draft       bool      `json:"draft"`
+		Prerelease  bool      `json:"prerelease"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ghReleases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases of %s: %w", repo, err)
+	}
+
+	releases := make([]types.ReleaseNote, 0, len(ghReleases))
+	for _, r := range ghReleases {
+		if r.Draft || r.Prerelease {
+			continue
+		}
+		releases = append(releases, types.ReleaseNote{
+			Version:     r.TagName,
+			Name:        r.Name,
+			Notes:       r.Body,
+			URL:         r.HTMLURL,
+			PublishedAt: r.PublishedAt,
+		})
+	}
+
+	return releases, nil
+}
+
+// Since returns the releases newer than the installed version, newest first, and whether
+// the installed version was among the releases. When it wasn't, e.g. because it is older
+// than the fetched page, every release is returned.
+func Since(releases []types.ReleaseNote, installed string) ([]types.ReleaseNote, bool) {
+	installed = normalizeVersion(installed)
+	for i, release := range releases {
+		if normalizeVersion(release.Version) == installed {
+			return releases[:i], true
+		}
+	}
+	return releases, false
+}
+
+// normalizeVersion strips the v prefix of tags such as v4.0.5.1710
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(strings.ToLower(version)), "v")
+}
+
+// isRateLimited reports whether GitHub refused the request because of its rate limit
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// rateLimitReset reads when the rate limit resets from the response headers
+func rateLimitReset(resp *http.Response) time.Time {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if unix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(unix, 0)
+	}
+	return time.Time{}
+}
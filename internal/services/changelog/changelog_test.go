@@ -0,0 +1,66 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package changelog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/Sonarr/Sonarr/releases":
+			_, _ = w.Write([]byte(`[
+				{"tag_name": "v4.0.6.1805", "body": "next", "prerelease": true},
+				{"tag_name": "v4.0.5.1710", "body": "latest"},
+				{"tag_name": "v4.0.4.1491", "body": "fixes"},
+				{"tag_name": "v4.0.3.1413", "body": "installed"}
+			]`))
+		default:
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "4102444800")
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	previous := apiURL
+	apiURL = server.URL
+	defer func() { apiURL = previous }()
+
+	releases, err := FetchReleases(context.Background(), "Sonarr/Sonarr")
+	if err != nil {
+		t.Fatalf("Failed to fetch releases: %v", err)
+	}
+	if len(releases) != 3 || releases[0].Version != "v4.0.5.1710" {
+		t.Fatalf("Expected 3 stable releases starting with v4.0.5.1710, got %+v", releases)
+	}
+
+	newer, found := Since(releases, "4.0.3.1413")
+	if !found || len(newer) != 2 {
+		t.Errorf("Expected 2 releases newer than the installed version, got %d (found %t)", len(newer), found)
+	}
+	if newer, found := Since(releases, "3.0.10"); found || len(newer) != 3 {
+		t.Errorf("Expected every release for an unknown version, got %d (found %t)", len(newer), found)
+	}
+
+	_, err = FetchReleases(context.Background(), "Radarr/Radarr")
+	var rateLimited *ErrRateLimited
+	if !errors.As(err, &rateLimited) || rateLimited.Reset.Unix() != 4102444800 {
+		t.Errorf("Expected a rate limit error with the reset time, got %v", err)
+	}
+}
+
+func TestRepo(t *testing.T) {
+	if repo, err := Repo("sonarr"); err != nil || repo != "Sonarr/Sonarr" {
+		t.Errorf("Expected Sonarr/Sonarr, got %q %v", repo, err)
+	}
+	if _, err := Repo("plex"); !errors.Is(err, ErrUnknownRepo) {
+		t.Errorf("Expected ErrUnknownRepo for plex, got %v", err)
+	}
+}
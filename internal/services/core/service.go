@@ -4,14 +4,17 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -34,8 +37,65 @@ var (
 	// Default timeouts
 	DefaultTimeout     = 30 * time.Second // Increased from 15s to 30s
 	DefaultLongTimeout = 60 * time.Second // Added for services that need longer timeouts
+
+	// Response time in milliseconds above which an online service is reported as degraded, 0 disables
+	slowResponseThreshold atomic.Int64
 )
 
+// PoolSettings configure the connection pool of the HTTP clients services are polled with
+type PoolSettings struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// DefaultPoolSettings are used for the fields SetPoolSettings leaves at zero
+var DefaultPoolSettings = PoolSettings{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+var poolSettings atomic.Pointer[PoolSettings]
+
+// SetPoolSettings configures the connection pool of clients created from now on. Zero
+// fields use DefaultPoolSettings. Raising MaxIdleConnsPerHost avoids connection churn when
+// many services sit behind one reverse proxy.
+func SetPoolSettings(settings PoolSettings) {
+	if settings.MaxIdleConns <= 0 {
+		settings.MaxIdleConns = DefaultPoolSettings.MaxIdleConns
+	}
+	if settings.MaxIdleConnsPerHost <= 0 {
+		settings.MaxIdleConnsPerHost = DefaultPoolSettings.MaxIdleConnsPerHost
+	}
+	if settings.IdleConnTimeout <= 0 {
+		settings.IdleConnTimeout = DefaultPoolSettings.IdleConnTimeout
+	}
+	poolSettings.Store(&settings)
+}
+
+// currentPoolSettings returns the pool settings in effect
+func currentPoolSettings() PoolSettings {
+	if settings := poolSettings.Load(); settings != nil {
+		return *settings
+	}
+	return DefaultPoolSettings
+}
+
+// clientKey identifies a pooled client, clients are shared by requests with the same
+// timeout, pool settings and client certificate
+type clientKey struct {
+	timeout time.Duration
+	pool    PoolSettings
+	cert    [sha256.Size]byte // Fingerprint of the client certificate, zero for none
+}
+
+// SetSlowResponseThreshold sets the response time above which an otherwise online service
+// is reported as a warning. A zero threshold disables the check.
+func SetSlowResponseThreshold(threshold time.Duration) {
+	slowResponseThreshold.Store(threshold.Milliseconds())
+}
+
 type ServiceCore struct {
 	Type           string
 	DisplayName    string
@@ -43,7 +103,9 @@ type ServiceCore struct {
 	DefaultURL     string
 	ApiKey         string
 	HealthEndpoint string
-	Timeout        time.Duration // Added configurable timeout
+	Timeout        time.Duration    // Added configurable timeout
+	AuthHeader     string           // Overrides the header the API key is sent in, empty keeps the service default
+	ClientCert     *tls.Certificate // Presented to services that require mutual TLS, nil presents none
 	cache          cache.Store
 	db             *database.DB
 }
@@ -58,27 +120,69 @@ func (s *ServiceCore) SetTimeout(timeout time.Duration) {
 	s.Timeout = timeout
 }
 
-// getHTTPClient returns a client with the specified timeout
-func getHTTPClient(timeout time.Duration) *http.Client {
-	// Use the timeout as the key
-	if client, ok := httpClients.Load(timeout); ok {
+// SetAuthHeader overrides the header the API key is sent in, for forks and proxies that
+// expect a different one. An empty name restores the default of the service.
+func (s *ServiceCore) SetAuthHeader(name string) {
+	s.AuthHeader = name
+}
+
+// SetClientCertificate sets the client certificate presented to services behind proxies
+// that require mutual TLS. nil presents none.
+func (s *ServiceCore) SetClientCertificate(cert *tls.Certificate) {
+	s.ClientCert = cert
+}
+
+// APIKeyHeader returns the header the API key should be sent in, the override when one is
+// set and fallback otherwise
+func (s *ServiceCore) APIKeyHeader(fallback string) string {
+	if s.AuthHeader != "" {
+		return s.AuthHeader
+	}
+	return fallback
+}
+
+// isAPIKeyHeader reports whether a header is one of the default API key headers an
+// override replaces
+func isAPIKeyHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "X-Api-Key", "X-Api-Token":
+		return true
+	}
+	return false
+}
+
+// getHTTPClient returns a client with the specified timeout and the current pool settings,
+// presenting cert when it is not nil
+func getHTTPClient(timeout time.Duration, cert *tls.Certificate) *http.Client {
+	key := clientKey{timeout: timeout, pool: currentPoolSettings()}
+	if cert != nil && len(cert.Certificate) > 0 {
+		key.cert = sha256.Sum256(cert.Certificate[0])
+	}
+	if client, ok := httpClients.Load(key); ok {
 		return client.(*http.Client)
 	}
 
 	// Create new client if not found
+	transport := &http.Transport{
+		MaxIdleConns:        key.pool.MaxIdleConns,
+		MaxIdleConnsPerHost: key.pool.MaxIdleConnsPerHost,
+		IdleConnTimeout:     key.pool.IdleConnTimeout,
+		DisableKeepAlives:   false,
+	}
+	if key.cert != ([sha256.Size]byte{}) {
+		// Certificates are only presented on their own transport, so connections made
+		// with one are never reused for a service that should not see it
+		transport.TLSClientConfig = &tls.Config{Certificates: []tls.Certificate{*cert}}
+	}
+
 	client := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 10,
-			IdleConnTimeout:     90 * time.Second,
-			DisableKeepAlives:   false,
-		},
-		Timeout: timeout,
-	}
-
-	// Store in pool
-	httpClients.Store(timeout, client)
-	return client
+		Transport: transport,
+		Timeout:   timeout,
+	}
+
+	// Store in pool, a concurrent caller may have stored one first
+	actual, _ := httpClients.LoadOrStore(key, client)
+	return actual.(*http.Client)
 }
 
 func (s *ServiceCore) initCache() error {
@@ -86,15 +190,9 @@ func (s *ServiceCore) initCache() error {
 		return nil
 	}
 
-	// Get database directory from environment
-	dataDir := filepath.Dir(os.Getenv("DASHBRR__DB_PATH"))
-	if dataDir == "." {
-		dataDir = "./data" // Default to ./data if not set
-	}
-
 	// Initialize cache config
 	cfg := cache.Config{
-		DataDir: dataDir,
+		DataDir: cache.DataDir(),
 	}
 
 	// Add Redis configuration if available
@@ -123,6 +221,20 @@ func (s *ServiceCore) initCache() error {
 
 // MakeRequestWithContext makes an HTTP request with the provided context and timeout
 func (s *ServiceCore) MakeRequestWithContext(ctx context.Context, url string, apiKey string, headers map[string]string) (*http.Response, error) {
+	// Get method from headers if provided, default to GET
+	method := http.MethodGet
+	if m, ok := headers["method"]; ok {
+		method = m
+		delete(headers, "method") // Remove method from headers after using it
+	}
+
+	return s.MakeRequestWithBody(ctx, method, url, nil, headers)
+}
+
+// MakeRequestWithBody is like MakeRequestWithContext with an explicit method and a JSON
+// body, nil sends none. The request is cancelled with ctx and bounded by the timeout of
+// the service when ctx has no deadline.
+func (s *ServiceCore) MakeRequestWithBody(ctx context.Context, method, url string, body []byte, headers map[string]string) (*http.Response, error) {
 	if url == "" {
 		log.Error().Msg("Service is not configured")
 		return nil, ErrServiceNotConfigured
@@ -137,14 +249,12 @@ func (s *ServiceCore) MakeRequestWithContext(ctx context.Context, url string, ap
 		timeout = time.Until(deadline)
 	}
 
-	// Get method from headers if provided, default to GET
-	method := http.MethodGet
-	if m, ok := headers["method"]; ok {
-		method = m
-		delete(headers, "method") // Remove method from headers after using it
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		log.Error().Err(err).Str("url", url).Msg("Failed to create request")
 		return nil, err
@@ -154,27 +264,37 @@ func (s *ServiceCore) MakeRequestWithContext(ctx context.Context, url string, ap
 	buildinfo.AttachUserAgentHeader(req)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Connection", "keep-alive")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	if headers != nil {
 		// Handle auth header first if present
 		if authHeader, ok := headers["auth_header"]; ok {
 			if authValue, ok := headers["auth_value"]; ok && authValue != "" {
+				if isAPIKeyHeader(authHeader) {
+					authHeader = s.APIKeyHeader(authHeader)
+				}
 				req.Header.Set(authHeader, authValue)
 			}
 		}
 
 		// Set other headers
 		for headerKey, headerValue := range headers {
-			if headerKey != "auth_header" && headerKey != "auth_value" {
-				req.Header.Set(headerKey, headerValue)
+			if headerKey == "auth_header" || headerKey == "auth_value" {
+				continue
+			}
+			if isAPIKeyHeader(headerKey) {
+				headerKey = s.APIKeyHeader(headerKey)
 			}
+			req.Header.Set(headerKey, headerValue)
 		}
 	}
 
 	start := time.Now()
 
 	// Get client with appropriate timeout
-	client := getHTTPClient(timeout)
+	client := getHTTPClient(timeout, s.ClientCert)
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Error().Err(err).
@@ -335,8 +455,33 @@ func (s *ServiceCore) CacheVersion(baseURL, version string, ttl time.Duration) e
 	return nil
 }
 
+// InvalidateVersion drops the cached version so the next check fetches it again, e.g.
+// after the service was upgraded
+func (s *ServiceCore) InvalidateVersion(baseURL string) {
+	if err := s.initCache(); err != nil {
+		log.Error().Err(err).Str("url", baseURL).Msg("Failed to initialize cache")
+		return
+	}
+
+	if err := s.cache.Delete(context.Background(), "version:"+baseURL); err != nil {
+		log.Debug().Err(err).Str("url", baseURL).Msg("Failed to invalidate cached version")
+	}
+}
+
+// NotConfiguredResponse reports a service that lacks the URL or API key it needs to be
+// checked. It comes with http.StatusOK, so the tile asks to be configured instead of
+// showing a failed check.
+func (s *ServiceCore) NotConfiguredResponse(startTime time.Time, missing string) (models.ServiceHealth, int) {
+	return s.CreateHealthResponse(startTime, models.StatusUnconfigured, "Service not configured: missing "+missing), http.StatusOK
+}
+
 // CreateHealthResponse creates a standardized health response
-func (s *ServiceCore) CreateHealthResponse(lastChecked time.Time, status string, message string, extras ...map[string]interface{}) models.ServiceHealth {
+func (s *ServiceCore) CreateHealthResponse(lastChecked time.Time, status models.ServiceStatus, message string, extras ...map[string]interface{}) models.ServiceHealth {
+	if !status.IsValid() {
+		log.Warn().Str("status", string(status)).Msg("Unknown health status, normalizing")
+		status = models.ParseServiceStatus(string(status))
+	}
+
 	response := models.ServiceHealth{
 		Status:      status,
 		LastChecked: lastChecked,
@@ -361,9 +506,26 @@ func (s *ServiceCore) CreateHealthResponse(lastChecked time.Time, status string,
 		}
 	}
 
+	if threshold := slowResponseThreshold.Load(); threshold > 0 && response.Status == models.StatusOnline && response.ResponseTime > threshold {
+		response.Status = models.StatusWarning
+		slowMessage := fmt.Sprintf("Slow response (%dms)", response.ResponseTime)
+		if response.Message != "" {
+			response.Message = slowMessage + ": " + response.Message
+		} else {
+			response.Message = slowMessage
+		}
+	}
+
 	return response
 }
 
+// CreateUnauthorizedResponse reports credentials rejected by the service with the given
+// status code, keeping them apart from connection failures
+func (s *ServiceCore) CreateUnauthorizedResponse(lastChecked time.Time, statusCode int) models.ServiceHealth {
+	return s.CreateHealthResponse(lastChecked, models.StatusUnauthorized,
+		fmt.Sprintf("%d %s: check credentials", statusCode, http.StatusText(statusCode)))
+}
+
 // GetCachedVersion attempts to get version from cache or fetches it if not found
 func (s *ServiceCore) GetCachedVersion(ctx context.Context, baseURL, apiKey string, fetchVersion func(string, string) (string, error)) (string, error) {
 	if err := s.initCache(); err != nil {
@@ -396,23 +558,33 @@ func (s *ServiceCore) GetCachedVersion(ctx context.Context, baseURL, apiKey stri
 	return version, nil
 }
 
-// ConcurrentRequest executes multiple requests concurrently and returns their results
-func (s *ServiceCore) ConcurrentRequest(requests []func() (interface{}, error)) []interface{} {
+// ConcurrentRequest executes multiple requests concurrently and returns their results and
+// errors, both indexed like requests. At most maxConcurrency requests run at once, 0 runs
+// them all at once.
+func (s *ServiceCore) ConcurrentRequest(requests []func() (interface{}, error), maxConcurrency int) ([]interface{}, []error) {
 	var wg sync.WaitGroup
 	results := make([]interface{}, len(requests))
+	errs := make([]error, len(requests))
+
+	var slots chan struct{}
+	if maxConcurrency > 0 && maxConcurrency < len(requests) {
+		slots = make(chan struct{}, maxConcurrency)
+	}
 
 	for i, request := range requests {
 		wg.Add(1)
+		if slots != nil {
+			slots <- struct{}{}
+		}
 		go func(index int, req func() (interface{}, error)) {
 			defer wg.Done()
-			if result, err := req(); err == nil {
-				results[index] = result
-			} else {
-				log.Error().Err(err).Int("request_index", index).Msg("Concurrent request failed")
+			if slots != nil {
+				defer func() { <-slots }()
 			}
+			results[index], errs[index] = req()
 		}(i, request)
 	}
 
 	wg.Wait()
-	return results
+	return results, errs
 }
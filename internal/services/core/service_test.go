@@ -0,0 +1,133 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetHTTPClientPoolSettings(t *testing.T) {
+	t.Cleanup(func() { SetPoolSettings(PoolSettings{}) })
+
+	SetPoolSettings(PoolSettings{})
+	transport := getHTTPClient(time.Second, nil).Transport.(*http.Transport)
+	if transport.MaxIdleConns != 100 || transport.MaxIdleConnsPerHost != 10 || transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("default pool = %d, %d, %v, want 100, 10, 90s",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+	defaultClient := getHTTPClient(time.Second, nil)
+
+	SetPoolSettings(PoolSettings{MaxIdleConnsPerHost: 50})
+	client := getHTTPClient(time.Second, nil)
+	if client == defaultClient {
+		t.Fatal("changed pool settings reused the client of the previous settings")
+	}
+	transport = client.Transport.(*http.Transport)
+	if transport.MaxIdleConns != 100 || transport.MaxIdleConnsPerHost != 50 || transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("pool = %d, %d, %v, want 100, 50, 90s",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+	if getHTTPClient(time.Second, nil) != client {
+		t.Error("same settings did not reuse the pooled client")
+	}
+}
+
+func TestConcurrentRequest(t *testing.T) {
+	var running, peak atomic.Int32
+	requests := make([]func() (interface{}, error), 10)
+	for i := range requests {
+		requests[i] = func() (interface{}, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := peak.Load()
+				if n <= current || peak.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			if i == 3 {
+				return nil, errors.New("failed")
+			}
+			return i, nil
+		}
+	}
+
+	results, errs := (&ServiceCore{}).ConcurrentRequest(requests, 2)
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", p)
+	}
+	for i := range requests {
+		if i == 3 {
+			if errs[i] == nil || results[i] != nil {
+				t.Errorf("request 3 = %v, %v, want an error", results[i], errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil || results[i] != i {
+			t.Errorf("request %d = %v, %v, want %d", i, results[i], errs[i], i)
+		}
+	}
+}
+
+func TestMakeRequestClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dashbrr"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// The pooled client only trusts system roots, make it trust the test server
+	client := getHTTPClient(DefaultTimeout, cert)
+	client.Transport.(*http.Transport).TLSClientConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	if getHTTPClient(DefaultTimeout, nil) == client {
+		t.Fatal("client with a certificate is shared with clients without one")
+	}
+
+	s := &ServiceCore{}
+	s.SetClientCertificate(cert)
+	resp, err := s.MakeRequestWithContext(context.Background(), server.URL, "", nil)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "dashbrr" {
+		t.Errorf("server saw client certificate %q, want dashbrr", body)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/core"
+)
+
+// drivers maps the URL schemes a database can be configured with to their sql driver
+var drivers = map[string]string{
+	"postgres":   "postgres",
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+	"mariadb":    "mysql",
+}
+
+// dialect holds the queries reporting the server version and the active connections
+type dialect struct {
+	version string
+	active  string
+}
+
+var dialects = map[string]dialect{
+	"postgres": {
+		version: "SHOW server_version",
+		// pg_stat_activity only lists the sessions the user may see, it is informational only
+		active: "SELECT count(*) FROM pg_stat_activity WHERE state = 'active'",
+	},
+	"mysql": {
+		version: "SELECT VERSION()",
+		// Same for the process list without the PROCESS privilege
+		active: "SELECT COUNT(*) FROM information_schema.PROCESSLIST WHERE COMMAND <> 'Sleep'",
+	},
+}
+
+func init() {
+	models.NewDatabaseService = NewDatabaseService
+	models.ValidateDatabaseURL = ValidateURL
+}
+
+func NewDatabaseService() models.ServiceHealthChecker {
+	service := &DatabaseService{}
+	service.Type = "database"
+	service.DisplayName = "" // Allow display name to be set via configuration
+	service.Description = "Monitor a PostgreSQL or MySQL database with SELECT 1, reporting active connections"
+	service.SetTimeout(core.DefaultTimeout)
+	return service
+}
+
+// DatabaseService checks a database by connecting to it and running a trivial query
+type DatabaseService struct {
+	core.ServiceCore
+}
+
+// supportedSchemes lists the URL schemes in drivers for error messages
+func supportedSchemes() string {
+	schemes := make([]string, 0, len(drivers))
+	for scheme := range drivers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return strings.Join(schemes, ", ")
+}
+
+// parseDSN returns the driver and data source name of a database URL such as
+// postgres://dashbrr@db:5432/media?sslmode=disable or mysql://dashbrr@db:3306/media. The
+// password is taken from the API key, which is stored encrypted unlike the URL, so URLs
+// carrying a password are rejected.
+func parseDSN(raw, apiKey string) (driver, dsn string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid database URL, expected e.g. postgres://user@host:5432/db")
+	}
+
+	driver, ok := drivers[strings.ToLower(u.Scheme)]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported database %q, supported: %s", u.Scheme, supportedSchemes())
+	}
+
+	if _, hasPassword := u.User.Password(); hasPassword {
+		return "", "", fmt.Errorf("remove the password from the database URL and set it as the API key, which is stored encrypted")
+	}
+
+	if driver == "mysql" {
+		dsn, err := mysqlDSN(u, apiKey)
+		return driver, dsn, err
+	}
+
+	if apiKey != "" {
+		u.User = url.UserPassword(u.User.Username(), apiKey)
+	}
+
+	return driver, u.String(), nil
+}
+
+// ValidateURL checks a database URL without connecting: the scheme has to be a supported
+// database and the URL must not hold the password, which is set as the API key
+func ValidateURL(raw string) error {
+	_, _, err := parseDSN(raw, "")
+	return err
+}
+
+// mysqlDSN turns a mysql:// URL into the user:password@tcp(host:port)/db form of the MySQL
+// driver. Query parameters are passed on as driver options, e.g. tls=true.
+func mysqlDSN(u *url.URL, password string) (string, error) {
+	dsn := "/" + strings.TrimPrefix(u.Path, "/")
+	if u.RawQuery != "" {
+		dsn += "?" + u.RawQuery
+	}
+
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", fmt.Errorf("invalid MySQL options: %w", err)
+	}
+	cfg.User = u.User.Username()
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = u.Host
+
+	return cfg.FormatDSN(), nil
+}
+
+// CheckHealth connects to the database and runs SELECT 1. The server version and the
+// number of active connections are reported as well.
+func (s *DatabaseService) CheckHealth(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
+	startTime := time.Now()
+
+	if url == "" {
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+
+	driver, dsn, err := parseDSN(url, apiKey)
+	if err != nil {
+		return s.CreateHealthResponse(startTime, models.StatusError, err.Error()), http.StatusBadRequest
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Invalid connection settings: %v", err)), http.StatusBadRequest
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	healthCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	var one int
+	if err := db.QueryRowContext(healthCtx, "SELECT 1").Scan(&one); err != nil {
+		if isAuthError(err) {
+			return s.CreateHealthResponse(startTime, models.StatusUnauthorized, fmt.Sprintf("Authentication failed: %v", err)), http.StatusUnauthorized
+		}
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusServiceUnavailable
+	}
+
+	extras := map[string]interface{}{
+		"responseTime": time.Since(startTime).Milliseconds(),
+	}
+
+	queries := dialects[driver]
+
+	var version string
+	if err := db.QueryRowContext(healthCtx, queries.version).Scan(&version); err == nil {
+		extras["version"] = version
+	}
+
+	var active int
+	if err := db.QueryRowContext(healthCtx, queries.active).Scan(&active); err != nil {
+		log.Debug().Err(err).Msg("Failed to count active database connections")
+		return s.CreateHealthResponse(startTime, models.StatusOnline, "Connected", extras), http.StatusOK
+	}
+	extras["details"] = map[string]interface{}{
+		"database": map[string]interface{}{
+			"activeConnections": active,
+		},
+	}
+
+	return s.CreateHealthResponse(startTime, models.StatusOnline, fmt.Sprintf("%d active connections", active), extras), http.StatusOK
+}
+
+// isAuthError reports whether the server rejected the credentials, SQLSTATE class 28 on
+// PostgreSQL and access denied errors on MySQL
+func isAuthError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Class() == "28"
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1044, 1045, 1698: // ER_DBACCESS_DENIED_ERROR, ER_ACCESS_DENIED_ERROR, ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+			return true
+		}
+	}
+	return false
+}
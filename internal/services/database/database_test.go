@@ -0,0 +1,75 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name, url, apiKey string
+		driver, dsn       string
+		valid             bool
+	}{
+		{"postgres", "postgres://dashbrr@db:5432/media", "", "postgres", "postgres://dashbrr@db:5432/media", true},
+		{"postgresql scheme", "postgresql://dashbrr@db/media", "", "postgres", "postgresql://dashbrr@db/media", true},
+		{"password from api key", "postgres://dashbrr@db/media?sslmode=disable", "s3cret", "postgres", "postgres://dashbrr:s3cret@db/media?sslmode=disable", true},
+		{"password in url", "postgres://dashbrr:inline@db/media", "s3cret", "", "", false},
+		{"mysql", "mysql://dashbrr@db:3306/media", "s3cret", "mysql", "dashbrr:s3cret@tcp(db:3306)/media", true},
+		{"mariadb scheme with options", "mariadb://dashbrr@db/media?tls=skip-verify", "", "mysql", "dashbrr@tcp(db)/media?tls=skip-verify", true},
+		{"mysql password in url", "mysql://dashbrr:inline@db/media", "", "", "", false},
+		{"unsupported driver", "sqlserver://dashbrr@db/media", "", "", "", false},
+		{"not a url", "db:5432", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver, dsn, err := parseDSN(tt.url, tt.apiKey)
+			if (err == nil) != tt.valid {
+				t.Fatalf("parseDSN(%q) = %v, want valid %t", tt.url, err, tt.valid)
+			}
+			if driver != tt.driver || dsn != tt.dsn {
+				t.Errorf("Expected %s %s, got %s %s", tt.driver, tt.dsn, driver, dsn)
+			}
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	// A closed port, the listener is gone by the time it is dialed
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closed := ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		status models.ServiceStatus
+		code   int
+	}{
+		{"connection refused", "postgres://dashbrr@" + closed + "/media?sslmode=disable", models.StatusOffline, http.StatusServiceUnavailable},
+		{"mysql connection refused", "mysql://dashbrr@" + closed + "/media", models.StatusOffline, http.StatusServiceUnavailable},
+		{"unsupported driver", "sqlserver://dashbrr@" + closed + "/media", models.StatusError, http.StatusBadRequest},
+		{"missing url", "", models.StatusUnconfigured, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDatabaseService().(*DatabaseService)
+			health, code := service.CheckHealth(context.Background(), tt.url, "")
+			if health.Status != tt.status || code != tt.code {
+				t.Fatalf("Expected %s (%d), got %s (%d): %s", tt.status, tt.code, health.Status, code, health.Message)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,13 +33,43 @@ func NewGeneralService() models.ServiceHealthChecker {
 
 type GeneralService struct {
 	core.ServiceCore
+
+	method         string
+	expectedStatus []int
+}
+
+// SetHTTPCheck sets the request method and the status codes counted as healthy. An empty
+// method checks with GET and no codes accept any 2xx status.
+func (s *GeneralService) SetHTTPCheck(method string, expectedStatus []int) {
+	s.method = method
+	s.expectedStatus = expectedStatus
+}
+
+// expects reports whether a response with the status code counts as healthy
+func (s *GeneralService) expects(statusCode int) bool {
+	if len(s.expectedStatus) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	return slices.Contains(s.expectedStatus, statusCode)
+}
+
+// expectedStatusText describes the status codes counted as healthy, e.g. "200, 204"
+func (s *GeneralService) expectedStatusText() string {
+	if len(s.expectedStatus) == 0 {
+		return "2xx"
+	}
+	codes := make([]string, len(s.expectedStatus))
+	for i, code := range s.expectedStatus {
+		codes[i] = strconv.Itoa(code)
+	}
+	return strings.Join(codes, ", ")
 }
 
 func (s *GeneralService) CheckHealth(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
 	startTime := time.Now()
 
 	if url == "" {
-		return s.CreateHealthResponse(startTime, "error", "URL is required"), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "URL")
 	}
 
 	// Create a child context with timeout if needed
@@ -48,63 +80,69 @@ func (s *GeneralService) CheckHealth(ctx context.Context, url, apiKey string) (m
 	if apiKey != "" {
 		headers["Authorization"] = fmt.Sprintf("Bearer %s", apiKey)
 	}
+	method := http.MethodGet
+	if s.method != "" {
+		method = s.method
+	}
 
-	resp, err := s.MakeRequestWithContext(healthCtx, url, apiKey, headers)
+	resp, err := s.MakeRequestWithBody(healthCtx, method, url, nil, headers)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "offline", fmt.Sprintf("Failed to connect: %v", err)), http.StatusServiceUnavailable
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusServiceUnavailable
 	}
 	defer resp.Body.Close()
 
 	// Calculate response time directly
 	responseTime := time.Since(startTime).Milliseconds()
 
+	if !s.expects(resp.StatusCode) {
+		message := fmt.Sprintf("Unexpected status code %d, expected %s", resp.StatusCode, s.expectedStatusText())
+		return s.CreateHealthResponse(startTime, models.StatusError, message), resp.StatusCode
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Failed to read response: %v", err)), http.StatusInternalServerError
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Failed to read response: %v", err)), http.StatusInternalServerError
+	}
+
+	extras := map[string]interface{}{
+		"responseTime": responseTime,
 	}
 
 	// Try to parse as JSON first
 	var jsonResponse map[string]interface{}
 	if err := json.Unmarshal(body, &jsonResponse); err == nil {
 		// Handle JSON response
-		status := "online"
+		status := models.StatusOnline
 		message := ""
 
 		if statusVal, ok := jsonResponse["status"].(string); ok {
 			// Map status values to our supported statuses
 			switch strings.ToLower(statusVal) {
 			case "healthy", "ok", "online":
-				status = "online"
+				status = models.StatusOnline
 			case "unhealthy", "error", "offline":
-				status = "offline"
+				status = models.StatusOffline
 			case "warning":
-				status = "warning"
+				status = models.StatusWarning
 			default:
-				status = "unknown"
+				status = models.StatusUnknown
 			}
 		}
 		if messageVal, ok := jsonResponse["message"].(string); ok {
 			message = messageVal
 		}
 
-		extras := map[string]interface{}{
-			"responseTime": responseTime,
-		}
-
-		return s.CreateHealthResponse(startTime, status, message, extras), resp.StatusCode
+		return s.CreateHealthResponse(startTime, status, message, extras), http.StatusOK
 	}
 
-	// If JSON parsing fails, treat as plain text
+	// If JSON parsing fails, treat as plain text. An empty body, e.g. the response to a
+	// HEAD request or a 204, only has its status code to go by.
 	textResponse := strings.TrimSpace(string(body))
-	extras := map[string]interface{}{
-		"responseTime": responseTime,
-	}
-
-	if strings.EqualFold(textResponse, "ok") {
-		return s.CreateHealthResponse(startTime, "online", "", extras), resp.StatusCode
+	if textResponse == "" || strings.EqualFold(textResponse, "ok") {
+		return s.CreateHealthResponse(startTime, models.StatusOnline, "", extras), http.StatusOK
 	}
 
-	return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Unexpected response: %s", textResponse), extras), resp.StatusCode
+	return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Unexpected response: %s", textResponse), extras), http.StatusOK
 }
 
 func (s *GeneralService) GetVersion(ctx context.Context, url, apiKey string) (string, error) {
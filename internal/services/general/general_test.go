@@ -0,0 +1,63 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package general
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+func TestGeneralService_HTTPCheck(t *testing.T) {
+	var gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		switch r.URL.Path {
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			_, _ = w.Write([]byte("OK"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		path           string
+		method         string
+		expectedStatus string
+		wantMethod     string
+		wantStatus     models.ServiceStatus
+		wantCode       int
+	}{
+		{"default", "/", "", "", http.MethodGet, models.StatusOnline, http.StatusOK},
+		{"head", "/", "head", "", http.MethodHead, models.StatusOnline, http.StatusOK},
+		{"post", "/", "POST", "", http.MethodPost, models.StatusOnline, http.StatusOK},
+		{"any 2xx", "/no-content", "", "", http.MethodGet, models.StatusOnline, http.StatusOK},
+		{"expected 401", "/unauthorized", "", "200,401", http.MethodGet, models.StatusOnline, http.StatusOK},
+		{"unexpected 401", "/unauthorized", "", "", http.MethodGet, models.StatusError, http.StatusUnauthorized},
+		{"unexpected 200", "/", "", "204", http.MethodGet, models.StatusError, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewGeneralService()
+			config := models.ServiceConfiguration{Method: tt.method, ExpectedStatus: tt.expectedStatus}
+			config.Configure(service)
+
+			health, code := service.CheckHealth(context.Background(), server.URL+tt.path, "")
+			if gotMethod != tt.wantMethod {
+				t.Errorf("Expected a %s request, got %s", tt.wantMethod, gotMethod)
+			}
+			if health.Status != tt.wantStatus || code != tt.wantCode {
+				t.Errorf("Expected %s with %d, got %s with %d: %s", tt.wantStatus, tt.wantCode, health.Status, code, health.Message)
+			}
+		})
+	}
+}
@@ -23,12 +23,12 @@ type HealthService struct {
 }
 
 type HealthCheck struct {
-	Status          string    `json:"status"`
-	ResponseTime    int64     `json:"responseTime"`
-	Message         string    `json:"message,omitempty"`
-	Version         string    `json:"version,omitempty"`
-	LastChecked     time.Time `json:"lastChecked"`
-	UpdateAvailable bool      `json:"updateAvailable,omitempty"`
+	Status          models.ServiceStatus `json:"status"`
+	ResponseTime    int64                `json:"responseTime"`
+	Message         string               `json:"message,omitempty"`
+	Version         string               `json:"version,omitempty"`
+	LastChecked     time.Time            `json:"lastChecked"`
+	UpdateAvailable bool                 `json:"updateAvailable,omitempty"`
 }
 
 func NewHealthService() *HealthService {
@@ -44,10 +44,10 @@ func CheckServiceHealth(serviceType, url, apiKey string) (models.ServiceHealth,
 
 	if url == "" {
 		return models.ServiceHealth{
-			Status:      "error",
+			Status:      models.StatusUnconfigured,
 			LastChecked: time.Now(),
-			Message:     "URL is required",
-		}, http.StatusBadRequest
+			Message:     "Service not configured: missing URL",
+		}, http.StatusOK
 	}
 
 	// Get the appropriate service checker from the registry
@@ -55,7 +55,7 @@ func CheckServiceHealth(serviceType, url, apiKey string) (models.ServiceHealth,
 	if serviceChecker == nil {
 		log.Warn().Str("service_type", serviceType).Msg("No service checker found for type")
 		return models.ServiceHealth{
-			Status:       "error",
+			Status:       models.StatusError,
 			ResponseTime: time.Since(startTime).Milliseconds(),
 			LastChecked:  time.Now(),
 			Message:      "Unsupported service type: " + serviceType,
@@ -88,7 +88,7 @@ func (h *HealthService) StartMonitoring(instanceID string, checkFn func(context.
 	health, err := checkFn(ctx)
 	if err != nil {
 		h.healthChecks[instanceID] = &HealthCheck{
-			Status:      "error",
+			Status:      models.StatusError,
 			Message:     err.Error(),
 			LastChecked: time.Now(),
 		}
@@ -110,7 +110,7 @@ func (h *HealthService) StartMonitoring(instanceID string, checkFn func(context.
 				h.mu.Lock()
 				if err != nil {
 					h.healthChecks[instanceID] = &HealthCheck{
-						Status:      "error",
+						Status:      models.StatusError,
 						Message:     err.Error(),
 						LastChecked: time.Now(),
 					}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/dashbrr/internal/models"
 )
 
 func TestNewHealthService(t *testing.T) {
@@ -24,7 +26,7 @@ func TestCheckServiceHealth(t *testing.T) {
 		serviceType string
 		url         string
 		apiKey      string
-		wantStatus  string
+		wantStatus  models.ServiceStatus
 		wantCode    int
 	}{
 		{
@@ -32,15 +34,15 @@ func TestCheckServiceHealth(t *testing.T) {
 			serviceType: "test",
 			url:         "",
 			apiKey:      "test-key",
-			wantStatus:  "error",
-			wantCode:    400,
+			wantStatus:  models.StatusUnconfigured,
+			wantCode:    200,
 		},
 		{
 			name:        "Invalid Service Type",
 			serviceType: "invalid-service",
 			url:         "http://test.com",
 			apiKey:      "test-key",
-			wantStatus:  "error",
+			wantStatus:  models.StatusError,
 			wantCode:    400,
 		},
 	}
@@ -61,7 +63,7 @@ func TestHealthService_StartStopMonitoring(t *testing.T) {
 	// Start monitoring
 	hs.StartMonitoring(instanceID, func(ctx context.Context) (*HealthCheck, error) {
 		return &HealthCheck{
-			Status:      "healthy",
+			Status:      models.StatusOnline,
 			LastChecked: time.Now(),
 		}, nil
 	})
@@ -80,7 +82,7 @@ func TestHealthService_StartStopMonitoring(t *testing.T) {
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	assert.Equal(t, "healthy", health.Status)
+	assert.Equal(t, models.StatusOnline, health.Status)
 
 	// Stop monitoring
 	hs.StopMonitoring(instanceID)
@@ -97,8 +99,8 @@ func TestHealthService_GetAllHealth(t *testing.T) {
 
 	// Add some test health checks
 	hs.mu.Lock()
-	hs.healthChecks["test1"] = &HealthCheck{Status: "healthy"}
-	hs.healthChecks["test2"] = &HealthCheck{Status: "error"}
+	hs.healthChecks["test1"] = &HealthCheck{Status: models.StatusOnline}
+	hs.healthChecks["test2"] = &HealthCheck{Status: models.StatusError}
 	hs.mu.Unlock()
 
 	// Get all health checks
@@ -111,7 +113,7 @@ func TestHealthService_GetAllHealth(t *testing.T) {
 	test1Health, exists := allHealth["test1"]
 	assert.True(t, exists, "test1 health check should exist")
 	if test1Health != nil {
-		assert.Equal(t, "healthy", test1Health.Status)
+		assert.Equal(t, models.StatusOnline, test1Health.Status)
 	} else {
 		t.Error("test1 health check should not be nil")
 	}
@@ -119,7 +121,7 @@ func TestHealthService_GetAllHealth(t *testing.T) {
 	test2Health, exists := allHealth["test2"]
 	assert.True(t, exists, "test2 health check should exist")
 	if test2Health != nil {
-		assert.Equal(t, "error", test2Health.Status)
+		assert.Equal(t, models.StatusError, test2Health.Status)
 	} else {
 		t.Error("test2 health check should not be nil")
 	}
@@ -132,7 +134,7 @@ func TestHealthService_Cleanup(t *testing.T) {
 	// Start monitoring
 	hs.StartMonitoring(instanceID, func(ctx context.Context) (*HealthCheck, error) {
 		return &HealthCheck{
-			Status:      "healthy",
+			Status:      models.StatusOnline,
 			LastChecked: time.Now(),
 		}, nil
 	})
@@ -172,7 +174,7 @@ func TestHealthService_ConcurrentAccess(t *testing.T) {
 		go func() {
 			mockCheckFn := func(ctx context.Context) (*HealthCheck, error) {
 				return &HealthCheck{
-					Status:      "healthy",
+					Status:      models.StatusOnline,
 					LastChecked: time.Now(),
 				}, nil
 			}
@@ -207,3 +209,32 @@ func TestHealthService_ConcurrentAccess(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckHealthNotConfigured(t *testing.T) {
+	// Services that can be checked without an API key
+	keyOptional := map[string]bool{"database": true, "general": true, "redis": true, "tcp": true}
+
+	registry := models.NewServiceRegistry()
+	for _, serviceType := range models.SupportedServiceTypes {
+		t.Run(serviceType, func(t *testing.T) {
+			checker := registry.CreateService(serviceType)
+			if !assert.NotNil(t, checker) {
+				return
+			}
+
+			// Tailscale talks to the Tailscale API and only needs a key
+			if serviceType != "tailscale" {
+				health, code := checker.CheckHealth(context.Background(), "", "key")
+				assert.Equal(t, models.StatusUnconfigured, health.Status, health.Message)
+				assert.Equal(t, 200, code)
+			}
+
+			if !keyOptional[serviceType] {
+				// Nothing listens there, a check that got past the key would fail instead
+				health, code := checker.CheckHealth(context.Background(), "http://127.0.0.1:1", "")
+				assert.Equal(t, models.StatusUnconfigured, health.Status, health.Message)
+				assert.Equal(t, 200, code)
+			}
+		})
+	}
+}
@@ -138,7 +138,10 @@ func (s *MaintainerrService) CheckHealth(ctx context.Context, url, apiKey string
 	startTime := time.Now()
 
 	if url == "" {
-		return s.CreateHealthResponse(startTime, "error", "URL is required"), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+	if apiKey == "" {
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
 	// Create a child context with longer timeout if needed
@@ -162,7 +165,7 @@ func (s *MaintainerrService) CheckHealth(ctx context.Context, url, apiKey string
 	healthEndpoint := s.GetHealthEndpoint(url)
 	resp, err := s.MakeRequestWithContext(healthCtx, healthEndpoint, "", nil)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "offline", fmt.Sprintf("Failed to connect: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusOK
 	}
 	defer resp.Body.Close()
 
@@ -171,12 +174,12 @@ func (s *MaintainerrService) CheckHealth(ctx context.Context, url, apiKey string
 
 	body, err := s.ReadBody(resp)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Failed to read response: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Failed to read response: %v", err)), http.StatusOK
 	}
 
 	if resp.StatusCode >= 400 {
 		statusText := http.StatusText(resp.StatusCode)
-		status := "error"
+		status := models.StatusError
 		message := fmt.Sprintf("Server returned %s (%d)", statusText, resp.StatusCode)
 
 		// Determine appropriate status based on response code
@@ -196,7 +199,7 @@ func (s *MaintainerrService) CheckHealth(ctx context.Context, url, apiKey string
 
 	var statusResponse StatusResponse
 	if err := json.Unmarshal(body, &statusResponse); err != nil {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Failed to parse status response: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Failed to parse status response: %v", err)), http.StatusOK
 	}
 
 	var version string
@@ -228,7 +231,7 @@ func (s *MaintainerrService) CheckHealth(ctx context.Context, url, apiKey string
 		extras["versionError"] = versionErr.Error()
 	}
 
-	return s.CreateHealthResponse(startTime, "online", "Healthy", extras), http.StatusOK
+	return s.CreateHealthResponse(startTime, models.StatusOnline, "Healthy", extras), http.StatusOK
 }
 
 func (s *MaintainerrService) GetCollections(ctx context.Context, url, apiKey string) ([]Collection, error) {
@@ -85,7 +85,10 @@ func (s *OmegabrrService) CheckHealth(ctx context.Context, url, apiKey string) (
 	startTime := time.Now()
 
 	if url == "" {
-		return s.CreateHealthResponse(startTime, "error", "URL is required"), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+	if apiKey == "" {
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
 	// Create a child context with longer timeout if needed
@@ -106,7 +109,7 @@ func (s *OmegabrrService) CheckHealth(ctx context.Context, url, apiKey string) (
 
 	resp, err := s.MakeRequestWithContext(healthCtx, healthEndpoint, "", headers)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "offline", "Failed to connect: "+err.Error()), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusOffline, "Failed to connect: "+err.Error()), http.StatusOK
 	}
 	defer resp.Body.Close()
 
@@ -115,15 +118,15 @@ func (s *OmegabrrService) CheckHealth(ctx context.Context, url, apiKey string) (
 
 	body, err := s.ReadBody(resp)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "warning", "Failed to read response: "+err.Error()), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusWarning, "Failed to read response: "+err.Error()), http.StatusOK
 	}
 
 	if resp.StatusCode >= 400 {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Server returned error: %d", resp.StatusCode)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Server returned error: %d", resp.StatusCode)), http.StatusOK
 	}
 
 	if strings.TrimSpace(string(body)) != "OK" {
-		return s.CreateHealthResponse(startTime, "warning", "Unexpected response from server"), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusWarning, "Unexpected response from server"), http.StatusOK
 	}
 
 	extras := map[string]interface{}{
@@ -136,7 +139,7 @@ func (s *OmegabrrService) CheckHealth(ctx context.Context, url, apiKey string) (
 		extras["updateAvailable"] = s.GetUpdateStatusFromCache(url)
 	}
 
-	return s.CreateHealthResponse(startTime, "online", "Healthy", extras), http.StatusOK
+	return s.CreateHealthResponse(startTime, models.StatusOnline, "Healthy", extras), http.StatusOK
 }
 
 // TriggerARRsWebhook triggers the ARRs webhook
@@ -4,18 +4,21 @@
 package overseerr
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/autobrr/dashbrr/internal/database"
 	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
 	"github.com/autobrr/dashbrr/internal/services/core"
 	"github.com/autobrr/dashbrr/internal/services/radarr"
 	"github.com/autobrr/dashbrr/internal/services/sonarr"
@@ -38,9 +41,34 @@ func (e *ErrOverseerr) Error() string {
 	return fmt.Sprintf("%s:\n• %s", e.Message, errorList)
 }
 
+const (
+	// DefaultTitleLookupConcurrency is the number of Radarr and Sonarr title lookups run at once
+	DefaultTitleLookupConcurrency = 4
+
+	titleCachePrefix     = "overseerr:title:"
+	tmdbTitleCachePrefix = "overseerr:tmdbtitle:"
+	titleCacheTTL        = 24 * time.Hour
+)
+
+var titleLookupConcurrency atomic.Int64
+
+// SetTitleLookupConcurrency sets how many request titles are looked up in Radarr and Sonarr
+// at once, 0 uses DefaultTitleLookupConcurrency
+func SetTitleLookupConcurrency(concurrency int) {
+	titleLookupConcurrency.Store(int64(concurrency))
+}
+
+func getTitleLookupConcurrency() int {
+	if concurrency := titleLookupConcurrency.Load(); concurrency > 0 {
+		return int(concurrency)
+	}
+	return DefaultTitleLookupConcurrency
+}
+
 type OverseerrService struct {
 	core.ServiceCore
-	db *database.DB
+	db    *database.DB
+	store cache.Store
 }
 
 func init() {
@@ -68,6 +96,12 @@ func (s *OverseerrService) SetDB(db *database.DB) {
 	s.db = db
 }
 
+// SetCache sets the store request titles are cached in, titles are looked up on every
+// refresh without one
+func (s *OverseerrService) SetCache(store cache.Store) {
+	s.store = store
+}
+
 // UpdateRequestStatus updates the status of a media request (approve/reject)
 func (s *OverseerrService) UpdateRequestStatus(ctx context.Context, url, apiKey string, requestID int, approve bool) error {
 	if url == "" {
@@ -81,19 +115,9 @@ func (s *OverseerrService) UpdateRequestStatus(ctx context.Context, url, apiKey
 	}
 	endpoint := fmt.Sprintf("%s/api/v1/request/%d/%s", baseURL, requestID, status)
 
-	// Create an empty request body
-	emptyBody := bytes.NewReader([]byte("{}"))
-
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, emptyBody)
-	if err != nil {
-		return &ErrOverseerr{Message: "Failed to create request", Errors: []string{err.Error()}}
-	}
-
-	req.Header.Set("X-Api-Key", apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.MakeRequestWithBody(ctx, http.MethodPost, endpoint, []byte("{}"), map[string]string{
+		"X-Api-Key": apiKey,
+	})
 	if err != nil {
 		return &ErrOverseerr{Message: "Connection error", Errors: []string{err.Error()}}
 	}
@@ -109,47 +133,56 @@ func (s *OverseerrService) UpdateRequestStatus(ctx context.Context, url, apiKey
 	return nil
 }
 
-// fetchMediaTitle fetches the title from either Radarr or Sonarr based on mediaType
-func (s *OverseerrService) fetchMediaTitle(ctx context.Context, request types.MediaRequest) (string, error) {
+// arrServices holds the Radarr and Sonarr services titles are looked up in, resolved once per
+// page of requests
+type arrServices struct {
+	radarr *models.ServiceConfiguration
+	sonarr *models.ServiceConfiguration
+}
+
+// findArrServices resolves the Radarr and Sonarr services used for title lookups
+func (s *OverseerrService) findArrServices(ctx context.Context) (arrServices, error) {
+	var services arrServices
 	if s.db == nil {
-		return "", fmt.Errorf("database not initialized")
+		return services, fmt.Errorf("database not initialized")
 	}
 
-	var service *models.ServiceConfiguration
 	var err error
+	if services.radarr, err = s.db.GetServiceByInstancePrefix(ctx, "radarr"); err != nil {
+		return services, fmt.Errorf("failed to get Radarr service: %w", err)
+	}
+	if services.sonarr, err = s.db.GetServiceByInstancePrefix(ctx, "sonarr"); err != nil {
+		return services, fmt.Errorf("failed to get Sonarr service: %w", err)
+	}
+	return services, nil
+}
 
+// fetchMediaTitle fetches the title from either Radarr or Sonarr based on mediaType
+func (s *OverseerrService) fetchMediaTitle(ctx context.Context, request types.MediaRequest, services arrServices) (string, error) {
 	switch request.Media.MediaType {
 	case "movie":
-		// Find Radarr service by URL
-		service, err = s.db.GetServiceByInstancePrefix(context.Background(), "radarr")
-		if err != nil {
-			return "", fmt.Errorf("failed to get Radarr service: %w", err)
-		}
-		if service == nil {
+		if services.radarr == nil {
 			return "", fmt.Errorf("no Radarr service found")
 		}
 
 		radarrService := &radarr.RadarrService{}
+		services.radarr.Configure(radarrService)
 		// Use TmdbID for movie lookups
-		movie, err := radarrService.LookupByTmdbId(ctx, service.URL, service.APIKey, request.Media.TmdbID)
+		movie, err := radarrService.LookupByTmdbId(ctx, services.radarr.URL, services.radarr.APIKey, request.Media.TmdbID)
 		if err != nil {
 			return "", fmt.Errorf("failed to fetch movie from Radarr: %w", err)
 		}
 		return movie.Title, nil
 
 	case "tv":
-		// Find Sonarr service by URL
-		service, err = s.db.GetServiceByInstancePrefix(context.Background(), "sonarr")
-		if err != nil {
-			return "", fmt.Errorf("failed to get Sonarr service: %w", err)
-		}
-		if service == nil {
+		if services.sonarr == nil {
 			return "", fmt.Errorf("no Sonarr service found")
 		}
 
 		sonarrService := &sonarr.SonarrService{}
+		services.sonarr.Configure(sonarrService)
 		// Use TvdbID for TV show lookups
-		series, err := sonarrService.LookupByTvdbId(ctx, service.URL, service.APIKey, request.Media.TvdbID)
+		series, err := sonarrService.LookupByTvdbId(ctx, services.sonarr.URL, services.sonarr.APIKey, request.Media.TvdbID)
 		if err != nil {
 			return "", fmt.Errorf("failed to fetch series from Sonarr: %w", err)
 		}
@@ -160,6 +193,134 @@ func (s *OverseerrService) fetchMediaTitle(ctx context.Context, request types.Me
 	}
 }
 
+// fetchTMDBTitle fetches the title from Overseerr's own TMDB metadata, for requests whose
+// title can't be looked up in Radarr or Sonarr
+func (s *OverseerrService) fetchTMDBTitle(ctx context.Context, baseURL, apiKey string, request types.MediaRequest) (string, error) {
+	var endpoint string
+	switch request.Media.MediaType {
+	case "movie":
+		endpoint = fmt.Sprintf("%s/api/v1/movie/%d", baseURL, request.Media.TmdbID)
+	case "tv":
+		endpoint = fmt.Sprintf("%s/api/v1/tv/%d", baseURL, request.Media.TmdbID)
+	default:
+		return "", fmt.Errorf("unknown media type: %s", request.Media.MediaType)
+	}
+
+	resp, err := s.MakeRequestWithContext(ctx, endpoint, "", map[string]string{"X-Api-Key": apiKey})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	// Movies have a title, series a name
+	var details struct {
+		Title string `json:"title"`
+		Name  string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &details); err != nil {
+		return "", fmt.Errorf("failed to parse media details: %w", err)
+	}
+
+	if details.Title != "" {
+		return details.Title, nil
+	}
+	if details.Name != "" {
+		return details.Name, nil
+	}
+	return "", fmt.Errorf("no title in media details")
+}
+
+// titleCacheKey identifies a title by the id it is looked up with
+func titleCacheKey(request types.MediaRequest) string {
+	if request.Media.MediaType == "tv" {
+		return titleCachePrefix + "tv:" + strconv.Itoa(request.Media.TvdbID)
+	}
+	return titleCachePrefix + request.Media.MediaType + ":" + strconv.Itoa(request.Media.TmdbID)
+}
+
+// cachedTitle returns the cached title for cacheKey or fetches and caches it
+func (s *OverseerrService) cachedTitle(ctx context.Context, cacheKey string, fetch func() (string, error)) (string, error) {
+	var title string
+	if s.store != nil {
+		if err := s.store.Get(ctx, cacheKey, &title); err == nil && title != "" {
+			return title, nil
+		}
+	}
+
+	title, err := fetch()
+	if err != nil {
+		return "", err
+	}
+
+	if s.store != nil && title != "" {
+		if err := s.store.Set(ctx, cacheKey, title, titleCacheTTL); err != nil {
+			log.Debug().Err(err).Str("key", cacheKey).Msg("Failed to cache Overseerr request title")
+		}
+	}
+	return title, nil
+}
+
+// mediaTitle returns the title of the request. The Radarr or Sonarr title is preferred, when
+// that lookup fails the title Overseerr returned is kept, and only when there is none the
+// title is fetched from Overseerr's TMDB metadata.
+func (s *OverseerrService) mediaTitle(ctx context.Context, baseURL, apiKey string, request types.MediaRequest, services arrServices) (string, error) {
+	title, err := s.cachedTitle(ctx, titleCacheKey(request), func() (string, error) {
+		return s.fetchMediaTitle(ctx, request, services)
+	})
+	if err == nil {
+		return title, nil
+	}
+
+	if request.Media.Title != "" {
+		return request.Media.Title, nil
+	}
+
+	cacheKey := tmdbTitleCachePrefix + request.Media.MediaType + ":" + strconv.Itoa(request.Media.TmdbID)
+	return s.cachedTitle(ctx, cacheKey, func() (string, error) {
+		return s.fetchTMDBTitle(ctx, baseURL, apiKey, request)
+	})
+}
+
+// enrichTitles looks up the titles of the requests, running at most the configured number of
+// lookups at once
+func (s *OverseerrService) enrichTitles(ctx context.Context, baseURL, apiKey string, requests []types.MediaRequest) {
+	if len(requests) == 0 {
+		return
+	}
+
+	// Without Radarr or Sonarr the titles still come from Overseerr
+	services, err := s.findArrServices(ctx)
+	if err != nil {
+		log.Debug().Err(err).Msg("Skipping Radarr and Sonarr title lookups")
+	}
+
+	sem := make(chan struct{}, getTitleLookupConcurrency())
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func(request *types.MediaRequest) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if title, err := s.mediaTitle(ctx, baseURL, apiKey, *request, services); err == nil {
+				request.Media.Title = title
+			}
+		}(&requests[i])
+	}
+	wg.Wait()
+}
+
 func (s *OverseerrService) GetRequests(ctx context.Context, url, apiKey string) (*types.RequestsStats, error) {
 	if url == "" {
 		return nil, &ErrOverseerr{Message: "Configuration error", Errors: []string{"URL is required"}}
@@ -207,15 +368,19 @@ func (s *OverseerrService) GetRequests(ctx context.Context, url, apiKey string)
 			pendingCount++
 		}
 
-		// Try to fetch the title using the appropriate lookup method
-		title, err := s.fetchMediaTitle(ctx, mediaRequest)
-		if err == nil {
-			mediaRequest.Media.Title = title
+		mediaRequest.ResolveRequester(baseURL)
+
+		// Overseerr's own title is the fallback when the lookups below fail
+		if mediaRequest.Media.Title == "" {
+			mediaRequest.Media.Title = mediaRequest.Media.Name
 		}
 
 		mediaRequests = append(mediaRequests, mediaRequest)
 	}
 
+	// Look up the titles using the appropriate lookup method
+	s.enrichTitles(ctx, baseURL, apiKey, mediaRequests)
+
 	return &types.RequestsStats{
 		PendingCount: pendingCount,
 		Requests:     mediaRequests,
@@ -226,10 +391,10 @@ func (s *OverseerrService) CheckHealth(ctx context.Context, url, apiKey string)
 	startTime := time.Now()
 
 	if url == "" {
-		return s.CreateHealthResponse(startTime, "error", (&ErrOverseerr{
-			Message: "Configuration error",
-			Errors:  []string{"URL is required"},
-		}).Error()), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+	if apiKey == "" {
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
 	healthEndpoint := s.GetHealthEndpoint(url)
@@ -240,7 +405,7 @@ func (s *OverseerrService) CheckHealth(ctx context.Context, url, apiKey string)
 
 	resp, err := s.MakeRequestWithContext(ctx, healthEndpoint, "", headers)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "offline", (&ErrOverseerr{
+		return s.CreateHealthResponse(startTime, models.StatusOffline, (&ErrOverseerr{
 			Message: "Connection error",
 			Errors:  []string{err.Error()},
 		}).Error()), http.StatusOK
@@ -250,6 +415,10 @@ func (s *OverseerrService) CheckHealth(ctx context.Context, url, apiKey string)
 	// Calculate response time directly
 	responseTime := time.Since(startTime).Milliseconds()
 
+	if models.StatusForResponseCode(resp.StatusCode) == models.StatusUnauthorized {
+		return s.CreateUnauthorizedResponse(startTime, resp.StatusCode), http.StatusOK
+	}
+
 	body, err := s.ReadBody(resp)
 	if err != nil {
 		errMsg := (&ErrOverseerr{
@@ -259,15 +428,15 @@ func (s *OverseerrService) CheckHealth(ctx context.Context, url, apiKey string)
 
 		// Align error status with request failures
 		if resp.StatusCode >= 500 {
-			return s.CreateHealthResponse(startTime, "error", errMsg), http.StatusOK
+			return s.CreateHealthResponse(startTime, models.StatusError, errMsg), http.StatusOK
 		}
-		return s.CreateHealthResponse(startTime, "warning", errMsg), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusWarning, errMsg), http.StatusOK
 	}
 
 	// Parse the response
 	var statusResponse types.StatusResponse
 	if err := json.Unmarshal(body, &statusResponse); err != nil {
-		return s.CreateHealthResponse(startTime, "warning", (&ErrOverseerr{
+		return s.CreateHealthResponse(startTime, models.StatusWarning, (&ErrOverseerr{
 			Message: "Response error",
 			Errors:  []string{"Failed to parse status response"},
 		}).Error()), http.StatusOK
@@ -280,12 +449,12 @@ func (s *OverseerrService) CheckHealth(ctx context.Context, url, apiKey string)
 		"responseTime":    responseTime,
 	}
 
-	status := "online"
+	status := models.StatusOnline
 	message := "healthy"
 
 	if statusResponse.Status != 0 {
 		if statusResponse.Status >= 400 {
-			status = "warning"
+			status = models.StatusWarning
 			message = (&ErrOverseerr{
 				Message: "Service warning",
 				Errors:  []string{fmt.Sprintf("Service reported status code: %d", statusResponse.Status)},
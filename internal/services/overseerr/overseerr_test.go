@@ -0,0 +1,189 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package overseerr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/database"
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/cache"
+	"github.com/autobrr/dashbrr/internal/types"
+)
+
+func TestEnrichTitles(t *testing.T) {
+	var calls, active, maxActive atomic.Int32
+	radarr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			seen := maxActive.Load()
+			if current <= seen || maxActive.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		fmt.Fprintf(w, `{"title":"Movie %s"}`, r.URL.Query().Get("tmdbId"))
+	}))
+	t.Cleanup(radarr.Close)
+
+	ctx := context.Background()
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to init database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.CreateService(ctx, &models.ServiceConfiguration{InstanceID: "radarr-1", URL: radarr.URL, APIKey: "key"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	store := cache.NewMemoryStore(ctx, t.TempDir())
+	t.Cleanup(func() { store.Close() })
+
+	SetTitleLookupConcurrency(2)
+	t.Cleanup(func() { SetTitleLookupConcurrency(0) })
+
+	service := &OverseerrService{}
+	service.SetDB(db)
+	service.SetCache(store)
+
+	requests := make([]types.MediaRequest, 6)
+	for i := range requests {
+		requests[i].Media.MediaType = "movie"
+		requests[i].Media.TmdbID = i + 1
+	}
+
+	service.enrichTitles(ctx, "", "", requests)
+
+	for i, request := range requests {
+		if want := fmt.Sprintf("Movie %d", i+1); request.Media.Title != want {
+			t.Errorf("Expected title %q, got %q", want, request.Media.Title)
+		}
+	}
+	if maxActive.Load() > 2 {
+		t.Errorf("Expected at most 2 lookups at once, got %d", maxActive.Load())
+	}
+
+	// Recurring requests are served from the cache
+	before := calls.Load()
+	service.enrichTitles(ctx, "", "", requests)
+	if calls.Load() != before {
+		t.Errorf("Expected cached titles, got %d new lookups", calls.Load()-before)
+	}
+}
+
+func TestEnrichTitlesFallback(t *testing.T) {
+	overseerr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tv/5" || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"Some Show"}`))
+	}))
+	t.Cleanup(overseerr.Close)
+
+	// Neither Radarr nor Sonarr is configured
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to init database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := &OverseerrService{}
+	service.SetDB(db)
+
+	requests := make([]types.MediaRequest, 2)
+	requests[0].Media.MediaType = "movie"
+	requests[0].Media.Title = "Overseerr Title"
+	requests[1].Media.MediaType = "tv"
+	requests[1].Media.TmdbID = 5
+
+	service.enrichTitles(context.Background(), overseerr.URL, "key", requests)
+
+	if requests[0].Media.Title != "Overseerr Title" {
+		t.Errorf("Expected Overseerr's title to be kept, got %q", requests[0].Media.Title)
+	}
+	if requests[1].Media.Title != "Some Show" {
+		t.Errorf("Expected the TMDB title, got %q", requests[1].Media.Title)
+	}
+}
+
+func TestUpdateRequestStatus(t *testing.T) {
+	var path, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		if r.Method != http.MethodPost || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewOverseerrService().(*OverseerrService)
+	if err := service.UpdateRequestStatus(context.Background(), server.URL, "key", 7, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if path != "/api/v1/request/7/decline" || contentType != "application/json" {
+		t.Errorf("Unexpected request to %s with content type %q", path, contentType)
+	}
+}
+
+func TestUpdateRequestStatusCancelled(t *testing.T) {
+	// The upstream hangs until the client gives up. The body is drained first, the server
+	// only notices the client going away once it has been read.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		timeout time.Duration
+	}{
+		{"context deadline", deadline, 0},
+		{"service timeout without deadline", context.Background(), 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewOverseerrService().(*OverseerrService)
+			if tt.timeout > 0 {
+				service.SetTimeout(tt.timeout)
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- service.UpdateRequestStatus(tt.ctx, server.URL, "key", 7, true)
+			}()
+
+			select {
+			case err := <-done:
+				if err == nil {
+					t.Fatal("Expected an error from a hung upstream")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("UpdateRequestStatus did not give up on the hung upstream")
+			}
+		})
+	}
+}
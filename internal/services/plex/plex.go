@@ -150,7 +150,10 @@ func (s *PlexService) CheckHealth(ctx context.Context, url, apiKey string) (mode
 	startTime := time.Now()
 
 	if url == "" {
-		return s.CreateHealthResponse(startTime, "error", "URL is required"), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+	if apiKey == "" {
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
 	healthEndpoint := s.GetHealthEndpoint(url)
@@ -158,7 +161,7 @@ func (s *PlexService) CheckHealth(ctx context.Context, url, apiKey string) (mode
 
 	resp, err := s.MakeRequestWithContext(ctx, healthEndpoint, "", headers)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "offline", fmt.Sprintf("Failed to connect: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusOK
 	}
 	defer resp.Body.Close()
 
@@ -167,11 +170,11 @@ func (s *PlexService) CheckHealth(ctx context.Context, url, apiKey string) (mode
 
 	body, err := s.ReadBody(resp)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "warning", fmt.Sprintf("Failed to read response: %v", err)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusWarning, fmt.Sprintf("Failed to read response: %v", err)), http.StatusOK
 	}
 
 	if resp.StatusCode >= 400 {
-		return s.CreateHealthResponse(startTime, "error", fmt.Sprintf("Server returned error: %d", resp.StatusCode)), http.StatusOK
+		return s.CreateHealthResponse(startTime, models.StatusError, fmt.Sprintf("Server returned error: %d", resp.StatusCode)), http.StatusOK
 	}
 
 	// Get version using GetCachedVersion for better caching
@@ -186,7 +189,7 @@ func (s *PlexService) CheckHealth(ctx context.Context, url, apiKey string) (mode
 	if err := json.Unmarshal(body, &plexResponse); err != nil {
 		var mediaContainer types.MediaContainer
 		if xmlErr := xml.Unmarshal(body, &mediaContainer); xmlErr != nil {
-			return s.CreateHealthResponse(startTime, "warning", "Failed to parse server response"), http.StatusOK
+			return s.CreateHealthResponse(startTime, models.StatusWarning, "Failed to parse server response"), http.StatusOK
 		}
 		plexResponse.MediaContainer = mediaContainer
 	}
@@ -203,5 +206,5 @@ func (s *PlexService) CheckHealth(ctx context.Context, url, apiKey string) (mode
 		message = fmt.Sprintf("Healthy - Running on %s", plexResponse.MediaContainer.Platform)
 	}
 
-	return s.CreateHealthResponse(startTime, "online", message, extras), http.StatusOK
+	return s.CreateHealthResponse(startTime, models.StatusOnline, message, extras), http.StatusOK
 }
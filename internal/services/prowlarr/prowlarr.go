@@ -61,18 +61,11 @@ func NewProwlarrService() models.ServiceHealthChecker {
 	return service
 }
 
-// makeRequest is a helper function to make requests with proper headers
+// makeRequest makes a request with proper headers through the pooled client of the service
 func (s *ProwlarrService) makeRequest(ctx context.Context, method, url, apiKey string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("X-Api-Key", apiKey)
-	req.Header.Set("Accept", "*/*")
-
-	client := &http.Client{}
-	return client.Do(req)
+	return s.MakeRequestWithBody(ctx, method, url, nil, map[string]string{
+		"X-Api-Key": apiKey,
+	})
 }
 
 // GetSystemStatus fetches the system status from Prowlarr
@@ -142,16 +135,115 @@ func (s *ProwlarrService) GetIndexerStats(ctx context.Context, baseURL, apiKey s
 	}
 
 	var stats types.ProwlarrIndexerStatsResponse
-	if err := json.Unmarshal(body, &stats); err != nil {
+	if err := arr.DecodeJSON(body, &stats); err != nil {
 		return nil, &ErrProwlarr{Op: "get_indexer_stats", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
 	return &stats, nil
 }
 
+// GetIndexerStatus fetches the indexers Prowlarr has disabled after failures
+func (s *ProwlarrService) GetIndexerStatus(ctx context.Context, baseURL, apiKey string) ([]types.ProwlarrIndexerStatus, error) {
+	if baseURL == "" {
+		return nil, &ErrProwlarr{Op: "get_indexer_status", Err: fmt.Errorf("URL is required")}
+	}
+
+	statusURL := fmt.Sprintf("%s/api/v1/indexerstatus", strings.TrimRight(baseURL, "/"))
+	resp, err := s.makeRequest(ctx, http.MethodGet, statusURL, apiKey)
+	if err != nil {
+		return nil, &ErrProwlarr{Op: "get_indexer_status", Err: fmt.Errorf("failed to make request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErrProwlarr{Op: "get_indexer_status", HttpCode: resp.StatusCode}
+	}
+
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return nil, &ErrProwlarr{Op: "get_indexer_status", Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	var statuses []types.ProwlarrIndexerStatus
+	if err := arr.DecodeJSON(body, &statuses); err != nil {
+		return nil, &ErrProwlarr{Op: "get_indexer_status", Err: fmt.Errorf("failed to parse response: %w", err)}
+	}
+
+	return statuses, nil
+}
+
+// IndexersResult holds the indexers enriched with their stats. The indexers are still
+// returned when only the stats failed to load, StatsErr records that failure so an
+// incomplete result isn't presented as a complete one.
+type IndexersResult struct {
+	Indexers []types.ProwlarrIndexer
+	StatsErr error
+}
+
+// Partial reports whether the indexers are missing their stats
+func (r *IndexersResult) Partial() bool {
+	return r.StatsErr != nil
+}
+
+// GetIndexers fetches the indexers from Prowlarr and enriches them with their stats
+func (s *ProwlarrService) GetIndexers(ctx context.Context, baseURL, apiKey string) (*IndexersResult, error) {
+	if baseURL == "" {
+		return nil, &ErrProwlarr{Op: "get_indexers", Err: fmt.Errorf("URL is required")}
+	}
+
+	indexersURL := fmt.Sprintf("%s/api/v1/indexer", strings.TrimRight(baseURL, "/"))
+	resp, err := s.makeRequest(ctx, http.MethodGet, indexersURL, apiKey)
+	if err != nil {
+		return nil, &ErrProwlarr{Op: "get_indexers", Err: fmt.Errorf("failed to make request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErrProwlarr{Op: "get_indexers", HttpCode: resp.StatusCode}
+	}
+
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return nil, &ErrProwlarr{Op: "get_indexers", Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	var indexers []types.ProwlarrIndexer
+	if err := arr.DecodeJSON(body, &indexers); err != nil {
+		return nil, &ErrProwlarr{Op: "get_indexers", Err: fmt.Errorf("failed to parse response: %w", err)}
+	}
+
+	result := &IndexersResult{Indexers: indexers}
+
+	stats, err := s.GetIndexerStats(ctx, baseURL, apiKey)
+	if err != nil {
+		result.StatsErr = err
+		return result, nil
+	}
+
+	statsMap := make(map[int]types.ProwlarrIndexerStats, len(stats.Indexers))
+	for _, stat := range stats.Indexers {
+		statsMap[stat.IndexerID] = stat
+	}
+
+	for i := range result.Indexers {
+		if stat, ok := statsMap[result.Indexers[i].ID]; ok {
+			result.Indexers[i].AverageResponseTime = stat.AverageResponseTime
+			result.Indexers[i].NumberOfGrabs = stat.NumberOfGrabs
+			result.Indexers[i].NumberOfQueries = stat.NumberOfQueries
+		}
+	}
+
+	return result, nil
+}
+
 // CheckForUpdates checks if there are any updates available
 func (s *ProwlarrService) CheckForUpdates(url, apiKey string) (bool, error) {
-	return arr.CheckArrForUpdates("prowlarr", url, apiKey)
+	return arr.CheckArrForUpdates("prowlarr", url, apiKey, "", &s.ServiceCore)
+}
+
+// RefreshUpdate checks Prowlarr for updates right away, bypassing the cached result
+func (s *ProwlarrService) RefreshUpdate(ctx context.Context, url, apiKey string) (bool, error) {
+	return arr.RefreshArrUpdate(&s.ServiceCore, url, apiKey, s)
 }
 
 // GetQueue gets the current queue status
@@ -169,3 +261,8 @@ func (s *ProwlarrService) GetHealthEndpoint(baseURL string) string {
 func (s *ProwlarrService) CheckHealth(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
 	return arr.ArrHealthCheck(&s.ServiceCore, url, apiKey, s)
 }
+
+// GetHealthIssues returns the issues Prowlarr reports about itself
+func (s *ProwlarrService) GetHealthIssues(ctx context.Context, url, apiKey string) ([]arr.HealthResponse, error) {
+	return arr.GetHealthIssues(ctx, "prowlarr", url, apiKey, &s.ServiceCore, s)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package prowlarr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestServer serves the indexer and indexer stats endpoints, failing the ones
+// with a non-200 status
+func newTestServer(t *testing.T, indexersStatus, statsStatus int) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/indexer", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(indexersStatus)
+		_, _ = w.Write([]byte(`[{"id":1,"name":"indexer-1","enable":true},{"id":2,"name":"indexer-2"}]`))
+	})
+	mux.HandleFunc("/api/v1/indexerstats", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statsStatus)
+		_, _ = w.Write([]byte(`{"indexers":[{"indexerId":1,"numberOfGrabs":5,"numberOfQueries":10}]}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetIndexers(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, http.StatusOK)
+	service := NewProwlarrService().(*ProwlarrService)
+
+	result, err := service.GetIndexers(context.Background(), server.URL, "key")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Partial() {
+		t.Errorf("Expected a complete result, got stats error %v", result.StatsErr)
+	}
+	if len(result.Indexers) != 2 {
+		t.Fatalf("Expected 2 indexers, got %d", len(result.Indexers))
+	}
+	if result.Indexers[0].NumberOfGrabs != 5 || result.Indexers[0].NumberOfQueries != 10 {
+		t.Errorf("Expected indexer 1 to be enriched with its stats, got %+v", result.Indexers[0])
+	}
+}
+
+func TestGetIndexersWithoutStats(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, http.StatusInternalServerError)
+	service := NewProwlarrService().(*ProwlarrService)
+
+	result, err := service.GetIndexers(context.Background(), server.URL, "key")
+	if err != nil {
+		t.Fatalf("Expected the indexers despite the stats failing, got error %v", err)
+	}
+	if !result.Partial() {
+		t.Fatal("Expected a partial result")
+	}
+	if len(result.Indexers) != 2 {
+		t.Errorf("Expected 2 indexers, got %d", len(result.Indexers))
+	}
+
+	var prowlarrErr *ErrProwlarr
+	if !errors.As(result.StatsErr, &prowlarrErr) {
+		t.Fatalf("Expected an *ErrProwlarr, got %T", result.StatsErr)
+	}
+	if prowlarrErr.Op != "get_indexer_stats" || prowlarrErr.HttpCode != http.StatusInternalServerError {
+		t.Errorf("Unexpected stats error %+v", prowlarrErr)
+	}
+}
+
+func TestGetIndexersFailure(t *testing.T) {
+	server := newTestServer(t, http.StatusUnauthorized, http.StatusOK)
+	service := NewProwlarrService().(*ProwlarrService)
+
+	result, err := service.GetIndexers(context.Background(), server.URL, "key")
+	if err == nil {
+		t.Fatalf("Expected an error, got %+v", result)
+	}
+
+	var prowlarrErr *ErrProwlarr
+	if !errors.As(err, &prowlarrErr) || prowlarrErr.HttpCode != http.StatusUnauthorized {
+		t.Errorf("Expected a 401 *ErrProwlarr, got %v", err)
+	}
+}
+
+func TestGetIndexersAuthHeaderOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Custom-Key") != "key" || r.Header.Get("X-Api-Key") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewProwlarrService().(*ProwlarrService)
+	service.SetAuthHeader("X-Custom-Key")
+
+	if _, err := service.GetIndexers(context.Background(), server.URL, "key"); err != nil {
+		t.Fatalf("Expected the API key in the overridden header, got error %v", err)
+	}
+}
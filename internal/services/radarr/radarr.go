@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/rs/zerolog/log"
 
@@ -20,6 +19,14 @@ import (
 
 type RadarrService struct {
 	core.ServiceCore
+
+	// APIVersion is the API path version, empty uses models.DefaultArrAPIVersion
+	APIVersion string
+}
+
+// SetAPIVersion sets the API path version used to build endpoint URLs
+func (s *RadarrService) SetAPIVersion(version string) {
+	s.APIVersion = version
 }
 
 func init() {
@@ -38,8 +45,7 @@ func NewRadarrService() models.ServiceHealthChecker {
 }
 
 func (s *RadarrService) GetHealthEndpoint(baseURL string) string {
-	baseURL = strings.TrimRight(baseURL, "/")
-	return fmt.Sprintf("%s/api/v3/health", baseURL)
+	return arr.APIURL(baseURL, s.APIVersion, "/health")
 }
 
 // DeleteQueueItem deletes a queue item with the specified options
@@ -53,8 +59,8 @@ func (s *RadarrService) DeleteQueueItem(ctx context.Context, baseURL, apiKey str
 	}
 
 	// Build delete URL with query parameters
-	deleteURL := fmt.Sprintf("%s/api/v3/queue/%s?removeFromClient=%t&blocklist=%t&skipRedownload=%t",
-		strings.TrimRight(baseURL, "/"),
+	deleteURL := fmt.Sprintf("%s/queue/%s?removeFromClient=%t&blocklist=%t&skipRedownload=%t",
+		arr.APIURL(baseURL, s.APIVersion, ""),
 		queueId,
 		options.RemoveFromClient,
 		options.Blocklist,
@@ -76,7 +82,7 @@ func (s *RadarrService) DeleteQueueItem(ctx context.Context, baseURL, apiKey str
 		Msg("Attempting to delete queue item")
 
 	// Execute DELETE request
-	resp, err := arr.MakeArrRequest(ctx, http.MethodDelete, deleteURL, apiKey, nil)
+	resp, err := arr.MakeArrRequest(ctx, http.MethodDelete, deleteURL, apiKey, &s.ServiceCore, nil)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -114,6 +120,16 @@ func (s *RadarrService) DeleteQueueItem(ctx context.Context, baseURL, apiKey str
 
 // GetQueue fetches the current queue from Radarr
 func (s *RadarrService) GetQueue(ctx context.Context, url, apiKey string) (interface{}, error) {
+	records, err := s.GetQueueWithOptions(ctx, url, apiKey, types.RadarrQueueOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetQueueWithOptions fetches the current queue from Radarr, optionally with the items it
+// can't match to a movie
+func (s *RadarrService) GetQueueWithOptions(ctx context.Context, url, apiKey string, options types.RadarrQueueOptions) ([]types.RadarrQueueRecord, error) {
 	if url == "" {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue", Err: fmt.Errorf("URL is required")}
 	}
@@ -123,10 +139,12 @@ func (s *RadarrService) GetQueue(ctx context.Context, url, apiKey string) (inter
 	}
 
 	// Build queue URL with query parameters
-	queueURL := fmt.Sprintf("%s/api/v3/queue?page=1&pageSize=10&includeUnknownMovieItems=false&includeMovie=false",
-		strings.TrimRight(url, "/"))
+	queueURL := fmt.Sprintf("%s/queue?page=1&pageSize=10&includeUnknownMovieItems=%t&includeMovie=%t",
+		arr.APIURL(url, s.APIVersion, ""),
+		options.IncludeUnknownMovieItems,
+		options.IncludeMovie)
 
-	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, queueURL, apiKey, nil)
+	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, queueURL, apiKey, &s.ServiceCore, nil)
 	if err != nil {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
@@ -142,23 +160,58 @@ func (s *RadarrService) GetQueue(ctx context.Context, url, apiKey string) (inter
 	}
 
 	var queue types.RadarrQueueResponse
-	if err := json.Unmarshal(body, &queue); err != nil {
+	if err := arr.DecodeJSON(body, &queue); err != nil {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
 	return queue.Records, nil
 }
 
-// GetQueueForHealth is a wrapper around GetQueue that returns []types.RadarrQueueRecord
-func (s *RadarrService) GetQueueForHealth(ctx context.Context, url, apiKey string) ([]types.RadarrQueueRecord, error) {
-	records, err := s.GetQueue(ctx, url, apiKey)
+// GetQueueItem fetches a single queue record with its full status messages. Radarr has no
+// endpoint for one record, so it is looked up in the queue details.
+func (s *RadarrService) GetQueueItem(ctx context.Context, url, apiKey string, id int) (*types.RadarrQueueRecord, error) {
+	if url == "" {
+		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", Err: fmt.Errorf("URL is required")}
+	}
+
+	if apiKey == "" {
+		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", Err: fmt.Errorf("API key is required")}
+	}
+
+	detailsURL := fmt.Sprintf("%s/queue/details?includeMovie=true", arr.APIURL(url, s.APIVersion, ""))
+
+	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, detailsURL, apiKey, &s.ServiceCore, nil)
 	if err != nil {
-		return nil, err
+		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
-	if records == nil {
-		return nil, nil
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", HttpCode: resp.StatusCode}
+	}
+
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	var records []types.RadarrQueueRecord
+	if err := arr.DecodeJSON(body, &records); err != nil {
+		return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", Err: fmt.Errorf("failed to parse response: %w", err)}
+	}
+
+	for i := range records {
+		if records[i].ID == id {
+			return &records[i], nil
+		}
 	}
-	return records.([]types.RadarrQueueRecord), nil
+
+	return nil, &arr.ErrArr{Service: "radarr", Op: "get_queue_item", HttpCode: http.StatusNotFound}
+}
+
+// GetQueueForHealth returns the queue with the default options
+func (s *RadarrService) GetQueueForHealth(ctx context.Context, url, apiKey string) ([]types.RadarrQueueRecord, error) {
+	return s.GetQueueWithOptions(ctx, url, apiKey, types.RadarrQueueOptions{})
 }
 
 // LookupByTmdbId fetches movie details from Radarr by TMDB ID
@@ -171,9 +224,9 @@ func (s *RadarrService) LookupByTmdbId(ctx context.Context, baseURL, apiKey stri
 		return nil, &arr.ErrArr{Service: "radarr", Op: "lookup_tmdb", Err: fmt.Errorf("API key is required")}
 	}
 
-	lookupURL := fmt.Sprintf("%s/api/v3/movie/lookup/tmdb?tmdbId=%d", strings.TrimRight(baseURL, "/"), tmdbId)
+	lookupURL := fmt.Sprintf("%s/movie/lookup/tmdb?tmdbId=%d", arr.APIURL(baseURL, s.APIVersion, ""), tmdbId)
 
-	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, lookupURL, apiKey, nil)
+	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, lookupURL, apiKey, &s.ServiceCore, nil)
 	if err != nil {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "lookup_tmdb", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
@@ -189,7 +242,7 @@ func (s *RadarrService) LookupByTmdbId(ctx context.Context, baseURL, apiKey stri
 	}
 
 	var movie types.RadarrMovieResponse
-	if err := json.Unmarshal(body, &movie); err != nil {
+	if err := arr.DecodeJSON(body, &movie); err != nil {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "lookup_tmdb", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
@@ -206,9 +259,9 @@ func (s *RadarrService) GetMovie(ctx context.Context, baseURL, apiKey string, mo
 		return nil, &arr.ErrArr{Service: "radarr", Op: "get_movie", Err: fmt.Errorf("API key is required")}
 	}
 
-	movieURL := fmt.Sprintf("%s/api/v3/movie/%d", strings.TrimRight(baseURL, "/"), movieID)
+	movieURL := fmt.Sprintf("%s/movie/%d", arr.APIURL(baseURL, s.APIVersion, ""), movieID)
 
-	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, movieURL, apiKey, nil)
+	resp, err := arr.MakeArrRequest(ctx, http.MethodGet, movieURL, apiKey, &s.ServiceCore, nil)
 	if err != nil {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "get_movie", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
@@ -224,7 +277,7 @@ func (s *RadarrService) GetMovie(ctx context.Context, baseURL, apiKey string, mo
 	}
 
 	var movie types.RadarrMovieResponse
-	if err := json.Unmarshal(body, &movie); err != nil {
+	if err := arr.DecodeJSON(body, &movie); err != nil {
 		return nil, &arr.ErrArr{Service: "radarr", Op: "get_movie", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
@@ -233,14 +286,24 @@ func (s *RadarrService) GetMovie(ctx context.Context, baseURL, apiKey string, mo
 
 // GetSystemStatus fetches the system status from Radarr
 func (s *RadarrService) GetSystemStatus(url, apiKey string) (string, error) {
-	return arr.GetArrSystemStatus("radarr", url, apiKey, s.GetVersionFromCache, s.CacheVersion)
+	return arr.GetArrSystemStatus("radarr", url, apiKey, s.APIVersion, &s.ServiceCore, s.GetVersionFromCache, s.CacheVersion)
 }
 
 // CheckForUpdates checks if there are any updates available for Radarr
 func (s *RadarrService) CheckForUpdates(url, apiKey string) (bool, error) {
-	return arr.CheckArrForUpdates("radarr", url, apiKey)
+	return arr.CheckArrForUpdates("radarr", url, apiKey, s.APIVersion, &s.ServiceCore)
+}
+
+// RefreshUpdate checks Radarr for updates right away, bypassing the cached result
+func (s *RadarrService) RefreshUpdate(ctx context.Context, url, apiKey string) (bool, error) {
+	return arr.RefreshArrUpdate(&s.ServiceCore, url, apiKey, s)
 }
 
 func (s *RadarrService) CheckHealth(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
 	return arr.ArrHealthCheck(&s.ServiceCore, url, apiKey, s)
 }
+
+// GetHealthIssues returns the issues Radarr reports about itself
+func (s *RadarrService) GetHealthIssues(ctx context.Context, url, apiKey string) ([]arr.HealthResponse, error) {
+	return arr.GetHealthIssues(ctx, "radarr", url, apiKey, &s.ServiceCore, s)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/core"
+)
+
+func init() {
+	models.NewRedisService = NewRedisService
+}
+
+func NewRedisService() models.ServiceHealthChecker {
+	service := &RedisService{}
+	service.Type = "redis"
+	service.DisplayName = "Redis"
+	service.Description = "Monitor a Redis server with PING, reporting memory use and connected clients"
+	service.SetTimeout(core.DefaultTimeout)
+	return service
+}
+
+// RedisService checks a Redis server with its own connection, independent of the cache
+// dashbrr itself may keep in Redis
+type RedisService struct {
+	core.ServiceCore
+}
+
+// options parses redis://[user:password@]host:port[/db] or host:port. The API key is
+// used as the password when the URL carries none.
+func options(raw, apiKey string) (*redis.Options, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "redis://" + raw
+	}
+
+	opts, err := redis.ParseURL(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %v", err)
+	}
+	if opts.Password == "" {
+		opts.Password = apiKey
+	}
+
+	// One connection without retries, a failed check is reported rather than retried
+	opts.PoolSize = 1
+	opts.MaxRetries = -1
+	return opts, nil
+}
+
+// CheckHealth pings the server and reads its version, memory use and connected clients.
+// The server is still reported online when INFO is not permitted.
+func (s *RedisService) CheckHealth(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
+	startTime := time.Now()
+
+	if url == "" {
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+
+	opts, err := options(url, apiKey)
+	if err != nil {
+		return s.CreateHealthResponse(startTime, models.StatusError, err.Error()), http.StatusBadRequest
+	}
+
+	healthCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	if err := client.Ping(healthCtx).Err(); err != nil {
+		if isAuthError(err) {
+			return s.CreateHealthResponse(startTime, models.StatusUnauthorized, fmt.Sprintf("Authentication failed: %v", err)), http.StatusUnauthorized
+		}
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusServiceUnavailable
+	}
+	latency := time.Since(startTime)
+
+	extras := map[string]interface{}{
+		"responseTime": latency.Milliseconds(),
+	}
+
+	raw, err := client.Info(healthCtx, "server", "memory", "clients").Result()
+	if err != nil {
+		log.Debug().Err(err).Str("address", opts.Addr).Msg("Failed to read Redis info")
+		return s.CreateHealthResponse(startTime, models.StatusOnline, "PONG", extras), http.StatusOK
+	}
+
+	info := parseInfo(raw)
+	if version := info["redis_version"]; version != "" {
+		extras["version"] = version
+	}
+
+	details := map[string]interface{}{}
+	if usedMemory, err := strconv.ParseInt(info["used_memory"], 10, 64); err == nil {
+		details["usedMemory"] = usedMemory
+	}
+	if clients, err := strconv.Atoi(info["connected_clients"]); err == nil {
+		details["connectedClients"] = clients
+	}
+	extras["details"] = map[string]interface{}{"redis": details}
+
+	message := "PONG"
+	if human := info["used_memory_human"]; human != "" {
+		message = fmt.Sprintf("%s used, %s clients", human, info["connected_clients"])
+	}
+
+	return s.CreateHealthResponse(startTime, models.StatusOnline, message, extras), http.StatusOK
+}
+
+// isAuthError reports whether the server rejected the credentials or requires some
+func isAuthError(err error) bool {
+	message := err.Error()
+	return strings.HasPrefix(message, "NOAUTH") || strings.HasPrefix(message, "WRONGPASS") ||
+		strings.Contains(message, "invalid password")
+}
+
+// parseInfo parses the output of INFO into its fields, skipping section headers
+func parseInfo(raw string) map[string]string {
+	info := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			info[key] = value
+		}
+	}
+	return info
+}
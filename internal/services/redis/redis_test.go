@@ -0,0 +1,159 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+const testInfo = "# Server\r\nredis_version:7.2.4\r\n\r\n# Clients\r\nconnected_clients:3\r\n\r\n# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\n"
+
+// serve runs a minimal RESP server answering AUTH, PING and INFO until the test ends.
+// A non-empty password must be sent with AUTH before any other command.
+func serve(t *testing.T, password string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn, password)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func handle(conn net.Conn, password string) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		switch command := strings.ToUpper(args[0]); {
+		case command == "AUTH":
+			if args[len(args)-1] != password {
+				fmt.Fprint(conn, "-WRONGPASS invalid username-password pair\r\n")
+				continue
+			}
+			authenticated = true
+			fmt.Fprint(conn, "+OK\r\n")
+		case !authenticated:
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+		case command == "PING":
+			fmt.Fprint(conn, "+PONG\r\n")
+		case command == "INFO":
+			fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(testInfo), testInfo)
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+// readCommand reads one RESP array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil { // $<length>
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSpace(arg))
+	}
+	return args, nil
+}
+
+func TestCheckHealth(t *testing.T) {
+	open := serve(t, "")
+	protected := serve(t, "secret")
+
+	// A closed port, the listener is gone by the time it is dialed
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closed := ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		apiKey string
+		status models.ServiceStatus
+		code   int
+	}{
+		{"host and port", open, "", models.StatusOnline, http.StatusOK},
+		{"redis scheme", "redis://" + open + "/0", "", models.StatusOnline, http.StatusOK},
+		{"password in url", "redis://:secret@" + protected, "", models.StatusOnline, http.StatusOK},
+		{"password as api key", protected, "secret", models.StatusOnline, http.StatusOK},
+		{"wrong password", protected, "wrong", models.StatusUnauthorized, http.StatusUnauthorized},
+		{"no password", protected, "", models.StatusUnauthorized, http.StatusUnauthorized},
+		{"connection refused", closed, "", models.StatusOffline, http.StatusServiceUnavailable},
+		{"invalid scheme", "http://" + open, "", models.StatusError, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRedisService().(*RedisService)
+			health, code := service.CheckHealth(context.Background(), tt.url, tt.apiKey)
+			if health.Status != tt.status || code != tt.code {
+				t.Fatalf("Expected %s (%d), got %s (%d): %s", tt.status, tt.code, health.Status, code, health.Message)
+			}
+		})
+	}
+}
+
+func TestCheckHealthInfo(t *testing.T) {
+	service := NewRedisService().(*RedisService)
+	health, _ := service.CheckHealth(context.Background(), serve(t, ""), "")
+
+	if health.Version != "7.2.4" {
+		t.Errorf("Expected version 7.2.4, got %q", health.Version)
+	}
+	if health.Message != "1.00M used, 3 clients" {
+		t.Errorf("Unexpected message %q", health.Message)
+	}
+
+	details, ok := health.Details["redis"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected details.redis, got %+v", health.Details)
+	}
+	if details["usedMemory"] != int64(1048576) || details["connectedClients"] != 3 {
+		t.Errorf("Unexpected details %+v", details)
+	}
+}
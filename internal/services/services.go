@@ -7,6 +7,7 @@ import (
 	// Import all services to register their init functions
 
 	_ "github.com/autobrr/dashbrr/internal/services/autobrr"
+	_ "github.com/autobrr/dashbrr/internal/services/database"
 	_ "github.com/autobrr/dashbrr/internal/services/general"
 	_ "github.com/autobrr/dashbrr/internal/services/maintainerr"
 	_ "github.com/autobrr/dashbrr/internal/services/omegabrr"
@@ -14,6 +15,8 @@ import (
 	_ "github.com/autobrr/dashbrr/internal/services/plex"
 	_ "github.com/autobrr/dashbrr/internal/services/prowlarr"
 	_ "github.com/autobrr/dashbrr/internal/services/radarr"
+	_ "github.com/autobrr/dashbrr/internal/services/redis"
 	_ "github.com/autobrr/dashbrr/internal/services/sonarr"
 	_ "github.com/autobrr/dashbrr/internal/services/tailscale"
+	_ "github.com/autobrr/dashbrr/internal/services/tcp"
 )
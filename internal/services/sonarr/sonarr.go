@@ -4,12 +4,10 @@
 package sonarr
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -43,6 +41,14 @@ func (e *ErrSonarr) Unwrap() error {
 
 type SonarrService struct {
 	core.ServiceCore
+
+	// APIVersion is the API path version, empty uses models.DefaultArrAPIVersion
+	APIVersion string
+}
+
+// SetAPIVersion sets the API path version used to build endpoint URLs
+func (s *SonarrService) SetAPIVersion(version string) {
+	s.APIVersion = version
 }
 
 type SystemStatusResponse struct {
@@ -65,24 +71,14 @@ func NewSonarrService() models.ServiceHealthChecker {
 }
 
 func (s *SonarrService) GetHealthEndpoint(baseURL string) string {
-	baseURL = strings.TrimRight(baseURL, "/")
-	return fmt.Sprintf("%s/api/v3/health", baseURL)
+	return arr.APIURL(baseURL, s.APIVersion, "/health")
 }
 
-// makeRequest is a helper function to make requests with proper headers
+// makeRequest makes a request with proper headers through the pooled client of the service
 func (s *SonarrService) makeRequest(ctx context.Context, method, url, apiKey string, body []byte) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
-
-	// Set headers correctly
-	req.Header.Set("X-Api-Key", apiKey)
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	return client.Do(req)
+	return s.MakeRequestWithBody(ctx, method, url, body, map[string]string{
+		"X-Api-Key": apiKey,
+	})
 }
 
 // DeleteQueueItem deletes a queue item with the specified options
@@ -96,8 +92,8 @@ func (s *SonarrService) DeleteQueueItem(ctx context.Context, baseURL, apiKey str
 	}
 
 	// Build delete URL with query parameters
-	deleteURL := fmt.Sprintf("%s/api/v3/queue/%s?removeFromClient=%t&blocklist=%t&skipRedownload=%t",
-		strings.TrimRight(baseURL, "/"),
+	deleteURL := fmt.Sprintf("%s/queue/%s?removeFromClient=%t&blocklist=%t&skipRedownload=%t",
+		arr.APIURL(baseURL, s.APIVersion, ""),
 		queueId,
 		options.RemoveFromClient,
 		options.Blocklist,
@@ -157,6 +153,16 @@ func (s *SonarrService) DeleteQueueItem(ctx context.Context, baseURL, apiKey str
 
 // GetQueue fetches the current queue from Sonarr
 func (s *SonarrService) GetQueue(ctx context.Context, url, apiKey string) (interface{}, error) {
+	records, err := s.GetQueueWithOptions(ctx, url, apiKey, types.DefaultSonarrQueueOptions)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetQueueWithOptions fetches the current queue from Sonarr, optionally with the items it
+// can't match to a series
+func (s *SonarrService) GetQueueWithOptions(ctx context.Context, url, apiKey string, options types.SonarrQueueOptions) ([]types.QueueRecord, error) {
 	if url == "" {
 		return nil, &ErrSonarr{Op: "get_queue", Err: fmt.Errorf("URL is required")}
 	}
@@ -165,8 +171,11 @@ func (s *SonarrService) GetQueue(ctx context.Context, url, apiKey string) (inter
 		return nil, &ErrSonarr{Op: "get_queue", Err: fmt.Errorf("API key is required")}
 	}
 
-	queueURL := fmt.Sprintf("%s/api/v3/queue?page=1&pageSize=10&includeUnknownSeriesItems=false&includeSeries=true&includeEpisode=true",
-		strings.TrimRight(url, "/"))
+	queueURL := fmt.Sprintf("%s/queue?page=1&pageSize=10&includeUnknownSeriesItems=%t&includeSeries=%t&includeEpisode=%t",
+		arr.APIURL(url, s.APIVersion, ""),
+		options.IncludeUnknownSeriesItems,
+		options.IncludeSeries,
+		options.IncludeEpisode)
 
 	resp, err := s.makeRequest(ctx, http.MethodGet, queueURL, apiKey, nil)
 	if err != nil {
@@ -184,23 +193,58 @@ func (s *SonarrService) GetQueue(ctx context.Context, url, apiKey string) (inter
 	}
 
 	var queue types.SonarrQueueResponse
-	if err := json.Unmarshal(body, &queue); err != nil {
+	if err := arr.DecodeJSON(body, &queue); err != nil {
 		return nil, &ErrSonarr{Op: "get_queue", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
 	return queue.Records, nil
 }
 
-// GetQueueForHealth is a wrapper around GetQueue that returns []types.QueueRecord
-func (s *SonarrService) GetQueueForHealth(ctx context.Context, url, apiKey string) ([]types.QueueRecord, error) {
-	records, err := s.GetQueue(ctx, url, apiKey)
+// GetQueueItem fetches a single queue record with its full status messages. Sonarr has no
+// endpoint for one record, so it is looked up in the queue details.
+func (s *SonarrService) GetQueueItem(ctx context.Context, url, apiKey string, id int) (*types.QueueRecord, error) {
+	if url == "" {
+		return nil, &ErrSonarr{Op: "get_queue_item", Err: fmt.Errorf("URL is required")}
+	}
+
+	if apiKey == "" {
+		return nil, &ErrSonarr{Op: "get_queue_item", Err: fmt.Errorf("API key is required")}
+	}
+
+	detailsURL := fmt.Sprintf("%s/queue/details?includeSeries=true&includeEpisode=true", arr.APIURL(url, s.APIVersion, ""))
+
+	resp, err := s.makeRequest(ctx, http.MethodGet, detailsURL, apiKey, nil)
 	if err != nil {
-		return nil, err
+		return nil, &ErrSonarr{Op: "get_queue_item", Err: fmt.Errorf("failed to make request: %w", err)}
 	}
-	if records == nil {
-		return nil, nil
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErrSonarr{Op: "get_queue_item", HttpCode: resp.StatusCode}
 	}
-	return records.([]types.QueueRecord), nil
+
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return nil, &ErrSonarr{Op: "get_queue_item", Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	var records []types.QueueRecord
+	if err := arr.DecodeJSON(body, &records); err != nil {
+		return nil, &ErrSonarr{Op: "get_queue_item", Err: fmt.Errorf("failed to parse response: %w", err)}
+	}
+
+	for i := range records {
+		if records[i].ID == id {
+			return &records[i], nil
+		}
+	}
+
+	return nil, &ErrSonarr{Op: "get_queue_item", HttpCode: http.StatusNotFound}
+}
+
+// GetQueueForHealth returns the queue with the default options
+func (s *SonarrService) GetQueueForHealth(ctx context.Context, url, apiKey string) ([]types.QueueRecord, error) {
+	return s.GetQueueWithOptions(ctx, url, apiKey, types.DefaultSonarrQueueOptions)
 }
 
 // LookupByTvdbId fetches series details from Sonarr by TVDB ID
@@ -213,7 +257,7 @@ func (s *SonarrService) LookupByTvdbId(ctx context.Context, baseURL, apiKey stri
 		return nil, &ErrSonarr{Op: "lookup_tvdb", Err: fmt.Errorf("API key is required")}
 	}
 
-	lookupURL := fmt.Sprintf("%s/api/v3/series/lookup?term=tvdb%%3A%d", strings.TrimRight(baseURL, "/"), tvdbId)
+	lookupURL := fmt.Sprintf("%s/series/lookup?term=tvdb%%3A%d", arr.APIURL(baseURL, s.APIVersion, ""), tvdbId)
 
 	resp, err := s.makeRequest(ctx, http.MethodGet, lookupURL, apiKey, nil)
 	if err != nil {
@@ -231,7 +275,7 @@ func (s *SonarrService) LookupByTvdbId(ctx context.Context, baseURL, apiKey stri
 	}
 
 	var series []types.Series
-	if err := json.Unmarshal(body, &series); err != nil {
+	if err := arr.DecodeJSON(body, &series); err != nil {
 		return nil, &ErrSonarr{Op: "lookup_tvdb", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
@@ -253,7 +297,7 @@ func (s *SonarrService) GetSeries(ctx context.Context, baseURL, apiKey string, s
 		return nil, &ErrSonarr{Op: "get_series", Err: fmt.Errorf("API key is required")}
 	}
 
-	seriesURL := fmt.Sprintf("%s/api/v3/series/%d", strings.TrimRight(baseURL, "/"), seriesID)
+	seriesURL := fmt.Sprintf("%s/series/%d", arr.APIURL(baseURL, s.APIVersion, ""), seriesID)
 
 	resp, err := s.makeRequest(ctx, http.MethodGet, seriesURL, apiKey, nil)
 	if err != nil {
@@ -271,7 +315,7 @@ func (s *SonarrService) GetSeries(ctx context.Context, baseURL, apiKey string, s
 	}
 
 	var series types.Series
-	if err := json.Unmarshal(body, &series); err != nil {
+	if err := arr.DecodeJSON(body, &series); err != nil {
 		return nil, &ErrSonarr{Op: "get_series", Err: fmt.Errorf("failed to parse response: %w", err)}
 	}
 
@@ -292,7 +336,7 @@ func (s *SonarrService) GetSystemStatus(url, apiKey string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), core.DefaultTimeout)
 	defer cancel()
 
-	statusURL := fmt.Sprintf("%s/api/v3/system/status", strings.TrimRight(url, "/"))
+	statusURL := arr.APIURL(url, s.APIVersion, "/system/status")
 
 	resp, err := s.makeRequest(ctx, http.MethodGet, statusURL, apiKey, nil)
 	if err != nil {
@@ -325,9 +369,19 @@ func (s *SonarrService) GetSystemStatus(url, apiKey string) (string, error) {
 
 // CheckForUpdates checks if there are any updates available for Sonarr
 func (s *SonarrService) CheckForUpdates(url, apiKey string) (bool, error) {
-	return arr.CheckArrForUpdates("sonarr", url, apiKey)
+	return arr.CheckArrForUpdates("sonarr", url, apiKey, s.APIVersion, &s.ServiceCore)
+}
+
+// RefreshUpdate checks Sonarr for updates right away, bypassing the cached result
+func (s *SonarrService) RefreshUpdate(ctx context.Context, url, apiKey string) (bool, error) {
+	return arr.RefreshArrUpdate(&s.ServiceCore, url, apiKey, s)
 }
 
 func (s *SonarrService) CheckHealth(ctx context.Context, url, apiKey string) (models.ServiceHealth, int) {
 	return arr.ArrHealthCheck(&s.ServiceCore, url, apiKey, s)
 }
+
+// GetHealthIssues returns the issues Sonarr reports about itself
+func (s *SonarrService) GetHealthIssues(ctx context.Context, url, apiKey string) ([]arr.HealthResponse, error) {
+	return arr.GetHealthIssues(ctx, "sonarr", url, apiKey, &s.ServiceCore, s)
+}
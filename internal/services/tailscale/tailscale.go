@@ -9,12 +9,25 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/autobrr/dashbrr/internal/models"
 	"github.com/autobrr/dashbrr/internal/services/core"
 )
 
+// apiURL is the Tailscale API, the configured service URL is not used for API calls
+var apiURL = "https://api.tailscale.com"
+
+// Days before a device key expires at which the health check escalates to warning, 0 disables
+var keyExpiryWarningDays atomic.Int64
+
+// SetKeyExpiryWarningDays sets the number of days before a device key expires at which
+// the health check reports a warning. 0 disables the check.
+func SetKeyExpiryWarningDays(days int) {
+	keyExpiryWarningDays.Store(int64(days))
+}
+
 type TailscaleService struct {
 	core.ServiceCore
 }
@@ -44,12 +57,38 @@ type TailscaleAPIResponse struct {
 		ClientVersion      string   `json:"clientVersion"`
 		UpdateAvailable    bool     `json:"updateAvailable"`
 		Tags               []string `json:"tags"`
+		Expires            string   `json:"expires"`
+		KeyExpiryDisabled  bool     `json:"keyExpiryDisabled"`
 		ClientConnectivity *struct {
 			Endpoints []string `json:"endpoints"`
 		} `json:"clientConnectivity,omitempty"`
 	} `json:"devices"`
 }
 
+// TailnetInfo summarizes the tailnet the API key belongs to
+type TailnetInfo struct {
+	Name              string           `json:"name"`
+	Devices           int              `json:"devices"`
+	KeyExpiryDisabled bool             `json:"keyExpiryDisabled"` // True when no device key ever expires
+	NoExpiryDevices   int              `json:"noExpiryDevices"`
+	ExpiringDevices   []ExpiringDevice `json:"expiringDevices"`
+	DNS               *TailnetDNS      `json:"dns,omitempty"`
+}
+
+// ExpiringDevice is a device whose key expires within the configured warning window
+type ExpiringDevice struct {
+	Name    string    `json:"name"`
+	ID      string    `json:"id"`
+	Expires time.Time `json:"expires"`
+}
+
+// TailnetDNS holds the DNS settings of a tailnet
+type TailnetDNS struct {
+	MagicDNS    bool     `json:"magicDNS"`
+	Nameservers []string `json:"nameservers"`
+	SearchPaths []string `json:"searchPaths"`
+}
+
 func init() {
 	models.NewTailscaleService = NewTailscaleService
 }
@@ -73,7 +112,7 @@ func (s *TailscaleService) getDevicesWithContext(ctx context.Context, apiKey str
 	}
 
 	// Use the correct API endpoint with the tailnet parameter
-	devicesURL := "https://api.tailscale.com/api/v2/tailnet/-/devices"
+	devicesURL := apiURL + "/api/v2/tailnet/-/devices"
 
 	headers := map[string]string{
 		"Authorization": fmt.Sprintf("Bearer %s", apiKey),
@@ -137,7 +176,7 @@ func (s *TailscaleService) CheckHealth(ctx context.Context, url string, apiKey s
 	startTime := time.Now()
 
 	if apiKey == "" {
-		return s.CreateHealthResponse(startTime, "error", "Service not configured: missing API key"), http.StatusBadRequest
+		return s.NotConfiguredResponse(startTime, "API key")
 	}
 
 	// Create a child context with timeout if needed
@@ -151,7 +190,7 @@ func (s *TailscaleService) CheckHealth(ctx context.Context, url string, apiKey s
 
 	apiResponse, responseTime, err := s.getDevicesWithContext(healthCtx, apiKey)
 	if err != nil {
-		return s.CreateHealthResponse(startTime, "error", err.Error()), http.StatusServiceUnavailable
+		return s.CreateHealthResponse(startTime, models.StatusError, err.Error()), http.StatusServiceUnavailable
 	}
 
 	onlineCount := 0
@@ -161,13 +200,158 @@ func (s *TailscaleService) CheckHealth(ctx context.Context, url string, apiKey s
 		}
 	}
 
+	tailnet := summarizeTailnet(apiResponse, time.Now())
+
 	extras := map[string]interface{}{
 		"responseTime":    responseTime.Milliseconds(),
 		"version":         version,
 		"updateAvailable": s.GetUpdateStatusFromCache(url),
+		"details": map[string]interface{}{
+			"tailscale": map[string]interface{}{
+				"tailnet": tailnet,
+			},
+		},
+	}
+
+	status := models.StatusOnline
+	message := fmt.Sprintf("%d devices online", onlineCount)
+	if len(tailnet.ExpiringDevices) > 0 {
+		status = models.StatusWarning
+		message = fmt.Sprintf("%s, %d device keys expiring soon", message, len(tailnet.ExpiringDevices))
+	}
+
+	return s.CreateHealthResponse(startTime, status, message, extras), http.StatusOK
+}
+
+// GetTailnetInfo returns a summary of the tailnet including its DNS settings
+func (s *TailscaleService) GetTailnetInfo(ctx context.Context, apiKey string) (*TailnetInfo, error) {
+	apiResponse, _, err := s.getDevicesWithContext(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	info := summarizeTailnet(apiResponse, time.Now())
+
+	dns, err := s.getDNS(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	info.DNS = dns
+
+	return &info, nil
+}
+
+// summarizeTailnet derives the tailnet summary from the device list. The health check
+// uses it as is, the DNS settings need separate requests and are left out.
+func summarizeTailnet(apiResponse *TailscaleAPIResponse, now time.Time) TailnetInfo {
+	info := TailnetInfo{
+		Devices:         len(apiResponse.Devices),
+		ExpiringDevices: []ExpiringDevice{},
+	}
+
+	days := keyExpiryWarningDays.Load()
+	deadline := now.Add(time.Duration(days) * 24 * time.Hour)
+
+	for _, device := range apiResponse.Devices {
+		// Device names are fully qualified, e.g. host.tailnet-name.ts.net
+		if info.Name == "" {
+			if _, tailnet, ok := strings.Cut(device.Name, "."); ok {
+				info.Name = tailnet
+			}
+		}
+
+		if device.KeyExpiryDisabled {
+			info.NoExpiryDevices++
+			continue
+		}
+
+		if days <= 0 {
+			continue
+		}
+
+		expires, err := time.Parse(time.RFC3339, device.Expires)
+		if err != nil || expires.IsZero() {
+			continue
+		}
+
+		if expires.Before(deadline) {
+			info.ExpiringDevices = append(info.ExpiringDevices, ExpiringDevice{
+				Name:    device.Name,
+				ID:      device.ID,
+				Expires: expires,
+			})
+		}
+	}
+
+	info.KeyExpiryDisabled = info.Devices > 0 && info.NoExpiryDevices == info.Devices
+
+	return info
+}
+
+func (s *TailscaleService) getDNS(ctx context.Context, apiKey string) (*TailnetDNS, error) {
+	var preferences struct {
+		MagicDNS bool `json:"magicDNS"`
+	}
+	if err := s.getTailnetJSON(ctx, apiKey, "/dns/preferences", &preferences); err != nil {
+		return nil, err
+	}
+
+	var nameservers struct {
+		DNS []string `json:"dns"`
+	}
+	if err := s.getTailnetJSON(ctx, apiKey, "/dns/nameservers", &nameservers); err != nil {
+		return nil, err
+	}
+
+	var searchPaths struct {
+		SearchPaths []string `json:"searchPaths"`
+	}
+	if err := s.getTailnetJSON(ctx, apiKey, "/dns/searchpaths", &searchPaths); err != nil {
+		return nil, err
+	}
+
+	dns := &TailnetDNS{
+		MagicDNS:    preferences.MagicDNS,
+		Nameservers: nameservers.DNS,
+		SearchPaths: searchPaths.SearchPaths,
+	}
+	if dns.Nameservers == nil {
+		dns.Nameservers = []string{}
+	}
+	if dns.SearchPaths == nil {
+		dns.SearchPaths = []string{}
+	}
+
+	return dns, nil
+}
+
+// getTailnetJSON decodes a GET request against the default tailnet
+func (s *TailscaleService) getTailnetJSON(ctx context.Context, apiKey, path string, v interface{}) error {
+	headers := map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", apiKey),
+		"Accept":        "application/json",
+	}
+
+	resp, err := s.MakeRequestWithContext(ctx, apiURL+"/api/v2/tailnet/-"+path, "", headers)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := s.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed (Status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
 	}
 
-	return s.CreateHealthResponse(startTime, "online", fmt.Sprintf("%d devices online", onlineCount), extras), http.StatusOK
+	return nil
 }
 
 func isDeviceOnline(lastSeen string) bool {
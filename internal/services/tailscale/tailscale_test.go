@@ -0,0 +1,87 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+func TestGetTailnetInfo(t *testing.T) {
+	soon := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	later := time.Now().Add(90 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	lastSeen := time.Now().UTC().Format(time.RFC3339)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/tailnet/-/devices":
+			w.Write([]byte(`{"devices":[
+				{"id":"1","name":"nas.example-tailnet.ts.net","lastSeen":"` + lastSeen + `","expires":"` + soon + `"},
+				{"id":"2","name":"laptop.example-tailnet.ts.net","lastSeen":"` + lastSeen + `","expires":"` + later + `"},
+				{"id":"3","name":"router.example-tailnet.ts.net","lastSeen":"` + lastSeen + `","keyExpiryDisabled":true}
+			]}`))
+		case "/api/v2/tailnet/-/dns/preferences":
+			w.Write([]byte(`{"magicDNS":true}`))
+		case "/api/v2/tailnet/-/dns/nameservers":
+			w.Write([]byte(`{"dns":["1.1.1.1"]}`))
+		case "/api/v2/tailnet/-/dns/searchpaths":
+			w.Write([]byte(`{"searchPaths":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	defaultURL := apiURL
+	apiURL = server.URL
+	t.Cleanup(func() {
+		apiURL = defaultURL
+		SetKeyExpiryWarningDays(0)
+	})
+
+	service := NewTailscaleService().(*TailscaleService)
+	apiKey := "tskey-api-test"
+
+	info, err := service.GetTailnetInfo(context.Background(), apiKey)
+	if err != nil {
+		t.Fatalf("GetTailnetInfo: %v", err)
+	}
+	if info.Name != "example-tailnet.ts.net" {
+		t.Errorf("name = %q, want example-tailnet.ts.net", info.Name)
+	}
+	if info.Devices != 3 || info.NoExpiryDevices != 1 || info.KeyExpiryDisabled {
+		t.Errorf("unexpected key expiry summary: %+v", info)
+	}
+	if len(info.ExpiringDevices) != 0 {
+		t.Errorf("expiring devices reported with the check disabled: %+v", info.ExpiringDevices)
+	}
+	if info.DNS == nil || !info.DNS.MagicDNS || len(info.DNS.Nameservers) != 1 {
+		t.Errorf("unexpected DNS settings: %+v", info.DNS)
+	}
+
+	health, _ := service.CheckHealth(context.Background(), server.URL, apiKey)
+	if health.Status != models.StatusOnline {
+		t.Errorf("status = %q with the check disabled, want online", health.Status)
+	}
+
+	SetKeyExpiryWarningDays(7)
+
+	health, _ = service.CheckHealth(context.Background(), server.URL, apiKey)
+	if health.Status != models.StatusWarning {
+		t.Fatalf("status = %q, want warning", health.Status)
+	}
+	details, _ := health.Details["tailscale"].(map[string]interface{})
+	tailnet, ok := details["tailnet"].(TailnetInfo)
+	if !ok {
+		t.Fatalf("tailnet missing from details: %+v", health.Details)
+	}
+	if len(tailnet.ExpiringDevices) != 1 || tailnet.ExpiringDevices[0].ID != "1" {
+		t.Errorf("expiring devices = %+v, want device 1", tailnet.ExpiringDevices)
+	}
+}
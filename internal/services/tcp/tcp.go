@@ -0,0 +1,147 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+	"github.com/autobrr/dashbrr/internal/services/core"
+)
+
+// maxBannerSize bounds how much of the greeting is read when matching a banner
+const maxBannerSize = 1024
+
+func init() {
+	models.NewTCPService = NewTCPService
+}
+
+func NewTCPService() models.ServiceHealthChecker {
+	service := &TCPService{}
+	service.Type = "tcp"
+	service.DisplayName = "" // Allow display name to be set via configuration
+	service.Description = "Checks that a TCP port accepts connections, for services without an HTTP API"
+	service.SetTimeout(core.DefaultTimeout)
+	return service
+}
+
+// TCPService reports a service online when a TCP connection to it succeeds. It is meant
+// for daemons that only expose a port, such as databases and game servers.
+type TCPService struct {
+	core.ServiceCore
+}
+
+// target is a parsed TCP service URL
+type target struct {
+	address string
+	banner  string
+	timeout time.Duration
+}
+
+// parseTarget parses host:port or tcp://host:port. The optional banner query parameter is
+// text the server must send after connecting, timeout overrides the default dial timeout,
+// e.g. tcp://db:5432?timeout=2s or tcp://host:22?banner=SSH-2.0
+func parseTarget(raw string) (target, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "tcp://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return target{}, fmt.Errorf("invalid address: %v", err)
+	}
+	if u.Scheme != "tcp" {
+		return target{}, fmt.Errorf("unsupported scheme %q, expected tcp://host:port", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return target{}, fmt.Errorf("address must be of the form host:port")
+	}
+
+	t := target{
+		address: u.Host,
+		banner:  u.Query().Get("banner"),
+		timeout: core.DefaultTimeout,
+	}
+
+	if value := u.Query().Get("timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return target{}, fmt.Errorf("invalid timeout %q", value)
+		}
+		t.timeout = timeout
+	}
+
+	return t, nil
+}
+
+// CheckHealth dials the configured address. The API key is not used.
+func (s *TCPService) CheckHealth(ctx context.Context, url, _ string) (models.ServiceHealth, int) {
+	startTime := time.Now()
+
+	if url == "" {
+		return s.NotConfiguredResponse(startTime, "URL")
+	}
+
+	t, err := parseTarget(url)
+	if err != nil {
+		return s.CreateHealthResponse(startTime, models.StatusError, err.Error()), http.StatusBadRequest
+	}
+
+	healthCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(healthCtx, "tcp", t.address)
+	if err != nil {
+		return s.CreateHealthResponse(startTime, models.StatusOffline, fmt.Sprintf("Failed to connect: %v", err)), http.StatusServiceUnavailable
+	}
+	defer conn.Close()
+
+	extras := map[string]interface{}{
+		"responseTime": time.Since(startTime).Milliseconds(),
+	}
+
+	if t.banner == "" {
+		return s.CreateHealthResponse(startTime, models.StatusOnline, "Port open", extras), http.StatusOK
+	}
+
+	if deadline, ok := healthCtx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+	}
+
+	banner, err := readBanner(conn, t.banner)
+	if !strings.Contains(banner, t.banner) {
+		message := fmt.Sprintf("Unexpected banner: %s", strings.TrimSpace(banner))
+		if banner == "" && err != nil {
+			message = fmt.Sprintf("No banner received: %v", err)
+		}
+		return s.CreateHealthResponse(startTime, models.StatusWarning, message, extras), http.StatusOK
+	}
+
+	return s.CreateHealthResponse(startTime, models.StatusOnline, "Banner matched", extras), http.StatusOK
+}
+
+// readBanner reads the greeting of the server until it contains expected, the connection
+// is closed or maxBannerSize bytes are read
+func readBanner(conn net.Conn, expected string) (string, error) {
+	buf := make([]byte, maxBannerSize)
+	n := 0
+	for n < len(buf) {
+		read, err := conn.Read(buf[n:])
+		n += read
+		if strings.Contains(string(buf[:n]), expected) {
+			return string(buf[:n]), nil
+		}
+		if err != nil {
+			return string(buf[:n]), err
+		}
+	}
+	return string(buf[:n]), nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package tcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+// listen accepts connections and greets them with banner until the test ends
+func listen(t *testing.T, banner string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(banner))
+			conn.Close()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestCheckHealth(t *testing.T) {
+	address := listen(t, "SSH-2.0-OpenSSH_9.6\r\n")
+
+	// A closed port, the listener is gone by the time it is dialed
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closed := ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		status models.ServiceStatus
+		code   int
+	}{
+		{"port open", address, models.StatusOnline, http.StatusOK},
+		{"tcp scheme", "tcp://" + address, models.StatusOnline, http.StatusOK},
+		{"banner matched", "tcp://" + address + "?banner=SSH-2.0", models.StatusOnline, http.StatusOK},
+		{"banner mismatch", "tcp://" + address + "?banner=220%20", models.StatusWarning, http.StatusOK},
+		{"connection refused", "tcp://" + closed + "?timeout=1s", models.StatusOffline, http.StatusServiceUnavailable},
+		{"missing port", "tcp://localhost", models.StatusError, http.StatusBadRequest},
+		{"invalid timeout", "tcp://" + address + "?timeout=soon", models.StatusError, http.StatusBadRequest},
+	}
+
+	service := NewTCPService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, code := service.CheckHealth(context.Background(), tt.url, "")
+			if health.Status != tt.status || code != tt.code {
+				t.Errorf("CheckHealth(%q) = %s (%d), want %s (%d): %s", tt.url, health.Status, code, tt.status, tt.code, health.Message)
+			}
+		})
+	}
+}
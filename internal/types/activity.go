@@ -0,0 +1,29 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import "time"
+
+// Activity item types
+const (
+	ActivityRelease = "release" // Release seen by autobrr
+	ActivityRequest = "request" // Media requested in Overseerr
+	ActivityGrab    = "grab"    // Release grabbed by Sonarr or Radarr
+)
+
+// ActivityItem is an entry of the activity feed, normalized across services
+type ActivityItem struct {
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"` // Instance id, e.g. sonarr-1
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// ActivityFeed holds the most recent activity across all services, newest first.
+// Services that could not be read are listed in Errors, the feed is built from the rest.
+type ActivityFeed struct {
+	Items  []ActivityItem    `json:"items"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
@@ -7,6 +7,7 @@ import "time"
 
 // AuthConfig holds the OIDC configuration
 type AuthConfig struct {
+	Name         string // Provider name used to select it at login
 	Issuer       string
 	ClientID     string
 	ClientSecret string
@@ -22,6 +23,7 @@ type SessionData struct {
 	ExpiresAt    time.Time `json:"expires_at"`
 	UserID       int64     `json:"user_id,omitempty"`   // Added for built-in auth
 	AuthType     string    `json:"auth_type,omitempty"` // "oidc" or "builtin"
+	Provider     string    `json:"provider,omitempty"`  // OIDC provider that authenticated the user
 }
 
 // User represents a user in the system
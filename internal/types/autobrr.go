@@ -22,6 +22,44 @@ type IRCStatus struct {
 	Enabled bool   `json:"enabled"`
 }
 
+// IRCDetailResponse lists every IRC network of an autobrr instance with a count of the
+// enabled and healthy ones, e.g. to show "3/4 networks up"
+type IRCDetailResponse struct {
+	Networks []IRCStatus `json:"networks"`
+	Total    int         `json:"total"`
+	Enabled  int         `json:"enabled"`
+	Healthy  int         `json:"healthy"`
+}
+
+// NewIRCDetailResponse counts the enabled and healthy networks, only enabled networks
+// count as healthy
+func NewIRCDetailResponse(networks []IRCStatus) IRCDetailResponse {
+	detail := IRCDetailResponse{Networks: networks, Total: len(networks)}
+	for _, network := range networks {
+		if !network.Enabled {
+			continue
+		}
+		detail.Enabled++
+		if network.Healthy {
+			detail.Healthy++
+		}
+	}
+	return detail
+}
+
+// AutobrrFilter is the part of an autobrr filter needed for the filter summary
+type AutobrrFilter struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// AutobrrFilterSummary counts the configured filters, e.g. "12 filters (10 enabled)"
+type AutobrrFilterSummary struct {
+	Total   int `json:"total"`
+	Enabled int `json:"enabled"`
+}
+
 type VersionResponse struct {
 	Version string `json:"version"`
 }
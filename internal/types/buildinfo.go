@@ -0,0 +1,15 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import "github.com/autobrr/dashbrr/internal/buildinfo"
+
+// BuildInfoResponse describes the running build and the backends it uses
+type BuildInfoResponse struct {
+	buildinfo.Info
+	Uptime        string `json:"uptime"`
+	UptimeSeconds int64  `json:"uptimeSeconds"`
+	Database      string `json:"database"`
+	Cache         string `json:"cache"`
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import "time"
+
+// ReleaseNote is a published release of a service with its notes
+type ReleaseNote struct {
+	Version     string    `json:"version"`
+	Name        string    `json:"name,omitempty"`
+	Notes       string    `json:"notes"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+// ChangelogResponse lists the releases between the installed and the latest version of a
+// service, newest first. Without a known installed version only the latest release is listed.
+type ChangelogResponse struct {
+	InstanceID      string        `json:"instanceId"`
+	Repo            string        `json:"repo"`
+	Installed       string        `json:"installed,omitempty"`
+	Latest          string        `json:"latest,omitempty"`
+	UpdateAvailable bool          `json:"updateAvailable"`
+	Releases        []ReleaseNote `json:"releases"`
+	// Partial is set when the installed version isn't among the fetched releases, the
+	// notes of older releases are then missing
+	Partial bool `json:"partial,omitempty"`
+}
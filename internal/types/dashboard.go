@@ -0,0 +1,60 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import (
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+// DashboardSummary holds everything needed to render the overview in a single payload
+type DashboardSummary struct {
+	Services    []DashboardEntry `json:"services"`
+	Overall     OverallHealth    `json:"overall"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+}
+
+// DashboardEntry is the summary of one service. Type discriminates the entry and
+// decides what the headline stat counts.
+type DashboardEntry struct {
+	InstanceID  string                `json:"instanceId"`
+	Type        string                `json:"type"`
+	DisplayName string                `json:"displayName"`
+	Critical    bool                  `json:"critical,omitempty"`
+	Pinned      bool                  `json:"pinned,omitempty"`
+	Health      *models.ServiceHealth `json:"health,omitempty"`
+	Stat        *DashboardStat        `json:"stat,omitempty"`
+}
+
+// DashboardStat is the headline number of a service, e.g. the queue size for arrs
+type DashboardStat struct {
+	Label string `json:"label"`
+	Value int    `json:"value"`
+}
+
+// OverallStatus is the verdict over all services shown by the global indicator
+type OverallStatus string
+
+const (
+	OverallHealthy  OverallStatus = "healthy"
+	OverallDegraded OverallStatus = "degraded"
+	OverallDown     OverallStatus = "down"
+)
+
+// OverallHealth is the overall verdict with the services behind it. Down lists the
+// services that are down, Degraded the ones with warnings.
+type OverallHealth struct {
+	Status   OverallStatus `json:"status"`
+	Down     []string      `json:"down"`
+	Degraded []string      `json:"degraded"`
+}
+
+// SummaryBadges holds the counts shown as navigation badges
+type SummaryBadges struct {
+	Offline         int `json:"offline"`
+	Updates         int `json:"updates"`
+	PendingRequests int `json:"pendingRequests"`
+	ActiveDownloads int `json:"activeDownloads"`
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import "time"
+
+// MaintenanceStatus is the maintenance mode state of the instance
+type MaintenanceStatus struct {
+	Enabled bool       `json:"enabled"`
+	Since   *time.Time `json:"since,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+}
+
+// MaintenanceRequest turns maintenance mode on, the reason is shown to other users
+type MaintenanceRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
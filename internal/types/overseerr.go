@@ -3,7 +3,10 @@
 
 package types
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type StatusResponse struct {
 	Version         string `json:"version"`
@@ -38,6 +41,7 @@ type MediaRequest struct {
 		MediaType         string   `json:"mediaType"`
 		ServiceUrl        string   `json:"serviceUrl"`
 		Title             string   `json:"title,omitempty"`
+		Name              string   `json:"name,omitempty"`
 		ExternalServiceID int      `json:"externalServiceId,omitempty"`
 	} `json:"media"`
 	RequestedBy struct {
@@ -70,6 +74,62 @@ type MediaRequest struct {
 	ServerID   int    `json:"serverId"`
 	ProfileID  int    `json:"profileId"`
 	RootFolder string `json:"rootFolder"`
+
+	// Requester is resolved from RequestedBy by ResolveRequester
+	Requester Requester `json:"requester"`
+}
+
+// Overseerr user types, Jellyseerr adds Jellyfin and Emby users
+const (
+	OverseerrUserTypePlex     = 1
+	OverseerrUserTypeLocal    = 2
+	OverseerrUserTypeJellyfin = 3
+	OverseerrUserTypeEmby     = 4
+)
+
+// Requester is the user behind a request with the name the requests widget should show
+type Requester struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"` // plex, local, jellyfin or emby
+	AvatarURL string `json:"avatarUrl,omitempty"`
+}
+
+// ResolveRequester sets Requester from RequestedBy. Plex users are shown with their Plex
+// username, local users with their Overseerr username. Relative avatar paths are resolved
+// against baseURL.
+func (r *MediaRequest) ResolveRequester(baseURL string) {
+	user := r.RequestedBy
+
+	var requester Requester
+	switch user.UserType {
+	case OverseerrUserTypePlex:
+		requester = Requester{Name: firstNonEmpty(user.PlexUsername, user.Username, user.Email), Type: "plex"}
+	case OverseerrUserTypeJellyfin:
+		requester = Requester{Name: firstNonEmpty(user.Username, user.Email), Type: "jellyfin"}
+	case OverseerrUserTypeEmby:
+		requester = Requester{Name: firstNonEmpty(user.Username, user.Email), Type: "emby"}
+	default:
+		requester = Requester{Name: firstNonEmpty(user.Username, user.Email), Type: "local"}
+	}
+
+	switch {
+	case user.Avatar == "":
+	case strings.HasPrefix(user.Avatar, "http://"), strings.HasPrefix(user.Avatar, "https://"):
+		requester.AvatarURL = user.Avatar
+	default:
+		requester.AvatarURL = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(user.Avatar, "/")
+	}
+
+	r.Requester = requester
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
 }
 
 type RequestsStats struct {
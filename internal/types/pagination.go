@@ -0,0 +1,29 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+// PagedResponse is a page of a list together with the size of the whole list. List
+// endpoints respond with it when limit or offset is given.
+type PagedResponse[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// NewPagedResponse pages a list held in memory. A limit of 0 returns everything after offset.
+func NewPagedResponse[T any](items []T, limit, offset int) PagedResponse[T] {
+	page := PagedResponse[T]{Items: []T{}, Total: len(items), Limit: limit, Offset: offset}
+	if offset >= len(items) {
+		return page
+	}
+
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	page.Items = items[offset:end]
+
+	return page
+}
@@ -29,6 +29,32 @@ type PlexSessionsResponse struct {
 	} `json:"MediaContainer"`
 }
 
+// PlexNowPlaying is the summary of an active Plex session shown on the dashboard
+type PlexNowPlaying struct {
+	SessionKey string `json:"sessionKey"`
+	Title      string `json:"title"`
+	Type       string `json:"type"`
+	User       string `json:"user"`
+	Player     string `json:"player"`
+	State      string `json:"state"`
+	Decision   string `json:"decision"` // directplay, directstream or transcode
+	Progress   int    `json:"progress"` // Percent watched
+}
+
+// PlexNowPlayingResponse lists the active sessions of a Plex instance
+type PlexNowPlayingResponse struct {
+	Sessions []PlexNowPlaying `json:"sessions"`
+}
+
+// PlexLoad summarizes the streaming load of a Plex instance
+type PlexLoad struct {
+	Bandwidth    int            `json:"bandwidth"` // Total of all sessions in kbps
+	Quality      map[string]int `json:"quality"`   // Streams per resolution: 4k, 1080p, 720p and sd
+	DirectPlay   int            `json:"directPlay"`
+	DirectStream int            `json:"directStream"`
+	Transcode    int            `json:"transcode"`
+}
+
 type PlexUser struct {
 	ID    string `json:"id"`
 	Title string `json:"title"`
@@ -93,14 +119,15 @@ type PlexSession struct {
 }
 
 type PlexMedia struct {
-	AudioChannels int        `json:"audioChannels"`
-	AudioCodec    string     `json:"audioCodec"`
-	Bitrate       int        `json:"bitrate"`
-	Container     string     `json:"container"`
-	Duration      int        `json:"duration"`
-	ID            string     `json:"id"`
-	Selected      bool       `json:"selected"`
-	Part          []PlexPart `json:"Part"`
+	AudioChannels   int        `json:"audioChannels"`
+	AudioCodec      string     `json:"audioCodec"`
+	Bitrate         int        `json:"bitrate"`
+	Container       string     `json:"container"`
+	Duration        int        `json:"duration"`
+	ID              string     `json:"id"`
+	Selected        bool       `json:"selected"`
+	VideoResolution string     `json:"videoResolution"`
+	Part            []PlexPart `json:"Part"`
 }
 
 type PlexPart struct {
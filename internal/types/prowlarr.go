@@ -3,10 +3,12 @@
 
 package types
 
+import "time"
+
 type ProwlarrStatsResponse struct {
-	GrabCount    int `json:"grabCount"`
-	FailCount    int `json:"failCount"`
-	IndexerCount int `json:"indexerCount"`
+	GrabCount    int64 `json:"grabCount"`
+	FailCount    int64 `json:"failCount"`
+	IndexerCount int   `json:"indexerCount"`
 }
 
 type ProwlarrIndexer struct {
@@ -16,8 +18,8 @@ type ProwlarrIndexer struct {
 	Enable              bool   `json:"enable"`
 	Priority            int    `json:"priority"`
 	AverageResponseTime int    `json:"averageResponseTime"`
-	NumberOfGrabs       int    `json:"numberOfGrabs"`
-	NumberOfQueries     int    `json:"numberOfQueries"`
+	NumberOfGrabs       int64  `json:"numberOfGrabs"`
+	NumberOfQueries     int64  `json:"numberOfQueries"`
 }
 
 type ProwlarrIndexerStats struct {
@@ -25,16 +27,44 @@ type ProwlarrIndexerStats struct {
 	IndexerID                 int    `json:"indexerId"`
 	IndexerName               string `json:"indexerName"`
 	AverageResponseTime       int    `json:"averageResponseTime"`
-	NumberOfQueries           int    `json:"numberOfQueries"`
-	NumberOfGrabs             int    `json:"numberOfGrabs"`
-	NumberOfRssQueries        int    `json:"numberOfRssQueries"`
-	NumberOfAuthQueries       int    `json:"numberOfAuthQueries"`
-	NumberOfFailedQueries     int    `json:"numberOfFailedQueries"`
-	NumberOfFailedGrabs       int    `json:"numberOfFailedGrabs"`
-	NumberOfFailedRssQueries  int    `json:"numberOfFailedRssQueries"`
-	NumberOfFailedAuthQueries int    `json:"numberOfFailedAuthQueries"`
+	NumberOfQueries           int64  `json:"numberOfQueries"`
+	NumberOfGrabs             int64  `json:"numberOfGrabs"`
+	NumberOfRssQueries        int64  `json:"numberOfRssQueries"`
+	NumberOfAuthQueries       int64  `json:"numberOfAuthQueries"`
+	NumberOfFailedQueries     int64  `json:"numberOfFailedQueries"`
+	NumberOfFailedGrabs       int64  `json:"numberOfFailedGrabs"`
+	NumberOfFailedRssQueries  int64  `json:"numberOfFailedRssQueries"`
+	NumberOfFailedAuthQueries int64  `json:"numberOfFailedAuthQueries"`
 }
 
 type ProwlarrIndexerStatsResponse struct {
 	Indexers []ProwlarrIndexerStats `json:"indexers"`
 }
+
+// ProwlarrIndexerStatus is an indexer Prowlarr has disabled after it failed
+type ProwlarrIndexerStatus struct {
+	IndexerID         int        `json:"indexerId"`
+	DisabledTill      *time.Time `json:"disabledTill,omitempty"`
+	InitialFailure    *time.Time `json:"initialFailure,omitempty"`
+	MostRecentFailure *time.Time `json:"mostRecentFailure,omitempty"`
+}
+
+// FailingIndexer is a failing indexer of one Prowlarr instance
+type FailingIndexer struct {
+	InstanceID        string     `json:"instanceId"`
+	IndexerID         int        `json:"indexerId"`
+	Name              string     `json:"name"`
+	Error             string     `json:"error"`
+	DisabledTill      *time.Time `json:"disabledTill,omitempty"`
+	MostRecentFailure *time.Time `json:"mostRecentFailure,omitempty"`
+}
+
+// FailingIndexersResponse holds the failing indexers of all Prowlarr instances. Alert is set
+// once the number of failing indexers reaches the configured threshold.
+type FailingIndexersResponse struct {
+	Indexers  []FailingIndexer  `json:"indexers"`
+	Count     int               `json:"count"`
+	Threshold int               `json:"threshold,omitempty"`
+	Alert     bool              `json:"alert"`
+	Errors    map[string]string `json:"errors,omitempty"` // Instances that couldn't be queried
+}
@@ -3,6 +3,8 @@
 
 package types
 
+import "github.com/autobrr/dashbrr/internal/utils"
+
 // RadarrQueueResponse represents the queue response from Radarr API
 type RadarrQueueResponse struct {
 	Page          int                 `json:"page"`
@@ -13,6 +15,26 @@ type RadarrQueueResponse struct {
 	Records       []RadarrQueueRecord `json:"records"`
 }
 
+// Humanized returns a copy of the queue with human readable sizes next to the raw bytes.
+// The records are copied, so a cached queue is left untouched.
+func (q RadarrQueueResponse) Humanized() RadarrQueueResponse {
+	records := make([]RadarrQueueRecord, len(q.Records))
+	for i, record := range q.Records {
+		record.SizeHuman = utils.FormatBytes(record.Size)
+		record.SizeLeftHuman = utils.FormatBytes(record.SizeLeft)
+		records[i] = record
+	}
+	q.Records = records
+	return q
+}
+
+// RadarrQueueOptions selects what the Radarr queue includes. The zero value leaves out
+// unknown items and the movie.
+type RadarrQueueOptions struct {
+	IncludeUnknownMovieItems bool // Items Radarr can't match to a movie
+	IncludeMovie             bool
+}
+
 // RadarrQueueRecord represents a record in the Radarr queue
 type RadarrQueueRecord struct {
 	ID                      int                   `json:"id"`
@@ -26,6 +48,8 @@ type RadarrQueueRecord struct {
 	DownloadClient          string                `json:"downloadClient"`
 	Size                    int64                 `json:"size"`
 	SizeLeft                int64                 `json:"sizeleft"`
+	SizeHuman               string                `json:"sizeHuman,omitempty"`
+	SizeLeftHuman           string                `json:"sizeleftHuman,omitempty"`
 	CustomFormatScore       int                   `json:"customFormatScore"`
 	TrackedDownloadStatus   string                `json:"trackedDownloadStatus"`
 	TrackedDownloadState    string                `json:"trackedDownloadState"`
@@ -0,0 +1,20 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import "github.com/autobrr/dashbrr/internal/models"
+
+// SetupStatus tells the frontend whether to show the first-run setup flow
+type SetupStatus struct {
+	SetupRequired bool   `json:"setupRequired"`
+	HasUsers      bool   `json:"hasUsers"`
+	HasServices   bool   `json:"hasServices"`
+	AuthMode      string `json:"authMode"` // "local", "oidc" or "none"
+}
+
+// SetupRequest creates the first user and, optionally, an initial service
+type SetupRequest struct {
+	User    RegisterRequest              `json:"user" binding:"required"`
+	Service *models.ServiceConfiguration `json:"service,omitempty"`
+}
@@ -3,6 +3,8 @@
 
 package types
 
+import "github.com/autobrr/dashbrr/internal/utils"
+
 // SonarrQueueResponse represents the queue response from Sonarr API
 type SonarrQueueResponse struct {
 	Page          int           `json:"page"`
@@ -13,6 +15,19 @@ type SonarrQueueResponse struct {
 	Records       []QueueRecord `json:"records"`
 }
 
+// Humanized returns a copy of the queue with human readable sizes next to the raw bytes.
+// The records are copied, so a cached queue is left untouched.
+func (q SonarrQueueResponse) Humanized() SonarrQueueResponse {
+	records := make([]QueueRecord, len(q.Records))
+	for i, record := range q.Records {
+		record.SizeHuman = utils.FormatBytes(record.Size)
+		record.SizeLeftHuman = utils.FormatBytes(record.SizeLeft)
+		records[i] = record
+	}
+	q.Records = records
+	return q
+}
+
 // SonarrQueueDeleteOptions represents the options for deleting a queue item in Sonarr
 type SonarrQueueDeleteOptions struct {
 	RemoveFromClient bool `json:"removeFromClient"`
@@ -21,6 +36,16 @@ type SonarrQueueDeleteOptions struct {
 	ChangeCategory   bool `json:"changeCategory"`
 }
 
+// SonarrQueueOptions selects what the Sonarr queue includes
+type SonarrQueueOptions struct {
+	IncludeUnknownSeriesItems bool // Items Sonarr can't match to a series
+	IncludeSeries             bool
+	IncludeEpisode            bool
+}
+
+// DefaultSonarrQueueOptions leaves out unknown items and includes the series and episode
+var DefaultSonarrQueueOptions = SonarrQueueOptions{IncludeSeries: true, IncludeEpisode: true}
+
 // QueueRecord represents a record in the Sonarr queue
 type QueueRecord struct {
 	ID                                  int             `json:"id"`
@@ -33,6 +58,8 @@ type QueueRecord struct {
 	Status                              string          `json:"status"`
 	Size                                int64           `json:"size"`
 	SizeLeft                            int64           `json:"sizeleft"`
+	SizeHuman                           string          `json:"sizeHuman,omitempty"`
+	SizeLeftHuman                       string          `json:"sizeleftHuman,omitempty"`
 	TimeLeft                            string          `json:"timeleft,omitempty"`
 	EstimatedCompletionTime             string          `json:"estimatedCompletionTime"`
 	Added                               string          `json:"added"`
@@ -107,15 +134,25 @@ type StatusMessage struct {
 
 // SonarrStatsResponse represents the stats response from Sonarr API
 type SonarrStatsResponse struct {
-	MovieCount       int   `json:"movieCount"`
-	EpisodeCount     int   `json:"episodeCount"`
-	EpisodeFileCount int   `json:"episodeFileCount"`
+	MovieCount       int64 `json:"movieCount"`
+	EpisodeCount     int64 `json:"episodeCount"`
+	EpisodeFileCount int64 `json:"episodeFileCount"`
 	FreeSpaceBytes   int64 `json:"freeSpaceBytes"`
 	TotalSpaceBytes  int64 `json:"totalSpaceBytes"`
-	Monitored        int   `json:"monitored"`
-	Unmonitored      int   `json:"unmonitored"`
-	QueuedCount      int   `json:"queuedCount"`
-	MissingCount     int   `json:"missingCount"`
+	Monitored        int64 `json:"monitored"`
+	Unmonitored      int64 `json:"unmonitored"`
+	QueuedCount      int64 `json:"queuedCount"`
+	MissingCount     int64 `json:"missingCount"`
+
+	FreeSpaceHuman  string `json:"freeSpaceHuman,omitempty"`
+	TotalSpaceHuman string `json:"totalSpaceHuman,omitempty"`
+}
+
+// Humanized returns a copy of the stats with human readable sizes next to the raw bytes
+func (s SonarrStatsResponse) Humanized() SonarrStatsResponse {
+	s.FreeSpaceHuman = utils.FormatBytes(s.FreeSpaceBytes)
+	s.TotalSpaceHuman = utils.FormatBytes(s.TotalSpaceBytes)
+	return s
 }
 
 // SonarrUpdateResponse represents an update response from Sonarr
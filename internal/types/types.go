@@ -3,7 +3,11 @@
 
 package types
 
-import "time"
+import (
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
 
 type ServiceHealth struct {
 	Status          string    `json:"status"`
@@ -57,3 +61,173 @@ type FindServiceParams struct {
 	URL            string
 	AccessURL      string
 }
+
+// UpdateServiceParams holds the service fields to update. Nil fields are left unchanged.
+type UpdateServiceParams struct {
+	DisplayName *string   `json:"displayName,omitempty"`
+	URL         *string   `json:"url,omitempty"`
+	APIKey      *string   `json:"apiKey,omitempty"`
+	AccessURL   *string   `json:"accessUrl,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	APIVersion  *string   `json:"apiVersion,omitempty"`
+	Color       *string   `json:"color,omitempty"`
+	Icon        *string   `json:"icon,omitempty"`
+
+	CheckIntervalSeconds *int    `json:"checkIntervalSeconds,omitempty"`
+	Method               *string `json:"method,omitempty"`
+	ExpectedStatus       *string `json:"expectedStatus,omitempty"`
+	Critical             *bool   `json:"critical,omitempty"`
+	AuthHeaderName       *string `json:"authHeaderName,omitempty"`
+	Notes                *string `json:"notes,omitempty"`
+	ClientCert           *string `json:"clientCert,omitempty"`
+	ClientKey            *string `json:"clientKey,omitempty"`
+	ExpectedVersion      *string `json:"expectedVersion,omitempty"`
+}
+
+// IsEmpty reports whether no fields are set
+func (p UpdateServiceParams) IsEmpty() bool {
+	return p.DisplayName == nil && p.URL == nil && p.APIKey == nil && p.AccessURL == nil && p.Tags == nil && p.APIVersion == nil &&
+		p.Color == nil && p.Icon == nil && p.CheckIntervalSeconds == nil &&
+		p.Method == nil && p.ExpectedStatus == nil && p.Critical == nil && p.AuthHeaderName == nil &&
+		p.Notes == nil && p.ClientCert == nil && p.ClientKey == nil && p.ExpectedVersion == nil
+}
+
+// ApplyTo sets the fields of the update on config, e.g. to validate a service as it is
+// after the update
+func (p UpdateServiceParams) ApplyTo(config *models.ServiceConfiguration) {
+	if p.DisplayName != nil {
+		config.DisplayName = *p.DisplayName
+	}
+	if p.URL != nil {
+		config.URL = *p.URL
+	}
+	if p.APIKey != nil {
+		config.APIKey = *p.APIKey
+	}
+	if p.AccessURL != nil {
+		config.AccessURL = *p.AccessURL
+	}
+	if p.Tags != nil {
+		config.Tags = *p.Tags
+	}
+	if p.APIVersion != nil {
+		config.APIVersion = *p.APIVersion
+	}
+	if p.Color != nil {
+		config.Color = *p.Color
+	}
+	if p.Icon != nil {
+		config.Icon = *p.Icon
+	}
+	if p.CheckIntervalSeconds != nil {
+		config.CheckIntervalSeconds = *p.CheckIntervalSeconds
+	}
+	if p.Method != nil {
+		config.Method = *p.Method
+	}
+	if p.ExpectedStatus != nil {
+		config.ExpectedStatus = *p.ExpectedStatus
+	}
+	if p.Critical != nil {
+		config.Critical = *p.Critical
+	}
+	if p.AuthHeaderName != nil {
+		config.AuthHeaderName = *p.AuthHeaderName
+	}
+	if p.Notes != nil {
+		config.Notes = *p.Notes
+	}
+	if p.ClientCert != nil {
+		config.ClientCert = *p.ClientCert
+	}
+	if p.ClientKey != nil {
+		config.ClientKey = *p.ClientKey
+	}
+	if p.ExpectedVersion != nil {
+		config.ExpectedVersion = *p.ExpectedVersion
+	}
+}
+
+// CloneServiceRequest copies a service configuration into a new instance of the same type
+type CloneServiceRequest struct {
+	InstanceID  string `json:"instanceId" binding:"required"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// ServiceLinkResponse is the user-facing URL of a service
+type ServiceLinkResponse struct {
+	InstanceID string `json:"instanceId"`
+	URL        string `json:"url"`
+	Source     string `json:"source"` // accessUrl or url
+}
+
+// BatchServiceResult reports whether a single service of a batch was created
+type BatchServiceResult struct {
+	InstanceID string `json:"instanceId"`
+	Created    bool   `json:"created"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BatchCreateServicesResponse lists the outcome of every service in a batch, in request order
+type BatchCreateServicesResponse struct {
+	Results []BatchServiceResult `json:"results"`
+	Created int                  `json:"created"`
+}
+
+// ListServicesParams filters and pages the service list. Zero values disable a filter.
+type ListServicesParams struct {
+	Limit  int
+	Offset int
+	Type   string // instance id prefix, e.g. "sonarr"
+	Tag    string
+}
+
+// MuteServiceRequest mutes a service either until a fixed time or for a duration such as "2h"
+type MuteServiceRequest struct {
+	Until    *time.Time `json:"until,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+}
+
+// ReorderServicesRequest sets the dashboard order of services, listed from first to last
+type ReorderServicesRequest struct {
+	InstanceIDs []string `json:"instanceIds" binding:"required"`
+}
+
+// QueueDeletePreview is returned instead of deleting a Sonarr or Radarr queue item when
+// the delete is a dry run
+type QueueDeletePreview struct {
+	DryRun                bool        `json:"dryRun"`
+	ID                    int         `json:"id"`
+	Title                 string      `json:"title"`
+	Status                string      `json:"status"`
+	TrackedDownloadStatus string      `json:"trackedDownloadStatus,omitempty"`
+	Options               interface{} `json:"options"` // The delete options that would be applied
+}
+
+// UpdateCheckResponse is the result of an on-demand update check
+type UpdateCheckResponse struct {
+	InstanceID      string    `json:"instanceId"`
+	UpdateAvailable bool      `json:"updateAvailable"`
+	Version         string    `json:"version,omitempty"` // Installed version reported by the fresh health check
+	CheckedAt       time.Time `json:"checkedAt"`
+}
+
+// HealthPollResponse carries the health results cached after the cursor a client polled
+// with. The client passes Cursor as since on its next poll.
+type HealthPollResponse struct {
+	Updates []models.ServiceHealth `json:"updates"`
+	Cursor  uint64                 `json:"cursor"`
+}
+
+// EventStreamStats reports the SSE clients and the broadcasts they missed since startup
+type EventStreamStats struct {
+	Clients               int64  `json:"clients"`
+	SkippedBroadcasts     uint64 `json:"skippedBroadcasts"`     // Updates not delivered because a client was too slow
+	SlowClientDisconnects uint64 `json:"slowClientDisconnects"` // Clients disconnected after too many skipped updates
+	BroadcastTimeoutMs    int64  `json:"broadcastTimeoutMs"`
+}
+
+// SetServiceEnabledRequest enables or disables polling for a service
+type SetServiceEnabledRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
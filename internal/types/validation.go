@@ -0,0 +1,38 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package types
+
+import (
+	"time"
+
+	"github.com/autobrr/dashbrr/internal/models"
+)
+
+// ValidateServiceRequest describes a service connection to test before saving it
+type ValidateServiceRequest struct {
+	Type       string `json:"type" binding:"required"`
+	URL        string `json:"url" binding:"required"`
+	APIKey     string `json:"apiKey"`
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Method and ExpectedStatus configure the request of the general service
+	Method         string `json:"method,omitempty"`
+	ExpectedStatus string `json:"expectedStatus,omitempty"`
+
+	// AuthHeaderName overrides the header the API key is sent in
+	AuthHeaderName string `json:"authHeaderName,omitempty"`
+
+	// ClientCert and ClientKey are presented to services that require mutual TLS
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+}
+
+// ServiceValidationResult is the outcome of a connection test. Results are cached
+// briefly, Age is the number of seconds since the check actually ran.
+type ServiceValidationResult struct {
+	Valid     bool                 `json:"valid"`
+	Health    models.ServiceHealth `json:"health"`
+	CheckedAt time.Time            `json:"checkedAt"`
+	Age       int64                `json:"age"`
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package utils
+
+import "fmt"
+
+var byteUnits = []string{"KB", "MB", "GB", "TB", "PB", "EB"}
+
+// FormatBytes formats a size in bytes as a human readable string such as "12.4 GB".
+// Units are powers of 1024, matching how the arr apps display sizes.
+func FormatBytes(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	value := float64(bytes) / 1024
+	unit := 0
+	for value >= 1024 && unit < len(byteUnits)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, byteUnits[unit])
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2024, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package utils
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{13314398618, "12.4 GB"},
+		{3 << 40, "3.0 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.bytes); got != tt.expected {
+			t.Errorf("FormatBytes(%d) = %q, expected %q", tt.bytes, got, tt.expected)
+		}
+	}
+}
@@ -67,8 +67,10 @@ func subFS(currentFs fs.FS, root string) (fs.FS, error) {
 	return fs.Sub(currentFs, root)
 }
 
-// ServeStatic registers static file handlers with Gin
-func ServeStatic(r *gin.Engine) {
+// ServeStatic registers static file handlers with Gin below the given base path
+func ServeStatic(r *gin.Engine, basePath string) {
+	g := r.Group(basePath)
+
 	// Helper function to serve static files with proper headers
 	serveStaticFile := func(c *gin.Context, filepath string, contentType string) {
 		file, err := DistDirFS.Open(filepath)
@@ -94,7 +96,7 @@ func ServeStatic(r *gin.Engine) {
 		if strings.Contains(filepath, "sw.js") || strings.Contains(filepath, "manifest.json") {
 			c.Header("Cache-Control", "no-cache")
 			if strings.Contains(filepath, "sw.js") {
-				c.Header("Service-Worker-Allowed", "/")
+				c.Header("Service-Worker-Allowed", basePath+"/")
 			}
 		} else {
 			c.Header("Cache-Control", "public, max-age=31536000")
@@ -106,63 +108,75 @@ func ServeStatic(r *gin.Engine) {
 	}
 
 	// Serve static files from root path
-	r.GET("/logo.svg", func(c *gin.Context) {
+	g.GET("/logo.svg", func(c *gin.Context) {
 		serveStaticFile(c, "logo.svg", "image/svg+xml")
 	})
 
-	r.GET("/masked-icon.svg", func(c *gin.Context) {
+	g.GET("/masked-icon.svg", func(c *gin.Context) {
 		serveStaticFile(c, "masked-icon.svg", "image/svg+xml")
 	})
 
-	r.GET("/favicon.ico", func(c *gin.Context) {
+	g.GET("/favicon.ico", func(c *gin.Context) {
 		serveStaticFile(c, "favicon.ico", "image/x-icon")
 	})
 
-	r.GET("/apple-touch-icon.png", func(c *gin.Context) {
+	g.GET("/apple-touch-icon.png", func(c *gin.Context) {
 		serveStaticFile(c, "apple-touch-icon.png", "image/png")
 	})
 
-	r.GET("/apple-touch-icon-iphone-60x60.png", func(c *gin.Context) {
+	g.GET("/apple-touch-icon-iphone-60x60.png", func(c *gin.Context) {
 		serveStaticFile(c, "apple-touch-icon-iphone-60x60.png", "image/png")
 	})
 
-	r.GET("/apple-touch-icon-ipad-76x76.png", func(c *gin.Context) {
+	g.GET("/apple-touch-icon-ipad-76x76.png", func(c *gin.Context) {
 		serveStaticFile(c, "apple-touch-icon-ipad-76x76.png", "image/png")
 	})
 
-	r.GET("/apple-touch-icon-iphone-retina-120x120.png", func(c *gin.Context) {
+	g.GET("/apple-touch-icon-iphone-retina-120x120.png", func(c *gin.Context) {
 		serveStaticFile(c, "apple-touch-icon-iphone-retina-120x120.png", "image/png")
 	})
 
-	r.GET("/apple-touch-icon-ipad-retina-152x152.png", func(c *gin.Context) {
+	g.GET("/apple-touch-icon-ipad-retina-152x152.png", func(c *gin.Context) {
 		serveStaticFile(c, "apple-touch-icon-ipad-retina-152x152.png", "image/png")
 	})
 
-	r.GET("/pwa-192x192.png", func(c *gin.Context) {
+	g.GET("/pwa-192x192.png", func(c *gin.Context) {
 		serveStaticFile(c, "pwa-192x192.png", "image/png")
 	})
 
-	r.GET("/pwa-512x512.png", func(c *gin.Context) {
+	g.GET("/pwa-512x512.png", func(c *gin.Context) {
 		serveStaticFile(c, "pwa-512x512.png", "image/png")
 	})
 
-	// Serve manifest.json
-	r.GET("/manifest.json", func(c *gin.Context) {
-		serveStaticFile(c, "manifest.json", "application/manifest+json; charset=utf-8")
+	// Serve manifest.json, rewriting its absolute paths when served below a base path
+	g.GET("/manifest.json", func(c *gin.Context) {
+		if basePath == "" {
+			serveStaticFile(c, "manifest.json", "application/manifest+json; charset=utf-8")
+			return
+		}
+
+		data, err := fs.ReadFile(DistDirFS, "manifest.json")
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.Header("Cache-Control", "no-cache")
+		c.Data(http.StatusOK, "application/manifest+json; charset=utf-8", bytes.ReplaceAll(data, []byte(`"/`), []byte(`"`+basePath+`/`)))
 	})
 
 	// Serve service worker
-	r.GET("/sw.js", func(c *gin.Context) {
+	g.GET("/sw.js", func(c *gin.Context) {
 		serveStaticFile(c, "sw.js", "text/javascript; charset=utf-8")
 	})
 
 	// Serve workbox files
-	r.GET("/workbox-:hash.js", func(c *gin.Context) {
-		serveStaticFile(c, c.Request.URL.Path[1:], "text/javascript; charset=utf-8")
+	g.GET("/workbox-:hash.js", func(c *gin.Context) {
+		serveStaticFile(c, strings.TrimPrefix(c.Request.URL.Path, basePath+"/"), "text/javascript; charset=utf-8")
 	})
 
 	// Serve assets directory
-	r.GET("/assets/*filepath", func(c *gin.Context) {
+	g.GET("/assets/*filepath", func(c *gin.Context) {
 		filepath := strings.TrimPrefix(c.Param("filepath"), "/")
 		fullPath := path.Join("assets", filepath)
 
@@ -193,14 +207,22 @@ func ServeStatic(r *gin.Engine) {
 		serveStaticFile(c, fullPath, contentType)
 	})
 
+	serveIndex := indexHandler(basePath)
+
 	// Serve index.html for root path and direct requests
-	r.GET("/", serveIndex)
-	r.GET("/index.html", serveIndex)
+	g.GET("/", serveIndex)
+	g.GET("/index.html", serveIndex)
 
 	// Handle all other routes
 	r.NoRoute(func(c *gin.Context) {
-		// Don't serve index.html for API routes
-		if strings.HasPrefix(c.Request.URL.Path, "/api") {
+		path := c.Request.URL.Path
+
+		// Don't serve index.html for API routes or paths outside the base path
+		if basePath != "" && path != basePath && !strings.HasPrefix(path, basePath+"/") {
+			c.AbortWithStatus(404)
+			return
+		}
+		if strings.HasPrefix(path, basePath+"/api") {
 			c.AbortWithStatus(404)
 			return
 		}
@@ -210,19 +232,37 @@ func ServeStatic(r *gin.Engine) {
 	})
 }
 
-// serveIndex serves index.html with proper headers
-func serveIndex(c *gin.Context) {
-	file, err := DistDirFS.Open("index.html")
-	if err != nil {
-		c.Status(http.StatusNotFound)
-		return
+// indexHandler serves index.html with proper headers. When a base path is set, absolute
+// asset paths are rewritten and the base path is exposed to the frontend.
+func indexHandler(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := fs.ReadFile(DistDirFS, "index.html")
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+		c.Header("Pragma", "no-cache")
+		c.Header("Expires", "0")
+
+		if basePath != "" {
+			data = rewriteIndex(data, basePath)
+		}
+
+		c.Writer.Write(data)
 	}
-	defer file.Close()
+}
 
-	c.Header("Content-Type", "text/html; charset=utf-8")
-	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
-	c.Header("Pragma", "no-cache")
-	c.Header("Expires", "0")
+// rewriteIndex prefixes absolute href and src attributes with the base path and injects
+// a base element and the window.__DASHBRR_BASE_PATH__ global used by the frontend
+func rewriteIndex(data []byte, basePath string) []byte {
+	data = bytes.ReplaceAll(data, []byte(`href="/`), []byte(`href="`+basePath+`/`))
+	data = bytes.ReplaceAll(data, []byte(`src="/`), []byte(`src="`+basePath+`/`))
 
-	io.Copy(c.Writer, file)
+	inject := fmt.Sprintf(`<head>
+    <base href="%s/" />
+    <script>window.__DASHBRR_BASE_PATH__ = %q;</script>`, basePath, basePath)
+	return bytes.Replace(data, []byte("<head>"), []byte(inject), 1)
 }
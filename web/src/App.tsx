@@ -22,6 +22,7 @@ import { ServiceType } from "./types/service";
 import { ArrowRightStartOnRectangleIcon } from "@heroicons/react/20/solid";
 import { StatusCounters } from "./components/shared/StatusCounters";
 import { useServiceHealth } from "./hooks/useServiceHealth";
+import { BASE_PATH } from "./config/basePath";
 
 // Preload the logo image
 const preloadLogo = new Image();
@@ -29,7 +30,7 @@ preloadLogo.src = logo;
 
 function App() {
   return (
-    <BrowserRouter>
+    <BrowserRouter basename={BASE_PATH || undefined}>
       <AuthProvider>
         <ConfigurationProvider>
           <Routes>
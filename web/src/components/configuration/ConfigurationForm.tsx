@@ -5,7 +5,7 @@
 
 import { useState } from "react";
 import { useConfiguration } from "../../contexts/useConfiguration";
-import { ServiceConfig } from "../../types/service";
+import { ServiceConfig, ServiceValidationResult } from "../../types/service";
 import { Button } from "../ui/Button";
 import { FormInput } from "../ui/FormInput";
 import { toast } from "react-hot-toast";
@@ -41,18 +41,20 @@ export const ConfigurationForm = ({
 
   const validateConfiguration = async (config: ServiceConfig) => {
     try {
-      const queryParams = new URLSearchParams({
-        url: config.url || "",
-        ...(config.apiKey && { apiKey: config.apiKey }),
-      }).toString();
+      const result = await api.post<ServiceValidationResult>(
+        "/api/services/validate",
+        {
+          type: serviceType,
+          url: config.url || "",
+          apiKey: config.apiKey || "",
+          apiVersion: currentConfig?.apiVersion,
+        }
+      );
 
-      const health = await api.get<{
-        status: string;
-        message?: string;
-      }>(`/api/health/${instanceId}?${queryParams}`);
-
-      if (health.status === "error" || health.status === "offline") {
-        throw new Error(health.message || "Failed to validate configuration");
+      if (!result.valid) {
+        throw new Error(
+          result.health.message || "Failed to validate configuration"
+        );
       }
 
       return true;
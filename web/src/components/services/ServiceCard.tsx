@@ -158,6 +158,7 @@ export const ServiceCard: React.FC<ServiceCardProps> = ({
                 accessUrl={currentConfig?.accessUrl || service.accessUrl}
                 version={service.version}
                 updateAvailable={service.updateAvailable}
+                healthIssues={service.details?.arr?.healthIssues}
                 healthEndpoint={service.healthEndpoint}
                 onConfigure={(e?: React.MouseEvent) => {
                   e?.stopPropagation();
@@ -214,6 +215,14 @@ export const ServiceCard: React.FC<ServiceCardProps> = ({
                 </span>
               </p>
             )}
+            {service.lastSuccess && service.status !== "online" && service.status !== "warning" && (
+              <p className="text-xs font-medium text-gray-600 dark:text-gray-400">
+                Last success:{" "}
+                <span className="font-normal">
+                  {new Date(service.lastSuccess).toLocaleString()}
+                </span>
+              </p>
+            )}
           </div>
 
           {/* Collapse/Expand Icon */}
@@ -68,6 +68,7 @@ export const AutobrrStats: React.FC<AutobrrStatsProps> = ({ instanceId }) => {
     push_error_count: 0,
   };
   const ircStatus = service.details?.autobrr?.irc;
+  const filters = service.details?.autobrr?.filters;
   const releases = service.releases?.data || [];
 
   const showMessage = service.message || service.status !== "online";
@@ -115,8 +116,15 @@ export const AutobrrStats: React.FC<AutobrrStatsProps> = ({ instanceId }) => {
       {/* Stats */}
       {showStats && (
         <div>
-          <div className="text-xs mb-2 font-semibold text-gray-700 dark:text-gray-300">
-            Stats:
+          <div className="flex justify-between items-center text-xs mb-2">
+            <span className="font-semibold text-gray-700 dark:text-gray-300">
+              Stats:
+            </span>
+            {filters && (
+              <span className="text-gray-500 dark:text-gray-400">
+                {filters.total} {filters.total === 1 ? "filter" : "filters"} ({filters.enabled} enabled)
+              </span>
+            )}
           </div>
           <div className="grid grid-cols-2 gap-2">
             <a
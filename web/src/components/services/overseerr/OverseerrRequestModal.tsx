@@ -39,9 +39,9 @@ export const OverseerrRequestModal: React.FC<OverseerrRequestModalProps> = ({
     });
   };
 
-  const getUserDisplayName = (
-    requestedBy: OverseerrMediaRequest["requestedBy"]
-  ) => {
+  const getUserDisplayName = (request: OverseerrMediaRequest) => {
+    const { requestedBy, requester } = request;
+    if (requester?.name) return requester.name;
     if (!requestedBy) return "Unknown User";
     return (
       requestedBy.username ||
@@ -105,9 +105,21 @@ export const OverseerrRequestModal: React.FC<OverseerrRequestModalProps> = ({
                   <div className="flex items-center text-gray-300 gap-2">
                     <UserIcon className="h-4 w-4 text-gray-400" />
                     <span className="font-medium">Requested by:</span>
+                    {request.requester?.avatarUrl && (
+                      <img
+                        src={request.requester.avatarUrl}
+                        alt=""
+                        className="h-4 w-4 rounded-full"
+                      />
+                    )}
                     <span className="text-gray-400">
-                      {getUserDisplayName(request.requestedBy)}
+                      {getUserDisplayName(request)}
                     </span>
+                    {request.requester && (
+                      <span className="text-xs text-gray-500">
+                        ({request.requester.type} user)
+                      </span>
+                    )}
                   </div>
                   <div className="flex items-center text-gray-300 gap-2">
                     <ClockIcon className="h-4 w-4 text-gray-400" />
@@ -143,9 +143,9 @@ export const OverseerrStats: React.FC<OverseerrStatsProps> = ({
     });
   };
 
-  const getUserDisplayName = (
-    requestedBy: OverseerrMediaRequest["requestedBy"]
-  ) => {
+  const getUserDisplayName = (request: OverseerrMediaRequest) => {
+    const { requestedBy, requester } = request;
+    if (requester?.name) return requester.name;
     if (!requestedBy) return "Unknown User";
     return (
       requestedBy.username ||
@@ -181,7 +181,14 @@ export const OverseerrStats: React.FC<OverseerrStatsProps> = ({
             {getMediaTitle(request)}
           </div>
           <div className="text-gray-500 text-[11px] flex items-center gap-2 pointer-events-none">
-            <span>{getUserDisplayName(request.requestedBy)}</span>
+            {request.requester?.avatarUrl && (
+              <img
+                src={request.requester.avatarUrl}
+                alt=""
+                className="h-3.5 w-3.5 rounded-full"
+              />
+            )}
+            <span>{getUserDisplayName(request)}</span>
             <span>•</span>
             <span>{formatDate(request.createdAt)}</span>
           </div>
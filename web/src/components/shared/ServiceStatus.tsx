@@ -48,6 +48,26 @@ export const ServiceStatus: React.FC<ServiceStatusProps> = ({
       text: "text-purple-700 dark:text-purple-300",
       label: "Not Configured",
     },
+    checking: {
+      color: "bg-blue-500",
+      text: "text-blue-700 dark:text-blue-300",
+      label: "Checking",
+    },
+    unconfigured: {
+      color: "bg-purple-500",
+      text: "text-purple-700 dark:text-purple-300",
+      label: "Not Configured",
+    },
+    disabled: {
+      color: "bg-gray-500",
+      text: "text-gray-700 dark:text-gray-300",
+      label: "Disabled",
+    },
+    unauthorized: {
+      color: "bg-orange-500",
+      text: "text-orange-700 dark:text-orange-300",
+      label: "Check Credentials",
+    },
     unknown: {
       color: "bg-gray-500",
       text: "text-gray-700 dark:text-gray-300",
@@ -20,6 +20,7 @@ interface ServiceHeaderProps {
   accessUrl?: string;
   version?: string;
   updateAvailable?: boolean;
+  healthIssues?: number;
   healthEndpoint?: string;
   onConfigure: (e?: React.MouseEvent) => void;
   onRemove: (e?: React.MouseEvent) => void;
@@ -33,6 +34,7 @@ export const ServiceHeader: React.FC<ServiceHeaderProps> = ({
   accessUrl,
   version,
   updateAvailable,
+  healthIssues,
   onConfigure,
   onRemove,
   needsConfiguration,
@@ -107,6 +109,14 @@ export const ServiceHeader: React.FC<ServiceHeaderProps> = ({
                 )}
               </span>
             )}
+            {healthIssues ? (
+              <span
+                className="inline-flex items-center justify-center px-2 py-1 rounded text-xs font-medium flex-shrink-0 text-yellow-700 dark:text-yellow-400 bg-yellow-50/90 dark:bg-yellow-900/30"
+                title="Issues reported by the service itself"
+              >
+                {healthIssues} {healthIssues === 1 ? "issue" : "issues"}
+              </span>
+            ) : null}
           </div>
         </div>
         <div className="flex items-center space-x-2 ml-4">
@@ -12,6 +12,7 @@ export const API_BASE_URL = import.meta.env.DEV
 export const API_PREFIX = '/api';
 
 import { api } from '../utils/api';
+import { withBasePath } from './basePath';
 
 interface ApiResponse {
   success: boolean;
@@ -77,6 +78,14 @@ export interface ReleaseStats {
 export interface IRCStatus {
   name: string;
   healthy: boolean;
+  enabled?: boolean;
+}
+
+export interface IRCDetail {
+  networks: IRCStatus[];
+  total: number;
+  enabled: number;
+  healthy: number;
 }
 
 export interface MaintainerrCollection {
@@ -105,7 +114,7 @@ export const getBackendUrl = (path: string) => {
   const apiPath = path.startsWith('/api') ? path : `${API_PREFIX}${path}`;
   return import.meta.env.DEV
     ? `http://localhost:8080${apiPath}`  // Development
-    : apiPath;                           // Production
+    : withBasePath(apiPath);             // Production
 };
 
 export const getPlexSessions = async (baseUrl: string, apiKey: string): Promise<PlexSession[]> => {
@@ -154,6 +163,16 @@ export const getAutobrrIRC = async (instanceId: string): Promise<IRCStatus[]> =>
   }
 };
 
+export const getAutobrrIRCDetail = async (instanceId: string): Promise<IRCDetail> => {
+  try {
+    const params = new URLSearchParams({ instanceId });
+    return await api.get<IRCDetail>(buildUrl(`/autobrr/irc/detail?${params}`));
+  } catch (error) {
+    console.error('Error fetching autobrr IRC networks:', error);
+    throw error;
+  }
+};
+
 export const getMaintainerrCollections = async (instanceId: string): Promise<MaintainerrCollection[]> => {
   try {
     const params = new URLSearchParams({ instanceId });
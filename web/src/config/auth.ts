@@ -3,38 +3,40 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+import { BASE_PATH, withBasePath } from './basePath';
+
 // Get the current frontend URL
 const getFrontendUrl = () => {
   // In development, use localhost:3000
   if (import.meta.env.DEV) {
-    return 'http://localhost:3000';
+    return `http://localhost:3000${BASE_PATH}`;
   }
-  // In production, use the current origin
-  return window.location.origin;
+  // In production, use the current origin and base path
+  return `${window.location.origin}${BASE_PATH}`;
 };
 
 // Common auth endpoints
 const COMMON_ENDPOINTS = {
-  config: '/api/auth/config',
-  userInfo: '/api/auth/userinfo',
+  config: withBasePath('/api/auth/config'),
+  userInfo: withBasePath('/api/auth/userinfo'),
 };
 
 // OIDC-specific endpoints
 const OIDC_ENDPOINTS = {
-  login: `/api/auth/oidc/login?frontendUrl=${encodeURIComponent(getFrontendUrl())}`,
-  callback: `/api/auth/oidc/callback?frontendUrl=${encodeURIComponent(getFrontendUrl())}`,
-  logout: `/api/auth/oidc/logout?frontendUrl=${encodeURIComponent(getFrontendUrl())}`,
-  refresh: '/api/auth/oidc/refresh',
-  verify: '/api/auth/oidc/verify',
-  userInfo: '/api/auth/oidc/userinfo',
+  login: withBasePath(`/api/auth/oidc/login?frontendUrl=${encodeURIComponent(getFrontendUrl())}`),
+  callback: withBasePath(`/api/auth/oidc/callback?frontendUrl=${encodeURIComponent(getFrontendUrl())}`),
+  logout: withBasePath(`/api/auth/oidc/logout?frontendUrl=${encodeURIComponent(getFrontendUrl())}`),
+  refresh: withBasePath('/api/auth/oidc/refresh'),
+  verify: withBasePath('/api/auth/oidc/verify'),
+  userInfo: withBasePath('/api/auth/oidc/userinfo'),
 };
 
 // Built-in auth endpoints
 const BUILTIN_ENDPOINTS = {
-  login: '/api/auth/login',
-  register: '/api/auth/register',
-  logout: '/api/auth/logout',
-  verify: '/api/auth/verify',
+  login: withBasePath('/api/auth/login'),
+  register: withBasePath('/api/auth/register'),
+  logout: withBasePath('/api/auth/logout'),
+  verify: withBasePath('/api/auth/verify'),
 };
 
 export const AUTH_URLS = {
@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2024, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+declare global {
+  interface Window {
+    // Injected into index.html by the backend when server.base_path is set
+    __DASHBRR_BASE_PATH__?: string;
+  }
+}
+
+// Sub-path the app is served under, e.g. "/dashbrr". Empty when served at the root.
+export const BASE_PATH = window.__DASHBRR_BASE_PATH__ ?? '';
+
+// Prefix an absolute path with the base path
+export const withBasePath = (path: string): string =>
+  path.startsWith('/') && !path.startsWith('//') ? `${BASE_PATH}${path}` : path;
@@ -5,10 +5,12 @@
 
 import { useState, useEffect, ReactNode, useCallback } from "react";
 import { API_BASE_URL, API_PREFIX } from "../config/api";
+import { withBasePath } from "../config/basePath";
 import { ServiceConfig } from "../types/service";
 import { useAuth } from "../hooks/useAuth";
 import { ConfigurationContext } from "./context";
 import { ConfigurationContextType } from "./types";
+import { getCSRFToken } from "../utils/api";
 
 export function ConfigurationProvider({ children }: { children: ReactNode }) {
   const { isAuthenticated } = useAuth();
@@ -20,13 +22,14 @@ export function ConfigurationProvider({ children }: { children: ReactNode }) {
 
   const buildUrl = useCallback((path: string) => {
     const apiPath = path.startsWith("/api") ? path : `${API_PREFIX}${path}`;
-    return `${API_BASE_URL}${apiPath}`;
+    return `${API_BASE_URL}${withBasePath(apiPath)}`;
   }, []);
 
   const getAuthHeaders = useCallback(() => {
     const accessToken = localStorage.getItem("access_token");
     return {
       "Content-Type": "application/json",
+      "X-CSRF-Token": getCSRFToken(),
       ...(accessToken ? { Authorization: `Bearer ${accessToken}` } : {}),
     };
   }, []);
@@ -5,6 +5,7 @@
 
 import { useEffect, useRef, useState, useCallback } from 'react';
 import { useAuth } from './useAuth';
+import { withBasePath } from '../config/basePath';
 
 // Error types for better error handling
 export enum EventSourceErrorType {
@@ -32,6 +33,29 @@ interface EventData {
   [key: string]: unknown;
 }
 
+// Version of the health event envelope this frontend understands
+export const HEALTH_SCHEMA_VERSION = 1;
+
+interface HealthEnvelope<T> {
+  v: number;
+  type: string;
+  data: T;
+}
+
+// parseHealthEvent unwraps the versioned `{v, type, data}` envelope of a health
+// event. Flat payloads from older backends are passed through unchanged.
+export const parseHealthEvent = <T>(raw: string): T => {
+  const payload = JSON.parse(raw);
+  if (payload && typeof payload === 'object' && 'v' in payload && 'data' in payload) {
+    const envelope = payload as HealthEnvelope<T>;
+    if (envelope.v > HEALTH_SCHEMA_VERSION) {
+      console.warn(`[EventSource] Health event schema v${envelope.v} is newer than the supported v${HEALTH_SCHEMA_VERSION}, reload to update`);
+    }
+    return envelope.data;
+  }
+  return payload as T;
+};
+
 // Extended EventSource type with additional properties
 interface ExtendedEventSource extends EventSource {
   status?: number;
@@ -61,6 +85,8 @@ export const useEventSource = <T extends EventData>(
   const authErrorRef = useRef<boolean>(false);
   const updateTimeoutRef = useRef<number | null>(null);
   const pendingUpdatesRef = useRef<T[]>([]);
+  // Id of the last health event, sent on reconnect so only missed results are replayed
+  const lastEventIdRef = useRef<string>('');
 
   const {
     onMessage,
@@ -180,9 +206,12 @@ export const useEventSource = <T extends EventData>(
 
         cleanup(); // Ensure clean slate before connecting
 
-        const url = new URL(path, window.location.origin);
+        const url = new URL(withBasePath(path), window.location.origin);
         url.searchParams.append('token', accessToken);
         url.searchParams.append('nocache', Date.now().toString());
+        if (lastEventIdRef.current) {
+          url.searchParams.append('lastEventId', lastEventIdRef.current);
+        }
 
         const eventSource = new EventSource(url.toString());
         eventSourceRef.current = eventSource;
@@ -220,8 +249,11 @@ export const useEventSource = <T extends EventData>(
           if (!mountedRef.current) return;
 
           try {
-            const data = JSON.parse(event.data) as T;
+            const data = parseHealthEvent<T>(event.data);
             lastMessageTimeRef.current = Date.now();
+            if (event.lastEventId) {
+              lastEventIdRef.current = event.lastEventId;
+            }
             pendingUpdatesRef.current.push(data);
             processPendingUpdates();
           } catch (error) {
@@ -240,6 +272,14 @@ export const useEventSource = <T extends EventData>(
           lastMessageTimeRef.current = Date.now();
         });
 
+        // The server is going away, the browser reconnects once it is back
+        eventSource.addEventListener('shutdown', () => {
+          if (!mountedRef.current) return;
+          console.log('[EventSource] Server is shutting down, waiting to reconnect');
+          lastMessageTimeRef.current = Date.now();
+          setIsConnected(false);
+        });
+
         eventSource.onerror = (error) => {
           if (!mountedRef.current) return;
 
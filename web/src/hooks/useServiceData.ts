@@ -12,6 +12,7 @@ import {
   ServiceDetails,
   AutobrrStats,
   AutobrrIRC,
+  AutobrrIRCDetail,
   AutobrrReleases,
   MaintainerrCollection,
   PlexSession,
@@ -24,9 +25,11 @@ import {
   ServiceHealth} from '../types/service';
 import { useConfiguration } from '../contexts/useConfiguration';
 import { useAuth } from './useAuth';
+import { parseHealthEvent } from './useEventSource';
 import serviceTemplates from '../config/serviceTemplates';
 import { api } from '../utils/api';
 import { cache, CACHE_PREFIXES } from '../utils/cache';
+import { withBasePath } from '../config/basePath';
 
 interface ServiceData {
   stats?: ServiceStats;
@@ -198,7 +201,7 @@ export const useServiceData = () => {
   }, [updateServiceData]);
 
   const fetchServiceStats = useCallback(async (service: Service) => {
-    if (service.type === 'omegabrr' || service.type === 'tailscale' || service.type === 'general') return;
+    if (service.type === 'omegabrr' || service.type === 'tailscale' || service.type === 'general' || service.type === 'tcp' || service.type === 'redis' || service.type === 'database') return;
     if (!service.url || !service.apiKey) return;
 
     if (service.type === 'plex') {
@@ -331,7 +334,8 @@ export const useServiceData = () => {
             details: {
               plex: {
                 activeStreams: sessions.length,
-                transcoding: sessions.filter((s: PlexSession) => s.TranscodeSession).length
+                transcoding: sessions.filter((s: PlexSession) => s.TranscodeSession).length,
+                ...health.details?.plex
               }
             }
           });
@@ -463,7 +467,7 @@ export const useServiceData = () => {
   const initializeService = useCallback((instanceId: string, config: ServiceConfig) => {
     const [type] = instanceId.split('-');
     const template = serviceTemplates.find(t => t.type === type);
-    const hasRequiredConfig = Boolean(config.url && (config.apiKey || type === 'general'));
+    const hasRequiredConfig = Boolean(config.url && (config.apiKey || type === 'general' || type === 'tcp' || type === 'redis' || type === 'database'));
 
     const service = {
       id: instanceId,
@@ -565,22 +569,23 @@ const updateService = useCallback((service: Service) => {
       eventSourceRef.current.close();
     }
 
-    const eventSource = new EventSource('/api/events');
+    const eventSource = new EventSource(withBasePath('/api/events'));
 
     eventSource.onmessage = (event) => {
       try {
-        const health = JSON.parse(event.data) as ServiceHealth;
+        const health = parseHealthEvent<ServiceHealth>(event.data);
         
         switch (health.message) {
           case 'plex_sessions': {
             if (health.stats?.plex?.sessions) {
               const sessions = health.stats.plex.sessions;
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { plex: { sessions } },
                 details: {
                   plex: {
                     activeStreams: sessions.length,
-                    transcoding: sessions.filter((s: PlexSession) => s.TranscodeSession).length
+                    transcoding: sessions.filter((s: PlexSession) => s.TranscodeSession).length,
+                    ...health.details?.plex
                   }
                 }
               });
@@ -590,10 +595,11 @@ const updateService = useCallback((service: Service) => {
           case 'autobrr_irc_status': {
             if (health.details?.autobrr?.irc) {
               const ircStatus = health.details.autobrr.irc as AutobrrIRC[];
-              const currentService = services.get(health.serviceId);
-              updateServiceData(health.serviceId, {
+              const currentService = services.get(health.instanceId);
+              updateServiceData(health.instanceId, {
                 details: {
                   autobrr: {
+                    ...currentService?.details?.autobrr,
                     irc: ircStatus,
                     base_url: currentService?.url || ''
                   }
@@ -602,11 +608,26 @@ const updateService = useCallback((service: Service) => {
             }
             break;
           }
+          case 'autobrr_irc_detail': {
+            if (health.details?.autobrr?.ircDetail) {
+              const ircDetail = health.details.autobrr.ircDetail as AutobrrIRCDetail;
+              const currentService = services.get(health.instanceId);
+              updateServiceData(health.instanceId, {
+                details: {
+                  autobrr: {
+                    ...currentService?.details?.autobrr,
+                    ircDetail
+                  }
+                }
+              });
+            }
+            break;
+          }
           case 'autobrr_releases': {
             if (health.stats?.autobrr) {
               const releases = health.stats.autobrr as unknown as AutobrrReleases;
               if (releases && releases.data) {
-                updateServiceData(health.serviceId, {
+                updateServiceData(health.instanceId, {
                   releases
                 });
               }
@@ -616,7 +637,7 @@ const updateService = useCallback((service: Service) => {
           case 'autobrr_stats': {
             if (health.stats?.autobrr) {
               const stats = health.stats.autobrr as AutobrrStats;
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { autobrr: stats }
               });
             }
@@ -625,7 +646,7 @@ const updateService = useCallback((service: Service) => {
           case 'overseerr_requests': {
             if (health.stats?.overseerr) {
               const stats = health.stats.overseerr;
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { overseerr: stats },
                 details: {
                   overseerr: {
@@ -643,7 +664,7 @@ const updateService = useCallback((service: Service) => {
               const downloadingCount = queue.records.filter(r => r.status === 'downloading').length;
               const totalSize = queue.records.reduce((acc, r) => acc + r.size, 0);
               
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { radarr: { queue } },
                 details: {
                   radarr: {
@@ -664,7 +685,7 @@ const updateService = useCallback((service: Service) => {
               const episodeCount = queue.records.reduce((acc, r) => acc + r.episodes.length, 0);
               const totalSize = queue.records.reduce((acc, r) => acc + r.size, 0);
               
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { sonarr: { queue } },
                 details: {
                   sonarr: {
@@ -682,11 +703,11 @@ const updateService = useCallback((service: Service) => {
           }
           case 'sonarr_stats': {
             if (health.stats?.sonarr) {
-              const currentService = services.get(health.serviceId);
+              const currentService = services.get(health.instanceId);
               const sonarrStats = health.stats.sonarr;
               const currentQueue = currentService?.stats?.sonarr?.queue || { totalRecords: 0, records: [] };
               
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { 
                   sonarr: {
                     queue: currentQueue,
@@ -707,7 +728,7 @@ const updateService = useCallback((service: Service) => {
           }
           case 'prowlarr_stats': {
             if (health.stats?.prowlarr?.stats) {
-              const currentService = services.get(health.serviceId);
+              const currentService = services.get(health.instanceId);
               const prowlarrStats = health.stats.prowlarr.stats as ProwlarrStats;
               const currentIndexers = currentService?.stats?.prowlarr?.indexers || [];
               const currentIndexerStats = currentService?.stats?.prowlarr?.prowlarrIndexerStats || {
@@ -715,7 +736,7 @@ const updateService = useCallback((service: Service) => {
                 indexers: []
               };
               
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { 
                   prowlarr: {
                     stats: prowlarrStats,
@@ -735,7 +756,7 @@ const updateService = useCallback((service: Service) => {
           }
           case 'prowlarr_indexers': {
             if (health.stats?.prowlarr?.indexers) {
-              const currentService = services.get(health.serviceId);
+              const currentService = services.get(health.instanceId);
               const prowlarrIndexers = health.stats.prowlarr.indexers;
               const currentStats = currentService?.stats?.prowlarr?.stats as ProwlarrStats;
               const currentIndexerStats = currentService?.stats?.prowlarr?.prowlarrIndexerStats || {
@@ -743,7 +764,7 @@ const updateService = useCallback((service: Service) => {
                 indexers: []
               };
               
-              updateServiceData(health.serviceId, {
+              updateServiceData(health.instanceId, {
                 stats: { 
                   prowlarr: {
                     stats: currentStats,
@@ -761,9 +782,15 @@ const updateService = useCallback((service: Service) => {
             }
             break;
           }
+          case 'queue_item_removed': {
+            // The queue broadcast that follows the removal updates the card,
+            // this event must not overwrite the service health
+            console.info(`Stalled queue item removed from ${health.instanceId}:`, health.details?.queueItemRemoved);
+            break;
+          }
           default: {
-            if (health.serviceId) {
-              updateServiceData(health.serviceId, health);
+            if (health.instanceId) {
+              updateServiceData(health.instanceId, health);
             }
           }
         }
@@ -17,6 +17,10 @@ const initialStatusCount: StatusCount = {
   error: 0,
   loading: 0,
   pending: 0,
+  checking: 0,
+  unconfigured: 0,
+  disabled: 0,
+  unauthorized: 0,
   unknown: 0,
 };
 
@@ -3,18 +3,27 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-export type ServiceStatus = 'online' | 'offline' | 'warning' | 'error' | 'loading' | 'pending' | 'unknown';
+export type ServiceStatus = 'online' | 'offline' | 'warning' | 'error' | 'loading' | 'pending' | 'checking' | 'unconfigured' | 'disabled' | 'unauthorized' | 'unknown';
 
-export type ServiceType = 'autobrr' | 'omegabrr' | 'radarr' | 'sonarr' | 'prowlarr'| 'overseerr' | 'plex' | 'tailscale' | 'maintainerr' | 'general' | 'other';
+export type ServiceType = 'autobrr' | 'omegabrr' | 'radarr' | 'sonarr' | 'prowlarr'| 'overseerr' | 'plex' | 'tailscale' | 'maintainerr' | 'general' | 'tcp' | 'redis' | 'database' | 'other';
 
 export interface ServiceHealth {
   status: ServiceStatus;
   message: string;
+  detail?: string;
+  instanceId: string;
+  /** @deprecated Same as instanceId */
   serviceId: string;
   lastChecked?: Date;
+  lastSuccess?: Date;
   responseTime?: number;
   version?: string;
   updateAvailable?: boolean;
+  muted?: boolean;
+  pinned?: boolean;
+  maintenance?: boolean;
+  color?: string;
+  icon?: string;
   stats?: ServiceStats;
   details?: ServiceDetails;
   extras?: Record<string, unknown>;
@@ -31,10 +40,30 @@ export interface Service {
   url: string;
   accessUrl?: string;
   apiKey?: string;
+  mutedUntil?: string;
+  disabled?: boolean;
+  tags?: string[];
+  apiVersion?: string;
+  readOnly?: boolean;
+  color?: string;
+  icon?: string;
+  checkIntervalSeconds?: number;
+  method?: string;
+  expectedStatus?: string;
+  critical?: boolean;
+  authHeaderName?: string;
+  notes?: string;
+  clientCert?: string;
+  clientKey?: string;
+  expectedVersion?: string;
+  pinned?: boolean;
+  position?: number;
   lastChecked?: Date;
+  lastSuccess?: Date;
   responseTime?: number;
   healthEndpoint?: string;
   message?: string;
+  detail?: string;
   updateAvailable?: boolean;
   version?: string;
   retryCount?: number;
@@ -48,6 +77,18 @@ export interface ServiceConfig {
   url: string;
   accessUrl?: string;
   apiKey?: string;
+  apiVersion?: string;
+  color?: string;
+  icon?: string;
+  checkIntervalSeconds?: number;
+  method?: string;
+  expectedStatus?: string;
+  critical?: boolean;
+  authHeaderName?: string;
+  notes?: string;
+  clientCert?: string;
+  clientKey?: string;
+  expectedVersion?: string;
   displayName: string;
 }
 
@@ -61,9 +102,22 @@ export interface AutobrrStats {
   push_error_count: number;
 }
 
+export interface AutobrrFilterSummary {
+  total: number;
+  enabled: number;
+}
+
 export interface AutobrrIRC {
   name: string;
   healthy: boolean;
+  enabled?: boolean;
+}
+
+export interface AutobrrIRCDetail {
+  networks: AutobrrIRC[];
+  total: number;
+  enabled: number;
+  healthy: number;
 }
 
 export interface AutobrrReleases {
@@ -282,6 +336,7 @@ export interface OverseerrMediaRequest {
     updatedAt: string;
     serviceUrl?: string;
     title?: string;
+    name?: string;
     externalServiceId?: number;
     externalServiceSlug?: string;
   };
@@ -315,6 +370,13 @@ export interface OverseerrMediaRequest {
   serverId: number;
   profileId: number;
   rootFolder: string;
+  requester?: OverseerrRequester;
+}
+
+export interface OverseerrRequester {
+  name: string;
+  type: "plex" | "local" | "jellyfin" | "emby";
+  avatarUrl?: string;
 }
 
 export interface OverseerrStats {
@@ -477,11 +539,33 @@ export interface ServiceStats {
   };
 }
 
+// Outcome of POST /api/services/validate, age is the number of seconds since the check ran
+export interface ServiceValidationResult {
+  valid: boolean;
+  health: ServiceHealth;
+  checkedAt: string;
+  age: number;
+}
+
 // Service Details Union Type
+// A warning or error a Sonarr, Radarr or Prowlarr instance reports about itself
+export interface ArrHealthIssue {
+  source: string;
+  type: string;
+  message: string;
+  wikiUrl: string;
+}
+
 export interface ServiceDetails {
+  queueItemRemoved?: {
+    id: number;
+    title: string;
+  };
   autobrr?: {
-    irc: AutobrrIRC[];
-    base_url: string;
+    irc?: AutobrrIRC[];
+    ircDetail?: AutobrrIRCDetail;
+    base_url?: string;
+    filters?: AutobrrFilterSummary;
   };
   omegabrr?: {
     webhookStatus: OmegabrrWebhookStatus;
@@ -489,6 +573,10 @@ export interface ServiceDetails {
   plex?: {
     activeStreams: number;
     transcoding: number;
+    directPlay?: number;
+    directStream?: number;
+    bandwidth?: number;
+    quality?: Record<string, number>;
   };
   maintainerr?: {
     activeCollections: number;
@@ -499,6 +587,11 @@ export interface ServiceDetails {
     totalRequests?: number;
     pendingCount?: number;
   };
+  arr?: {
+    healthIssues: number;
+    issues?: ArrHealthIssue[];
+    version?: string;
+  };
   sonarr?: {
     queueCount: number;
     monitored: number;
@@ -518,4 +611,39 @@ export interface ServiceDetails {
     activeIndexers: number;
     totalGrabs: number;
   };
+  redis?: {
+    usedMemory?: number;
+    connectedClients?: number;
+  };
+  database?: {
+    activeConnections: number;
+  };
+}
+
+// Dashboard summary, GET /api/dashboard
+export interface DashboardStat {
+  label: string;
+  value: number;
+}
+
+export interface DashboardEntry {
+  instanceId: string;
+  type: ServiceType;
+  displayName: string;
+  pinned?: boolean;
+  health?: ServiceHealth;
+  stat?: DashboardStat;
+}
+
+export interface DashboardSummary {
+  services: DashboardEntry[];
+  generatedAt: string;
+}
+
+// Navigation badge counts, GET /api/summary/badges
+export interface SummaryBadges {
+  offline: number;
+  updates: number;
+  pendingRequests: number;
+  activeDownloads: number;
 }
@@ -3,6 +3,8 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+import { withBasePath } from '../config/basePath';
+
 interface RequestOptions {
   method: string;
   headers?: Record<string, string>;
@@ -86,11 +88,17 @@ class RequestQueue {
   }
 }
 
+export const getCSRFToken = (): string => {
+  const match = document.cookie.match(/(?:^|;\s*)csrf_token=([^;]*)/);
+  return match ? decodeURIComponent(match[1]) : '';
+};
+
 const getAuthHeaders = (): Record<string, string> => {
   const token = localStorage.getItem('access_token');
   return {
     'Authorization': token ? `Bearer ${token}` : '',
     'Content-Type': 'application/json',
+    'X-CSRF-Token': getCSRFToken(),
   };
 };
 
@@ -157,7 +165,7 @@ const handleRequest = async <T>(
   
   try {
     const apiPath = path.startsWith('/api') ? path : `/api${path}`;
-    const url = apiPath;
+    const url = withBasePath(apiPath);
 
     const controller = new AbortController();
     const timeoutId = setTimeout(() => controller.abort(), timeout);
@@ -177,7 +185,7 @@ const handleRequest = async <T>(
         localStorage.removeItem('id_token');
         localStorage.removeItem('auth_type');
         await unregisterServiceWorker();
-        window.location.href = '/login';
+        window.location.href = withBasePath('/login');
         throw new Error('Authentication required');
       } else {
         // For service-related 401s (like invalid API keys), just throw an error
@@ -238,5 +246,5 @@ export const api = {
 
 export const getEventSourceUrl = (path: string): string => {
   const apiPath = path.startsWith('/api') ? path : `/api${path}`;
-  return `${window.location.origin}${apiPath}`;
+  return `${window.location.origin}${withBasePath(apiPath)}`;
 };